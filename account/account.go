@@ -21,6 +21,10 @@ type Account struct {
 	ProgramHash  common.Uint168
 	RedeemScript []byte
 	Address      string
+
+	// Path is the derivation path of the account derived from the seed of
+	// a HD wallet, it's empty for the other accounts.
+	Path string
 }
 
 // Create an account instance with private key and public key
@@ -93,6 +97,55 @@ func NewMultiSigAccount(m int, pubKeys []*crypto.PublicKey) (*Account, error) {
 	}, nil
 }
 
+// NewWatchAccount creates a watch-only standard account of the public key,
+// which can build transactions but can not sign them.
+func NewWatchAccount(pubKey *crypto.PublicKey) (*Account, error) {
+	signatureContract, err := contract.CreateStandardContract(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	programHash := signatureContract.ToProgramHash()
+	address, err := programHash.ToAddress()
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		PrivateKey:   nil,
+		PublicKey:    pubKey,
+		ProgramHash:  *programHash,
+		RedeemScript: signatureContract.Code,
+		Address:      address,
+	}, nil
+}
+
+// NewWatchAccountWithAddress creates a watch-only account of the standard or
+// multi-signature address, the redeem script is unknown so the program of a
+// transaction spending it is added by the signer.
+func NewWatchAccountWithAddress(address string) (*Account, error) {
+	programHash, err := common.Uint168FromAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	prefixType := contract.GetPrefixType(*programHash)
+	if prefixType != contract.PrefixStandard &&
+		prefixType != contract.PrefixMultiSig {
+		return nil, errors.New("standard or multi-signature address expected")
+	}
+	return &Account{
+		PrivateKey:  nil,
+		PublicKey:   nil,
+		ProgramHash: *programHash,
+		Address:     address,
+	}, nil
+}
+
+// IsWatchOnly returns if the account is a standard account without the
+// private key.
+func (ac *Account) IsWatchOnly() bool {
+	return ac.PrivateKey == nil &&
+		contract.GetPrefixType(ac.ProgramHash) == contract.PrefixStandard
+}
+
 // Get account private key
 func (ac *Account) PrivKey() []byte {
 	return ac.PrivateKey
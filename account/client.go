@@ -2,14 +2,13 @@ package account
 
 import (
 	"bytes"
+	"crypto/rand"
 	"crypto/sha256"
 	"errors"
 	"fmt"
-	"math/rand"
 	"os"
 	"sort"
 	"sync"
-	"time"
 
 	"github.com/elastos/Elastos.ELA/common"
 	"github.com/elastos/Elastos.ELA/core/contract"
@@ -27,6 +26,7 @@ type Client struct {
 	path      string
 	iv        []byte
 	masterKey []byte
+	seed      []byte
 
 	mainAccount common.Uint160
 	accounts    map[common.Uint160]*Account
@@ -120,6 +120,9 @@ func (cl *Client) signStandardTransaction(txn *types.Transaction, program *pg.Pr
 	if acct == nil {
 		return nil, errors.New("no available account in wallet to do single-sign")
 	}
+	if acct.IsWatchOnly() {
+		return nil, errors.New("watch-only account " + acct.Address + " can not sign")
+	}
 
 	// Sign transaction
 	signature, err := SignBySigner(txn, acct)
@@ -151,7 +154,7 @@ func (cl *Client) signMultiSignTransaction(txn *types.Transaction, program *pg.P
 	var acc *Account
 	for i, hash := range codeHashes {
 		acc = cl.GetAccountByCodeHash(*hash)
-		if acc != nil {
+		if acc != nil && !acc.IsWatchOnly() {
 			signerIndex = i
 			break
 		}
@@ -213,75 +216,164 @@ func NewClient(path string, password []byte, create bool) *Client {
 
 	go client.HandleInterrupt()
 
-	passwordKey := crypto.ToAesKey(password)
 	if create {
 		//create new client
 		client.iv = make([]byte, 16)
 		client.masterKey = make([]byte, 32)
 
 		//generate random number for iv/masterkey
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		for i := 0; i < 16; i++ {
-			client.iv[i] = byte(r.Intn(256))
+		if _, err := rand.Read(client.iv); err != nil {
+			fmt.Println("error: failed to generate iv")
+			return nil
 		}
-		for i := 0; i < 32; i++ {
-			client.masterKey[i] = byte(r.Intn(256))
+		if _, err := rand.Read(client.masterKey); err != nil {
+			fmt.Println("error: failed to generate master key")
+			return nil
 		}
 
 		//new client store (build DB)
 		client.BuildDatabase(path)
 
-		if err := client.SaveStoredData("Version", []byte(KeystoreVersion)); err != nil {
-			fmt.Println("error: failed to save Version")
-			return nil
-		}
-
-		pwdhash := sha256.Sum256(passwordKey)
-		if err := client.SaveStoredData("PasswordHash", pwdhash[:]); err != nil {
-			fmt.Println("error: failed to save PasswordHash")
-			return nil
-		}
 		if err := client.SaveStoredData("IV", client.iv[:]); err != nil {
 			fmt.Println("error: failed to save IV")
 			return nil
 		}
-
-		aesmk, err := crypto.AesEncrypt(client.masterKey[:], passwordKey, client.iv)
-		if err != nil {
-			fmt.Println("error: failed to encrypt the passoword", err.Error())
-			return nil
-		}
-		if err := client.SaveStoredData("MasterKey", aesmk); err != nil {
-			fmt.Println("error: failed to save MasterKey")
+		if err := client.saveMasterKey(password); err != nil {
+			fmt.Println("error: failed to save MasterKey", err.Error())
 			return nil
 		}
 
 	} else {
-		if ok := client.verifyPasswordKey(passwordKey); !ok {
-			return nil
-		}
 		var err error
 		client.iv, err = client.LoadStoredData("IV")
 		if err != nil {
 			fmt.Println("error: failed to load iv")
 			return nil
 		}
-		encryptedMasterKey, err := client.LoadStoredData("MasterKey")
-		if err != nil {
-			fmt.Println("error: failed to load master key")
+		if err := client.loadMasterKey(password); err != nil {
+			fmt.Println("error:", err.Error())
 			return nil
 		}
-		client.masterKey, err = crypto.AesDecrypt(encryptedMasterKey, passwordKey, client.iv)
-		if err != nil {
-			fmt.Println("error: failed to decrypt master key")
+		if err := client.loadSeed(); err != nil {
+			fmt.Println("error:", err.Error())
 			return nil
 		}
 	}
-	common.ClearBytes(passwordKey)
 
 	return client
 }
 
+// loadSeed decrypts the seed of a HD wallet by the master key.
+func (cl *Client) loadSeed() error {
+	encryptedSeed, err := cl.LoadStoredData("Seed")
+	if err != nil {
+		return errors.New("failed to load seed")
+	}
+	if len(encryptedSeed) > 0 {
+		cl.seed, err = crypto.AesDecrypt(encryptedSeed, cl.masterKey, cl.iv)
+		if err != nil {
+			return errors.New("failed to decrypt seed")
+		}
+	}
+	return nil
+}
+
+// Lock clears the master key, the seed and the private keys of the accounts
+// from memory, the client can't sign or save accounts until it's unlocked.
+func (cl *Client) Lock() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	common.ClearBytes(cl.masterKey)
+	common.ClearBytes(cl.seed)
+	cl.masterKey = nil
+	cl.seed = nil
+	for _, ac := range cl.accounts {
+		common.ClearBytes(ac.PrivateKey)
+	}
+	cl.accounts = map[common.Uint160]*Account{}
+}
+
+// Unlock decrypts the master key by the password and loads the accounts
+// again after the client is locked.
+func (cl *Client) Unlock(password []byte) error {
+	if err := cl.loadMasterKey(password); err != nil {
+		return err
+	}
+	if err := cl.loadSeed(); err != nil {
+		cl.Lock()
+		return err
+	}
+	if err := cl.LoadAccounts(); err != nil {
+		cl.Lock()
+		return err
+	}
+	return nil
+}
+
+// Locked returns if the client is locked.
+func (cl *Client) Locked() bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.masterKey == nil
+}
+
+// loadMasterKey decrypts the master key by the password, a keystore of
+// version 1.0.0 is migrated to the current version.
+func (cl *Client) loadMasterKey(password []byte) error {
+	version, err := cl.LoadStoredData("Version")
+	if err != nil {
+		return errors.New("failed to load version")
+	}
+	encryptedMasterKey, err := cl.LoadStoredData("MasterKey")
+	if err != nil {
+		return errors.New("failed to load master key")
+	}
+
+	// the master key is decrypted into a local variable, so a wrong password
+	// doesn't clear the master key of an unlocked client.
+	var masterKey []byte
+	switch string(version) {
+	case KeystoreVersionV1:
+		passwordKey := crypto.ToAesKey(password)
+		defer common.ClearBytes(passwordKey)
+		if ok := cl.verifyPasswordKey(passwordKey); !ok {
+			return ErrPasswordWrong
+		}
+		masterKey, err = crypto.AesDecrypt(encryptedMasterKey, passwordKey, cl.iv)
+		if err != nil {
+			return errors.New("failed to decrypt master key")
+		}
+
+	case KeystoreVersion:
+		params, err := cl.LoadScryptParams()
+		if err != nil || params == nil {
+			return errors.New("failed to load scrypt params")
+		}
+		masterKey, err = decryptMasterKey(encryptedMasterKey, cl.iv,
+			password, params)
+		if err != nil {
+			return err
+		}
+
+	default:
+		return errors.New("unknown keystore version " + string(version))
+	}
+
+	cl.mu.Lock()
+	common.ClearBytes(cl.masterKey)
+	cl.masterKey = masterKey
+	cl.mu.Unlock()
+
+	if string(version) == KeystoreVersionV1 {
+		if err := cl.saveMasterKey(password); err != nil {
+			fmt.Println("warning: failed to migrate keystore to version",
+				KeystoreVersion, err)
+		}
+	}
+	return nil
+}
+
 // CreateAccount create a new Account then save it
 func (cl *Client) CreateAccount() (*Account, error) {
 	account, err := NewAccount()
@@ -333,7 +425,13 @@ func (cl *Client) SaveAccount(ac *Account) error {
 	common.ClearBytes(decryptedPrivateKey)
 
 	// save Account keys to db
-	err = cl.SaveAccountData(ac.ProgramHash.Bytes(), ac.RedeemScript, encryptedPrivateKey)
+	if ac.Path != "" {
+		err = cl.SaveHDAccountData(ac.ProgramHash.Bytes(), ac.RedeemScript,
+			encryptedPrivateKey, ac.Path)
+	} else {
+		err = cl.SaveAccountData(ac.ProgramHash.Bytes(), ac.RedeemScript,
+			encryptedPrivateKey)
+	}
 	if err != nil {
 		return err
 	}
@@ -341,6 +439,27 @@ func (cl *Client) SaveAccount(ac *Account) error {
 	return nil
 }
 
+// SaveWatchAccount saves a watch-only account to memory and db, no private
+// key is saved.
+func (cl *Client) SaveWatchAccount(ac *Account) error {
+	if ac.PrivateKey != nil {
+		return errors.New("watch-only account should not have private key")
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if _, ok := cl.accounts[ac.ProgramHash.ToCodeHash()]; ok {
+		return errors.New("account already exists")
+	}
+	if err := cl.SaveAccountData(ac.ProgramHash.Bytes(), ac.RedeemScript,
+		nil); err != nil {
+		return err
+	}
+	cl.accounts[ac.ProgramHash.ToCodeHash()] = ac
+
+	return nil
+}
+
 func (cl *Client) GetAccounts() []*Account {
 	accounts := make([]*Account, 0, len(cl.accounts))
 	for _, account := range cl.accounts {
@@ -372,7 +491,16 @@ func (cl *Client) LoadAccounts() error {
 			return err
 		}
 		prefixType := contract.GetPrefixType(*programHash)
-		if prefixType == contract.PrefixStandard {
+		if prefixType == contract.PrefixStandard && a.PrivateKeyEncrypted == "" {
+			ac, err := loadWatchAccount(a, *programHash)
+			if err != nil {
+				return err
+			}
+			accounts[ac.ProgramHash.ToCodeHash()] = ac
+			if a.Type == MAINACCOUNT {
+				cl.mainAccount = ac.ProgramHash.ToCodeHash()
+			}
+		} else if prefixType == contract.PrefixStandard {
 			encryptedKeyPair, _ := common.HexStringToBytes(a.PrivateKeyEncrypted)
 			keyPair, err := cl.DecryptPrivateKey(encryptedKeyPair)
 			if err != nil {
@@ -383,6 +511,7 @@ func (cl *Client) LoadAccounts() error {
 			if err != nil {
 				return err
 			}
+			ac.Path = a.Path
 			accounts[ac.ProgramHash.ToCodeHash()] = ac
 			if a.Type == MAINACCOUNT {
 				cl.mainAccount = ac.ProgramHash.ToCodeHash()
@@ -410,6 +539,28 @@ func (cl *Client) LoadAccounts() error {
 	return nil
 }
 
+// loadWatchAccount loads the watch-only standard account, the public key is
+// parsed from the redeem script if the account is not watched by address.
+func loadWatchAccount(a AccountData, programHash common.Uint168) (*Account,
+	error) {
+	rs, err := common.HexStringToBytes(a.RedeemScript)
+	if err != nil {
+		return nil, err
+	}
+	ac := &Account{
+		ProgramHash:  programHash,
+		RedeemScript: rs,
+		Address:      a.Address,
+	}
+	if len(rs) == crypto.PublicKeyScriptLength {
+		ac.PublicKey, err = crypto.DecodePoint(rs[1 : len(rs)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ac, nil
+}
+
 func (cl *Client) EncryptPrivateKey(prikey []byte) ([]byte, error) {
 	enc, err := crypto.AesEncrypt(prikey, cl.masterKey, cl.iv)
 	if err != nil {
@@ -447,11 +598,7 @@ func (cl *Client) verifyPasswordKey(passwordKey []byte) bool {
 	}
 	passwordHash := sha256.Sum256(passwordKey)
 	///ClearBytes(passwordKey, len(passwordKey))
-	if !bytes.Equal(savedPasswordHash, passwordHash[:]) {
-		fmt.Println("error: password wrong")
-		return false
-	}
-	return true
+	return bytes.Equal(savedPasswordHash, passwordHash[:])
 }
 
 func (cl *Client) HandleInterrupt() {
@@ -2,6 +2,9 @@ package account
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
 
 	"github.com/elastos/Elastos.ELA/common"
 	"github.com/elastos/Elastos.ELA/core/contract"
@@ -12,7 +15,7 @@ const (
 	MAINACCOUNT      = "main-account"
 	SUBACCOUNT       = "sub-account"
 	KeystoreFileName = "keystore.dat"
-	KeystoreVersion  = "1.0.0"
+	KeystoreVersion  = "2.0.0"
 
 	MaxSignalQueueLen = 5
 )
@@ -55,3 +58,17 @@ func GetCorssChainSigners(code []byte) ([]*common.Uint160, error) {
 
 	return signers, nil
 }
+
+// writeJSONFile writes the JSON of the value to a temporary file and renames
+// it, so the file will not be broken if the writing is interrupted.
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
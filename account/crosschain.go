@@ -0,0 +1,183 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/contract"
+)
+
+// CrossChainVersion is the version of the cross chain file.
+const CrossChainVersion = "1.0.0"
+
+// SideChain is a side chain known by the wallet.
+type SideChain struct {
+	Name string
+
+	// GenesisHash is the genesis block hash of the side chain in the reversed
+	// hex format as displayed by the side chain node.
+	GenesisHash string
+
+	// GenesisAddress is the cross chain address of the side chain on the main
+	// chain, which receives the deposits to the side chain.
+	GenesisAddress string
+}
+
+// Withdrawal is a side chain transaction withdrawing to the main chain
+// tracked by the wallet.
+type Withdrawal struct {
+	// TxID is the side chain transaction id in the reversed hex format.
+	TxID      string
+	SideChain string `json:",omitempty"`
+
+	// Completed is set once the withdrawal is found on the main chain, the
+	// status is not checked again.
+	Completed bool
+}
+
+// CrossChainData is the content of the cross chain file.
+type CrossChainData struct {
+	Version     string
+	SideChains  []*SideChain
+	Withdrawals []*Withdrawal
+}
+
+// CrossChain keeps the side chains and the tracked withdrawals of the wallet.
+type CrossChain struct {
+	CrossChainData
+	path string
+}
+
+// CrossChainPath returns the path of the cross chain file of the wallet,
+// which is beside the wallet file.
+func CrossChainPath(walletPath string) string {
+	ext := filepath.Ext(walletPath)
+	return strings.TrimSuffix(walletPath, ext) + ".crosschain"
+}
+
+// OpenCrossChain loads the cross chain file, an empty one is returned if the
+// file does not exist.
+func OpenCrossChain(path string) (*CrossChain, error) {
+	cc := &CrossChain{
+		CrossChainData: CrossChainData{Version: CrossChainVersion},
+		path:           path,
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &cc.CrossChainData); err != nil {
+		return nil, errors.New("invalid cross chain file: " + err.Error())
+	}
+	if cc.Version != CrossChainVersion {
+		return nil, errors.New("unsupported cross chain version " + cc.Version)
+	}
+	return cc, nil
+}
+
+// Save writes the cross chain file.
+func (cc *CrossChain) Save() error {
+	return writeJSONFile(cc.path, cc.CrossChainData)
+}
+
+// AddSideChain adds a side chain with the genesis block hash in the reversed
+// hex format.
+func (cc *CrossChain) AddSideChain(name, genesisHash string) (*SideChain, error) {
+	if name == "" {
+		return nil, errors.New("side chain name is empty")
+	}
+	if cc.SideChain(name) != nil {
+		return nil, errors.New("side chain " + name + " already exists")
+	}
+	address, err := CrossChainAddress(genesisHash)
+	if err != nil {
+		return nil, err
+	}
+	if sc := cc.SideChainByAddress(address); sc != nil {
+		return nil, errors.New("genesis hash is used by side chain " + sc.Name)
+	}
+	sc := &SideChain{
+		Name:           name,
+		GenesisHash:    strings.ToLower(genesisHash),
+		GenesisAddress: address,
+	}
+	cc.SideChains = append(cc.SideChains, sc)
+	sort.Slice(cc.SideChains, func(i, j int) bool {
+		return cc.SideChains[i].Name < cc.SideChains[j].Name
+	})
+	return sc, nil
+}
+
+// RemoveSideChain removes the side chain, the tracked withdrawals of the side
+// chain are kept.
+func (cc *CrossChain) RemoveSideChain(name string) error {
+	for i, sc := range cc.SideChains {
+		if sc.Name == name {
+			cc.SideChains = append(cc.SideChains[:i], cc.SideChains[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("side chain " + name + " not found")
+}
+
+// SideChain returns the side chain of the name, or nil if it's not found.
+func (cc *CrossChain) SideChain(name string) *SideChain {
+	for _, sc := range cc.SideChains {
+		if sc.Name == name {
+			return sc
+		}
+	}
+	return nil
+}
+
+// SideChainByAddress returns the side chain of the genesis block address, or
+// nil if it's not found.
+func (cc *CrossChain) SideChainByAddress(address string) *SideChain {
+	for _, sc := range cc.SideChains {
+		if sc.GenesisAddress == address {
+			return sc
+		}
+	}
+	return nil
+}
+
+// TrackWithdrawal adds the side chain transaction to the tracked withdrawals,
+// it returns false if the transaction is already tracked.
+func (cc *CrossChain) TrackWithdrawal(txID, sideChain string) bool {
+	txID = strings.ToLower(txID)
+	for _, w := range cc.Withdrawals {
+		if w.TxID == txID {
+			return false
+		}
+	}
+	cc.Withdrawals = append(cc.Withdrawals, &Withdrawal{
+		TxID:      txID,
+		SideChain: sideChain,
+	})
+	return true
+}
+
+// CrossChainAddress returns the cross chain address on the main chain of the
+// side chain with the genesis block hash in the reversed hex format.
+func CrossChainAddress(genesisHash string) (string, error) {
+	hashBytes, err := common.HexStringToBytes(genesisHash)
+	if err != nil {
+		return "", errors.New("invalid genesis hash: " + err.Error())
+	}
+	hash, err := common.Uint256FromBytes(common.BytesReverse(hashBytes))
+	if err != nil {
+		return "", errors.New("invalid genesis hash: " + err.Error())
+	}
+	programHash := common.ToProgramHash(byte(contract.PrefixCrossChain),
+		contract.CreateCrossChainRedeemScript(*hash))
+	return programHash.ToAddress()
+}
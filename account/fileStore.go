@@ -17,13 +17,16 @@ type AccountData struct {
 	RedeemScript        string
 	PrivateKeyEncrypted string
 	Type                string
+	Path                string `json:",omitempty"`
 }
 
 type FileData struct {
 	Version      string
-	PasswordHash string
+	PasswordHash string `json:",omitempty"`
 	IV           string
 	MasterKey    string
+	Scrypt       *ScryptParams `json:",omitempty"`
+	Seed         string        `json:",omitempty"`
 	Account      []AccountData
 }
 
@@ -65,17 +68,23 @@ func (cs *FileStore) writeDB(data []byte) error {
 	defer cs.Unlock()
 	defer cs.closeDB()
 
+	// write to a temporary file and rename it, so the keystore will not be
+	// broken if the writing is interrupted.
+	tmpPath := cs.path + ".tmp"
 	var err error
-	cs.file, err = os.OpenFile(cs.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	cs.file, err = os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
 	if err != nil {
 		return err
 	}
-
-	if cs.file != nil {
-		cs.file.Write(data)
+	if _, err := cs.file.Write(data); err != nil {
+		return err
+	}
+	if err := cs.file.Sync(); err != nil {
+		return err
 	}
+	cs.closeDB()
 
-	return nil
+	return os.Rename(tmpPath, cs.path)
 }
 
 func (cs *FileStore) closeDB() {
@@ -99,6 +108,18 @@ func (cs *FileStore) BuildDatabase(path string) {
 }
 
 func (cs *FileStore) SaveAccountData(programHash []byte, redeemScript []byte, encryptedPrivateKey []byte) error {
+	return cs.saveAccountData(programHash, redeemScript, encryptedPrivateKey, "")
+}
+
+// SaveHDAccountData saves the account derived from the seed of the wallet
+// with the derivation path.
+func (cs *FileStore) SaveHDAccountData(programHash []byte, redeemScript []byte,
+	encryptedPrivateKey []byte, path string) error {
+	return cs.saveAccountData(programHash, redeemScript, encryptedPrivateKey, path)
+}
+
+func (cs *FileStore) saveAccountData(programHash []byte, redeemScript []byte,
+	encryptedPrivateKey []byte, path string) error {
 	JSONData, err := cs.readDB()
 	if err != nil {
 		return errors.New("error: reading db")
@@ -128,6 +149,7 @@ func (cs *FileStore) SaveAccountData(programHash []byte, redeemScript []byte, en
 		RedeemScript:        common.BytesToHexString(redeemScript),
 		PrivateKeyEncrypted: common.BytesToHexString(encryptedPrivateKey),
 		Type:                accountType,
+		Path:                path,
 	}
 
 	for _, v := range cs.data.Account {
@@ -203,7 +225,8 @@ func (cs *FileStore) SaveStoredData(name string, value []byte) error {
 		cs.data.MasterKey = hexValue
 	case "PasswordHash":
 		cs.data.PasswordHash = hexValue
-
+	case "Seed":
+		cs.data.Seed = hexValue
 	}
 	JSONBlob, err := json.Marshal(cs.data)
 	if err != nil {
@@ -214,6 +237,43 @@ func (cs *FileStore) SaveStoredData(name string, value []byte) error {
 	return nil
 }
 
+// SaveKeyData saves the keystore version and the master key encrypted by the
+// key derived from the password with the scrypt parameters.
+func (cs *FileStore) SaveKeyData(version string, encryptedMasterKey []byte,
+	params *ScryptParams) error {
+	JSONData, err := cs.readDB()
+	if err != nil {
+		return errors.New("error: reading db")
+	}
+	if err := json.Unmarshal(JSONData, &cs.data); err != nil {
+		return errors.New("error: unmarshal db")
+	}
+
+	cs.data.Version = version
+	cs.data.PasswordHash = ""
+	cs.data.MasterKey = common.BytesToHexString(encryptedMasterKey)
+	cs.data.Scrypt = params
+
+	JSONBlob, err := json.Marshal(cs.data)
+	if err != nil {
+		return errors.New("error: marshal db")
+	}
+	return cs.writeDB(JSONBlob)
+}
+
+// LoadScryptParams loads the scrypt parameters of the keystore, it returns nil
+// for the keystores of version 1.0.0.
+func (cs *FileStore) LoadScryptParams() (*ScryptParams, error) {
+	JSONData, err := cs.readDB()
+	if err != nil {
+		return nil, errors.New("error: reading db")
+	}
+	if err := json.Unmarshal(JSONData, &cs.data); err != nil {
+		return nil, errors.New("error: unmarshal db")
+	}
+	return cs.data.Scrypt, nil
+}
+
 func (cs *FileStore) LoadStoredData(name string) ([]byte, error) {
 	JSONData, err := cs.readDB()
 	if err != nil {
@@ -231,6 +291,8 @@ func (cs *FileStore) LoadStoredData(name string) ([]byte, error) {
 		return common.HexStringToBytes(cs.data.MasterKey)
 	case "PasswordHash":
 		return common.HexStringToBytes(cs.data.PasswordHash)
+	case "Seed":
+		return common.HexStringToBytes(cs.data.Seed)
 	}
 
 	return nil, errors.New("can't find the key: " + name)
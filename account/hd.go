@@ -0,0 +1,211 @@
+package account
+
+import (
+	"errors"
+
+	"github.com/elastos/Elastos.ELA/crypto"
+)
+
+const (
+	// Purpose is the purpose of the BIP44 derivation path.
+	Purpose = 44
+
+	// CoinType is the registered coin type of ELA in the BIP44 derivation
+	// path.
+	CoinType = 2305
+
+	// ExternalChain is the chain of the receiving addresses.
+	ExternalChain = 0
+
+	// InternalChain is the chain of the change addresses.
+	InternalChain = 1
+
+	// DefaultGapLimit is the consecutive unused addresses to stop the address
+	// discovery.
+	DefaultGapLimit = 20
+
+	// MnemonicEntropyBits is the entropy bits of a new mnemonic of 12 words.
+	MnemonicEntropyBits = 128
+)
+
+// HDPath returns the BIP44 derivation path of the address index of the chain
+// in the account, which is m/44'/2305'/account'/change/index.
+func HDPath(account, change, index uint32) []uint32 {
+	return []uint32{
+		Purpose + crypto.HardenedKeyStart,
+		CoinType + crypto.HardenedKeyStart,
+		account + crypto.HardenedKeyStart,
+		change,
+		index,
+	}
+}
+
+// NewMnemonic generates a random mnemonic for a new HD wallet.
+func NewMnemonic() (string, error) {
+	entropy, err := crypto.NewEntropy(MnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return crypto.NewMnemonic(entropy)
+}
+
+// NewHDAccount derives the standard account of the path from the seed.
+func NewHDAccount(seed []byte, path []uint32) (*Account, error) {
+	master, err := crypto.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	key, err := master.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	ac, err := NewAccountWithPrivateKey(key.PrivateKey())
+	if err != nil {
+		return nil, err
+	}
+	ac.Path = crypto.FormatDerivationPath(path)
+	return ac, nil
+}
+
+// CreateHD creates a HD wallet from the mnemonic and the optional passphrase,
+// the main account is the first receiving address.
+func CreateHD(path string, password []byte, mnemonic,
+	passphrase string) (*Client, error) {
+	seed, err := crypto.NewSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	client := NewClient(path, password, true)
+	if client == nil {
+		return nil, errors.New("create HD wallet failed")
+	}
+	if err := client.saveSeed(seed); err != nil {
+		return nil, err
+	}
+	account, err := client.DeriveAccount(ExternalChain)
+	if err != nil {
+		return nil, err
+	}
+
+	client.mainAccount = account.ProgramHash.ToCodeHash()
+
+	return client, nil
+}
+
+func (cl *Client) saveSeed(seed []byte) error {
+	encryptedSeed, err := crypto.AesEncrypt(seed, cl.masterKey, cl.iv)
+	if err != nil {
+		return err
+	}
+	if err := cl.SaveStoredData("Seed", encryptedSeed); err != nil {
+		return err
+	}
+	cl.seed = seed
+	return nil
+}
+
+// IsHD returns if the wallet is created from a mnemonic.
+func (cl *Client) IsHD() bool {
+	return cl.seed != nil
+}
+
+// DeriveAccount derives and saves the next address of the chain in the first
+// BIP44 account of the HD wallet.
+func (cl *Client) DeriveAccount(change uint32) (*Account, error) {
+	if !cl.IsHD() {
+		return nil, errors.New("not a HD wallet")
+	}
+
+	for index := cl.nextIndex(change); ; index++ {
+		ac, err := NewHDAccount(cl.seed, HDPath(0, change, index))
+		if err == crypto.ErrInvalidChildKey {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := cl.SaveAccount(ac); err != nil {
+			return nil, err
+		}
+		return ac, nil
+	}
+}
+
+// Discover finds the used addresses of the HD wallet with the gap limit, the
+// addresses of each chain are derived until gapLimit consecutive addresses
+// are unused, and the addresses up to the last used one are saved. The used
+// function reports if an address has been used, it returns the new saved
+// accounts.
+func (cl *Client) Discover(gapLimit uint32,
+	used func(address string) (bool, error)) ([]*Account, error) {
+	if !cl.IsHD() {
+		return nil, errors.New("not a HD wallet")
+	}
+
+	var saved []*Account
+	for _, change := range []uint32{ExternalChain, InternalChain} {
+		var pending []*Account
+		for index, gap := uint32(0), uint32(0); gap < gapLimit; index++ {
+			ac, err := NewHDAccount(cl.seed, HDPath(0, change, index))
+			if err == crypto.ErrInvalidChildKey {
+				continue
+			}
+			if err != nil {
+				return saved, err
+			}
+			pending = append(pending, ac)
+
+			ok, err := used(ac.Address)
+			if err != nil {
+				return saved, err
+			}
+			if !ok {
+				gap++
+				continue
+			}
+			gap = 0
+
+			for _, a := range pending {
+				if cl.GetAccountByCodeHash(a.ProgramHash.ToCodeHash()) != nil {
+					continue
+				}
+				if err := cl.SaveAccount(a); err != nil {
+					return saved, err
+				}
+				saved = append(saved, a)
+			}
+			pending = pending[:0]
+		}
+	}
+	return saved, nil
+}
+
+// nextIndex returns the index after the last derived address of the chain in
+// the first BIP44 account.
+func (cl *Client) nextIndex(change uint32) uint32 {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	prefix := HDPath(0, change, 0)
+	var next uint32
+	for _, ac := range cl.accounts {
+		if ac.Path == "" {
+			continue
+		}
+		path, err := crypto.ParseDerivationPath(ac.Path)
+		if err != nil || len(path) != len(prefix) {
+			continue
+		}
+		matched := true
+		for i := 0; i < len(prefix)-1; i++ {
+			if path[i] != prefix[i] {
+				matched = false
+				break
+			}
+		}
+		if matched && path[len(path)-1] >= next {
+			next = path[len(path)-1] + 1
+		}
+	}
+	return next
+}
@@ -0,0 +1,374 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+)
+
+const (
+	// HistoryVersion is the version of the history file.
+	HistoryVersion = "1.0.0"
+
+	// historyReorgDepth is the count of the latest synced block hashes kept to
+	// detect the reorganization of the chain.
+	historyReorgDepth = 100
+)
+
+// ErrHistoryFork is returned if the block to add is not the child of the last
+// synced block, the history should be rolled back to the fork point.
+var ErrHistoryFork = errors.New("block is not on the synced chain")
+
+// HistoryTx is a transaction sending or receiving ELA of the wallet
+// addresses.
+type HistoryTx struct {
+	TxID      string
+	Height    uint32
+	Timestamp uint32
+	Type      string
+
+	// Amount is the change of the wallet balance, negative if the wallet
+	// spent more than it received.
+	Amount common.Fixed64
+
+	// Fee is the transaction fee if all the inputs are from the wallet,
+	// otherwise it's zero.
+	Fee common.Fixed64
+
+	// Addresses are the wallet addresses sending or receiving ELA.
+	Addresses []string
+
+	// Finalized is set once the block of the transaction is confirmed by
+	// DPoS, the status is not checked again.
+	Finalized bool
+
+	// GenesisAddress is the cross chain address of the side chain which the
+	// transaction deposits to or withdraws from.
+	GenesisAddress string `json:",omitempty"`
+
+	// CrossChainAddresses are the side chain recipients of a deposit.
+	CrossChainAddresses []string `json:",omitempty"`
+
+	// SideChainTxs are the side chain transactions in the reversed hex format
+	// of a withdrawal.
+	SideChainTxs []string `json:",omitempty"`
+}
+
+// HistoryOutput is an output to a wallet address, it's used to find the
+// spending transactions of the wallet.
+type HistoryOutput struct {
+	Address     string
+	Value       common.Fixed64
+	Height      uint32
+	SpentBy     string `json:",omitempty"`
+	SpentHeight uint32 `json:",omitempty"`
+}
+
+// HistoryData is the content of the history file.
+type HistoryData struct {
+	Version string
+
+	// StartHeight is the height to start syncing.
+	StartHeight uint32
+
+	// CreationHeight is the best height of the node when the wallet was
+	// created, no transaction of the wallet is before it. It's 0 if unknown
+	// or the accounts created before it are added to the wallet.
+	CreationHeight uint32 `json:",omitempty"`
+
+	// Height is the last synced height, BlockHashes are the hashes of the
+	// latest synced blocks ending at Height.
+	Height      uint32
+	BlockHashes []string
+
+	Transactions  []*HistoryTx
+	Outputs       map[string]*HistoryOutput
+	TxLabels      map[string]string
+	AddressLabels map[string]string
+}
+
+// History is the local transaction history of the wallet addresses synced
+// from the blocks of the node, with the labels of the transactions and the
+// addresses.
+type History struct {
+	HistoryData
+	path string
+}
+
+// HistoryPath returns the path of the history file of the wallet, which is
+// beside the wallet file.
+func HistoryPath(walletPath string) string {
+	ext := filepath.Ext(walletPath)
+	return strings.TrimSuffix(walletPath, ext) + ".history"
+}
+
+// OpenHistory loads the history file, a new history is returned if the file
+// does not exist.
+func OpenHistory(path string) (*History, error) {
+	h := &History{
+		HistoryData: HistoryData{
+			Version:       HistoryVersion,
+			Outputs:       make(map[string]*HistoryOutput),
+			TxLabels:      make(map[string]string),
+			AddressLabels: make(map[string]string),
+		},
+		path: path,
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &h.HistoryData); err != nil {
+		return nil, errors.New("invalid history file: " + err.Error())
+	}
+	if h.Version != HistoryVersion {
+		return nil, errors.New("unsupported history version " + h.Version)
+	}
+	if h.Outputs == nil {
+		h.Outputs = make(map[string]*HistoryOutput)
+	}
+	if h.TxLabels == nil {
+		h.TxLabels = make(map[string]string)
+	}
+	if h.AddressLabels == nil {
+		h.AddressLabels = make(map[string]string)
+	}
+	return h, nil
+}
+
+// Copy returns a deep copy of the history, the copy is saved to the same
+// file.
+func (h *History) Copy() (*History, error) {
+	data, err := json.Marshal(h.HistoryData)
+	if err != nil {
+		return nil, err
+	}
+	c := &History{path: h.path}
+	if err := json.Unmarshal(data, &c.HistoryData); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save writes the history file.
+func (h *History) Save() error {
+	return writeJSONFile(h.path, h.HistoryData)
+}
+
+// Reset clears the synced transactions to sync again from the start height,
+// the labels are kept.
+func (h *History) Reset(startHeight uint32) {
+	h.StartHeight = startHeight
+	h.Height = 0
+	h.BlockHashes = nil
+	h.Transactions = nil
+	h.Outputs = make(map[string]*HistoryOutput)
+}
+
+// NextHeight returns the height of the next block to sync.
+func (h *History) NextHeight() uint32 {
+	if len(h.BlockHashes) == 0 {
+		return h.StartHeight
+	}
+	return h.Height + 1
+}
+
+// BlockHash returns the hash of the synced block of the height, it returns
+// false if the block is not synced or is too old to be kept.
+func (h *History) BlockHash(height uint32) (string, bool) {
+	if len(h.BlockHashes) == 0 || height > h.Height {
+		return "", false
+	}
+	offset := h.Height - height
+	if offset >= uint32(len(h.BlockHashes)) {
+		return "", false
+	}
+	return h.BlockHashes[len(h.BlockHashes)-1-int(offset)], true
+}
+
+// AddBlock adds the transactions of the block sending or receiving ELA of the
+// addresses, which maps the program hashes to the wallet addresses.
+func (h *History) AddBlock(block *types.Block,
+	addresses map[common.Uint168]string) error {
+	if block.Height != h.NextHeight() {
+		return errors.New("block height " + strconv.Itoa(int(block.Height)) +
+			" is not the next height to sync")
+	}
+	if len(h.BlockHashes) > 0 &&
+		reversedHex(block.Previous) != h.BlockHashes[len(h.BlockHashes)-1] {
+		return ErrHistoryFork
+	}
+
+	for _, txn := range block.Transactions {
+		h.addTransaction(txn, block.Height, block.Timestamp, addresses)
+	}
+
+	h.Height = block.Height
+	h.BlockHashes = append(h.BlockHashes, reversedHex(block.Hash()))
+	if len(h.BlockHashes) > historyReorgDepth {
+		h.BlockHashes = h.BlockHashes[len(h.BlockHashes)-historyReorgDepth:]
+	}
+	return nil
+}
+
+func (h *History) addTransaction(txn *types.Transaction, height,
+	timestamp uint32, addresses map[common.Uint168]string) {
+	txID := reversedHex(txn.Hash())
+	involved := make(map[string]struct{})
+
+	var spent common.Fixed64
+	allInputsOwned := len(txn.Inputs) > 0
+	for _, input := range txn.Inputs {
+		output, ok := h.Outputs[outPointKey(input.Previous)]
+		if !ok || output.SpentBy != "" {
+			allInputsOwned = false
+			continue
+		}
+		output.SpentBy = txID
+		output.SpentHeight = height
+		spent += output.Value
+		involved[output.Address] = struct{}{}
+	}
+
+	var received, outputAmount common.Fixed64
+	for i, output := range txn.Outputs {
+		if !output.AssetID.IsEqual(*SystemAssetID) {
+			continue
+		}
+		outputAmount += output.Value
+		address, ok := addresses[output.ProgramHash]
+		if !ok {
+			continue
+		}
+		h.Outputs[outPointKey(types.OutPoint{
+			TxID: txn.Hash(), Index: uint16(i)})] = &HistoryOutput{
+			Address: address,
+			Value:   output.Value,
+			Height:  height,
+		}
+		received += output.Value
+		involved[address] = struct{}{}
+	}
+
+	if len(involved) == 0 {
+		return
+	}
+	record := &HistoryTx{
+		TxID:      txID,
+		Height:    height,
+		Timestamp: timestamp,
+		Type:      txn.TxType.Name(),
+		Amount:    received - spent,
+		Addresses: make([]string, 0, len(involved)),
+	}
+	if allInputsOwned {
+		record.Fee = spent - outputAmount
+	}
+	for address := range involved {
+		record.Addresses = append(record.Addresses, address)
+	}
+	sort.Strings(record.Addresses)
+
+	switch p := txn.Payload.(type) {
+	case *payload.TransferCrossChainAsset:
+		record.CrossChainAddresses = p.CrossChainAddresses
+		for _, index := range p.OutputIndexes {
+			if index >= uint64(len(txn.Outputs)) {
+				continue
+			}
+			address, err := txn.Outputs[index].ProgramHash.ToAddress()
+			if err == nil {
+				record.GenesisAddress = address
+			}
+			break
+		}
+	case *payload.WithdrawFromSideChain:
+		record.GenesisAddress = p.GenesisBlockAddress
+		for _, hash := range p.SideChainTransactionHashes {
+			record.SideChainTxs = append(record.SideChainTxs, reversedHex(hash))
+		}
+	}
+	h.Transactions = append(h.Transactions, record)
+}
+
+// Rollback removes the transactions after the height, the spent outputs of
+// the removed transactions become unspent.
+func (h *History) Rollback(height uint32) error {
+	if height >= h.Height {
+		return nil
+	}
+	if _, ok := h.BlockHash(height); !ok {
+		return errors.New("the fork point is older than the synced blocks " +
+			"kept, the history must be synced again")
+	}
+
+	transactions := h.Transactions[:0]
+	for _, tx := range h.Transactions {
+		if tx.Height <= height {
+			transactions = append(transactions, tx)
+		}
+	}
+	h.Transactions = transactions
+
+	for key, output := range h.Outputs {
+		if output.Height > height {
+			delete(h.Outputs, key)
+			continue
+		}
+		if output.SpentBy != "" && output.SpentHeight > height {
+			output.SpentBy = ""
+			output.SpentHeight = 0
+		}
+	}
+
+	h.BlockHashes = h.BlockHashes[:len(h.BlockHashes)-int(h.Height-height)]
+	h.Height = height
+	return nil
+}
+
+// SetTxLabel sets the label of the transaction, an empty label removes it.
+func (h *History) SetTxLabel(txID, label string) {
+	if label == "" {
+		delete(h.TxLabels, txID)
+		return
+	}
+	h.TxLabels[txID] = label
+}
+
+// SetAddressLabel sets the label of the address, an empty label removes it.
+func (h *History) SetAddressLabel(address, label string) {
+	if label == "" {
+		delete(h.AddressLabels, address)
+		return
+	}
+	h.AddressLabels[address] = label
+}
+
+// Balance returns the total amount of the unspent outputs of the address, all
+// the wallet addresses are counted if the address is empty.
+func (h *History) Balance(address string) common.Fixed64 {
+	var balance common.Fixed64
+	for _, output := range h.Outputs {
+		if output.SpentBy == "" &&
+			(address == "" || output.Address == address) {
+			balance += output.Value
+		}
+	}
+	return balance
+}
+
+func outPointKey(op types.OutPoint) string {
+	return reversedHex(op.TxID) + ":" + strconv.Itoa(int(op.Index))
+}
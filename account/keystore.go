@@ -0,0 +1,131 @@
+package account
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/crypto"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// KeystoreVersionV1 is the keystore version of which the master key is
+	// encrypted by the double SHA256 of the password.
+	KeystoreVersionV1 = "1.0.0"
+
+	// scryptN, scryptR and scryptP are the scrypt parameters of new
+	// keystores, which cost 256MB memory.
+	scryptN = 1 << 18
+	scryptR = 8
+	scryptP = 1
+
+	// scryptKeyLength is the length of the key derived from the password,
+	// the first half encrypts the master key and the second half is the MAC
+	// key.
+	scryptKeyLength = 64
+
+	// saltLength is the length of the random salt of scrypt.
+	saltLength = 32
+)
+
+// ErrPasswordWrong is returned if the password does not match the keystore.
+var ErrPasswordWrong = errors.New("password wrong")
+
+// ScryptParams is the KDF parameters of a keystore, the MAC is the SHA256 of
+// the MAC key and the encrypted master key to verify the password.
+type ScryptParams struct {
+	N    int
+	R    int
+	P    int
+	Salt string
+	MAC  string
+}
+
+// encryptMasterKey encrypts the master key by the key derived from the
+// password with a new salt.
+func encryptMasterKey(masterKey, iv, password []byte) ([]byte, *ScryptParams,
+	error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	params := &ScryptParams{
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+		Salt: common.BytesToHexString(salt),
+	}
+
+	derivedKey, err := scrypt.Key(password, salt, params.N, params.R,
+		params.P, scryptKeyLength)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer common.ClearBytes(derivedKey)
+
+	encryptedMasterKey, err := crypto.AesEncrypt(masterKey,
+		derivedKey[:scryptKeyLength/2], iv)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.MAC = common.BytesToHexString(
+		keystoreMAC(derivedKey[scryptKeyLength/2:], encryptedMasterKey))
+	return encryptedMasterKey, params, nil
+}
+
+// decryptMasterKey verifies the password by the MAC and decrypts the master
+// key.
+func decryptMasterKey(encryptedMasterKey, iv, password []byte,
+	params *ScryptParams) ([]byte, error) {
+	salt, err := common.HexStringToBytes(params.Salt)
+	if err != nil {
+		return nil, errors.New("invalid keystore salt")
+	}
+	mac, err := common.HexStringToBytes(params.MAC)
+	if err != nil {
+		return nil, errors.New("invalid keystore MAC")
+	}
+
+	derivedKey, err := scrypt.Key(password, salt, params.N, params.R,
+		params.P, scryptKeyLength)
+	if err != nil {
+		return nil, err
+	}
+	defer common.ClearBytes(derivedKey)
+
+	if !hmac.Equal(mac, keystoreMAC(derivedKey[scryptKeyLength/2:],
+		encryptedMasterKey)) {
+		return nil, ErrPasswordWrong
+	}
+	return crypto.AesDecrypt(encryptedMasterKey,
+		derivedKey[:scryptKeyLength/2], iv)
+}
+
+func keystoreMAC(macKey, encryptedMasterKey []byte) []byte {
+	data := make([]byte, 0, len(macKey)+len(encryptedMasterKey))
+	data = append(data, macKey...)
+	data = append(data, encryptedMasterKey...)
+	mac := sha256.Sum256(data)
+	return mac[:]
+}
+
+// saveMasterKey encrypts the master key by the password and saves it in the
+// current keystore version.
+func (cl *Client) saveMasterKey(password []byte) error {
+	encryptedMasterKey, params, err := encryptMasterKey(cl.masterKey, cl.iv,
+		password)
+	if err != nil {
+		return err
+	}
+	return cl.SaveKeyData(KeystoreVersion, encryptedMasterKey, params)
+}
+
+// ChangePassword changes the password of the wallet, the keys encrypted by
+// the master key are not changed.
+func (cl *Client) ChangePassword(password []byte) error {
+	return cl.saveMasterKey(password)
+}
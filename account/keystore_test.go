@@ -0,0 +1,172 @@
+package account
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/crypto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	password    = []byte("password")
+	newPassword = []byte("new password")
+)
+
+func newTestClient(t *testing.T) (*Client, string) {
+	path := filepath.Join(t.TempDir(), KeystoreFileName)
+	client, err := Create(path, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, path
+}
+
+// saveV1MasterKey saves the master key of the client in the keystore of
+// version 1.0.0, which is encrypted by the double SHA256 of the password.
+func saveV1MasterKey(t *testing.T, cl *Client, password []byte) {
+	passwordKey := crypto.ToAesKey(password)
+	passwordHash := sha256.Sum256(passwordKey)
+	encryptedMasterKey, err := crypto.AesEncrypt(cl.masterKey, passwordKey,
+		cl.iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.SaveKeyData(KeystoreVersionV1, encryptedMasterKey,
+		nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.SaveStoredData("PasswordHash", passwordHash[:]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// keyPair is a copy of the key pair of an account, the private keys of the
+// accounts are cleared when the client is locked.
+type keyPair struct {
+	codeHash   common.Uint160
+	privateKey []byte
+	publicKey  crypto.PublicKey
+}
+
+func newKeyPair(ac *Account) *keyPair {
+	return &keyPair{
+		codeHash:   ac.ProgramHash.ToCodeHash(),
+		privateKey: append([]byte{}, ac.PrivKey()...),
+		publicKey:  *ac.PublicKey,
+	}
+}
+
+func assertPrivateKey(t *testing.T, cl *Client, kp *keyPair) {
+	opened := cl.GetAccountByCodeHash(kp.codeHash)
+	if !assert.NotNil(t, opened) {
+		return
+	}
+	assert.True(t, bytes.Equal(kp.privateKey, opened.PrivKey()))
+
+	data := []byte("data to sign")
+	signature, err := crypto.Sign(opened.PrivKey(), data)
+	assert.NoError(t, err)
+	assert.NoError(t, crypto.Verify(kp.publicKey, data, signature))
+}
+
+func TestEncryptMasterKey(t *testing.T) {
+	masterKey := bytes.Repeat([]byte{1}, 32)
+	iv := bytes.Repeat([]byte{2}, 16)
+
+	encrypted, params, err := encryptMasterKey(masterKey, iv, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, scryptN, params.N)
+	assert.False(t, bytes.Equal(masterKey, encrypted))
+
+	decrypted, err := decryptMasterKey(encrypted, iv, password, params)
+	assert.NoError(t, err)
+	assert.Equal(t, masterKey, decrypted)
+
+	_, err = decryptMasterKey(encrypted, iv, newPassword, params)
+	assert.Equal(t, ErrPasswordWrong, err)
+
+	// a new salt is used every time
+	_, other, err := encryptMasterKey(masterKey, iv, password)
+	assert.NoError(t, err)
+	assert.NotEqual(t, params.Salt, other.Salt)
+}
+
+func TestClient_UpgradeKeystore(t *testing.T) {
+	client, path := newTestClient(t)
+	main := newKeyPair(client.GetMainAccount())
+	saveV1MasterKey(t, client, password)
+
+	// the wrong password is rejected by the password hash of version 1.0.0
+	client.Lock()
+	assert.Equal(t, ErrPasswordWrong, client.Unlock(newPassword))
+	version, err := client.LoadStoredData("Version")
+	assert.NoError(t, err)
+	assert.Equal(t, KeystoreVersionV1, string(version))
+
+	// the keystore is rewritten in the current version after opened
+	opened, err := Open(path, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPrivateKey(t, opened, main)
+	version, err = opened.LoadStoredData("Version")
+	assert.NoError(t, err)
+	assert.Equal(t, KeystoreVersion, string(version))
+	passwordHash, err := opened.LoadStoredData("PasswordHash")
+	assert.NoError(t, err)
+	assert.Empty(t, passwordHash)
+	params, err := opened.LoadScryptParams()
+	assert.NoError(t, err)
+	assert.NotNil(t, params)
+
+	// open the upgraded keystore again
+	opened, err = Open(path, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPrivateKey(t, opened, main)
+}
+
+func TestClient_ChangePassword(t *testing.T) {
+	client, path := newTestClient(t)
+	main := newKeyPair(client.GetMainAccount())
+	ac, err := client.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := newKeyPair(ac)
+
+	client.Lock()
+	assert.Equal(t, ErrPasswordWrong, client.Unlock(newPassword))
+	assert.True(t, client.Locked())
+	assert.NoError(t, client.Unlock(password))
+
+	assert.NoError(t, client.ChangePassword(newPassword))
+
+	// the old password fails and the new one works
+	client.Lock()
+	assert.Equal(t, ErrPasswordWrong, client.Unlock(password))
+	assert.NoError(t, client.Unlock(newPassword))
+
+	// a wrong password doesn't lose the keys of an unlocked client
+	assert.Equal(t, ErrPasswordWrong, client.Unlock(password))
+	assert.False(t, client.Locked())
+	assertPrivateKey(t, client, main)
+
+	// the private keys encrypted by the master key are not changed
+	opened, err := Open(path, newPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPrivateKey(t, opened, main)
+	assertPrivateKey(t, opened, other)
+	_, err = Open(path, password)
+	assert.Error(t, err)
+}
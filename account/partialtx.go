@@ -0,0 +1,785 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/elastos/Elastos.ELA/common"
+	pg "github.com/elastos/Elastos.ELA/core/contract/program"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/crypto"
+)
+
+const (
+	// PartialTxVersion is the version of the partially signed transaction
+	// format.
+	PartialTxVersion = 2
+
+	// partialTxVersionV1 is the version of which the signatures are in the
+	// programs of the transaction, it's imported as the current version.
+	partialTxVersionV1 = 1
+)
+
+// PartialProgram is the program of a program hash to be signed. The
+// signatures are collected by the public keys and are put into the parameter
+// of the program when the transaction is finalized.
+type PartialProgram struct {
+	ProgramHash common.Uint168
+
+	// RedeemScript is the redeem script of the program hash, it's nil if
+	// the program hash is watched by address and is set by the signer.
+	RedeemScript []byte
+
+	// Signatures are the collected signatures by the public keys in hex
+	// string.
+	Signatures map[string][]byte
+
+	// Paths are the signer hints of the derivation paths of the public keys
+	// in hex string, so a HD wallet can sign by a key not derived yet.
+	Paths map[string]string
+}
+
+func newPartialProgram(programHash common.Uint168) *PartialProgram {
+	return &PartialProgram{
+		ProgramHash: programHash,
+		Signatures:  make(map[string][]byte),
+		Paths:       make(map[string]string),
+	}
+}
+
+// SetRedeemScript sets the redeem script of the program, which must match the
+// program hash.
+func (pp *PartialProgram) SetRedeemScript(redeemScript []byte) error {
+	if pp.RedeemScript != nil {
+		if !bytes.Equal(pp.RedeemScript, redeemScript) {
+			return errors.New("redeem script mismatch")
+		}
+		return nil
+	}
+	scriptType, err := crypto.GetScriptType(redeemScript)
+	if err != nil {
+		return err
+	}
+	if scriptType != common.STANDARD && scriptType != common.MULTISIG {
+		return errors.New("standard or multi-signature redeem script expected")
+	}
+	if !common.ToCodeHash(redeemScript).IsEqual(pp.ProgramHash.ToCodeHash()) {
+		return errors.New("redeem script does not match the program hash")
+	}
+	pp.RedeemScript = redeemScript
+	return nil
+}
+
+// PublicKeys returns the compressed public keys of the redeem script in
+// order, it's empty if the redeem script is unknown.
+func (pp *PartialProgram) PublicKeys() ([][]byte, error) {
+	if pp.RedeemScript == nil {
+		return nil, nil
+	}
+	scriptType, err := crypto.GetScriptType(pp.RedeemScript)
+	if err != nil {
+		return nil, err
+	}
+	if scriptType == common.STANDARD {
+		return [][]byte{pp.RedeemScript[1 : len(pp.RedeemScript)-1]}, nil
+	}
+	scripts, err := crypto.ParseMultisigScript(pp.RedeemScript)
+	if err != nil {
+		return nil, err
+	}
+	publicKeys := make([][]byte, 0, len(scripts))
+	for _, script := range scripts {
+		publicKeys = append(publicKeys, script[1:])
+	}
+	return publicKeys, nil
+}
+
+// SignStatus returns the count of the collected signatures and the count of
+// the required signatures, a program of unknown redeem script requires one
+// signature at least.
+func (pp *PartialProgram) SignStatus() (haveSign, needSign int) {
+	if pp.RedeemScript == nil {
+		return 0, 1
+	}
+	needSign = 1
+	if m, err := crypto.GetM(pp.RedeemScript); err == nil {
+		needSign = int(m)
+	}
+	return len(pp.Signatures), needSign
+}
+
+// Completed returns if the program has enough signatures.
+func (pp *PartialProgram) Completed() bool {
+	haveSign, needSign := pp.SignStatus()
+	return pp.RedeemScript != nil && haveSign >= needSign
+}
+
+// AddSignature verifies the signature of the data by the public key of the
+// redeem script and adds it.
+func (pp *PartialProgram) AddSignature(publicKey, signature,
+	data []byte) error {
+	if !pp.hasPublicKey(publicKey) {
+		return errors.New("public key is not a signer of the program")
+	}
+	pubKey, err := crypto.DecodePoint(publicKey)
+	if err != nil {
+		return err
+	}
+	if err := crypto.Verify(*pubKey, data, signature); err != nil {
+		return errors.New("invalid signature of " +
+			common.BytesToHexString(publicKey))
+	}
+	pp.Signatures[common.BytesToHexString(publicKey)] = signature
+	return nil
+}
+
+// SetPath sets the derivation path hint of the public key.
+func (pp *PartialProgram) SetPath(publicKey []byte, path string) error {
+	if !pp.hasPublicKey(publicKey) {
+		return errors.New("public key is not a signer of the program")
+	}
+	if _, err := crypto.ParseDerivationPath(path); err != nil {
+		return err
+	}
+	pp.Paths[common.BytesToHexString(publicKey)] = path
+	return nil
+}
+
+func (pp *PartialProgram) hasPublicKey(publicKey []byte) bool {
+	publicKeys, err := pp.PublicKeys()
+	if err != nil {
+		return false
+	}
+	for _, pk := range publicKeys {
+		if bytes.Equal(pk, publicKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// importParameter adds the signatures in the parameter of a program, the
+// signatures are matched to the public keys by verifying.
+func (pp *PartialProgram) importParameter(parameter, data []byte) {
+	publicKeys, _ := pp.PublicKeys()
+	for i := 0; i+crypto.SignatureScriptLength <= len(parameter); i += crypto.SignatureScriptLength {
+		signature := parameter[i+1 : i+crypto.SignatureScriptLength]
+		for _, publicKey := range publicKeys {
+			if pp.AddSignature(publicKey, signature, data) == nil {
+				break
+			}
+		}
+	}
+}
+
+// Program returns the program with the collected signatures in the order of
+// the public keys.
+func (pp *PartialProgram) Program() (*pg.Program, error) {
+	if !pp.Completed() {
+		return nil, errors.New("not enough signatures of " +
+			pp.ProgramHash.String())
+	}
+	publicKeys, err := pp.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	for _, publicKey := range publicKeys {
+		signature, ok := pp.Signatures[common.BytesToHexString(publicKey)]
+		if !ok {
+			continue
+		}
+		buf.WriteByte(byte(len(signature)))
+		buf.Write(signature)
+	}
+	return &pg.Program{
+		Code:      pp.RedeemScript,
+		Parameter: buf.Bytes(),
+	}, nil
+}
+
+// merge merges the redeem script, the signatures and the signer hints of the
+// other program of the same program hash.
+func (pp *PartialProgram) merge(other *PartialProgram, data []byte) error {
+	if other.RedeemScript != nil {
+		if err := pp.SetRedeemScript(other.RedeemScript); err != nil {
+			return err
+		}
+	}
+	for publicKey, signature := range other.Signatures {
+		pk, err := common.HexStringToBytes(publicKey)
+		if err != nil {
+			return err
+		}
+		if err := pp.AddSignature(pk, signature, data); err != nil {
+			return err
+		}
+	}
+	for publicKey, path := range other.Paths {
+		pp.Paths[publicKey] = path
+	}
+	return nil
+}
+
+// PartialTx is a transaction to be signed together with the previous
+// transactions referenced by its inputs and the programs to be signed. The
+// previous transactions are verified by their hashes, so the referenced
+// outputs, the fee and the programs to sign can be trusted on an offline
+// machine without node access. The signatures are collected by public keys
+// in the programs, so the signers can sign in parallel and the partial
+// transactions are combined before finalizing.
+type PartialTx struct {
+	Transaction *types.Transaction
+	Previous    map[common.Uint256]*types.Transaction
+
+	// Programs are the programs to be signed sorted by code hash as the
+	// order of the programs of the transaction.
+	Programs []*PartialProgram
+}
+
+// NewPartialTx creates a partial transaction, the previous transactions
+// must contain all the transactions referenced by the inputs. The redeem
+// scripts and signatures in the programs of the transaction are imported and
+// the programs are removed until the transaction is finalized.
+func NewPartialTx(txn *types.Transaction,
+	previous []*types.Transaction) (*PartialTx, error) {
+	p := &PartialTx{
+		Transaction: txn,
+		Previous:    make(map[common.Uint256]*types.Transaction),
+	}
+	for _, prev := range previous {
+		p.Previous[prev.Hash()] = prev
+	}
+	hashes, err := p.ProgramHashes()
+	if err != nil {
+		return nil, err
+	}
+	for _, hash := range hashes {
+		p.Programs = append(p.Programs, newPartialProgram(hash))
+	}
+
+	data, err := p.signData()
+	if err != nil {
+		return nil, err
+	}
+	for _, program := range txn.Programs {
+		pp := p.program(*common.ToCodeHash(program.Code))
+		if pp == nil {
+			return nil, errors.New("program is not required by the transaction")
+		}
+		if err := pp.SetRedeemScript(program.Code); err != nil {
+			return nil, err
+		}
+		pp.importParameter(program.Parameter, data)
+	}
+	txn.Programs = []*pg.Program{}
+
+	return p, nil
+}
+
+// References returns the outputs referenced by the inputs of the transaction.
+func (p *PartialTx) References() (map[*types.Input]*types.Output, error) {
+	references := make(map[*types.Input]*types.Output)
+	for _, input := range p.Transaction.Inputs {
+		prev, ok := p.Previous[input.Previous.TxID]
+		if !ok {
+			return nil, errors.New("previous transaction " +
+				input.Previous.TxID.String() + " not found")
+		}
+		if int(input.Previous.Index) >= len(prev.Outputs) {
+			return nil, fmt.Errorf("invalid input index %d of previous "+
+				"transaction %s", input.Previous.Index,
+				input.Previous.TxID.String())
+		}
+		references[input] = prev.Outputs[input.Previous.Index]
+	}
+	return references, nil
+}
+
+// ProgramHashes returns the program hashes which should sign the
+// transaction, sorted by code hash as the order of the programs.
+func (p *PartialTx) ProgramHashes() ([]common.Uint168, error) {
+	references, err := p.References()
+	if err != nil {
+		return nil, err
+	}
+	unique := make(map[common.Uint168]struct{})
+	for _, output := range references {
+		unique[output.ProgramHash] = struct{}{}
+	}
+	for _, attribute := range p.Transaction.Attributes {
+		if attribute.Usage == types.Script {
+			hash, err := common.Uint168FromBytes(attribute.Data)
+			if err != nil {
+				return nil, errors.New("invalid script attribute")
+			}
+			unique[*hash] = struct{}{}
+		}
+	}
+
+	hashes := make([]common.Uint168, 0, len(unique))
+	for hash := range unique {
+		hashes = append(hashes, hash)
+	}
+	common.SortProgramHashByCodeHash(hashes)
+	return hashes, nil
+}
+
+// Fee returns the ELA fee of the transaction, which is the ELA of the
+// referenced outputs minus the ELA of the outputs.
+func (p *PartialTx) Fee() (common.Fixed64, error) {
+	references, err := p.References()
+	if err != nil {
+		return 0, err
+	}
+	var fee common.Fixed64
+	for _, output := range references {
+		if output.AssetID.IsEqual(*SystemAssetID) {
+			fee += output.Value
+		}
+	}
+	for _, output := range p.Transaction.Outputs {
+		if output.AssetID.IsEqual(*SystemAssetID) {
+			fee -= output.Value
+		}
+	}
+	return fee, nil
+}
+
+// SignStatus returns the count of the collected signatures and the count of
+// the required signatures of all the programs.
+func (p *PartialTx) SignStatus() (haveSign, needSign int) {
+	for _, pp := range p.Programs {
+		have, need := pp.SignStatus()
+		if have > need {
+			have = need
+		}
+		haveSign += have
+		needSign += need
+	}
+	return haveSign, needSign
+}
+
+// Completed returns if every program of the transaction has enough
+// signatures.
+func (p *PartialTx) Completed() bool {
+	for _, pp := range p.Programs {
+		if !pp.Completed() {
+			return false
+		}
+	}
+	return true
+}
+
+// AddSignerHint sets the derivation path of the public key to the programs
+// which can be signed by it, it returns if any program is found.
+func (p *PartialTx) AddSignerHint(publicKey []byte, path string) bool {
+	var found bool
+	for _, pp := range p.Programs {
+		if pp.SetPath(publicKey, path) == nil {
+			found = true
+		}
+	}
+	return found
+}
+
+// Combine merges the redeem scripts, signatures and signer hints of the
+// other partial transaction of the same transaction.
+func (p *PartialTx) Combine(other *PartialTx) error {
+	if !p.Transaction.Hash().IsEqual(other.Transaction.Hash()) {
+		return errors.New("can not combine different transactions")
+	}
+	if len(p.Programs) != len(other.Programs) {
+		return errors.New("programs count mismatch")
+	}
+	data, err := p.signData()
+	if err != nil {
+		return err
+	}
+	for i, pp := range p.Programs {
+		if !pp.ProgramHash.IsEqual(other.Programs[i].ProgramHash) {
+			return errors.New("program hash mismatch")
+		}
+		if err := pp.merge(other.Programs[i], data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Finalize puts the collected signatures into the programs of the
+// transaction, an error is returned if the signatures are not enough.
+func (p *PartialTx) Finalize() (*types.Transaction, error) {
+	programs := make([]*pg.Program, 0, len(p.Programs))
+	for _, pp := range p.Programs {
+		program, err := pp.Program()
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, program)
+	}
+	p.Transaction.Programs = programs
+	return p.Transaction, nil
+}
+
+// program returns the program of the code hash.
+func (p *PartialTx) program(codeHash common.Uint160) *PartialProgram {
+	for _, pp := range p.Programs {
+		if pp.ProgramHash.ToCodeHash().IsEqual(codeHash) {
+			return pp
+		}
+	}
+	return nil
+}
+
+// signData returns the unsigned transaction data to be signed.
+func (p *PartialTx) signData() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := p.Transaction.SerializeUnsigned(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SignPartialTx signs the programs of the partial transaction with the local
+// accounts. The unknown redeem scripts are set from the local accounts, so a
+// transaction built by a watch-only account of an address can be signed
+// offline, and the keys of the signer hints are derived from the seed of a
+// HD wallet. It returns the count of new signatures.
+func (cl *Client) SignPartialTx(p *PartialTx) (int, error) {
+	data, err := p.signData()
+	if err != nil {
+		return 0, err
+	}
+
+	var signed int
+	for _, pp := range p.Programs {
+		if pp.RedeemScript == nil {
+			acc := cl.GetAccountByCodeHash(pp.ProgramHash.ToCodeHash())
+			if acc == nil || len(acc.RedeemScript) == 0 {
+				continue
+			}
+			if err := pp.SetRedeemScript(acc.RedeemScript); err != nil {
+				return 0, err
+			}
+		}
+
+		publicKeys, err := pp.PublicKeys()
+		if err != nil {
+			return 0, err
+		}
+		for _, publicKey := range publicKeys {
+			if pp.Completed() {
+				break
+			}
+			key := common.BytesToHexString(publicKey)
+			if _, ok := pp.Signatures[key]; ok {
+				continue
+			}
+			acc, err := cl.signerAccount(publicKey, pp.Paths[key])
+			if err != nil {
+				return 0, err
+			}
+			if acc == nil {
+				continue
+			}
+			signature, err := acc.Sign(data)
+			if err != nil {
+				return 0, err
+			}
+			if err := pp.AddSignature(publicKey, signature, data); err != nil {
+				return 0, err
+			}
+			signed++
+		}
+	}
+
+	if signed == 0 {
+		return 0, errors.New("no available account in wallet to sign")
+	}
+	return signed, nil
+}
+
+// signerAccount returns the local account which can sign by the public key,
+// the account is derived by the path hint if it's not found in a HD wallet.
+func (cl *Client) signerAccount(publicKey []byte, path string) (*Account,
+	error) {
+	pubKey, err := crypto.DecodePoint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := cl.GetAccount(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if acc != nil && !acc.IsWatchOnly() {
+		return acc, nil
+	}
+	if path == "" || !cl.IsHD() {
+		return nil, nil
+	}
+
+	indexes, err := crypto.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	acc, err = NewHDAccount(cl.seed, indexes)
+	if err != nil {
+		return nil, err
+	}
+	if !crypto.Equal(acc.PublicKey, pubKey) {
+		return nil, nil
+	}
+	return acc, nil
+}
+
+type partialTxInfo struct {
+	Version     int                  `json:"version"`
+	Transaction string               `json:"transaction"`
+	Previous    []string             `json:"previous"`
+	Programs    []partialProgramInfo `json:"programs"`
+	Inputs      []partialInputInfo   `json:"inputs"`
+	Outputs     []partialOutputInfo  `json:"outputs"`
+	Fee         string               `json:"fee"`
+	Completed   bool                 `json:"completed"`
+}
+
+type partialProgramInfo struct {
+	Address      string              `json:"address"`
+	RedeemScript string              `json:"redeemscript,omitempty"`
+	Required     int                 `json:"required"`
+	Signers      []partialSignerInfo `json:"signers"`
+}
+
+type partialSignerInfo struct {
+	PublicKey string `json:"publickey"`
+	Path      string `json:"path,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+type partialInputInfo struct {
+	TxID    string `json:"txid"`
+	VOut    uint16 `json:"vout"`
+	Address string `json:"address"`
+	AssetID string `json:"assetid"`
+	Value   string `json:"value"`
+}
+
+type partialOutputInfo struct {
+	Address    string `json:"address"`
+	AssetID    string `json:"assetid"`
+	Value      string `json:"value"`
+	OutputLock uint32 `json:"outputlock"`
+}
+
+// MarshalJSON encodes the partial transaction. The inputs, outputs, fee, the
+// required signatures and the sign status are informative for the users and
+// are ignored by UnmarshalJSON.
+func (p *PartialTx) MarshalJSON() ([]byte, error) {
+	references, err := p.References()
+	if err != nil {
+		return nil, err
+	}
+	fee, err := p.Fee()
+	if err != nil {
+		return nil, err
+	}
+	info := partialTxInfo{
+		Version:   PartialTxVersion,
+		Previous:  make([]string, 0, len(p.Previous)),
+		Programs:  make([]partialProgramInfo, 0, len(p.Programs)),
+		Inputs:    make([]partialInputInfo, 0, len(p.Transaction.Inputs)),
+		Outputs:   make([]partialOutputInfo, 0, len(p.Transaction.Outputs)),
+		Fee:       fee.String(),
+		Completed: p.Completed(),
+	}
+
+	buf := new(bytes.Buffer)
+	if err := p.Transaction.Serialize(buf); err != nil {
+		return nil, err
+	}
+	info.Transaction = common.BytesToHexString(buf.Bytes())
+
+	hashes := make([]common.Uint256, 0, len(p.Previous))
+	for hash := range p.Previous {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i].Compare(hashes[j]) < 0
+	})
+	for _, hash := range hashes {
+		buf := new(bytes.Buffer)
+		if err := p.Previous[hash].Serialize(buf); err != nil {
+			return nil, err
+		}
+		info.Previous = append(info.Previous,
+			common.BytesToHexString(buf.Bytes()))
+	}
+
+	for _, pp := range p.Programs {
+		address, err := pp.ProgramHash.ToAddress()
+		if err != nil {
+			return nil, err
+		}
+		_, needSign := pp.SignStatus()
+		programInfo := partialProgramInfo{
+			Address:      address,
+			RedeemScript: common.BytesToHexString(pp.RedeemScript),
+			Required:     needSign,
+			Signers:      make([]partialSignerInfo, 0),
+		}
+		publicKeys, err := pp.PublicKeys()
+		if err != nil {
+			return nil, err
+		}
+		for _, publicKey := range publicKeys {
+			key := common.BytesToHexString(publicKey)
+			programInfo.Signers = append(programInfo.Signers,
+				partialSignerInfo{
+					PublicKey: key,
+					Path:      pp.Paths[key],
+					Signature: common.BytesToHexString(pp.Signatures[key]),
+				})
+		}
+		info.Programs = append(info.Programs, programInfo)
+	}
+
+	for _, input := range p.Transaction.Inputs {
+		output := references[input]
+		address, err := output.ProgramHash.ToAddress()
+		if err != nil {
+			return nil, err
+		}
+		info.Inputs = append(info.Inputs, partialInputInfo{
+			TxID:    reversedHex(input.Previous.TxID),
+			VOut:    input.Previous.Index,
+			Address: address,
+			AssetID: reversedHex(output.AssetID),
+			Value:   output.Value.String(),
+		})
+	}
+	for _, output := range p.Transaction.Outputs {
+		address, err := output.ProgramHash.ToAddress()
+		if err != nil {
+			return nil, err
+		}
+		info.Outputs = append(info.Outputs, partialOutputInfo{
+			Address:    address,
+			AssetID:    reversedHex(output.AssetID),
+			Value:      output.Value.String(),
+			OutputLock: output.OutputLock,
+		})
+	}
+
+	return json.MarshalIndent(info, "", "\t")
+}
+
+// UnmarshalJSON decodes the partial transaction and verifies the previous
+// transactions, the redeem scripts and the signatures.
+func (p *PartialTx) UnmarshalJSON(data []byte) error {
+	var info partialTxInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return err
+	}
+	if info.Version != PartialTxVersion && info.Version != partialTxVersionV1 {
+		return fmt.Errorf("unsupported partial transaction version %d",
+			info.Version)
+	}
+
+	txn, err := decodeTransaction(info.Transaction)
+	if err != nil {
+		return errors.New("invalid transaction: " + err.Error())
+	}
+	previous := make([]*types.Transaction, 0, len(info.Previous))
+	for _, prevHex := range info.Previous {
+		prev, err := decodeTransaction(prevHex)
+		if err != nil {
+			return errors.New("invalid previous transaction: " + err.Error())
+		}
+		previous = append(previous, prev)
+	}
+
+	partialTx, err := NewPartialTx(txn, previous)
+	if err != nil {
+		return err
+	}
+	signData, err := partialTx.signData()
+	if err != nil {
+		return err
+	}
+	for _, programInfo := range info.Programs {
+		programHash, err := common.Uint168FromAddress(programInfo.Address)
+		if err != nil {
+			return err
+		}
+		pp := partialTx.program(programHash.ToCodeHash())
+		if pp == nil || !pp.ProgramHash.IsEqual(*programHash) {
+			return errors.New("program of " + programInfo.Address +
+				" is not required by the transaction")
+		}
+		if err := programInfo.decode(pp, signData); err != nil {
+			return errors.New("invalid program of " + programInfo.Address +
+				": " + err.Error())
+		}
+	}
+
+	*p = *partialTx
+	return nil
+}
+
+// decode sets the redeem script, signatures and signer hints of the program.
+func (info *partialProgramInfo) decode(pp *PartialProgram,
+	data []byte) error {
+	if info.RedeemScript == "" {
+		return nil
+	}
+	redeemScript, err := common.HexStringToBytes(info.RedeemScript)
+	if err != nil {
+		return err
+	}
+	if err := pp.SetRedeemScript(redeemScript); err != nil {
+		return err
+	}
+	for _, signer := range info.Signers {
+		publicKey, err := common.HexStringToBytes(signer.PublicKey)
+		if err != nil {
+			return err
+		}
+		if signer.Signature != "" {
+			signature, err := common.HexStringToBytes(signer.Signature)
+			if err != nil {
+				return err
+			}
+			if err := pp.AddSignature(publicKey, signature, data); err != nil {
+				return err
+			}
+		}
+		if signer.Path != "" {
+			if err := pp.SetPath(publicKey, signer.Path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func decodeTransaction(txHex string) (*types.Transaction, error) {
+	txBytes, err := common.HexStringToBytes(txHex)
+	if err != nil {
+		return nil, err
+	}
+	var txn types.Transaction
+	if err := txn.Deserialize(bytes.NewReader(txBytes)); err != nil {
+		return nil, err
+	}
+	return &txn, nil
+}
+
+// reversedHex returns the hash in the reversed hex string format used by the
+// RPC interfaces.
+func reversedHex(hash common.Uint256) string {
+	return common.BytesToHexString(common.BytesReverse(hash.Bytes()))
+}
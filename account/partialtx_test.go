@@ -0,0 +1,268 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/blockchain"
+	"github.com/elastos/Elastos.ELA/common"
+	pg "github.com/elastos/Elastos.ELA/core/contract/program"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/crypto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// multiSigWallet is a 2 of 3 multi-signature account, every signer holds one
+// key and watches the multi-signature account.
+type multiSigWallet struct {
+	multiSig *Account
+	keys     map[string]*Account
+	signers  map[string]*Client
+	previous *types.Transaction
+}
+
+func newMultiSigWallet(t *testing.T) *multiSigWallet {
+	names := []string{"A", "B", "C"}
+	accounts := make([]*Account, 0, len(names))
+	publicKeys := make([]*crypto.PublicKey, 0, len(names))
+	for range names {
+		ac, err := NewAccount()
+		if err != nil {
+			t.Fatal(err)
+		}
+		accounts = append(accounts, ac)
+		publicKeys = append(publicKeys, ac.PublicKey)
+	}
+	multiSig, err := NewMultiSigAccount(2, publicKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &multiSigWallet{
+		multiSig: multiSig,
+		keys:     make(map[string]*Account),
+		signers:  make(map[string]*Client),
+		previous: &types.Transaction{
+			TxType:  types.TransferAsset,
+			Payload: &payload.TransferAsset{},
+			Outputs: []*types.Output{{
+				AssetID:     *SystemAssetID,
+				Value:       common.Fixed64(100),
+				ProgramHash: multiSig.ProgramHash,
+			}},
+			Attributes: []*types.Attribute{},
+			Programs:   []*pg.Program{},
+		},
+	}
+	for i, name := range names {
+		w.keys[name] = accounts[i]
+		w.signers[name] = &Client{
+			accounts: map[common.Uint160]*Account{
+				accounts[i].ProgramHash.ToCodeHash(): accounts[i],
+				multiSig.ProgramHash.ToCodeHash():    multiSig,
+			},
+		}
+	}
+	return w
+}
+
+// newPartialTx creates a partial transaction spending the output of the
+// multi-signature account, the nonce makes the transactions different.
+func (w *multiSigWallet) newPartialTx(t *testing.T, nonce uint32) *PartialTx {
+	txn := &types.Transaction{
+		TxType:   types.TransferAsset,
+		Payload:  &payload.TransferAsset{},
+		LockTime: nonce,
+		Inputs: []*types.Input{{
+			Previous: *types.NewOutPoint(w.previous.Hash(), 0),
+		}},
+		Outputs: []*types.Output{{
+			AssetID:     *SystemAssetID,
+			Value:       common.Fixed64(90),
+			ProgramHash: w.multiSig.ProgramHash,
+		}},
+		Attributes: []*types.Attribute{},
+		Programs:   []*pg.Program{},
+	}
+	p, err := NewPartialTx(txn, []*types.Transaction{w.previous})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// copyPartialTx copies the partial transaction by the JSON format as it's
+// passed to the other signers.
+func copyPartialTx(t *testing.T, p *PartialTx) *PartialTx {
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var copied PartialTx
+	if err := json.Unmarshal(data, &copied); err != nil {
+		t.Fatal(err)
+	}
+	return &copied
+}
+
+func TestPartialTx_MultiSig(t *testing.T) {
+	w := newMultiSigWallet(t)
+
+	tests := []struct {
+		name string
+
+		// signers sign the separate copies of the partial transaction.
+		signers []string
+
+		// conflict returns a partial transaction to be combined with.
+		conflict func(p *PartialTx) *PartialTx
+
+		combineErr string
+		haveSign   int
+		completed  bool
+	}{
+		{
+			name:     "sign with A",
+			signers:  []string{"A"},
+			haveSign: 1,
+		},
+		{
+			name:      "sign with A and B",
+			signers:   []string{"A", "B"},
+			haveSign:  2,
+			completed: true,
+		},
+		{
+			name:      "sign with B and C",
+			signers:   []string{"B", "C"},
+			haveSign:  2,
+			completed: true,
+		},
+		{
+			name:     "duplicate signatures of A",
+			signers:  []string{"A", "A"},
+			haveSign: 1,
+		},
+		{
+			name:    "conflicting signature of B",
+			signers: []string{"A"},
+			conflict: func(p *PartialTx) *PartialTx {
+				other := copyPartialTx(t, p)
+				publicKeys, _ := other.Programs[0].PublicKeys()
+				key := common.BytesToHexString(publicKeys[1])
+				// B signs a different transaction
+				data, _ := w.newPartialTx(t, 1).signData()
+				signature, _ := w.keys["B"].Sign(data)
+				other.Programs[0].Signatures[key] = signature
+				return other
+			},
+			combineErr: "invalid signature",
+			haveSign:   1,
+		},
+		{
+			name:    "different transaction",
+			signers: []string{"A"},
+			conflict: func(p *PartialTx) *PartialTx {
+				other := w.newPartialTx(t, 1)
+				if _, err := w.signers["B"].SignPartialTx(other); err != nil {
+					t.Fatal(err)
+				}
+				return other
+			},
+			combineErr: "can not combine different transactions",
+			haveSign:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := w.newPartialTx(t, 0)
+			_, needSign := p.SignStatus()
+			assert.Equal(t, 1, needSign)
+
+			// every signer signs a separate copy
+			copies := make([]*PartialTx, 0, len(test.signers))
+			for _, name := range test.signers {
+				copied := copyPartialTx(t, p)
+				signed, err := w.signers[name].SignPartialTx(copied)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, signed)
+				copies = append(copies, copyPartialTx(t, copied))
+			}
+
+			// combine the copies
+			combined := copies[0]
+			for _, copied := range copies[1:] {
+				assert.NoError(t, combined.Combine(copied))
+			}
+			if test.conflict != nil {
+				err := combined.Combine(test.conflict(combined))
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.combineErr)
+				}
+			}
+			haveSign, needSign := combined.Programs[0].SignStatus()
+			assert.Equal(t, test.haveSign, haveSign)
+			assert.Equal(t, 2, needSign)
+			assert.Equal(t, test.completed, combined.Completed())
+
+			// finalize and verify the programs
+			txn, err := combined.Finalize()
+			if !test.completed {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Len(t, txn.Programs, 1)
+			data, err := combined.signData()
+			assert.NoError(t, err)
+			hashes, err := combined.ProgramHashes()
+			assert.NoError(t, err)
+			assert.NoError(t, blockchain.RunPrograms(data, hashes,
+				txn.Programs))
+		})
+	}
+}
+
+func TestPartialTx_ImportPrograms(t *testing.T) {
+	w := newMultiSigWallet(t)
+	p := w.newPartialTx(t, 0)
+	if _, err := w.signers["A"].SignPartialTx(p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.signers["C"].SignPartialTx(p); err != nil {
+		t.Fatal(err)
+	}
+	txn, err := p.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the signatures in the programs of a signed transaction are imported
+	buf := new(bytes.Buffer)
+	if err := txn.Serialize(buf); err != nil {
+		t.Fatal(err)
+	}
+	var imported types.Transaction
+	if err := imported.Deserialize(buf); err != nil {
+		t.Fatal(err)
+	}
+	p, err = NewPartialTx(&imported, []*types.Transaction{w.previous})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, imported.Programs)
+	assert.True(t, p.Completed())
+	haveSign, needSign := p.SignStatus()
+	assert.Equal(t, 2, haveSign)
+	assert.Equal(t, 2, needSign)
+
+	// no more signatures are required
+	_, err = w.signers["B"].SignPartialTx(p)
+	assert.Error(t, err)
+}
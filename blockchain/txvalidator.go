@@ -34,203 +34,299 @@ const (
 	MaxStringLength = 100
 )
 
+// TxCheckError describes the failed check of a transaction.
+type TxCheckError struct {
+	// Check is the name of the failed check.
+	Check string
+
+	// Err is the reason why the check failed.
+	Err error
+}
+
+func (e *TxCheckError) Error() string {
+	return fmt.Sprint("[", e.Check, "], ", e.Err)
+}
+
 // CheckTransactionSanity verifies received single transaction
 func (b *BlockChain) CheckTransactionSanity(blockHeight uint32, txn *Transaction) ErrCode {
+	errCode, err := b.CheckTransactionSanityDetail(blockHeight, txn)
+	if err != nil {
+		log.Warn(err)
+	}
+	return errCode
+}
+
+// CheckTransactionSanityDetail verifies received single transaction, the
+// returned error describes the failed check if the transaction is invalid.
+func (b *BlockChain) CheckTransactionSanityDetail(blockHeight uint32,
+	txn *Transaction) (ErrCode, error) {
 	if err := checkTransactionSize(txn); err != nil {
-		log.Warn("[CheckTransactionSize],", err)
-		return ErrTransactionSize
+		return ErrTransactionSize, &TxCheckError{
+			Check: "CheckTransactionSize",
+			Err:   err,
+		}
 	}
 
 	if err := checkTransactionInput(txn); err != nil {
-		log.Warn("[CheckTransactionInput],", err)
-		return ErrInvalidInput
+		return ErrInvalidInput, &TxCheckError{
+			Check: "CheckTransactionInput",
+			Err:   err,
+		}
 	}
 
 	if err := b.checkTransactionOutput(blockHeight, txn); err != nil {
-		log.Warn("[CheckTransactionOutput],", err)
-		return ErrInvalidOutput
+		return ErrInvalidOutput, &TxCheckError{
+			Check: "CheckTransactionOutput",
+			Err:   err,
+		}
 	}
 
 	if err := checkAssetPrecision(txn); err != nil {
-		log.Warn("[CheckAssetPrecesion],", err)
-		return ErrAssetPrecision
+		return ErrAssetPrecision, &TxCheckError{
+			Check: "CheckAssetPrecesion",
+			Err:   err,
+		}
 	}
 
 	if err := checkAttributeProgram(txn); err != nil {
-		log.Warn("[CheckAttributeProgram],", err)
-		return ErrAttributeProgram
+		return ErrAttributeProgram, &TxCheckError{
+			Check: "CheckAttributeProgram",
+			Err:   err,
+		}
 	}
 
 	if err := checkTransactionPayload(txn); err != nil {
-		log.Warn("[CheckTransactionPayload],", err)
-		return ErrTransactionPayload
+		return ErrTransactionPayload, &TxCheckError{
+			Check: "CheckTransactionPayload",
+			Err:   err,
+		}
 	}
 
 	if err := checkDuplicateSidechainTx(txn); err != nil {
-		log.Warn("[CheckDuplicateSidechainTx],", err)
-		return ErrSidechainTxDuplicate
+		return ErrSidechainTxDuplicate, &TxCheckError{
+			Check: "CheckDuplicateSidechainTx",
+			Err:   err,
+		}
 	}
 
 	// check items above for Coinbase transaction
 	if txn.IsCoinBaseTx() {
-		return Success
+		return Success, nil
 	}
 
-	return Success
+	return Success, nil
 }
 
 // CheckTransactionContext verifies a transaction with history transaction in ledger
 func (b *BlockChain) CheckTransactionContext(blockHeight uint32, txn *Transaction) ErrCode {
+	errCode, err := b.CheckTransactionContextDetail(blockHeight, txn)
+	if err != nil {
+		log.Warn(err)
+	}
+	return errCode
+}
+
+// CheckTransactionContextDetail verifies a transaction with history
+// transaction in ledger, the returned error describes the failed check if the
+// transaction is invalid.
+func (b *BlockChain) CheckTransactionContextDetail(blockHeight uint32,
+	txn *Transaction) (ErrCode, error) {
 	// check if duplicated with transaction in ledger
 	if exist := b.db.IsTxHashDuplicate(txn.Hash()); exist {
-		log.Warn("[CheckTransactionContext] duplicate transaction check failed.")
-		return ErrTransactionDuplicate
+		return ErrTransactionDuplicate, &TxCheckError{
+			Check: "CheckTransactionContext",
+			Err:   errors.New("duplicate transaction check failed"),
+		}
 	}
 
 	switch txn.TxType {
 	case CoinBase:
-		return Success
+		return Success, nil
 
 	case IllegalProposalEvidence:
 		if err := b.checkIllegalProposalsTransaction(txn); err != nil {
-			log.Warn("[CheckIllegalProposalsTransaction],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "CheckIllegalProposalsTransaction",
+				Err:   err,
+			}
 		} else {
-			return Success
+			return Success, nil
 		}
 
 	case IllegalVoteEvidence:
 		if err := b.checkIllegalVotesTransaction(txn); err != nil {
-			log.Warn("[CheckIllegalVotesTransaction],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "CheckIllegalVotesTransaction",
+				Err:   err,
+			}
 		}
-		return Success
+		return Success, nil
 
 	case IllegalBlockEvidence:
 		if err := b.checkIllegalBlocksTransaction(txn); err != nil {
-			log.Warn("[CheckIllegalBlocksTransaction],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "CheckIllegalBlocksTransaction",
+				Err:   err,
+			}
 		}
-		return Success
+		return Success, nil
 
 	case IllegalSidechainEvidence:
 		if err := b.checkSidechainIllegalEvidenceTransaction(txn); err != nil {
-			log.Warn("[CheckSidechainIllegalEvidenceTransaction],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "CheckSidechainIllegalEvidenceTransaction",
+				Err:   err,
+			}
 		}
-		return Success
+		return Success, nil
 
 	case InactiveArbitrators:
 		if err := b.checkInactiveArbitratorsTransaction(txn); err != nil {
-			log.Warn("[CheckInactiveArbitrators],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "CheckInactiveArbitrators",
+				Err:   err,
+			}
 		}
-		return Success
+		return Success, nil
 
 	case UpdateVersion:
 		if err := b.checkUpdateVersionTransaction(txn); err != nil {
-			log.Warn("[checkUpdateVersionTransaction],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "checkUpdateVersionTransaction",
+				Err:   err,
+			}
 		}
-		return Success
+		return Success, nil
 
 	case SideChainPow:
 		arbitrator := DefaultLedger.Arbitrators.GetOnDutyCrossChainArbitrator()
 		if err := CheckSideChainPowConsensus(txn, arbitrator); err != nil {
-			log.Warn("[CheckSideChainPowConsensus],", err)
-			return ErrSideChainPowConsensus
+			return ErrSideChainPowConsensus, &TxCheckError{
+				Check: "CheckSideChainPowConsensus",
+				Err:   err,
+			}
 		}
 		if txn.IsNewSideChainPowTx() {
-			return Success
+			return Success, nil
 		}
 
 	case RegisterProducer:
 		if err := b.checkRegisterProducerTransaction(txn); err != nil {
-			log.Warn("[CheckRegisterProducerTransaction],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "CheckRegisterProducerTransaction",
+				Err:   err,
+			}
 		}
 
 	case CancelProducer:
 		if err := b.checkCancelProducerTransaction(txn); err != nil {
-			log.Warn("[CheckCancelProducerTransaction],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "CheckCancelProducerTransaction",
+				Err:   err,
+			}
 		}
 
 	case UpdateProducer:
 		if err := b.checkUpdateProducerTransaction(txn); err != nil {
-			log.Warn("[CheckUpdateProducerTransaction],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "CheckUpdateProducerTransaction",
+				Err:   err,
+			}
 		}
 
 	case ActivateProducer:
 		if err := b.checkActivateProducerTransaction(txn, blockHeight); err != nil {
-			log.Warn("[CheckActivateProducerTransaction],", err)
-			return ErrTransactionPayload
+			return ErrTransactionPayload, &TxCheckError{
+				Check: "CheckActivateProducerTransaction",
+				Err:   err,
+			}
 		}
-		return Success
+		return Success, nil
 	}
 
 	// check double spent transaction
 	if DefaultLedger.IsDoubleSpend(txn) {
-		log.Warn("[CheckTransactionContext] IsDoubleSpend check failed")
-		return ErrDoubleSpend
+		return ErrDoubleSpend, &TxCheckError{
+			Check: "CheckTransactionContext",
+			Err:   errors.New("IsDoubleSpend check failed"),
+		}
 	}
 
 	references, err := DefaultLedger.Store.GetTxReference(txn)
 	if err != nil {
-		log.Warn("[CheckTransactionContext] get transaction reference failed")
-		return ErrUnknownReferredTx
+		return ErrUnknownReferredTx, &TxCheckError{
+			Check: "CheckTransactionContext",
+			Err:   errors.New("get transaction reference failed"),
+		}
 	}
 
 	if txn.IsWithdrawFromSideChainTx() {
 		if err := b.checkWithdrawFromSideChainTransaction(txn, references); err != nil {
-			log.Warn("[CheckWithdrawFromSideChainTransaction],", err)
-			return ErrSidechainTxDuplicate
+			return ErrSidechainTxDuplicate, &TxCheckError{
+				Check: "CheckWithdrawFromSideChainTransaction",
+				Err:   err,
+			}
 		}
 	}
 
 	if txn.IsTransferCrossChainAssetTx() {
 		if err := b.checkTransferCrossChainAssetTransaction(txn, references); err != nil {
-			log.Warn("[CheckTransferCrossChainAssetTransaction],", err)
-			return ErrInvalidOutput
+			return ErrInvalidOutput, &TxCheckError{
+				Check: "CheckTransferCrossChainAssetTransaction",
+				Err:   err,
+			}
 		}
 	}
 
 	if txn.IsReturnDepositCoin() {
 		if err := b.checkReturnDepositCoinTransaction(txn, references); err != nil {
-			log.Warn("[CheckReturnDepositCoinTransaction],", err)
-			return ErrReturnDepositConsensus
+			return ErrReturnDepositConsensus, &TxCheckError{
+				Check: "CheckReturnDepositCoinTransaction",
+				Err:   err,
+			}
 		}
 	}
 
 	if err := checkTransactionUTXOLock(txn, references); err != nil {
-		log.Warn("[CheckTransactionUTXOLock],", err)
-		return ErrUTXOLocked
+		return ErrUTXOLocked, &TxCheckError{
+			Check: "CheckTransactionUTXOLock",
+			Err:   err,
+		}
 	}
 
 	if err := b.checkTransactionFee(txn, references); err != nil {
-		log.Warn("[CheckTransactionFee],", err)
-		return ErrTransactionBalance
+		return ErrTransactionBalance, &TxCheckError{
+			Check: "CheckTransactionFee",
+			Err:   err,
+		}
 	}
 
 	if err := checkDestructionAddress(references); err != nil {
-		log.Warn("[CheckDestructionAddress], ", err)
-		return ErrInvalidInput
+		return ErrInvalidInput, &TxCheckError{
+			Check: "CheckDestructionAddress",
+			Err:   err,
+		}
 	}
 
 	if err := checkTransactionDepositUTXO(txn, references); err != nil {
-		log.Warn("[CheckTransactionDepositUTXO],", err)
-		return ErrInvalidInput
+		return ErrInvalidInput, &TxCheckError{
+			Check: "CheckTransactionDepositUTXO",
+			Err:   err,
+		}
 	}
 
 	if err := checkTransactionSignature(txn, references); err != nil {
-		log.Warn("[CheckTransactionSignature],", err)
-		return ErrTransactionSignature
+		return ErrTransactionSignature, &TxCheckError{
+			Check: "CheckTransactionSignature",
+			Err:   err,
+		}
 	}
 
 	if err := b.checkInvalidUTXO(txn); err != nil {
-		log.Warn("[CheckTransactionCoinbaseLock]", err)
-		return ErrIneffectiveCoinbase
+		return ErrIneffectiveCoinbase, &TxCheckError{
+			Check: "CheckTransactionCoinbaseLock",
+			Err:   err,
+		}
 	}
 
 	if txn.Version >= TxVersion09 {
@@ -239,12 +335,14 @@ func (b *BlockChain) CheckTransactionContext(blockHeight uint32, txn *Transactio
 			producers = append(producers, b.state.GetPendingCanceledProducers()...)
 		}
 		if err := checkVoteProducerOutputs(txn.Outputs, references, getProducerPublicKeys(producers)); err != nil {
-			log.Warn("[CheckVoteProducerOutputs],", err)
-			return ErrInvalidOutput
+			return ErrInvalidOutput, &TxCheckError{
+				Check: "CheckVoteProducerOutputs",
+				Err:   err,
+			}
 		}
 	}
 
-	return Success
+	return Success, nil
 }
 
 func checkVoteProducerOutputs(outputs []*Output, references map[*Input]*Output, producers [][]byte) error {
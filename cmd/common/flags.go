@@ -28,6 +28,23 @@ var (
 		Name:  "pubkeys, pks",
 		Usage: "public key list of multi signature address, separate public keys with comma `,`",
 	}
+	AccountMnemonicFlag = cli.StringFlag{
+		Name:  "mnemonic",
+		Usage: "the mnemonic words of HD wallet separated by spaces",
+	}
+	AccountPassphraseFlag = cli.StringFlag{
+		Name:  "passphrase",
+		Usage: "the optional passphrase to protect the mnemonic of HD wallet",
+	}
+	AccountGapLimitFlag = cli.UintFlag{
+		Name:  "gaplimit",
+		Usage: "stop discovering addresses after `<number>` consecutive unused addresses",
+		Value: account.DefaultGapLimit,
+	}
+	AccountChangeFlag = cli.BoolFlag{
+		Name:  "change",
+		Usage: "derive a change address instead of a receiving address",
+	}
 
 	// Transaction flags
 	TransactionFromFlag = cli.StringFlag{
@@ -50,6 +67,19 @@ var (
 		Name:  "fee",
 		Usage: "the transfer `<fee>` of the transaction",
 	}
+	TransactionFeeRateFlag = cli.StringFlag{
+		Name:  "feerate",
+		Usage: "the `<fee rate>` in sela per byte to compute the fee by the transaction size",
+	}
+	TransactionStrategyFlag = cli.StringFlag{
+		Name:  "strategy",
+		Usage: "the coin selection `<strategy>`: bnb, largest, oldest or minchange",
+		Value: "bnb",
+	}
+	TransactionInputsFlag = cli.StringFlag{
+		Name:  "inputs",
+		Usage: "the manually selected `<inputs>` in the format of txid:vout, separated by comma",
+	}
 	TransactionLockFlag = cli.StringFlag{
 		Name:  "lock",
 		Usage: "the `<lock time>` to specify when the received asset can be spent",
@@ -60,7 +90,11 @@ var (
 	}
 	TransactionFileFlag = cli.StringFlag{
 		Name:  "file, f",
-		Usage: "the file path to specify a transaction file path with the hex string content to be sign",
+		Usage: "the file path to specify a transaction file path with the hex string content or a partially signed transaction file to be sign",
+	}
+	TransactionOfflineFlag = cli.BoolFlag{
+		Name:  "offline",
+		Usage: "build a partially signed transaction file with the referenced transactions to sign on an offline machine",
 	}
 	TransactionNodePublicKeyFlag = cli.StringFlag{
 		Name:  "nodepublickey",
@@ -71,6 +105,93 @@ var (
 		Usage: "the `<file>` path that holds the list of candidates",
 	}
 
+	// Producer flags
+	ProducerOwnerPublicKeyFlag = cli.StringFlag{
+		Name:  "ownerpublickey",
+		Usage: "the owner `<public key>` of the producer, the default is the public key of the main account",
+	}
+	ProducerNodePublicKeyFlag = cli.StringFlag{
+		Name:  "nodepublickey",
+		Usage: "the node `<public key>` of the producer to sign blocks",
+	}
+	ProducerNickNameFlag = cli.StringFlag{
+		Name:  "nickname",
+		Usage: "the `<nickname>` of the producer",
+	}
+	ProducerUrlFlag = cli.StringFlag{
+		Name:  "url",
+		Usage: "the `<url>` of the producer",
+	}
+	ProducerLocationFlag = cli.Uint64Flag{
+		Name:  "location",
+		Usage: "the `<location>` code of the producer",
+	}
+	ProducerNetAddressFlag = cli.StringFlag{
+		Name:  "netaddress",
+		Usage: "the `<net address>` of the producer node",
+	}
+
+	// History flags
+	HistoryStartHeightFlag = cli.UintFlag{
+		Name:  "startheight",
+		Usage: "the `<height>` to start syncing the history of a new or rescanned wallet",
+	}
+	HistoryRescanFlag = cli.BoolFlag{
+		Name:  "rescan",
+		Usage: "clear the synced history and sync again, the labels are kept",
+	}
+	HistoryAddressFlag = cli.StringFlag{
+		Name:  "address",
+		Usage: "the wallet `<address>`",
+	}
+	HistoryTxIDFlag = cli.StringFlag{
+		Name:  "txid",
+		Usage: "the `<txid>` of the transaction",
+	}
+	HistoryFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "the output `<format>`: table, csv or json",
+		Value: "table",
+	}
+	HistoryExportFlag = cli.StringFlag{
+		Name:  "export",
+		Usage: "export the history to the `<file>` in csv or json format",
+	}
+
+	// Cross chain flags
+	CrossChainSideChainFlag = cli.StringFlag{
+		Name:  "sidechain",
+		Usage: "the `<name>` of the side chain added by the sidechain command, or the genesis block hash of the side chain",
+	}
+	CrossChainFeeFlag = cli.StringFlag{
+		Name:  "crosschainfee",
+		Usage: "the `<fee>` paid to the side chain arbiters, the default is the minimum cross chain transaction fee",
+	}
+	CrossChainTrackFlag = cli.StringFlag{
+		Name:  "track",
+		Usage: "the side chain `<txids>` of the withdrawals to track, separated by comma",
+	}
+
+	// Wallet daemon flags
+	DaemonRPCPortFlag = cli.UintFlag{
+		Name:  "walletrpcport",
+		Usage: "the wallet JSON-RPC server listening port `<number>`",
+		Value: 20340,
+	}
+	DaemonRPCUserFlag = cli.StringFlag{
+		Name:  "walletrpcuser",
+		Usage: "username for the wallet JSON-RPC connections",
+	}
+	DaemonRPCPasswordFlag = cli.StringFlag{
+		Name:  "walletrpcpassword",
+		Usage: "password for the wallet JSON-RPC connections",
+	}
+	DaemonWsPortFlag = cli.UintFlag{
+		Name:  "wsport",
+		Usage: "the websocket `<port>` of the node to receive the notifications",
+		Value: 20335,
+	}
+
 	// RPC flags
 	RPCUserFlag = cli.StringFlag{
 		Name:  "rpcuser",
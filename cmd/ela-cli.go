@@ -10,6 +10,7 @@ import (
 	"github.com/elastos/Elastos.ELA/cmd/mine"
 	"github.com/elastos/Elastos.ELA/cmd/rollback"
 	"github.com/elastos/Elastos.ELA/cmd/script"
+	"github.com/elastos/Elastos.ELA/cmd/signer"
 	"github.com/elastos/Elastos.ELA/cmd/wallet"
 
 	"github.com/urfave/cli"
@@ -45,6 +46,7 @@ func main() {
 		*mine.NewCommand(),
 		*script.NewCommand(),
 		*rollback.NewCommand(),
+		*signer.NewCommand(),
 	}
 
 	//sort.Sort(cli.CommandsByName(app.Commands))
@@ -296,9 +296,10 @@ func dposManagerCheckStatusRunning(L *lua.LState) int {
 func dposManagerSignProposal(L *lua.LState) int {
 	m := checkDposManager(L, 1)
 	p := checkProposal(L, 2)
+	height := uint32(L.OptInt(3, 0))
 
 	result := false
-	if sign, err := m.Account.SignProposal(p); err == nil {
+	if sign, err := m.Account.SignProposal(height, p); err == nil {
 		p.Sign = sign
 		result = true
 	}
@@ -310,9 +311,11 @@ func dposManagerSignProposal(L *lua.LState) int {
 func dposManagerSignVote(L *lua.LState) int {
 	m := checkDposManager(L, 1)
 	v := checkVote(L, 2)
+	height := uint32(L.OptInt(3, 0))
+	viewOffset := uint32(L.OptInt(4, 0))
 
 	result := false
-	if sign, err := m.Account.SignVote(v); err == nil {
+	if sign, err := m.Account.SignVote(height, viewOffset, v); err == nil {
 		v.Sign = sign
 		result = true
 	}
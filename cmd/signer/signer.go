@@ -0,0 +1,91 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/elastos/Elastos.ELA/account"
+	cmdcom "github.com/elastos/Elastos.ELA/cmd/common"
+	"github.com/elastos/Elastos.ELA/dpos/signer"
+	"github.com/elastos/Elastos.ELA/dpos/store"
+	"github.com/elastos/Elastos.ELA/utils/signal"
+
+	"github.com/urfave/cli"
+)
+
+const (
+	// defaultSocket is the default Unix socket path of the signer daemon.
+	defaultSocket = "signer.sock"
+
+	// defaultDataDir is the default directory of the sign journal.
+	defaultDataDir = "elastos/data/signer"
+)
+
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "signer",
+		Usage: "Start the DPoS signer daemon",
+		Description: "With ela-cli signer, you could keep the arbiter keys in " +
+			"an isolated process and sign consensus messages for the node " +
+			"through a Unix socket.",
+		ArgsUsage: "[args]",
+		Flags: []cli.Flag{
+			cmdcom.AccountWalletFlag,
+			cmdcom.AccountPasswordFlag,
+			cli.StringFlag{
+				Name:  "socket",
+				Usage: "the Unix socket `<path>` the signer listens on",
+				Value: defaultSocket,
+			},
+			cli.StringFlag{
+				Name: "datadir",
+				Usage: "the `<path>` of the journal keeps the signed proposals " +
+					"and votes",
+				Value: defaultDataDir,
+			},
+			cli.Uint64Flag{
+				Name: "height",
+				Usage: "the current `<height>` of the chain, the node pushes " +
+					"its best height on each new block after it connected",
+			},
+		},
+		Action: signerAction,
+	}
+}
+
+func signerAction(c *cli.Context) error {
+	walletPath := c.String("wallet")
+	if _, err := os.Stat(walletPath); err != nil {
+		return fmt.Errorf("%s is not found", walletPath)
+	}
+	password, err := cmdcom.GetFlagPassword(c)
+	if err != nil {
+		return err
+	}
+	client, err := account.Open(walletPath, password)
+	if err != nil {
+		return err
+	}
+
+	journal, err := store.NewDposStore(c.String("datadir"))
+	if err != nil {
+		return err
+	}
+	defer journal.Close()
+
+	socket := c.String("socket")
+	server, err := signer.NewServer(&signer.Config{
+		Socket:  socket,
+		Client:  client,
+		Journal: journal,
+		Height:  uint32(c.Uint64("height")),
+	})
+	if err != nil {
+		return err
+	}
+	server.Start()
+	cmdcom.PrintInfoMsg("signer started, listening on %s", socket)
+
+	<-signal.NewInterrupt().C
+	return server.Stop()
+}
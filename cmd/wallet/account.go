@@ -14,6 +14,7 @@ import (
 	"github.com/elastos/Elastos.ELA/crypto"
 	"github.com/elastos/Elastos.ELA/utils"
 
+	"github.com/howeyc/gopass"
 	"github.com/urfave/cli"
 )
 
@@ -29,6 +30,41 @@ var accountCommand = []cli.Command{
 		},
 		Action: createAccount,
 	},
+	{
+		Category: "Account",
+		Name:     "createhd",
+		Usage:    "Create a HD wallet with new mnemonic words",
+		Flags: []cli.Flag{
+			cmdcom.AccountWalletFlag,
+			cmdcom.AccountPasswordFlag,
+			cmdcom.AccountPassphraseFlag,
+		},
+		Action: createHDWallet,
+	},
+	{
+		Category: "Account",
+		Name:     "restore",
+		Usage:    "Restore a HD wallet from mnemonic words and discover the used addresses",
+		Flags: []cli.Flag{
+			cmdcom.AccountWalletFlag,
+			cmdcom.AccountPasswordFlag,
+			cmdcom.AccountMnemonicFlag,
+			cmdcom.AccountPassphraseFlag,
+			cmdcom.AccountGapLimitFlag,
+		},
+		Action: restoreHDWallet,
+	},
+	{
+		Category: "Account",
+		Name:     "derive",
+		Usage:    "Derive the next address of the HD wallet",
+		Flags: []cli.Flag{
+			cmdcom.AccountWalletFlag,
+			cmdcom.AccountPasswordFlag,
+			cmdcom.AccountChangeFlag,
+		},
+		Action: deriveAccount,
+	},
 	{
 		Category: "Account",
 		Name:     "account",
@@ -82,12 +118,16 @@ var accountCommand = []cli.Command{
 		},
 		Action: delAccount,
 	},
-	//{
-	//	Category: "Account",
-	//	Name:     "chpwd",
-	//	Usage:    "Change wallet password",
-	//	Action:   changePassword,
-	//},
+	{
+		Category: "Account",
+		Name:     "chpwd",
+		Usage:    "Change wallet password",
+		Flags: []cli.Flag{
+			cmdcom.AccountWalletFlag,
+			cmdcom.AccountPasswordFlag,
+		},
+		Action: changePassword,
+	},
 	{
 		Category:  "Account",
 		Name:      "import",
@@ -99,6 +139,17 @@ var accountCommand = []cli.Command{
 		},
 		Action: importAccount,
 	},
+	{
+		Category:  "Account",
+		Name:      "watch",
+		Usage:     "Watch an address or a public key without the private key",
+		ArgsUsage: "<address|public key>",
+		Flags: []cli.Flag{
+			cmdcom.AccountWalletFlag,
+			cmdcom.AccountPasswordFlag,
+		},
+		Action: watchAccount,
+	},
 	{
 		Category: "Account",
 		Name:     "export",
@@ -142,10 +193,117 @@ func createAccount(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	saveCreationHeight(walletPath)
+
+	return ShowAccountInfo(client)
+}
+
+func createHDWallet(c *cli.Context) error {
+	walletPath := c.String("wallet")
+	password, err := getNewWalletPassword(c)
+	if err != nil {
+		return err
+	}
+
+	mnemonic, err := account.NewMnemonic()
+	if err != nil {
+		return err
+	}
+	client, err := account.CreateHD(walletPath, password, mnemonic,
+		c.String("passphrase"))
+	if err != nil {
+		return err
+	}
+
+	saveCreationHeight(walletPath)
+
+	fmt.Println("Mnemonic:", mnemonic)
+	fmt.Println("Write down the mnemonic words and keep them safe, " +
+		"they are the only way to restore the wallet.")
+	return ShowAccountInfo(client)
+}
+
+func restoreHDWallet(c *cli.Context) error {
+	walletPath := c.String("wallet")
+	mnemonic := c.String("mnemonic")
+	if mnemonic == "" {
+		fmt.Printf("Mnemonic:")
+		m, err := gopass.GetPasswd()
+		if err != nil {
+			return err
+		}
+		mnemonic = string(m)
+	}
+	if _, err := crypto.MnemonicToEntropy(mnemonic); err != nil {
+		return err
+	}
+	password, err := getNewWalletPassword(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := account.CreateHD(walletPath, password, mnemonic,
+		c.String("passphrase"))
+	if err != nil {
+		return err
+	}
+	accounts, err := client.Discover(uint32(c.Uint("gaplimit")),
+		func(address string) (bool, error) {
+			available, locked, err := getAddressUTXOs(address)
+			if err != nil {
+				return false, err
+			}
+			return len(available) > 0 || len(locked) > 0, nil
+		})
+	if err != nil {
+		fmt.Println("error: discover addresses failed,", err)
+	}
+	fmt.Println("Discovered", len(accounts), "used addresses")
 
 	return ShowAccountInfo(client)
 }
 
+func deriveAccount(c *cli.Context) error {
+	walletPath := c.String("wallet")
+	if exist := utils.FileExisted(walletPath); !exist {
+		fmt.Println(fmt.Sprintf("error: %s is not found.", walletPath))
+		cli.ShowCommandHelpAndExit(c, "derive", 1)
+	}
+	password, err := cmdcom.GetFlagPassword(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := account.Open(walletPath, password)
+	if err != nil {
+		return err
+	}
+	change := uint32(account.ExternalChain)
+	if c.Bool("change") {
+		change = account.InternalChain
+	}
+	acc, err := client.DeriveAccount(change)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-34s %-66s\n", "ADDRESS", "PATH")
+	fmt.Println(strings.Repeat("-", 34), strings.Repeat("-", 66))
+	fmt.Printf("%-34s %-66s\n", acc.Address, acc.Path)
+	fmt.Println(strings.Repeat("-", 34), strings.Repeat("-", 66))
+	return nil
+}
+
+// getNewWalletPassword gets the password of a new wallet from command line or
+// the confirmed user input.
+func getNewWalletPassword(c *cli.Context) ([]byte, error) {
+	password := c.String("password")
+	if password == "" {
+		return utils.GetConfirmedPassword()
+	}
+	return []byte(password), nil
+}
+
 func accountInfo(c *cli.Context) error {
 	walletPath := c.String("wallet")
 	if exist := utils.FileExisted(walletPath); !exist {
@@ -266,7 +424,31 @@ func delAccount(c *cli.Context) error {
 }
 
 func changePassword(c *cli.Context) error {
-	// todo
+	walletPath := c.String("wallet")
+	if exist := utils.FileExisted(walletPath); !exist {
+		fmt.Println(fmt.Sprintf("error: %s is not found.", walletPath))
+		cli.ShowCommandHelpAndExit(c, "chpwd", 1)
+	}
+	password, err := cmdcom.GetFlagPassword(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := account.Open(walletPath, password)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Enter the new password")
+	newPassword, err := utils.GetConfirmedPassword()
+	if err != nil {
+		return err
+	}
+	if err := client.ChangePassword(newPassword); err != nil {
+		return err
+	}
+
+	fmt.Println("Password changed")
 	return nil
 }
 
@@ -320,6 +502,60 @@ func importAccount(c *cli.Context) error {
 	if err := client.SaveAccount(acc); err != nil {
 		return err
 	}
+	clearCreationHeight(walletPath)
+
+	return ShowAccountInfo(client)
+}
+
+func watchAccount(c *cli.Context) error {
+	walletPath := c.String("wallet")
+	if c.NArg() < 1 {
+		cmdcom.PrintErrorMsg("Missing argument. Address or public key expected.")
+		cli.ShowCommandHelpAndExit(c, "watch", 1)
+	}
+	arg := strings.TrimSpace(c.Args().First())
+
+	acc, err := account.NewWatchAccountWithAddress(arg)
+	if err != nil {
+		pkBytes, err := common.HexStringToBytes(arg)
+		if err != nil {
+			return errors.New("invalid address or public key")
+		}
+		pubKey, err := crypto.DecodePoint(pkBytes)
+		if err != nil {
+			return err
+		}
+		acc, err = account.NewWatchAccount(pubKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	var client *account.Client
+	if exist := utils.FileExisted(walletPath); !exist {
+		password, err := getNewWalletPassword(c)
+		if err != nil {
+			return err
+		}
+		client = account.NewClient(walletPath, password, true)
+		if client == nil {
+			return errors.New("create wallet failed")
+		}
+	} else {
+		password, err := cmdcom.GetFlagPassword(c)
+		if err != nil {
+			return err
+		}
+		client, err = account.Open(walletPath, password)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := client.SaveWatchAccount(acc); err != nil {
+		return err
+	}
+	clearCreationHeight(walletPath)
 
 	return ShowAccountInfo(client)
 }
@@ -342,7 +578,7 @@ func exportAccount(c *cli.Context) error {
 	fmt.Println(strings.Repeat("-", 34), strings.Repeat("-", 66))
 	for _, account := range accounts {
 		prefixType := contract.GetPrefixType(account.ProgramHash)
-		if prefixType == contract.PrefixStandard {
+		if prefixType == contract.PrefixStandard && !account.IsWatchOnly() {
 			fmt.Printf("%-34s %-66s\n", account.Address, hex.EncodeToString(account.PrivKey()))
 			fmt.Println(strings.Repeat("-", 34), strings.Repeat("-", 66))
 		}
@@ -400,18 +636,7 @@ func generateCrossChainAddress(c *cli.Context) error {
 		cmdcom.PrintErrorMsg("Missing argument. The side chain genesis block hash expected.")
 		cli.ShowCommandHelpAndExit(c, "crosschainaddress", 1)
 	}
-	hashBytes, err := common.HexStringToBytes(c.Args().First())
-	if err != nil {
-		return err
-	}
-
-	hash, err := common.Uint256FromBytes(common.BytesReverse(hashBytes))
-	if err != nil {
-		return err
-	}
-
-	code := contract.CreateCrossChainRedeemScript(*hash)
-	address, err := common.ToProgramHash(byte(contract.PrefixCrossChain), code).ToAddress()
+	address, err := account.CrossChainAddress(c.Args().First())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,532 @@
+package wallet
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastos/Elastos.ELA/account"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/config"
+	pg "github.com/elastos/Elastos.ELA/core/contract/program"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/outputpayload"
+	"github.com/elastos/Elastos.ELA/crypto"
+
+	"github.com/urfave/cli"
+)
+
+const (
+	// StrategyLargestFirst selects the UTXOs of the largest amount first.
+	StrategyLargestFirst = "largest"
+
+	// StrategyBranchAndBound searches the UTXOs of which the amount matches
+	// the target exactly so no change is needed, the UTXOs are selected by
+	// StrategyLargestFirst if no match is found.
+	StrategyBranchAndBound = "bnb"
+
+	// StrategyOldestFirst selects the UTXOs of the most confirmations first.
+	StrategyOldestFirst = "oldest"
+
+	// StrategyMinimizeChange selects the UTXOs of which the change is the
+	// smallest.
+	StrategyMinimizeChange = "minchange"
+
+	// bnbMaxTries is the maximum tries of the branch and bound search.
+	bnbMaxTries = 100000
+)
+
+var (
+	errInsufficientFunds = errors.New("[Wallet], Available token is not enough")
+	errNoExactMatch      = errors.New("no exact match of the UTXOs")
+)
+
+// coinSelector selects the UTXOs of which the effective amount covers the
+// target, the cost of change is the fee of a change output.
+type coinSelector func(utxos []*utxo, target,
+	costOfChange common.Fixed64) ([]*utxo, error)
+
+var coinSelectors = map[string]coinSelector{
+	StrategyLargestFirst:   selectLargestFirst,
+	StrategyBranchAndBound: selectBranchAndBoundOrLargest,
+	StrategyOldestFirst:    selectOldestFirst,
+	StrategyMinimizeChange: selectMinimizeChange,
+}
+
+// utxo is a spendable output of the sender, the effective amount is the
+// amount minus the fee of spending it.
+type utxo struct {
+	OutPoint      types.OutPoint
+	Amount        common.Fixed64
+	OutputLock    uint32
+	Confirmations uint32
+
+	effective common.Fixed64
+}
+
+// selectOptions is the options to select the inputs and compute the fee.
+type selectOptions struct {
+	// Fee is the fixed fee of the transaction, it's nil if the fee is
+	// computed by the fee rate.
+	Fee *common.Fixed64
+
+	// FeeRate is the fee in sela per byte of the transaction size.
+	FeeRate common.Fixed64
+
+	// MinFee is the minimum fee required by the transaction type, such as
+	// the cross chain transaction fee.
+	MinFee common.Fixed64
+
+	Strategy string
+
+	// Inputs are the manually selected inputs, the strategy is ignored if
+	// they are specified.
+	Inputs []types.OutPoint
+}
+
+func parseSelectOptions(c *cli.Context) (*selectOptions, error) {
+	opts := &selectOptions{Strategy: c.String("strategy")}
+	if opts.Strategy == "" {
+		opts.Strategy = StrategyBranchAndBound
+	}
+	if _, ok := coinSelectors[opts.Strategy]; !ok {
+		return nil, errors.New("invalid coin selection strategy: " +
+			opts.Strategy)
+	}
+
+	feeStr := c.String("fee")
+	feeRateStr := c.String("feerate")
+	switch {
+	case feeStr != "" && feeRateStr != "":
+		return nil, errors.New("'--fee' cannot be specified when specify '--feerate' option")
+	case feeStr != "":
+		fee, err := common.StringToFixed64(feeStr)
+		if err != nil {
+			return nil, errors.New("invalid transaction fee")
+		}
+		opts.Fee = fee
+	case feeRateStr != "":
+		feeRate, err := strconv.ParseUint(feeRateStr, 10, 32)
+		if err != nil || feeRate == 0 {
+			return nil, errors.New("invalid transaction fee rate")
+		}
+		opts.FeeRate = common.Fixed64(feeRate)
+	default:
+		return nil, errors.New("use --fee or --feerate to specify transaction fee")
+	}
+
+	inputsStr := strings.TrimSpace(c.String("inputs"))
+	if inputsStr == "" {
+		return opts, nil
+	}
+	if c.IsSet("strategy") {
+		return nil, errors.New("'--strategy' cannot be specified when specify '--inputs' option")
+	}
+	for _, s := range strings.Split(inputsStr, ",") {
+		outPoint, err := parseOutPoint(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		opts.Inputs = append(opts.Inputs, *outPoint)
+	}
+	return opts, nil
+}
+
+// parseOutPoint parses the input in the format of txid:vout.
+func parseOutPoint(s string) (*types.OutPoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, errors.New("invalid input " + s + ", txid:vout expected")
+	}
+	txID, err := parseTxID(parts[0])
+	if err != nil {
+		return nil, errors.New("invalid input txid: " + parts[0])
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 16)
+	if err != nil {
+		return nil, errors.New("invalid input vout: " + parts[1])
+	}
+	return &types.OutPoint{TxID: *txID, Index: uint16(index)}, nil
+}
+
+// parseTxID parses the transaction hash in reversed hex string.
+func parseTxID(s string) (*common.Uint256, error) {
+	txIDBytes, err := common.HexStringToBytes(s)
+	if err != nil {
+		return nil, err
+	}
+	return common.Uint256FromBytes(common.BytesReverse(txIDBytes))
+}
+
+// getSpendableUTXOs gets the UTXOs of the address which can be spent in the
+// next block, the immature coinbase outputs and the outputs locked after
+// the best height are excluded.
+func getSpendableUTXOs(address string) ([]*utxo, uint32, error) {
+	bestHeight, err := getCurrentHeight()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	availableUTXOs, _, err := getAddressUTXOs(address)
+	if err != nil {
+		return nil, 0, err
+	}
+	utxos := make([]*utxo, 0, len(availableUTXOs))
+	for _, u := range availableUTXOs {
+		if u.OutputLock > bestHeight {
+			continue
+		}
+		txID, err := parseTxID(u.TxID)
+		if err != nil {
+			return nil, 0, err
+		}
+		amount, err := common.StringToFixed64(u.Amount)
+		if err != nil {
+			return nil, 0, err
+		}
+		utxos = append(utxos, &utxo{
+			OutPoint:      types.OutPoint{TxID: *txID, Index: uint16(u.VOut)},
+			Amount:        *amount,
+			OutputLock:    u.OutputLock,
+			Confirmations: u.Confirmations,
+		})
+	}
+	return utxos, bestHeight, nil
+}
+
+// fundTransaction selects the inputs of the sender to pay the outputs and the
+// fee of the transaction and appends the change output to the sender. The
+// fee is computed from the estimated size of the signed transaction if it's
+// not fixed.
+func fundTransaction(txn *types.Transaction, sender *account.AccountData,
+	opts *selectOptions) error {
+	utxos, bestHeight, err := getSpendableUTXOs(sender.Address)
+	if err != nil {
+		return err
+	}
+	return fundTransactionFrom(txn, sender, opts, utxos, bestHeight)
+}
+
+// fundTransactionFrom funds the transaction like fundTransaction from the
+// given spendable UTXOs of the sender at the best height.
+func fundTransactionFrom(txn *types.Transaction, sender *account.AccountData,
+	opts *selectOptions, utxos []*utxo, bestHeight uint32) error {
+	senderHash, err := common.Uint168FromAddress(sender.Address)
+	if err != nil {
+		return err
+	}
+	change := &types.Output{
+		AssetID:     *account.SystemAssetID,
+		OutputLock:  uint32(0),
+		ProgramHash: *senderHash,
+		Type:        types.OTNone,
+		Payload:     &outputpayload.DefaultOutput{},
+	}
+
+	var outputAmount common.Fixed64
+	for _, output := range txn.Outputs {
+		outputAmount += output.Value
+	}
+
+	// the fee of the transaction without inputs, the fee of an input and
+	// the cost of a change output.
+	var baseFee, inputFee, costOfChange common.Fixed64
+	if opts.Fee != nil {
+		if *opts.Fee < opts.MinFee {
+			return errors.New("transaction fee is less than the minimum " +
+				"fee " + opts.MinFee.String())
+		}
+		baseFee = *opts.Fee
+	} else {
+		baseFee, inputFee, costOfChange, err = estimateFees(txn, sender,
+			change, opts.FeeRate)
+		if err != nil {
+			return err
+		}
+		if baseFee < opts.MinFee {
+			baseFee = opts.MinFee
+		}
+	}
+	target := outputAmount + baseFee
+	for _, u := range utxos {
+		u.effective = u.Amount - inputFee
+	}
+
+	var selected []*utxo
+	if len(opts.Inputs) > 0 {
+		selected, err = findUTXOs(utxos, opts.Inputs)
+		if err != nil {
+			return err
+		}
+	} else {
+		candidates := make([]*utxo, 0, len(utxos))
+		for _, u := range utxos {
+			if u.effective > 0 {
+				candidates = append(candidates, u)
+			}
+		}
+		selected, err = coinSelectors[opts.Strategy](candidates, target,
+			costOfChange)
+		if err != nil {
+			return err
+		}
+	}
+
+	var total common.Fixed64
+	txn.Inputs = make([]*types.Input, 0, len(selected))
+	for _, u := range selected {
+		total += u.effective
+		input := &types.Input{
+			Previous: u.OutPoint,
+			Sequence: math.MaxUint32,
+		}
+		// the locked outputs can be spent by the transactions of which the
+		// lock time is not less than the output lock.
+		if u.OutputLock > 0 {
+			input.Sequence = math.MaxUint32 - 1
+			txn.LockTime = bestHeight
+		}
+		txn.Inputs = append(txn.Inputs, input)
+	}
+	if total < target {
+		return errInsufficientFunds
+	}
+	if excess := total - target; excess > costOfChange {
+		change.Value = excess - costOfChange
+		txn.Outputs = append(txn.Outputs, change)
+	}
+	return nil
+}
+
+// estimateFees estimates the fees of the transaction by the size, the
+// programs are estimated by the redeem script of the sender with the
+// required signatures, and the fee of the transaction is not less than the
+// minimum transaction fee.
+func estimateFees(txn *types.Transaction, sender *account.AccountData,
+	change *types.Output, feeRate common.Fixed64) (baseFee, inputFee,
+	costOfChange common.Fixed64, err error) {
+	redeemScript, err := common.HexStringToBytes(sender.RedeemScript)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if len(redeemScript) == 0 {
+		redeemScript = make([]byte, crypto.PublicKeyScriptLength)
+	}
+	signatures := 1
+	if m, err := crypto.GetM(redeemScript); err == nil {
+		signatures = int(m)
+	}
+
+	programs := txn.Programs
+	txn.Programs = []*pg.Program{{
+		Code:      redeemScript,
+		Parameter: make([]byte, signatures*crypto.SignatureScriptLength),
+	}}
+	size := txn.GetSize()
+	txn.Programs = programs
+	if size == types.InvalidTransactionSize {
+		return 0, 0, 0, errors.New("invalid transaction size")
+	}
+
+	var inputSize common.Fixed64 = common.UINT256SIZE + 2 + 4
+	outputSize := common.Fixed64(types.InvalidTransactionSize)
+	if size, err := serializedSize(func(w *sizeCounter) error {
+		return change.Serialize(w, txn.Version)
+	}); err == nil {
+		outputSize = common.Fixed64(size)
+	}
+
+	baseFee = common.Fixed64(size) * feeRate
+	if baseFee < config.DefaultParams.MinTransactionFee {
+		baseFee = config.DefaultParams.MinTransactionFee
+	}
+	return baseFee, inputSize * feeRate, outputSize * feeRate, nil
+}
+
+// sizeCounter is a writer counting the written bytes.
+type sizeCounter struct {
+	size int
+}
+
+func (w *sizeCounter) Write(p []byte) (int, error) {
+	w.size += len(p)
+	return len(p), nil
+}
+
+func serializedSize(serialize func(w *sizeCounter) error) (int, error) {
+	w := &sizeCounter{}
+	if err := serialize(w); err != nil {
+		return 0, err
+	}
+	return w.size, nil
+}
+
+// findUTXOs returns the UTXOs of the manually selected inputs.
+func findUTXOs(utxos []*utxo, inputs []types.OutPoint) ([]*utxo, error) {
+	selected := make([]*utxo, 0, len(inputs))
+	for _, input := range inputs {
+		var found *utxo
+		for _, u := range utxos {
+			if u.OutPoint.IsEqual(input) {
+				found = u
+				break
+			}
+		}
+		if found == nil {
+			return nil, errors.New("input " + common.BytesToHexString(
+				common.BytesReverse(input.TxID.Bytes())) + ":" +
+				strconv.Itoa(int(input.Index)) +
+				" is not a spendable UTXO of the sender")
+		}
+		for _, u := range selected {
+			if u == found {
+				return nil, errors.New("duplicated input")
+			}
+		}
+		selected = append(selected, found)
+	}
+	return selected, nil
+}
+
+// accumulate selects the UTXOs in order until the target is covered.
+func accumulate(utxos []*utxo, target common.Fixed64) ([]*utxo, error) {
+	var total common.Fixed64
+	for i, u := range utxos {
+		total += u.effective
+		if total >= target {
+			return utxos[:i+1], nil
+		}
+	}
+	return nil, errInsufficientFunds
+}
+
+func sortByAmount(utxos []*utxo) []*utxo {
+	sorted := make([]*utxo, len(utxos))
+	copy(sorted, utxos)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].effective > sorted[j].effective
+	})
+	return sorted
+}
+
+func selectLargestFirst(utxos []*utxo, target,
+	costOfChange common.Fixed64) ([]*utxo, error) {
+	return accumulate(sortByAmount(utxos), target)
+}
+
+func selectOldestFirst(utxos []*utxo, target,
+	costOfChange common.Fixed64) ([]*utxo, error) {
+	sorted := sortByAmount(utxos)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Confirmations > sorted[j].Confirmations
+	})
+	return accumulate(sorted, target)
+}
+
+// selectBranchAndBound searches the UTXOs of which the effective amount is
+// between the target and the target plus the cost of change by depth first,
+// the match of the least excess is returned.
+func selectBranchAndBound(utxos []*utxo, target,
+	costOfChange common.Fixed64) ([]*utxo, error) {
+	sorted := sortByAmount(utxos)
+	remaining := make([]common.Fixed64, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + sorted[i].effective
+	}
+	if remaining[0] < target {
+		return nil, errInsufficientFunds
+	}
+
+	var best []*utxo
+	bestExcess := common.Fixed64(math.MaxInt64)
+	selected := make([]*utxo, 0, len(sorted))
+	tries := 0
+	var search func(i int, total common.Fixed64)
+	search = func(i int, total common.Fixed64) {
+		if tries >= bnbMaxTries || bestExcess == 0 {
+			return
+		}
+		tries++
+		if total > target+costOfChange {
+			return
+		}
+		if total >= target {
+			if total-target < bestExcess {
+				bestExcess = total - target
+				best = append(best[:0], selected...)
+			}
+			return
+		}
+		if i == len(sorted) || total+remaining[i] < target {
+			return
+		}
+
+		selected = append(selected, sorted[i])
+		search(i+1, total+sorted[i].effective)
+		selected = selected[:len(selected)-1]
+
+		// skip the UTXOs of the same amount which lead to the same results.
+		j := i + 1
+		for j < len(sorted) && sorted[j].effective == sorted[i].effective {
+			j++
+		}
+		search(j, total)
+	}
+	search(0, 0)
+
+	if best == nil {
+		return nil, errNoExactMatch
+	}
+	return best, nil
+}
+
+func selectBranchAndBoundOrLargest(utxos []*utxo, target,
+	costOfChange common.Fixed64) ([]*utxo, error) {
+	selected, err := selectBranchAndBound(utxos, target, costOfChange)
+	if err == errNoExactMatch {
+		return selectLargestFirst(utxos, target, costOfChange)
+	}
+	return selected, err
+}
+
+// selectMinimizeChange selects the exact match if found, otherwise the
+// smallest UTXO covering the target or the largest first UTXOs without the
+// unnecessary small ones, whichever has the less change.
+func selectMinimizeChange(utxos []*utxo, target,
+	costOfChange common.Fixed64) ([]*utxo, error) {
+	selected, err := selectBranchAndBound(utxos, target, costOfChange)
+	if err != errNoExactMatch {
+		return selected, err
+	}
+
+	sorted := sortByAmount(utxos)
+	largest, err := accumulate(sorted, target)
+	if err != nil {
+		return nil, err
+	}
+	var total common.Fixed64
+	for _, u := range largest {
+		total += u.effective
+	}
+	pruned := make([]*utxo, 0, len(largest))
+	for i := len(largest) - 1; i >= 0; i-- {
+		if total-largest[i].effective >= target {
+			total -= largest[i].effective
+			continue
+		}
+		pruned = append(pruned, largest[i])
+	}
+	excess := total - target
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if sorted[i].effective >= target {
+			if sorted[i].effective-target < excess {
+				return []*utxo{sorted[i]}, nil
+			}
+			break
+		}
+	}
+	return pruned, nil
+}
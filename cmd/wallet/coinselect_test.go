@@ -0,0 +1,305 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/account"
+	cmdcom "github.com/elastos/Elastos.ELA/cmd/common"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/config"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/servers"
+	htp "github.com/elastos/Elastos.ELA/utils/http"
+	"github.com/elastos/Elastos.ELA/utils/http/jsonrpc"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+)
+
+// rpcHandler handles a JSON-RPC method of the test node.
+type rpcHandler func(params htp.Params) (interface{}, error)
+
+// newTestNode starts a JSON-RPC server as the node called by the wallet
+// commands, the methods not handled return an error.
+func newTestNode(t *testing.T, handlers map[string]rpcHandler) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+		var req struct {
+			Method string     `json:"method"`
+			Params htp.Params `json:"params"`
+		}
+		resp := jsonrpc.Response{Version: "2.0"}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp.Error = htp.NewError(jsonrpc.ParseError, err.Error())
+		} else if handler, ok := handlers[req.Method]; !ok {
+			resp.Error = htp.NewError(jsonrpc.MethodNotFound, req.Method)
+		} else if result, err := handler(req.Params); err != nil {
+			resp.Error = htp.NewError(jsonrpc.InternalError, err.Error())
+		} else {
+			resp.Result = result
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	setRPCPort(port)
+	t.Cleanup(func() { setRPCPort("20336") })
+}
+
+func setRPCPort(port string) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.String("rpcport", port, "")
+	cmdcom.SetRpcConfig(cli.NewContext(nil, set, nil))
+}
+
+func newTestSender(t *testing.T) (*account.Account, *account.AccountData) {
+	ac, err := account.NewAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ac, &account.AccountData{
+		Address:      ac.Address,
+		ProgramHash:  common.BytesToHexString(ac.ProgramHash.Bytes()),
+		RedeemScript: common.BytesToHexString(ac.RedeemScript),
+	}
+}
+
+// newTestUTXO creates a UTXO of which the effective amount is the amount.
+func newTestUTXO(n byte, amount common.Fixed64, confirmations uint32) *utxo {
+	return &utxo{
+		OutPoint:      types.OutPoint{TxID: common.Uint256{n}},
+		Amount:        amount,
+		Confirmations: confirmations,
+		effective:     amount,
+	}
+}
+
+// testTxID returns the reversed hex string of the transaction hash of which
+// the first byte is n.
+func testTxID(n byte) string {
+	txID := common.Uint256{n}
+	return common.BytesToHexString(common.BytesReverse(txID.Bytes()))
+}
+
+func TestCoinSelectors(t *testing.T) {
+	utxos := []*utxo{
+		newTestUTXO(1, 50, 10),
+		newTestUTXO(2, 30, 100),
+		newTestUTXO(3, 20, 5),
+		newTestUTXO(4, 7, 50),
+		newTestUTXO(5, 3, 200),
+	}
+
+	tests := []struct {
+		strategy string
+		target   common.Fixed64
+		want     []byte
+		err      error
+	}{
+		// 30 + 7 matches the target exactly
+		{StrategyLargestFirst, 37, []byte{1}, nil},
+		{StrategyBranchAndBound, 37, []byte{2, 4}, nil},
+		{StrategyOldestFirst, 37, []byte{5, 2, 4}, nil},
+		{StrategyMinimizeChange, 37, []byte{2, 4}, nil},
+
+		// no exact match, 30 is the smallest one covering the target
+		{StrategyLargestFirst, 25, []byte{1}, nil},
+		{StrategyBranchAndBound, 25, []byte{1}, nil},
+		{StrategyOldestFirst, 25, []byte{5, 2}, nil},
+		{StrategyMinimizeChange, 25, []byte{2}, nil},
+
+		// all the UTXOs are needed
+		{StrategyLargestFirst, 110, []byte{1, 2, 3, 4, 5}, nil},
+		{StrategyBranchAndBound, 110, []byte{1, 2, 3, 4, 5}, nil},
+		{StrategyOldestFirst, 110, []byte{5, 2, 4, 1, 3}, nil},
+		{StrategyMinimizeChange, 110, []byte{1, 2, 3, 4, 5}, nil},
+
+		{StrategyLargestFirst, 111, nil, errInsufficientFunds},
+		{StrategyBranchAndBound, 111, nil, errInsufficientFunds},
+		{StrategyOldestFirst, 111, nil, errInsufficientFunds},
+		{StrategyMinimizeChange, 111, nil, errInsufficientFunds},
+	}
+
+	for _, test := range tests {
+		selected, err := coinSelectors[test.strategy](utxos, test.target, 1)
+		assert.Equal(t, test.err, err, "%s %s", test.strategy, test.target)
+		var got []byte
+		for _, u := range selected {
+			got = append(got, u.OutPoint.TxID[0])
+		}
+		if test.strategy == StrategyBranchAndBound ||
+			test.strategy == StrategyMinimizeChange {
+			assert.ElementsMatch(t, test.want, got, "%s %s", test.strategy,
+				test.target)
+		} else {
+			assert.Equal(t, test.want, got, "%s %s", test.strategy,
+				test.target)
+		}
+	}
+}
+
+func TestFundTransaction_Fee(t *testing.T) {
+	ac, sender := newTestSender(t)
+	_, to := newTestSender(t)
+	amount := common.Fixed64(100000)
+	newTxn := func() *types.Transaction {
+		outputs, err := createNormalOutputs([]*OutputInfo{{to.Address,
+			&amount}}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txn, err := newTransferTransaction(sender, outputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return txn
+	}
+	utxos := func() []*utxo {
+		return []*utxo{
+			newTestUTXO(1, 60000, 10),
+			newTestUTXO(2, 70000, 10),
+		}
+	}
+	fee := func(txn *types.Transaction) common.Fixed64 {
+		var fee common.Fixed64
+		for _, input := range txn.Inputs {
+			for _, u := range utxos() {
+				if u.OutPoint.IsEqual(input.Previous) {
+					fee += u.Amount
+				}
+			}
+		}
+		for _, output := range txn.Outputs {
+			fee -= output.Value
+		}
+		return fee
+	}
+
+	// the fee is computed from the size of the signed transaction
+	feeRate := common.Fixed64(10)
+	txn := newTxn()
+	err := fundTransactionFrom(txn, sender, &selectOptions{
+		FeeRate:  feeRate,
+		Strategy: StrategyLargestFirst,
+	}, utxos(), 100)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, txn.Inputs, 2)
+	assert.Len(t, txn.Outputs, 2)
+	buf := new(bytes.Buffer)
+	assert.NoError(t, txn.SerializeUnsigned(buf))
+	signature, err := ac.Sign(buf.Bytes())
+	assert.NoError(t, err)
+	txn.Programs[0].Parameter = append([]byte{byte(len(signature))},
+		signature...)
+	assert.Equal(t, common.Fixed64(txn.GetSize())*feeRate, fee(txn))
+	assert.True(t, fee(txn) > config.DefaultParams.MinTransactionFee)
+
+	// the fixed fee
+	fixedFee := common.Fixed64(500)
+	txn = newTxn()
+	err = fundTransactionFrom(txn, sender, &selectOptions{
+		Fee:      &fixedFee,
+		Strategy: StrategyLargestFirst,
+	}, utxos(), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, fixedFee, fee(txn))
+
+	// the fixed fee less than the minimum fee
+	err = fundTransactionFrom(newTxn(), sender, &selectOptions{
+		Fee:      &fixedFee,
+		MinFee:   1000,
+		Strategy: StrategyLargestFirst,
+	}, utxos(), 100)
+	assert.Error(t, err)
+
+	// the UTXOs are not enough to pay the fee
+	feeRate = common.Fixed64(1000)
+	err = fundTransactionFrom(newTxn(), sender, &selectOptions{
+		FeeRate:  feeRate,
+		Strategy: StrategyLargestFirst,
+	}, utxos(), 100)
+	assert.Equal(t, errInsufficientFunds, err)
+}
+
+func TestFundTransaction_OutputLock(t *testing.T) {
+	_, sender := newTestSender(t)
+	_, to := newTestSender(t)
+	bestHeight := uint32(100)
+	transfer := byte(types.TransferAsset)
+	unspent := []servers.UTXOInfo{
+		{TxType: transfer, TxID: testTxID(1), Amount: "0.0003",
+			OutputLock: 0, Confirmations: 10},
+		{TxType: transfer, TxID: testTxID(2), Amount: "0.0002",
+			OutputLock: bestHeight, Confirmations: 20},
+		// locked after the best height
+		{TxType: transfer, TxID: testTxID(3), Amount: "1",
+			OutputLock: bestHeight + 1, Confirmations: 30},
+		// immature coinbase output
+		{TxType: byte(types.CoinBase), TxID: testTxID(4), Amount: "1",
+			Confirmations: 100},
+	}
+	newTestNode(t, map[string]rpcHandler{
+		"getcurrentheight": func(htp.Params) (interface{}, error) {
+			return bestHeight, nil
+		},
+		"listunspent": func(htp.Params) (interface{}, error) {
+			return unspent, nil
+		},
+	})
+
+	utxos, height, err := getSpendableUTXOs(sender.Address)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, bestHeight, height)
+	if assert.Len(t, utxos, 2) {
+		assert.Equal(t, byte(1), utxos[0].OutPoint.TxID[0])
+		assert.Equal(t, byte(2), utxos[1].OutPoint.TxID[0])
+	}
+
+	// the locked output is spent by the lock time of the best height
+	amount := common.Fixed64(40000)
+	outputs, err := createNormalOutputs([]*OutputInfo{{to.Address, &amount}},
+		0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err := newTransferTransaction(sender, outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fundTransaction(txn, sender, &selectOptions{
+		FeeRate:  1,
+		Strategy: StrategyLargestFirst,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, txn.Inputs, 2) {
+		assert.Equal(t, uint32(math.MaxUint32), txn.Inputs[0].Sequence)
+		assert.Equal(t, uint32(math.MaxUint32-1), txn.Inputs[1].Sequence)
+	}
+	assert.Equal(t, bestHeight, txn.LockTime)
+
+	// the locked outputs are not enough
+	amount = common.Fixed64(60000)
+	txn.Outputs[0].Value = amount
+	err = fundTransaction(txn, sender, &selectOptions{
+		FeeRate:  1,
+		Strategy: StrategyLargestFirst,
+	})
+	assert.Equal(t, errInsufficientFunds, err)
+}
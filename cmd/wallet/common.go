@@ -18,6 +18,7 @@ import (
 	"github.com/elastos/Elastos.ELA/core/types"
 	"github.com/elastos/Elastos.ELA/crypto"
 	"github.com/elastos/Elastos.ELA/servers"
+	"github.com/elastos/Elastos.ELA/utils"
 	"github.com/elastos/Elastos.ELA/utils/http"
 )
 
@@ -100,22 +101,16 @@ func ShowAccountBalance(walletPath string) error {
 	return nil
 }
 
-func getUTXOsByAmount(address string, amount common.Fixed64) ([]servers.UTXOInfo, error) {
-	result, err := cmdcom.RPCCall("getutxosbyamount", http.Params{
-		"address": address,
-		"amount":  amount.String(),
-	})
+func getCurrentHeight() (uint32, error) {
+	result, err := cmdcom.RPCCall("getcurrentheight", http.Params{})
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	data, err := json.Marshal(result)
-	if err != nil {
-		return nil, err
+	height, ok := result.(float64)
+	if !ok {
+		return 0, errors.New("invalid current height")
 	}
-	var UTXOs []servers.UTXOInfo
-	err = json.Unmarshal(data, &UTXOs)
-
-	return UTXOs, nil
+	return uint32(height), nil
 }
 
 func getAddressUTXOs(address string) ([]servers.UTXOInfo, []servers.UTXOInfo, error) {
@@ -222,6 +217,168 @@ func OutputTx(haveSign, needSign int, txn *types.Transaction) error {
 	return nil
 }
 
+// isPartialTx returns if the transaction content is a partially signed
+// transaction file instead of a raw transaction hex string.
+func isPartialTx(content string) bool {
+	return strings.HasPrefix(strings.TrimSpace(content), "{")
+}
+
+func parsePartialTx(content string) (*account.PartialTx, error) {
+	var p account.PartialTx
+	if err := json.Unmarshal([]byte(content), &p); err != nil {
+		return nil, errors.New("invalid partially signed transaction: " +
+			err.Error())
+	}
+	return &p, nil
+}
+
+// ShowPartialTx prints the referenced inputs, the outputs and the fee of the
+// partial transaction.
+func ShowPartialTx(p *account.PartialTx) error {
+	references, err := p.References()
+	if err != nil {
+		return err
+	}
+	fee, err := p.Fee()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-6s %-34s %-20s\n", "", "ADDRESS", "AMOUNT")
+	fmt.Println(strings.Repeat("-", 6), strings.Repeat("-", 34),
+		strings.Repeat("-", 20))
+	for _, input := range p.Transaction.Inputs {
+		output := references[input]
+		address, err := output.ProgramHash.ToAddress()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-6s %-34s %-20s\n", "INPUT", address,
+			output.Value.String())
+	}
+	for _, output := range p.Transaction.Outputs {
+		address, err := output.ProgramHash.ToAddress()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-6s %-34s %-20s\n", "OUTPUT", address,
+			output.Value.String())
+	}
+	fmt.Println(strings.Repeat("-", 6), strings.Repeat("-", 34),
+		strings.Repeat("-", 20))
+	fmt.Printf("%-6s %-34s %-20s\n", "FEE", "", fee.String())
+	haveSign, needSign := p.SignStatus()
+	fmt.Printf("%-6s %-34s %d / %d\n", "SIGNED", "", haveSign, needSign)
+
+	return nil
+}
+
+// OutputPartialTx writes the partial transaction to a file named by the sign
+// status.
+func OutputPartialTx(p *account.PartialTx) error {
+	content, err := p.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	fileName := "to_be_signed"
+	haveSign, needSign := p.SignStatus()
+	if p.Completed() {
+		fileName = "ready_to_finalize"
+	} else if haveSign > 0 {
+		fileName = fmt.Sprint(fileName, "_", haveSign, "_of_", needSign)
+	}
+	fileName = fileName + ".ptx"
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := file.Write(content); err != nil {
+		return err
+	}
+
+	fmt.Println("File: ", fileName)
+
+	return nil
+}
+
+// addSignerHints adds the derivation paths of the HD accounts in the wallet
+// to the partial transaction as the signer hints.
+func addSignerHints(walletPath string, p *account.PartialTx) error {
+	storeAccounts, err := account.GetWalletAccountData(walletPath)
+	if err != nil {
+		return err
+	}
+	for _, a := range storeAccounts {
+		if a.Path == "" {
+			continue
+		}
+		redeemScript, err := common.HexStringToBytes(a.RedeemScript)
+		if err != nil || len(redeemScript) != crypto.PublicKeyScriptLength {
+			continue
+		}
+		p.AddSignerHint(redeemScript[1:len(redeemScript)-1], a.Path)
+	}
+	return nil
+}
+
+// newPartialTx creates the partial transaction of the transaction with the
+// referenced transactions from the node and the signer hints of the wallet.
+func newPartialTx(walletPath string,
+	txn *types.Transaction) (*account.PartialTx, error) {
+	previous, err := getPreviousTransactions(txn)
+	if err != nil {
+		return nil, err
+	}
+	p, err := account.NewPartialTx(txn, previous)
+	if err != nil {
+		return nil, err
+	}
+	if utils.FileExisted(walletPath) {
+		if err := addSignerHints(walletPath, p); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// getPreviousTransactions gets the transactions referenced by the inputs of
+// the transaction from the node.
+func getPreviousTransactions(txn *types.Transaction) ([]*types.Transaction,
+	error) {
+	var previous []*types.Transaction
+	fetched := make(map[common.Uint256]bool)
+	for _, input := range txn.Inputs {
+		txID := input.Previous.TxID
+		if fetched[txID] {
+			continue
+		}
+		result, err := cmdcom.RPCCall("getrawtransaction", http.Params{
+			"txid": servers.ToReversedString(txID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		txHex, ok := result.(string)
+		if !ok {
+			return nil, errors.New("invalid raw transaction of " +
+				servers.ToReversedString(txID))
+		}
+		txBytes, err := common.HexStringToBytes(txHex)
+		if err != nil {
+			return nil, err
+		}
+		var prev types.Transaction
+		if err := prev.Deserialize(bytes.NewReader(txBytes)); err != nil {
+			return nil, err
+		}
+		previous = append(previous, &prev)
+		fetched[txID] = true
+	}
+	return previous, nil
+}
+
 func parseMultiOutput(path string) ([]*OutputInfo, error) {
 	if _, err := os.Stat(path); err != nil {
 		return nil, errors.New("invalid multi output file path")
@@ -0,0 +1,410 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/elastos/Elastos.ELA/account"
+	cmdcom "github.com/elastos/Elastos.ELA/cmd/common"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/config"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/outputpayload"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/utils"
+	"github.com/elastos/Elastos.ELA/utils/http"
+
+	"github.com/urfave/cli"
+)
+
+var crossChainCommand = []cli.Command{
+	{
+		Category: "CrossChain",
+		Name:     "sidechain",
+		Usage:    "Add, remove or list the side chains to deposit to",
+		Subcommands: []cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Add a side chain with the genesis block hash",
+				ArgsUsage: "<name> <genesis block hash>",
+				Flags: []cli.Flag{
+					cmdcom.AccountWalletFlag,
+				},
+				Action: addSideChain,
+			},
+			{
+				Name:      "remove",
+				Usage:     "Remove a side chain",
+				ArgsUsage: "<name>",
+				Flags: []cli.Flag{
+					cmdcom.AccountWalletFlag,
+				},
+				Action: removeSideChain,
+			},
+			{
+				Name:  "list",
+				Usage: "List the side chains with the genesis block addresses",
+				Flags: []cli.Flag{
+					cmdcom.AccountWalletFlag,
+				},
+				Action: listSideChains,
+			},
+		},
+	},
+	{
+		Category:    "CrossChain",
+		Name:        "withdrawals",
+		Usage:       "Track and show the withdrawals from the side chains",
+		Description: "use --track to add the side chain transactions of the withdrawals",
+		Flags: []cli.Flag{
+			cmdcom.CrossChainTrackFlag,
+			cmdcom.CrossChainSideChainFlag,
+			cmdcom.AccountWalletFlag,
+		},
+		Action: showWithdrawals,
+	},
+}
+
+func openWalletCrossChain(c *cli.Context) (*account.CrossChain, error) {
+	walletPath := c.String("wallet")
+	if exist := utils.FileExisted(walletPath); !exist {
+		return nil, errors.New(walletPath + " is not found")
+	}
+	return account.OpenCrossChain(account.CrossChainPath(walletPath))
+}
+
+func addSideChain(c *cli.Context) error {
+	if c.NArg() < 2 {
+		cmdcom.PrintErrorMsg("Missing argument. The side chain name and genesis block hash expected.")
+		cli.ShowCommandHelpAndExit(c, "add", 1)
+	}
+	crossChain, err := openWalletCrossChain(c)
+	if err != nil {
+		return err
+	}
+	sc, err := crossChain.AddSideChain(c.Args().Get(0), c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	if err := crossChain.Save(); err != nil {
+		return err
+	}
+	fmt.Println(sc.GenesisAddress)
+	return nil
+}
+
+func removeSideChain(c *cli.Context) error {
+	if c.NArg() < 1 {
+		cmdcom.PrintErrorMsg("Missing argument. The side chain name expected.")
+		cli.ShowCommandHelpAndExit(c, "remove", 1)
+	}
+	crossChain, err := openWalletCrossChain(c)
+	if err != nil {
+		return err
+	}
+	if err := crossChain.RemoveSideChain(c.Args().First()); err != nil {
+		return err
+	}
+	return crossChain.Save()
+}
+
+func listSideChains(c *cli.Context) error {
+	crossChain, err := openWalletCrossChain(c)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-16s %-34s %s\n", "NAME", "GENESIS ADDRESS", "GENESIS BLOCK HASH")
+	fmt.Println(strings.Repeat("-", 16), strings.Repeat("-", 34),
+		strings.Repeat("-", 64))
+	for _, sc := range crossChain.SideChains {
+		fmt.Printf("%-16s %-34s %s\n", sc.Name, sc.GenesisAddress,
+			sc.GenesisHash)
+	}
+	return nil
+}
+
+// getGenesisAddress returns the genesis block address of the side chain, the
+// side chain is the name added by the sidechain command or the genesis block
+// hash.
+func getGenesisAddress(walletPath, sideChain string) (string, error) {
+	crossChain, err := account.OpenCrossChain(
+		account.CrossChainPath(walletPath))
+	if err != nil {
+		return "", err
+	}
+	if sc := crossChain.SideChain(sideChain); sc != nil {
+		return sc.GenesisAddress, nil
+	}
+	if len(sideChain) != common.UINT256SIZE*2 {
+		return "", errors.New("side chain " + sideChain + " not found, " +
+			"use 'sidechain add' to add it")
+	}
+	return account.CrossChainAddress(sideChain)
+}
+
+// CreateCrossChainTransaction creates a transaction to deposit to the side
+// chain. The output to the genesis block address of the side chain includes
+// the cross chain fee paid to the side chain arbiters, and the fee of the
+// transaction is not less than the minimum cross chain transaction fee.
+func CreateCrossChainTransaction(c *cli.Context) error {
+	walletPath := c.String("wallet")
+
+	opts, err := parseSelectOptions(c)
+	if err != nil {
+		return err
+	}
+	opts.MinFee = config.DefaultParams.MinCrossChainTxFee
+
+	sideChain := c.String("sidechain")
+	if sideChain == "" {
+		return errors.New("use --sidechain to specify the side chain")
+	}
+	genesisAddress, err := getGenesisAddress(walletPath, sideChain)
+	if err != nil {
+		return err
+	}
+	genesisHash, err := common.Uint168FromAddress(genesisAddress)
+	if err != nil {
+		return err
+	}
+
+	to := c.String("to")
+	if to == "" {
+		return errors.New("use --to to specify the side chain recipient")
+	}
+	amountStr := c.String("amount")
+	if amountStr == "" {
+		return errors.New("use --amount to specify transfer amount")
+	}
+	amount, err := common.StringToFixed64(amountStr)
+	if err != nil || *amount <= 0 {
+		return errors.New("invalid transaction amount")
+	}
+
+	crossChainFee := config.DefaultParams.MinCrossChainTxFee
+	if feeStr := c.String("crosschainfee"); feeStr != "" {
+		fee, err := common.StringToFixed64(feeStr)
+		if err != nil {
+			return errors.New("invalid cross chain fee")
+		}
+		if *fee < config.DefaultParams.MinCrossChainTxFee {
+			return errors.New("cross chain fee is less than the minimum " +
+				"fee " + config.DefaultParams.MinCrossChainTxFee.String())
+		}
+		crossChainFee = *fee
+	}
+
+	sender, err := getSender(walletPath, c.String("from"))
+	if err != nil {
+		return err
+	}
+	redeemScript, err := common.HexStringToBytes(sender.RedeemScript)
+	if err != nil {
+		return err
+	}
+
+	txAttr := types.NewAttribute(types.Nonce, []byte(strconv.FormatInt(rand.Int63(), 10)))
+	txn := &types.Transaction{
+		Version: types.TxVersion09,
+		TxType:  types.TransferCrossChainAsset,
+		Payload: &payload.TransferCrossChainAsset{
+			CrossChainAddresses: []string{to},
+			OutputIndexes:       []uint64{0},
+			CrossChainAmounts:   []common.Fixed64{*amount},
+		},
+		Attributes: []*types.Attribute{&txAttr},
+		Outputs: []*types.Output{{
+			AssetID:     *account.SystemAssetID,
+			Value:       *amount + crossChainFee,
+			OutputLock:  0,
+			ProgramHash: *genesisHash,
+			Type:        types.OTNone,
+			Payload:     &outputpayload.DefaultOutput{},
+		}},
+		Programs: createPrograms(redeemScript),
+		LockTime: 0,
+	}
+
+	// create inputs and change
+	if err := fundTransaction(txn, sender, opts); err != nil {
+		return errors.New("create transaction failed: " + err.Error())
+	}
+
+	return outputUnsignedTx(c, txn)
+}
+
+// withdrawalRecord is a withdrawal from the side chain to show.
+type withdrawalRecord struct {
+	SideChainTxID string
+	SideChain     string
+	Status        string
+	TxID          string
+	Amount        string
+	Confirmations uint32
+}
+
+func showWithdrawals(c *cli.Context) error {
+	walletPath := c.String("wallet")
+	crossChain, err := openWalletCrossChain(c)
+	if err != nil {
+		return err
+	}
+
+	sideChain := c.String("sidechain")
+	if sideChain != "" && c.String("track") == "" {
+		return errors.New("'--sidechain' can only be specified with '--track' option")
+	}
+	if sideChain != "" && crossChain.SideChain(sideChain) == nil {
+		return errors.New("side chain " + sideChain + " not found")
+	}
+	if track := strings.TrimSpace(c.String("track")); track != "" {
+		for _, txID := range strings.Split(track, ",") {
+			txID = strings.TrimSpace(txID)
+			if _, err := parseTxID(txID); err != nil {
+				return errors.New("invalid txid " + txID)
+			}
+			crossChain.TrackWithdrawal(txID, sideChain)
+		}
+	}
+
+	// the withdrawals to the wallet addresses are found in the history.
+	history, err := account.OpenHistory(account.HistoryPath(walletPath))
+	if err != nil {
+		return err
+	}
+	if err := syncHistory(walletPath, history); err != nil {
+		fmt.Println("warning: sync history failed,", err)
+	}
+	if err := history.Save(); err != nil {
+		return err
+	}
+	withdrawTxs := make(map[string]*account.HistoryTx)
+	for _, tx := range history.Transactions {
+		for _, txID := range tx.SideChainTxs {
+			withdrawTxs[txID] = tx
+		}
+	}
+
+	// the tracked withdrawals to other addresses are checked by the node.
+	var pending []*account.Withdrawal
+	for _, w := range crossChain.Withdrawals {
+		if _, ok := withdrawTxs[w.TxID]; ok {
+			w.Completed = true
+		}
+		if !w.Completed {
+			pending = append(pending, w)
+		}
+	}
+	if err := updateWithdrawals(pending); err != nil {
+		fmt.Println("warning: check withdrawals failed,", err)
+	}
+	if err := crossChain.Save(); err != nil {
+		return err
+	}
+
+	records := make([]*withdrawalRecord, 0, len(crossChain.Withdrawals))
+	tracked := make(map[string]struct{}, len(crossChain.Withdrawals))
+	for _, w := range crossChain.Withdrawals {
+		tracked[w.TxID] = struct{}{}
+		record := &withdrawalRecord{
+			SideChainTxID: w.TxID,
+			SideChain:     w.SideChain,
+			Status:        "pending",
+		}
+		if w.Completed {
+			record.Status = "completed"
+		}
+		if tx, ok := withdrawTxs[w.TxID]; ok {
+			fillWithdrawalRecord(record, crossChain, tx, history.Height)
+		}
+		records = append(records, record)
+	}
+	for _, tx := range history.Transactions {
+		for _, txID := range tx.SideChainTxs {
+			if _, ok := tracked[txID]; ok {
+				continue
+			}
+			record := &withdrawalRecord{
+				SideChainTxID: txID,
+				Status:        "completed",
+			}
+			fillWithdrawalRecord(record, crossChain, tx, history.Height)
+			records = append(records, record)
+		}
+	}
+
+	fmt.Printf("%-64s %-16s %-9s %-64s %-20s %s\n", "SIDE CHAIN TXID",
+		"SIDE CHAIN", "STATUS", "TXID", "AMOUNT", "CONFIRMATIONS")
+	fmt.Println(strings.Repeat("-", 64), strings.Repeat("-", 16),
+		strings.Repeat("-", 9), strings.Repeat("-", 64),
+		strings.Repeat("-", 20), strings.Repeat("-", 13))
+	for _, r := range records {
+		fmt.Printf("%-64s %-16s %-9s %-64s %-20s %d\n",
+			r.SideChainTxID, r.SideChain, r.Status, r.TxID, r.Amount,
+			r.Confirmations)
+	}
+	return nil
+}
+
+// fillWithdrawalRecord fills the record with the main chain transaction of
+// the withdrawal, the side chain is the genesis block address if it's not
+// added by the sidechain command.
+func fillWithdrawalRecord(record *withdrawalRecord,
+	crossChain *account.CrossChain, tx *account.HistoryTx, bestHeight uint32) {
+	record.TxID = tx.TxID
+	record.Amount = tx.Amount.String()
+	if tx.Height <= bestHeight {
+		record.Confirmations = bestHeight - tx.Height + 1
+	}
+	if record.SideChain == "" {
+		record.SideChain = tx.GenesisAddress
+		if sc := crossChain.SideChainByAddress(tx.GenesisAddress); sc != nil {
+			record.SideChain = sc.Name
+		}
+	}
+}
+
+// updateWithdrawals marks the withdrawals completed if the withdraw
+// transactions of them are found in the main chain or the transaction pool
+// of the node.
+func updateWithdrawals(withdrawals []*account.Withdrawal) error {
+	if len(withdrawals) == 0 {
+		return nil
+	}
+	hashes := make(map[string]*account.Withdrawal, len(withdrawals))
+	txs := make([]string, 0, len(withdrawals))
+	for _, w := range withdrawals {
+		hash, err := parseTxID(w.TxID)
+		if err != nil {
+			return err
+		}
+		hashes[hash.String()] = w
+		txs = append(txs, hash.String())
+	}
+	result, err := cmdcom.RPCCall("getexistwithdrawtransactions", http.Params{
+		"txs": txs,
+	})
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	existTxs, ok := result.([]interface{})
+	if !ok {
+		return errors.New("invalid withdraw transactions")
+	}
+	for _, tx := range existTxs {
+		hash, ok := tx.(string)
+		if !ok {
+			continue
+		}
+		if w, ok := hashes[hash]; ok {
+			w.Completed = true
+		}
+	}
+	return nil
+}
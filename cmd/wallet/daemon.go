@@ -0,0 +1,474 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/elastos/Elastos.ELA/account"
+	cmdcom "github.com/elastos/Elastos.ELA/cmd/common"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/log"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/servers"
+	"github.com/elastos/Elastos.ELA/servers/httpwebsocket"
+	"github.com/elastos/Elastos.ELA/utils"
+	"github.com/elastos/Elastos.ELA/utils/elalog"
+	"github.com/elastos/Elastos.ELA/utils/signal"
+
+	"github.com/gorilla/websocket"
+	"github.com/urfave/cli"
+)
+
+const (
+	// daemonLogPath is the log path of the wallet daemon beside the wallet.
+	daemonLogPath = "logs/wallet"
+
+	// daemonRetryInterval is the interval to reconnect the websocket of the
+	// node.
+	daemonRetryInterval = 5 * time.Second
+
+	// daemonRefreshInterval is the interval to refresh the wallet without
+	// notifications, so the locked outputs become spendable in time.
+	daemonRefreshInterval = time.Minute
+
+	// daemonHeartbeatInterval keeps the websocket session alive, the node
+	// expires the sessions idle for a minute.
+	daemonHeartbeatInterval = 30 * time.Second
+)
+
+var daemonCommand = []cli.Command{
+	{
+		Category:    "Daemon",
+		Name:        "daemon",
+		Usage:       "Run the wallet daemon serving the wallet JSON-RPC",
+		Description: "the wallet is locked when the daemon starts, use the walletpassphrase method to unlock it",
+		Flags: []cli.Flag{
+			cmdcom.DaemonRPCPortFlag,
+			cmdcom.DaemonRPCUserFlag,
+			cmdcom.DaemonRPCPasswordFlag,
+			cmdcom.DaemonWsPortFlag,
+			cmdcom.AccountWalletFlag,
+		},
+		Action: runDaemon,
+	},
+}
+
+// errResubscribe is returned to reconnect the websocket of the node to
+// subscribe the new addresses of the wallet.
+var errResubscribe = errors.New("resubscribe the wallet addresses")
+
+// daemon keeps the UTXOs and the history of the wallet in sync with the node
+// by the websocket notifications, and serves the wallet JSON-RPC.
+type daemon struct {
+	walletPath string
+	wsURL      string
+
+	mtx sync.Mutex
+
+	// refreshMtx serializes the refreshes, the history is only replaced by
+	// the refresh.
+	refreshMtx sync.Mutex
+
+	// client is opened when the wallet is unlocked for the first time, and
+	// it's locked when the unlock timeout expires.
+	client    *account.Client
+	lockTimer *time.Timer
+
+	history    *account.History
+	bestHeight uint32
+	utxos      map[string][]*utxo
+
+	// reserved are the inputs of the transactions sent by the daemon which
+	// are not mined yet, they are excluded from the coin selection.
+	reserved map[types.OutPoint]string
+
+	newAddress chan struct{}
+}
+
+func runDaemon(c *cli.Context) error {
+	walletPath := c.String("wallet")
+	if exist := utils.FileExisted(walletPath); !exist {
+		return errors.New(walletPath + " is not found")
+	}
+	user := c.String("walletrpcuser")
+	pass := c.String("walletrpcpassword")
+	if user == "" || pass == "" {
+		return errors.New("use --walletrpcuser and --walletrpcpassword to " +
+			"authenticate the wallet JSON-RPC connections")
+	}
+
+	log.NewDefault(filepath.Join(filepath.Dir(walletPath), daemonLogPath),
+		uint8(elalog.LevelInfo), 0, 0)
+
+	history, err := account.OpenHistory(account.HistoryPath(walletPath))
+	if err != nil {
+		return err
+	}
+	d := &daemon{
+		walletPath: walletPath,
+		wsURL:      "ws://localhost:" + strconv.Itoa(int(c.Uint("wsport"))),
+		history:    history,
+		utxos:      make(map[string][]*utxo),
+		reserved:   make(map[types.OutPoint]string),
+		newAddress: make(chan struct{}, 1),
+	}
+	cmdcom.PrintInfoMsg("syncing wallet from height %d",
+		history.NextHeight())
+	if err := d.refresh(); err != nil {
+		return err
+	}
+
+	quit := make(chan struct{})
+	go d.syncLoop(quit)
+
+	server := newDaemonRPCServer(d, uint16(c.Uint("walletrpcport")), user,
+		pass)
+	go func() {
+		if err := server.Start(); err != nil {
+			cmdcom.PrintErrorMsg(err.Error())
+			close(quit)
+		}
+	}()
+	cmdcom.PrintInfoMsg("wallet daemon started, listening on %d",
+		c.Uint("walletrpcport"))
+
+	select {
+	case <-signal.NewInterrupt().C:
+		close(quit)
+	case <-quit:
+	}
+	server.Stop()
+	d.lock()
+	return d.saveHistory()
+}
+
+// addresses returns the addresses of the wallet.
+func (d *daemon) addresses() ([]string, error) {
+	storeAccounts, err := account.GetWalletAccountData(d.walletPath)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, 0, len(storeAccounts))
+	for _, a := range storeAccounts {
+		addresses = append(addresses, a.Address)
+	}
+	return addresses, nil
+}
+
+// refresh gets the spendable UTXOs of the wallet addresses from the node and
+// syncs the history to the best block. They are synced without holding the
+// mutex and swapped in at last, so that the wallet RPCs are not blocked.
+func (d *daemon) refresh() error {
+	d.refreshMtx.Lock()
+	defer d.refreshMtx.Unlock()
+
+	addresses, err := d.addresses()
+	if err != nil {
+		return err
+	}
+	utxos := make(map[string][]*utxo, len(addresses))
+	unspent := make(map[types.OutPoint]struct{})
+	var bestHeight uint32
+	for _, address := range addresses {
+		utxos[address], bestHeight, err = getSpendableUTXOs(address)
+		if err != nil {
+			return err
+		}
+		for _, u := range utxos[address] {
+			unspent[u.OutPoint] = struct{}{}
+		}
+	}
+
+	// the history is not changed by the others during the refresh.
+	history, err := d.history.Copy()
+	if err != nil {
+		return err
+	}
+	if err := syncHistory(d.walletPath, history); err != nil {
+		return err
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.utxos = utxos
+	d.bestHeight = bestHeight
+	d.history = history
+
+	// the reserved inputs are spent by the mined transactions if they are
+	// not unspent any more.
+	for outPoint := range d.reserved {
+		if _, ok := unspent[outPoint]; !ok {
+			delete(d.reserved, outPoint)
+		}
+	}
+	return nil
+}
+
+func (d *daemon) saveHistory() error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.history.Save()
+}
+
+// syncLoop keeps the websocket of the node connected until quit.
+func (d *daemon) syncLoop(quit chan struct{}) {
+	for {
+		err := d.subscribe(quit)
+		if err == errResubscribe {
+			continue
+		}
+		if err != nil {
+			log.Warn("websocket of the node disconnected:", err)
+		}
+		select {
+		case <-quit:
+			return
+		case <-time.After(daemonRetryInterval):
+		}
+	}
+}
+
+// wsMessage is the message pushed by the websocket of the node.
+type wsMessage struct {
+	Action string
+	Error  int
+	Result json.RawMessage
+}
+
+// subscribe connects the websocket of the node and subscribes the new blocks
+// and the transactions of the wallet addresses, the wallet is refreshed on
+// the notifications until the connection is closed.
+func (d *daemon) subscribe(quit chan struct{}) error {
+	conn, _, err := websocket.DefaultDialer.Dial(d.wsURL, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	addresses, err := d.addresses()
+	if err != nil {
+		return err
+	}
+	for _, req := range []map[string]interface{}{
+		{"action": "subscribe", "topic": "sendrawblock"},
+		{"action": "subscribe", "topic": httpwebsocket.TopicAddress,
+			"addresses": addresses},
+	} {
+		if err := conn.WriteJSON(req); err != nil {
+			return err
+		}
+	}
+
+	// the notifications are missed while disconnected.
+	if err := d.refresh(); err != nil {
+		log.Warn("refresh wallet failed:", err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	messages := make(chan *wsMessage)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			var msg wsMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case messages <- &msg:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(daemonHeartbeatInterval)
+	defer heartbeat.Stop()
+	refresh := time.NewTicker(daemonRefreshInterval)
+	defer refresh.Stop()
+	for {
+		select {
+		case msg := <-messages:
+			d.onMessage(msg)
+
+		case <-heartbeat.C:
+			err := conn.WriteJSON(map[string]interface{}{
+				"action": "heartbeat",
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-refresh.C:
+			if err := d.refresh(); err != nil {
+				log.Warn("refresh wallet failed:", err)
+			}
+
+		case <-d.newAddress:
+			return errResubscribe
+
+		case err := <-errs:
+			return err
+
+		case <-quit:
+			return nil
+		}
+	}
+}
+
+// onMessage refreshes the wallet if a new block is connected or a
+// transaction of the wallet addresses is mined or reorganized, and releases
+// the reserved inputs of the dropped transactions.
+func (d *daemon) onMessage(msg *wsMessage) {
+	if msg.Error != 0 {
+		log.Warnf("websocket %s error %d: %s", msg.Action, msg.Error,
+			string(msg.Result))
+		return
+	}
+	switch msg.Action {
+	case "sendrawblock":
+		// a new block is connected.
+
+	case httpwebsocket.TopicAddress:
+		var n httpwebsocket.AddressNotification
+		if err := json.Unmarshal(msg.Result, &n); err != nil {
+			log.Warn("invalid address notification:", err)
+			return
+		}
+		switch n.Status {
+		case servers.TxStatusDropped:
+			d.release(n.TxID)
+			return
+		case servers.TxStatusMined, servers.TxStatusReorged:
+			// the spendable UTXOs are changed.
+		default:
+			return
+		}
+
+	default:
+		return
+	}
+	if err := d.refresh(); err != nil {
+		log.Warn("refresh wallet failed:", err)
+	}
+}
+
+// reserve excludes the inputs of the sent transaction from the coin
+// selection until the transaction is mined or dropped.
+func (d *daemon) reserve(txn *types.Transaction, txID string) {
+	for _, input := range txn.Inputs {
+		d.reserved[input.Previous] = txID
+	}
+}
+
+// release releases the reserved inputs of the dropped transaction.
+func (d *daemon) release(txID string) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for outPoint, id := range d.reserved {
+		if id == txID {
+			delete(d.reserved, outPoint)
+		}
+	}
+}
+
+// spendableUTXOs returns the UTXOs of the address not reserved.
+func (d *daemon) spendableUTXOs(address string) []*utxo {
+	utxos := make([]*utxo, 0, len(d.utxos[address]))
+	for _, u := range d.utxos[address] {
+		if _, ok := d.reserved[u.OutPoint]; !ok {
+			copied := *u
+			utxos = append(utxos, &copied)
+		}
+	}
+	return utxos
+}
+
+// balance returns the total amount of the spendable UTXOs of the address,
+// all the wallet addresses are counted if the address is empty.
+func (d *daemon) balance(address string) common.Fixed64 {
+	var balance common.Fixed64
+	for addr := range d.utxos {
+		if address != "" && addr != address {
+			continue
+		}
+		for _, u := range d.spendableUTXOs(addr) {
+			balance += u.Amount
+		}
+	}
+	return balance
+}
+
+// unlock unlocks the wallet by the password for the timeout.
+func (d *daemon) unlock(password []byte, timeout time.Duration) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.client == nil {
+		client, err := account.Open(d.walletPath, password)
+		if err != nil {
+			return err
+		}
+		d.client = client
+	} else if err := d.client.Unlock(password); err != nil {
+		return err
+	}
+
+	if d.lockTimer != nil {
+		d.lockTimer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(timeout, func() {
+		d.mtx.Lock()
+		defer d.mtx.Unlock()
+		// the expired timer may be waiting for the mutex while the wallet
+		// is unlocked again, it's replaced by the new timer then.
+		if d.lockTimer == timer {
+			d.lockClient()
+		}
+	})
+	d.lockTimer = timer
+	log.Info("wallet unlocked for", timeout)
+	return nil
+}
+
+// lock locks the wallet, the keys are cleared from memory.
+func (d *daemon) lock() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.lockClient()
+}
+
+// lockClient locks the wallet, it must be called with the mutex held.
+func (d *daemon) lockClient() {
+	if d.lockTimer != nil {
+		d.lockTimer.Stop()
+		d.lockTimer = nil
+	}
+	if d.client != nil && !d.client.Locked() {
+		d.client.Lock()
+		log.Info("wallet locked")
+	}
+}
+
+// unlockedClient returns the client if the wallet is unlocked, it must be
+// called with the mutex held.
+func (d *daemon) unlockedClient() (*account.Client, error) {
+	if d.client == nil || d.client.Locked() {
+		return nil, errWalletLocked
+	}
+	return d.client, nil
+}
+
+// notifyNewAddress reconnects the websocket to subscribe the new address.
+func (d *daemon) notifyNewAddress() {
+	select {
+	case d.newAddress <- struct{}{}:
+	default:
+	}
+}
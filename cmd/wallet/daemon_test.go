@@ -0,0 +1,298 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elastos/Elastos.ELA/account"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/log"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/servers"
+	htp "github.com/elastos/Elastos.ELA/utils/http"
+	"github.com/elastos/Elastos.ELA/utils/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var password = []byte("password")
+
+func newTestDaemon(t *testing.T) *daemon {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+	walletPath := filepath.Join(t.TempDir(), account.KeystoreFileName)
+	if _, err := account.Create(walletPath, password); err != nil {
+		t.Fatal(err)
+	}
+	history, err := account.OpenHistory(account.HistoryPath(walletPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &daemon{
+		walletPath: walletPath,
+		history:    history,
+		utxos:      make(map[string][]*utxo),
+		reserved:   make(map[types.OutPoint]string),
+		newAddress: make(chan struct{}, 1),
+	}
+	t.Cleanup(d.lock)
+	return d
+}
+
+// locked returns if the wallet is locked with the mutex held.
+func (d *daemon) locked() bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	_, err := d.unlockedClient()
+	return err != nil
+}
+
+func TestDaemon_Relock(t *testing.T) {
+	d := newTestDaemon(t)
+	assert.True(t, d.locked())
+	_, err := d.getNewAddress(nil)
+	assert.Equal(t, errWalletLocked, err)
+
+	// wrong passphrase
+	_, err = d.walletPassphrase(htp.Params{
+		"passphrase": "wrong",
+		"timeout":    float64(1),
+	})
+	if assert.IsType(t, &htp.Error{}, err) {
+		assert.Equal(t, errCodeWalletPassphrase, err.(*htp.Error).Code)
+	}
+	assert.True(t, d.locked())
+
+	// the wallet is locked again after the timeout
+	assert.NoError(t, d.unlock(password, 100*time.Millisecond))
+	assert.False(t, d.locked())
+	_, err = d.getNewAddress(nil)
+	assert.NoError(t, err)
+	deadline := time.Now().Add(5 * time.Second)
+	for !d.locked() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, d.locked())
+	_, err = d.getNewAddress(nil)
+	assert.Equal(t, errWalletLocked, err)
+
+	// unlock again resets the timeout
+	assert.NoError(t, d.unlock(password, 100*time.Millisecond))
+	assert.NoError(t, d.unlock(password, time.Minute))
+	time.Sleep(300 * time.Millisecond)
+	assert.False(t, d.locked())
+
+	// lock before the timeout
+	_, err = d.walletLock(nil)
+	assert.NoError(t, err)
+	assert.True(t, d.locked())
+
+	// a wrong passphrase keeps the unlocked wallet and its timeout
+	assert.NoError(t, d.unlock(password, 200*time.Millisecond))
+	_, err = d.walletPassphrase(htp.Params{
+		"passphrase": "wrong",
+		"timeout":    float64(60),
+	})
+	if assert.IsType(t, &htp.Error{}, err) {
+		assert.Equal(t, errCodeWalletPassphrase, err.(*htp.Error).Code)
+	}
+	assert.False(t, d.locked())
+	_, err = d.getNewAddress(nil)
+	assert.NoError(t, err)
+	deadline = time.Now().Add(5 * time.Second)
+	for !d.locked() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, d.locked())
+	_, err = d.getNewAddress(nil)
+	assert.Equal(t, errWalletLocked, err)
+}
+
+func TestDaemon_ReserveUTXOs(t *testing.T) {
+	d := newTestDaemon(t)
+	sender, err := account.GetWalletMainAccountData(d.walletPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, to := newTestSender(t)
+	d.bestHeight = 100
+	d.utxos[sender.Address] = []*utxo{
+		newTestUTXO(1, 100000000, 10),
+		newTestUTXO(2, 100000000, 10),
+		newTestUTXO(3, 100000000, 10),
+	}
+
+	var mtx sync.Mutex
+	sent := make(map[string]*types.Transaction)
+	newTestNode(t, map[string]rpcHandler{
+		"sendrawtransaction": func(params htp.Params) (interface{}, error) {
+			data, _ := params.String("data")
+			txBytes, err := common.HexStringToBytes(data)
+			if err != nil {
+				return nil, err
+			}
+			var txn types.Transaction
+			if err := txn.Deserialize(bytes.NewReader(txBytes)); err != nil {
+				return nil, err
+			}
+			txID := txn.Hash()
+			mtx.Lock()
+			defer mtx.Unlock()
+			sent[txID.String()] = &txn
+			return common.BytesToHexString(common.BytesReverse(
+				txID.Bytes())), nil
+		},
+	})
+	send := func() (interface{}, error) {
+		return d.sendToAddress(htp.Params{
+			"address": to.Address,
+			"amount":  "0.5",
+			"feerate": float64(1),
+		})
+	}
+
+	// the wallet should be unlocked to send
+	_, err = send()
+	assert.Equal(t, errWalletLocked, err)
+	assert.NoError(t, d.unlock(password, time.Minute))
+
+	// every UTXO is spent by one of the concurrent transactions
+	var wg sync.WaitGroup
+	txIDs := make([]string, 3)
+	for i := range txIDs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := send()
+			assert.NoError(t, err)
+			txIDs[i], _ = result.(string)
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, sent, 3)
+	spent := make(map[types.OutPoint]struct{})
+	for _, txn := range sent {
+		if assert.Len(t, txn.Inputs, 1) {
+			spent[txn.Inputs[0].Previous] = struct{}{}
+		}
+	}
+	assert.Len(t, spent, 3)
+	assert.Len(t, d.reserved, 3)
+	balance, err := d.getBalance(htp.Params{})
+	assert.NoError(t, err)
+	assert.Equal(t, common.Fixed64(0).String(), balance)
+
+	// no spendable UTXOs until a transaction is dropped
+	_, err = send()
+	if assert.IsType(t, &htp.Error{}, err) {
+		assert.Equal(t, errCodeInsufficientFunds, err.(*htp.Error).Code)
+	}
+	d.release(txIDs[0])
+	assert.Len(t, d.reserved, 2)
+	_, err = send()
+	assert.NoError(t, err)
+	assert.Len(t, sent, 4)
+	assert.Len(t, d.reserved, 3)
+}
+
+func TestDaemon_ListTransactions(t *testing.T) {
+	d := newTestDaemon(t)
+	for i := 1; i <= 5; i++ {
+		d.history.Transactions = append(d.history.Transactions,
+			&account.HistoryTx{
+				TxID:   strconv.Itoa(i),
+				Height: uint32(i),
+			})
+	}
+	d.history.Height = 5
+
+	tests := []struct {
+		params htp.Params
+		want   []string
+	}{
+		{htp.Params{}, []string{"1", "2", "3", "4", "5"}},
+		{htp.Params{"count": float64(2)}, []string{"4", "5"}},
+		{htp.Params{"count": float64(2), "skip": float64(1)},
+			[]string{"3", "4"}},
+		{htp.Params{"count": float64(3), "skip": float64(3)},
+			[]string{"1", "2"}},
+		{htp.Params{"skip": float64(5)}, []string{}},
+		{htp.Params{"skip": float64(10)}, []string{}},
+		{htp.Params{"count": float64(0)}, []string{}},
+	}
+	for _, test := range tests {
+		result, err := d.listTransactions(test.params)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		records := result.([]*historyRecord)
+		txIDs := make([]string, 0, len(records))
+		for _, record := range records {
+			txIDs = append(txIDs, record.TxID)
+		}
+		assert.Equal(t, test.want, txIDs, "%v", test.params)
+	}
+
+	// the confirmations are counted to the synced height
+	result, err := d.listTransactions(htp.Params{"count": float64(1),
+		"skip": float64(4)})
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint32(5), result.([]*historyRecord)[0].Confirmations)
+	}
+}
+
+func TestDaemon_RefreshUnlocked(t *testing.T) {
+	d := newTestDaemon(t)
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	newTestNode(t, map[string]rpcHandler{
+		"getcurrentheight": func(htp.Params) (interface{}, error) {
+			return 0, nil
+		},
+		"listunspent": func(htp.Params) (interface{}, error) {
+			return []servers.UTXOInfo{}, nil
+		},
+		"getblockhash": func(htp.Params) (interface{}, error) {
+			return testTxID(1), nil
+		},
+		"getblock": func(htp.Params) (interface{}, error) {
+			close(fetching)
+			<-release
+			return nil, errors.New("block not found")
+		},
+	})
+
+	refreshed := make(chan error, 1)
+	go func() {
+		refreshed <- d.refresh()
+	}()
+	select {
+	case <-fetching:
+	case err := <-refreshed:
+		t.Fatal("refresh is not syncing the blocks:", err)
+	}
+
+	// the wallet RPCs are served while the blocks are fetched
+	done := make(chan struct{})
+	go func() {
+		d.listTransactions(htp.Params{})
+		d.walletLock(nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("wallet RPCs are blocked by the refresh")
+	}
+
+	// the history is not changed by the failed refresh
+	history := d.history
+	close(release)
+	assert.Error(t, <-refreshed)
+	assert.True(t, history == d.history)
+	assert.Empty(t, d.history.BlockHashes)
+}
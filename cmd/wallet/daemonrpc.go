@@ -0,0 +1,238 @@
+package wallet
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/elastos/Elastos.ELA/account"
+	cmdcom "github.com/elastos/Elastos.ELA/cmd/common"
+	"github.com/elastos/Elastos.ELA/common"
+	htp "github.com/elastos/Elastos.ELA/utils/http"
+	"github.com/elastos/Elastos.ELA/utils/http/jsonrpc"
+)
+
+const (
+	// errCodeWalletLocked is the error code returned if the wallet should be
+	// unlocked by walletpassphrase first.
+	errCodeWalletLocked = -32010
+
+	// errCodeWalletPassphrase is the error code returned if the wallet can't be
+	// unlocked by the passphrase.
+	errCodeWalletPassphrase = -32011
+
+	// errCodeInsufficientFunds is the error code returned if the spendable
+	// balance is not enough to pay the amount and the fee.
+	errCodeInsufficientFunds = -32012
+
+	// maxUnlockTimeout is the maximum seconds to unlock the wallet.
+	maxUnlockTimeout = 100000000
+
+	// defaultListCount is the default count of the listed transactions.
+	defaultListCount = 10
+)
+
+var errWalletLocked = htp.NewError(errCodeWalletLocked,
+	"wallet is locked, use walletpassphrase to unlock it")
+
+// newDaemonRPCServer creates the wallet JSON-RPC server of the daemon, it
+// only accepts the connections from localhost.
+func newDaemonRPCServer(d *daemon, port uint16, user,
+	pass string) *jsonrpc.Server {
+	s := jsonrpc.NewServer(&jsonrpc.Config{
+		ServePort: port,
+		User:      user,
+		Pass:      pass,
+		NetListen: func(port uint16) (net.Listener, error) {
+			return net.Listen("tcp4", "127.0.0.1:"+strconv.Itoa(int(port)))
+		},
+	})
+
+	s.RegisterAction("getbalance", d.getBalance, "address")
+	s.RegisterAction("listtransactions", d.listTransactions, "count", "skip")
+	s.RegisterRoleAction(jsonrpc.RoleWallet, "getnewaddress", d.getNewAddress)
+	s.RegisterRoleAction(jsonrpc.RoleWallet, "sendtoaddress", d.sendToAddress,
+		"address", "amount", "from", "feerate")
+	s.RegisterRoleAction(jsonrpc.RoleWallet, "walletlock", d.walletLock)
+	s.RegisterRoleAction(jsonrpc.RoleWallet, "walletpassphrase",
+		d.walletPassphrase, "passphrase", "timeout")
+	s.RegisterSensitiveParams("walletpassphrase", "passphrase")
+	return s
+}
+
+func (d *daemon) getBalance(params htp.Params) (interface{}, error) {
+	address, _ := params.String("address")
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.balance(address).String(), nil
+}
+
+// listTransactions returns the latest transactions of the history after
+// skipping the given count of latest ones, the oldest is the first.
+func (d *daemon) listTransactions(params htp.Params) (interface{}, error) {
+	count, ok := params.Uint("count")
+	if !ok {
+		count = defaultListCount
+	}
+	skip, _ := params.Uint("skip")
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	txs := d.history.Transactions
+	end := len(txs) - int(skip)
+	if end < 0 {
+		end = 0
+	}
+	start := end - int(count)
+	if start < 0 {
+		start = 0
+	}
+	records := make([]*historyRecord, 0, end-start)
+	for _, tx := range txs[start:end] {
+		records = append(records, newHistoryRecord(d.history, tx,
+			d.history.Height))
+	}
+	return records, nil
+}
+
+// getNewAddress derives the next receiving address of a HD wallet, or
+// creates a standard account in the other wallets.
+func (d *daemon) getNewAddress(params htp.Params) (interface{}, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	client, err := d.unlockedClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var ac *account.Account
+	if client.IsHD() {
+		ac, err = client.DeriveAccount(0)
+	} else {
+		ac, err = client.CreateAccount()
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.notifyNewAddress()
+	return ac.Address, nil
+}
+
+// sendToAddress sends the amount from the sender, the default sender is the
+// main account, and the fee rate is estimated by the node if not specified.
+// The mutex is held until the transaction is sent, so the inputs of the
+// transactions sent concurrently will not conflict.
+func (d *daemon) sendToAddress(params htp.Params) (interface{}, error) {
+	address, ok := params.String("address")
+	if !ok {
+		return nil, htp.NewError(jsonrpc.InvalidParams,
+			"need a string parameter named address")
+	}
+	if _, err := common.Uint168FromAddress(address); err != nil {
+		return nil, htp.NewError(jsonrpc.InvalidParams, "invalid address")
+	}
+	amountStr, ok := params.String("amount")
+	if !ok {
+		return nil, htp.NewError(jsonrpc.InvalidParams,
+			"need a string parameter named amount")
+	}
+	amount, err := common.StringToFixed64(amountStr)
+	if err != nil || *amount <= 0 {
+		return nil, htp.NewError(jsonrpc.InvalidParams, "invalid amount")
+	}
+	from, _ := params.String("from")
+	feeRate, ok := params.Uint("feerate")
+	if !ok || feeRate == 0 {
+		if feeRate, err = estimateFeeRate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sender, err := getSender(d.walletPath, from)
+	if err != nil {
+		return nil, htp.NewError(jsonrpc.InvalidParams, err.Error())
+	}
+	outputs, err := createNormalOutputs([]*OutputInfo{{address, amount}}, 0)
+	if err != nil {
+		return nil, err
+	}
+	txn, err := newTransferTransaction(sender, outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	client, err := d.unlockedClient()
+	if err != nil {
+		return nil, err
+	}
+	opts := &selectOptions{
+		FeeRate:  common.Fixed64(feeRate),
+		Strategy: StrategyBranchAndBound,
+	}
+	err = fundTransactionFrom(txn, sender, opts,
+		d.spendableUTXOs(sender.Address), d.bestHeight)
+	if err == errInsufficientFunds {
+		return nil, htp.NewError(errCodeInsufficientFunds, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := signFully(client, txn); err != nil {
+		return nil, err
+	}
+	txID, err := sendTransaction(txn)
+	if err != nil {
+		return nil, err
+	}
+	d.reserve(txn, txID)
+	return txID, nil
+}
+
+// walletPassphrase unlocks the wallet for the timeout in seconds.
+func (d *daemon) walletPassphrase(params htp.Params) (interface{}, error) {
+	passphrase, ok := params.String("passphrase")
+	if !ok {
+		return nil, htp.NewError(jsonrpc.InvalidParams,
+			"need a string parameter named passphrase")
+	}
+	timeout, ok := params.Uint("timeout")
+	if !ok || timeout == 0 {
+		return nil, htp.NewError(jsonrpc.InvalidParams,
+			"need a positive integer parameter named timeout")
+	}
+	if timeout > maxUnlockTimeout {
+		timeout = maxUnlockTimeout
+	}
+	err := d.unlock([]byte(passphrase), time.Duration(timeout)*time.Second)
+	if err != nil {
+		return nil, htp.NewError(errCodeWalletPassphrase, err.Error())
+	}
+	return nil, nil
+}
+
+func (d *daemon) walletLock(params htp.Params) (interface{}, error) {
+	d.lock()
+	return nil, nil
+}
+
+// estimateFeeRate returns the fee rate in sela per byte estimated by the node
+// to be packed in the next block.
+func estimateFeeRate() (uint, error) {
+	result, err := cmdcom.RPCCall("estimatesmartfee", htp.Params{
+		"confirmations": 1,
+	})
+	if err != nil {
+		return 0, err
+	}
+	feeRate, ok := result.(float64)
+	if !ok {
+		return 0, htp.NewError(jsonrpc.InternalError, "invalid fee rate")
+	}
+	// the estimated fee rate is in sela per KB.
+	if feeRate < 1000 {
+		return 1, nil
+	}
+	return uint(feeRate / 1000), nil
+}
@@ -0,0 +1,474 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastos/Elastos.ELA/account"
+	cmdcom "github.com/elastos/Elastos.ELA/cmd/common"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/servers"
+	"github.com/elastos/Elastos.ELA/utils"
+	"github.com/elastos/Elastos.ELA/utils/http"
+
+	"github.com/urfave/cli"
+)
+
+// historySaveInterval is the count of synced blocks to save the history.
+const historySaveInterval = 1000
+
+var historyCommand = []cli.Command{
+	{
+		Category: "History",
+		Name:     "history",
+		Usage:    "Sync and show the transaction history of the wallet",
+		Flags: []cli.Flag{
+			cmdcom.HistoryAddressFlag,
+			cmdcom.HistoryFormatFlag,
+			cmdcom.HistoryExportFlag,
+			cmdcom.HistoryStartHeightFlag,
+			cmdcom.HistoryRescanFlag,
+			cmdcom.AccountWalletFlag,
+		},
+		Action: showHistory,
+	},
+	{
+		Category:  "History",
+		Name:      "label",
+		Usage:     "Set or show the labels of transactions and addresses",
+		ArgsUsage: "[label]",
+		Flags: []cli.Flag{
+			cmdcom.HistoryTxIDFlag,
+			cmdcom.HistoryAddressFlag,
+			cmdcom.AccountWalletFlag,
+		},
+		Action: setLabel,
+	},
+}
+
+// historyRecord is a transaction of the history to show and export.
+type historyRecord struct {
+	TxID          string           `json:"txid"`
+	Height        uint32           `json:"height"`
+	Time          string           `json:"time"`
+	Type          string           `json:"type"`
+	Amount        string           `json:"amount"`
+	Fee           string           `json:"fee"`
+	Confirmations uint32           `json:"confirmations"`
+	Status        string           `json:"status"`
+	Label         string           `json:"label"`
+	Addresses     []historyAddress `json:"addresses"`
+}
+
+type historyAddress struct {
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+}
+
+func openWalletHistory(c *cli.Context) (*account.History, error) {
+	walletPath := c.String("wallet")
+	if exist := utils.FileExisted(walletPath); !exist {
+		return nil, errors.New(walletPath + " is not found")
+	}
+	return account.OpenHistory(account.HistoryPath(walletPath))
+}
+
+func showHistory(c *cli.Context) error {
+	walletPath := c.String("wallet")
+	history, err := openWalletHistory(c)
+	if err != nil {
+		return err
+	}
+	startHeight := uint32(c.Uint("startheight"))
+	if c.Bool("rescan") {
+		if !c.IsSet("startheight") {
+			startHeight = history.CreationHeight
+		}
+		history.Reset(startHeight)
+	} else if c.IsSet("startheight") {
+		if len(history.BlockHashes) > 0 {
+			return errors.New("history has been synced, use --rescan to change the start height")
+		}
+		history.StartHeight = startHeight
+	}
+
+	// the local history is shown if the node is not available.
+	if err := syncHistory(walletPath, history); err != nil {
+		fmt.Println("warning: sync history failed,", err)
+	}
+	bestHeight := history.Height
+
+	address := c.String("address")
+	records := make([]*historyRecord, 0, len(history.Transactions))
+	for _, tx := range history.Transactions {
+		if address != "" && !containsString(tx.Addresses, address) {
+			continue
+		}
+		records = append(records, newHistoryRecord(history, tx, bestHeight))
+	}
+	if err := history.Save(); err != nil {
+		return err
+	}
+
+	format := c.String("format")
+	if exportPath := c.String("export"); exportPath != "" {
+		if format != "csv" && format != "json" {
+			format = "csv"
+			if strings.ToLower(filepath.Ext(exportPath)) == ".json" {
+				format = "json"
+			}
+		}
+		file, err := os.OpenFile(exportPath,
+			os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := writeHistory(file, format, records); err != nil {
+			return err
+		}
+		fmt.Println("File: ", exportPath)
+		return nil
+	}
+
+	if format == "table" {
+		showHistoryTable(history, address, records)
+		return nil
+	}
+	return writeHistory(os.Stdout, format, records)
+}
+
+// saveCreationHeight saves the best height of the node as the creation height
+// of the new wallet, the history is synced from it instead of the genesis
+// block. The history is synced from height 0 if the node is not available.
+func saveCreationHeight(walletPath string) {
+	height, err := getCurrentHeight()
+	if err != nil {
+		fmt.Println("warning: get current height failed, the history will "+
+			"be synced from height 0,", err)
+		return
+	}
+	history, err := account.OpenHistory(account.HistoryPath(walletPath))
+	if err != nil {
+		fmt.Println("warning: open history failed,", err)
+		return
+	}
+	history.Reset(height)
+	history.CreationHeight = height
+	if err := history.Save(); err != nil {
+		fmt.Println("warning: save history failed,", err)
+	}
+}
+
+// clearCreationHeight clears the creation height of the wallet after the
+// accounts created before it are added, so that the first sync or a rescan
+// finds their old transactions.
+func clearCreationHeight(walletPath string) {
+	path := account.HistoryPath(walletPath)
+	if !utils.FileExisted(path) {
+		return
+	}
+	history, err := account.OpenHistory(path)
+	if err != nil {
+		fmt.Println("warning: open history failed,", err)
+		return
+	}
+	if history.CreationHeight == 0 {
+		return
+	}
+	history.CreationHeight = 0
+	if len(history.BlockHashes) == 0 {
+		history.StartHeight = 0
+	}
+	if err := history.Save(); err != nil {
+		fmt.Println("warning: save history failed,", err)
+	}
+}
+
+// syncHistory adds the blocks from the next height of the history to the
+// best height of the node, the history is rolled back first if the chain has
+// been reorganized.
+func syncHistory(walletPath string, history *account.History) error {
+	storeAccounts, err := account.GetWalletAccountData(walletPath)
+	if err != nil {
+		return err
+	}
+	addresses := make(map[common.Uint168]string, len(storeAccounts))
+	for _, a := range storeAccounts {
+		programHash, err := common.Uint168FromAddress(a.Address)
+		if err != nil {
+			return err
+		}
+		addresses[*programHash] = a.Address
+	}
+
+	bestHeight, err := getCurrentHeight()
+	if err != nil {
+		return err
+	}
+	if err := rollbackHistory(history, bestHeight); err != nil {
+		return err
+	}
+
+	for height := history.NextHeight(); height <= bestHeight; height++ {
+		block, err := getBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		err = history.AddBlock(block, addresses)
+		if err == account.ErrHistoryFork {
+			if err := rollbackHistory(history, bestHeight); err != nil {
+				return err
+			}
+			height = history.NextHeight() - 1
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if height%historySaveInterval == 0 {
+			if err := history.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("synced height %d / %d\n", height, bestHeight)
+		}
+	}
+
+	updateFinality(history)
+	return history.Save()
+}
+
+// rollbackHistory rolls back the history to the latest synced block which is
+// still on the chain of the node.
+func rollbackHistory(history *account.History, bestHeight uint32) error {
+	if len(history.BlockHashes) == 0 {
+		return nil
+	}
+	height := history.Height
+	if height > bestHeight {
+		height = bestHeight
+	}
+	for {
+		synced, ok := history.BlockHash(height)
+		if !ok {
+			return errors.New("the chain is reorganized before the synced " +
+				"blocks kept, use --rescan to sync again")
+		}
+		hash, err := getBlockHash(height)
+		if err != nil {
+			return err
+		}
+		if hash == synced {
+			return history.Rollback(height)
+		}
+		if height == 0 {
+			return errors.New("genesis block mismatch, use --rescan to sync again")
+		}
+		height--
+	}
+}
+
+// updateFinality checks the DPoS finality of the transactions not finalized
+// yet.
+func updateFinality(history *account.History) {
+	for _, tx := range history.Transactions {
+		if tx.Finalized {
+			continue
+		}
+		result, err := cmdcom.RPCCall("gettransactionstatus", http.Params{
+			"txid": tx.TxID,
+		})
+		if err != nil {
+			continue
+		}
+		status, ok := result.(map[string]interface{})
+		if ok && status["status"] == servers.TxStatusFinalized {
+			tx.Finalized = true
+		}
+	}
+}
+
+func getBlockHash(height uint32) (string, error) {
+	result, err := cmdcom.RPCCall("getblockhash", http.Params{
+		"height": height,
+	})
+	if err != nil {
+		return "", err
+	}
+	hash, ok := result.(string)
+	if !ok {
+		return "", errors.New("invalid block hash")
+	}
+	return hash, nil
+}
+
+func getBlockByHeight(height uint32) (*types.Block, error) {
+	hash, err := getBlockHash(height)
+	if err != nil {
+		return nil, err
+	}
+	result, err := cmdcom.RPCCall("getblock", http.Params{
+		"blockhash": hash,
+		"verbosity": 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+	blockHex, ok := result.(string)
+	if !ok {
+		return nil, errors.New("invalid block data")
+	}
+	blockBytes, err := common.HexStringToBytes(blockHex)
+	if err != nil {
+		return nil, err
+	}
+	var block types.Block
+	if err := block.Deserialize(bytes.NewReader(blockBytes)); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func newHistoryRecord(history *account.History, tx *account.HistoryTx,
+	bestHeight uint32) *historyRecord {
+	record := &historyRecord{
+		TxID:      tx.TxID,
+		Height:    tx.Height,
+		Time:      time.Unix(int64(tx.Timestamp), 0).UTC().Format(time.RFC3339),
+		Type:      tx.Type,
+		Amount:    tx.Amount.String(),
+		Fee:       tx.Fee.String(),
+		Status:    servers.TxStatusMined,
+		Label:     history.TxLabels[tx.TxID],
+		Addresses: make([]historyAddress, 0, len(tx.Addresses)),
+	}
+	if bestHeight >= tx.Height {
+		record.Confirmations = bestHeight - tx.Height + 1
+	}
+	if tx.Finalized {
+		record.Status = servers.TxStatusFinalized
+	}
+	for _, address := range tx.Addresses {
+		record.Addresses = append(record.Addresses, historyAddress{
+			Address: address,
+			Label:   history.AddressLabels[address],
+		})
+	}
+	return record
+}
+
+func writeHistory(w io.Writer, format string, records []*historyRecord) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(records, "", "\t")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"txid", "height", "time", "type", "amount",
+			"fee", "confirmations", "status", "label", "addresses",
+			"address labels"})
+		for _, r := range records {
+			addresses := make([]string, 0, len(r.Addresses))
+			labels := make([]string, 0, len(r.Addresses))
+			for _, a := range r.Addresses {
+				addresses = append(addresses, a.Address)
+				labels = append(labels, a.Label)
+			}
+			writer.Write([]string{r.TxID, strconv.Itoa(int(r.Height)), r.Time,
+				r.Type, r.Amount, r.Fee, strconv.Itoa(int(r.Confirmations)),
+				r.Status, r.Label, strings.Join(addresses, ";"),
+				strings.Join(labels, ";")})
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	return errors.New("invalid format " + format + ", table, csv or json expected")
+}
+
+func showHistoryTable(history *account.History, address string,
+	records []*historyRecord) {
+	fmt.Printf("%-64s %8s %-20s %-20s %13s %-9s %s\n", "TXID", "HEIGHT",
+		"TIME", "AMOUNT", "CONFIRMATIONS", "STATUS", "LABEL")
+	fmt.Println(strings.Repeat("-", 64), strings.Repeat("-", 8),
+		strings.Repeat("-", 20), strings.Repeat("-", 20),
+		strings.Repeat("-", 13), strings.Repeat("-", 9), strings.Repeat("-", 5))
+	for _, r := range records {
+		fmt.Printf("%-64s %8d %-20s %-20s %13d %-9s %s\n", r.TxID, r.Height,
+			r.Time, r.Amount, r.Confirmations, r.Status, r.Label)
+	}
+	fmt.Println("SYNCED HEIGHT:", history.Height)
+	fmt.Println("BALANCE:", history.Balance(address).String())
+}
+
+func setLabel(c *cli.Context) error {
+	history, err := openWalletHistory(c)
+	if err != nil {
+		return err
+	}
+	txID := c.String("txid")
+	address := c.String("address")
+	if txID != "" && address != "" {
+		return errors.New("'--address' cannot be specified when specify '--txid' option")
+	}
+	if txID == "" && address == "" {
+		showLabels(history)
+		return nil
+	}
+	if c.NArg() > 1 {
+		return errors.New("too many arguments, quote the label with spaces")
+	}
+
+	label := c.Args().First()
+	if txID != "" {
+		if _, err := parseTxID(txID); err != nil {
+			return errors.New("invalid txid")
+		}
+		history.SetTxLabel(txID, label)
+	} else {
+		if _, err := common.Uint168FromAddress(address); err != nil {
+			return errors.New("invalid address")
+		}
+		history.SetAddressLabel(address, label)
+	}
+	return history.Save()
+}
+
+func showLabels(history *account.History) {
+	fmt.Printf("%-64s %s\n", "TXID / ADDRESS", "LABEL")
+	fmt.Println(strings.Repeat("-", 64), strings.Repeat("-", 5))
+	for _, labels := range []map[string]string{history.AddressLabels,
+		history.TxLabels} {
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%-64s %s\n", key, labels[key])
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
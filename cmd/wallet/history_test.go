@@ -0,0 +1,78 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/account"
+	htp "github.com/elastos/Elastos.ELA/utils/http"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// captureStdout returns the printed output of the function.
+func captureStdout(t *testing.T, f func() error) (string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	err = f()
+	os.Stdout = stdout
+	w.Close()
+	output, _ := ioutil.ReadAll(r)
+	return string(output), err
+}
+
+func TestCreationHeight(t *testing.T) {
+	walletPath := filepath.Join(t.TempDir(), account.KeystoreFileName)
+	historyPath := account.HistoryPath(walletPath)
+	openHistory := func() *account.History {
+		history, err := account.OpenHistory(historyPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return history
+	}
+
+	// the history is synced from height 0 if the node is not available
+	setRPCPort("1")
+	t.Cleanup(func() { setRPCPort("20336") })
+	captureStdout(t, func() error {
+		saveCreationHeight(walletPath)
+		return nil
+	})
+	assert.Equal(t, uint32(0), openHistory().NextHeight())
+
+	// the history of a new wallet is synced from the creation height
+	newTestNode(t, map[string]rpcHandler{
+		"getcurrentheight": func(htp.Params) (interface{}, error) {
+			return 1234, nil
+		},
+	})
+	saveCreationHeight(walletPath)
+	history := openHistory()
+	assert.Equal(t, uint32(1234), history.CreationHeight)
+	assert.Equal(t, uint32(1234), history.NextHeight())
+
+	// the old accounts are added before the first sync
+	clearCreationHeight(walletPath)
+	history = openHistory()
+	assert.Equal(t, uint32(0), history.CreationHeight)
+	assert.Equal(t, uint32(0), history.NextHeight())
+
+	// the synced history is kept until rescanned
+	saveCreationHeight(walletPath)
+	history = openHistory()
+	history.Height = 1300
+	history.BlockHashes = []string{testTxID(1)}
+	assert.NoError(t, history.Save())
+	clearCreationHeight(walletPath)
+	history = openHistory()
+	assert.Equal(t, uint32(0), history.CreationHeight)
+	assert.Equal(t, uint32(1234), history.StartHeight)
+	assert.Equal(t, uint32(1301), history.NextHeight())
+}
@@ -0,0 +1,549 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+
+	"github.com/elastos/Elastos.ELA/account"
+	"github.com/elastos/Elastos.ELA/blockchain"
+	cmdcom "github.com/elastos/Elastos.ELA/cmd/common"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/contract"
+	pg "github.com/elastos/Elastos.ELA/core/contract/program"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/outputpayload"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/crypto"
+	"github.com/elastos/Elastos.ELA/dpos/state"
+	"github.com/elastos/Elastos.ELA/servers"
+	"github.com/elastos/Elastos.ELA/utils/http"
+
+	"github.com/urfave/cli"
+)
+
+// producerFundFlags are the flags to pay the fee of the producer
+// transactions.
+var producerFundFlags = []cli.Flag{
+	cmdcom.TransactionFromFlag,
+	cmdcom.TransactionFeeFlag,
+	cmdcom.TransactionFeeRateFlag,
+	cmdcom.TransactionStrategyFlag,
+	cmdcom.TransactionInputsFlag,
+	cmdcom.AccountWalletFlag,
+	cmdcom.AccountPasswordFlag,
+}
+
+var producerInfoFlags = []cli.Flag{
+	cmdcom.ProducerOwnerPublicKeyFlag,
+	cmdcom.ProducerNodePublicKeyFlag,
+	cmdcom.ProducerNickNameFlag,
+	cmdcom.ProducerUrlFlag,
+	cmdcom.ProducerLocationFlag,
+	cmdcom.ProducerNetAddressFlag,
+}
+
+var producerCommand = []cli.Command{
+	{
+		Category: "Producer",
+		Name:     "producer",
+		Usage:    "Register, update or cancel a producer and return the deposit",
+		Subcommands: []cli.Command{
+			{
+				Name:        "register",
+				Usage:       "Register a producer with the deposit",
+				Description: "the deposit is sent to the deposit address of the owner public key",
+				Flags: append(append(append([]cli.Flag{}, producerInfoFlags...),
+					cmdcom.TransactionAmountFlag), producerFundFlags...),
+				Action: producerAction(registerProducer),
+			},
+			{
+				Name:        "update",
+				Usage:       "Update the information of a producer",
+				Description: "the unspecified fields keep the registered values",
+				Flags: append(append([]cli.Flag{}, producerInfoFlags...),
+					producerFundFlags...),
+				Action: producerAction(updateProducer),
+			},
+			{
+				Name:  "cancel",
+				Usage: "Cancel a producer",
+				Flags: append([]cli.Flag{cmdcom.ProducerOwnerPublicKeyFlag},
+					producerFundFlags...),
+				Action: producerAction(cancelProducer),
+			},
+			{
+				Name:        "returndeposit",
+				Usage:       "Return the deposit of a canceled producer",
+				Description: "the deposit minus the penalty and the fee is returned to the owner address if --to is not specified",
+				Flags: []cli.Flag{
+					cmdcom.ProducerOwnerPublicKeyFlag,
+					cmdcom.TransactionToFlag,
+					cmdcom.TransactionAmountFlag,
+					cmdcom.TransactionFeeFlag,
+					cmdcom.TransactionFeeRateFlag,
+					cmdcom.AccountWalletFlag,
+					cmdcom.AccountPasswordFlag,
+				},
+				Action: producerAction(returnDeposit),
+			},
+		},
+	},
+}
+
+func producerAction(action func(c *cli.Context,
+	client *account.Client) error) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if c.NumFlags() == 0 {
+			cli.ShowSubcommandHelp(c)
+			return nil
+		}
+		password, err := cmdcom.GetFlagPassword(c)
+		if err != nil {
+			return err
+		}
+		client, err := account.Open(c.String("wallet"), password)
+		if err != nil {
+			return err
+		}
+		return action(c, client)
+	}
+}
+
+// getOwnerAccount returns the account of the owner public key, which must
+// hold the private key to sign the payload.
+func getOwnerAccount(c *cli.Context, client *account.Client) (*account.Account,
+	[]byte, error) {
+	var ownerPublicKey []byte
+	if publicKeyStr := c.String("ownerpublickey"); publicKeyStr != "" {
+		var err error
+		ownerPublicKey, err = common.HexStringToBytes(publicKeyStr)
+		if err != nil {
+			return nil, nil, errors.New("invalid owner public key")
+		}
+	} else {
+		mainAccount := client.GetMainAccount()
+		if mainAccount == nil || mainAccount.PublicKey == nil {
+			return nil, nil, errors.New("use --ownerpublickey to specify the owner public key")
+		}
+		var err error
+		ownerPublicKey, err = mainAccount.PublicKey.EncodePoint(true)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	codeHash, err := contract.PublicKeyToStandardCodeHash(ownerPublicKey)
+	if err != nil {
+		return nil, nil, errors.New("invalid owner public key")
+	}
+	acc := client.GetAccountByCodeHash(*codeHash)
+	if acc == nil || acc.IsWatchOnly() {
+		return nil, nil, errors.New("private key of the owner public key is not in wallet")
+	}
+	return acc, ownerPublicKey, nil
+}
+
+// getDepositAddress returns the deposit address of the owner public key.
+func getDepositAddress(ownerPublicKey []byte) (string, error) {
+	programHash, err := contract.PublicKeyToDepositProgramHash(ownerPublicKey)
+	if err != nil {
+		return "", err
+	}
+	return programHash.ToAddress()
+}
+
+// getProducer returns the registered producer of the owner public key.
+func getProducer(ownerPublicKey []byte) (*servers.Producer, error) {
+	result, err := cmdcom.RPCCall("listproducers", http.Params{
+		"state": "all",
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var producers servers.Producers
+	if err := json.Unmarshal(data, &producers); err != nil {
+		return nil, err
+	}
+	owner := common.BytesToHexString(ownerPublicKey)
+	for _, p := range producers.Producers {
+		if p.OwnerPublicKey == owner {
+			return &p, nil
+		}
+	}
+	return nil, errors.New("producer of owner public key " + owner +
+		" is not registered")
+}
+
+// newProducerInfo creates the signed producer information, the default values
+// are used for the unspecified fields.
+func newProducerInfo(c *cli.Context, owner *account.Account,
+	ownerPublicKey []byte, defaults *servers.Producer) (*payload.ProducerInfo,
+	error) {
+	info := &payload.ProducerInfo{
+		OwnerPublicKey: ownerPublicKey,
+		NodePublicKey:  ownerPublicKey,
+		NickName:       c.String("nickname"),
+		Url:            c.String("url"),
+		Location:       c.Uint64("location"),
+		NetAddress:     c.String("netaddress"),
+	}
+	if nodePublicKeyStr := c.String("nodepublickey"); nodePublicKeyStr != "" {
+		nodePublicKey, err := common.HexStringToBytes(nodePublicKeyStr)
+		if err != nil {
+			return nil, errors.New("invalid node public key")
+		}
+		info.NodePublicKey = nodePublicKey
+	} else if defaults != nil {
+		nodePublicKey, err := common.HexStringToBytes(defaults.NodePublicKey)
+		if err != nil {
+			return nil, errors.New("invalid registered node public key")
+		}
+		info.NodePublicKey = nodePublicKey
+	}
+	if defaults != nil {
+		if !c.IsSet("nickname") {
+			info.NickName = defaults.Nickname
+		}
+		if !c.IsSet("url") {
+			info.Url = defaults.Url
+		}
+		if !c.IsSet("location") {
+			info.Location = defaults.Location
+		}
+		if !c.IsSet("netaddress") {
+			info.NetAddress = defaults.NetAddress
+		}
+	}
+	if _, err := crypto.DecodePoint(info.NodePublicKey); err != nil {
+		return nil, errors.New("invalid node public key")
+	}
+	if info.NickName == "" {
+		return nil, errors.New("use --nickname to specify the nickname")
+	}
+	if info.Url == "" {
+		return nil, errors.New("use --url to specify the url")
+	}
+	if len(info.NickName) > blockchain.MaxStringLength ||
+		len(info.Url) > blockchain.MaxStringLength {
+		return nil, fmt.Errorf("nickname and url can not be longer than %d",
+			blockchain.MaxStringLength)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := info.SerializeUnsigned(buf, payload.ProducerInfoVersion); err != nil {
+		return nil, err
+	}
+	signature, err := owner.Sign(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	info.Signature = signature
+	return info, nil
+}
+
+func registerProducer(c *cli.Context, client *account.Client) error {
+	owner, ownerPublicKey, err := getOwnerAccount(c, client)
+	if err != nil {
+		return err
+	}
+	info, err := newProducerInfo(c, owner, ownerPublicKey, nil)
+	if err != nil {
+		return err
+	}
+
+	amount := common.Fixed64(blockchain.MinDepositAmount)
+	if amountStr := c.String("amount"); amountStr != "" {
+		value, err := common.StringToFixed64(amountStr)
+		if err != nil {
+			return errors.New("invalid deposit amount")
+		}
+		amount = *value
+	}
+	if amount < blockchain.MinDepositAmount {
+		return errors.New("deposit amount can not be less than " +
+			common.Fixed64(blockchain.MinDepositAmount).String())
+	}
+	depositHash, err := contract.PublicKeyToDepositProgramHash(ownerPublicKey)
+	if err != nil {
+		return err
+	}
+	depositAddress, err := depositHash.ToAddress()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Deposit address:", depositAddress)
+
+	return fundAndSendTransaction(c, client, newSpecialTransaction(
+		types.RegisterProducer, info, []*types.Output{{
+			AssetID:     *account.SystemAssetID,
+			Value:       amount,
+			OutputLock:  0,
+			ProgramHash: *depositHash,
+			Type:        types.OTNone,
+			Payload:     &outputpayload.DefaultOutput{},
+		}}))
+}
+
+func updateProducer(c *cli.Context, client *account.Client) error {
+	owner, ownerPublicKey, err := getOwnerAccount(c, client)
+	if err != nil {
+		return err
+	}
+	producer, err := getProducer(ownerPublicKey)
+	if err != nil {
+		return err
+	}
+	info, err := newProducerInfo(c, owner, ownerPublicKey, producer)
+	if err != nil {
+		return err
+	}
+
+	return fundAndSendTransaction(c, client, newSpecialTransaction(
+		types.UpdateProducer, info, []*types.Output{}))
+}
+
+func cancelProducer(c *cli.Context, client *account.Client) error {
+	owner, ownerPublicKey, err := getOwnerAccount(c, client)
+	if err != nil {
+		return err
+	}
+	if _, err := getProducer(ownerPublicKey); err != nil {
+		return err
+	}
+
+	processProducer := &payload.ProcessProducer{
+		OwnerPublicKey: ownerPublicKey,
+	}
+	buf := new(bytes.Buffer)
+	if err := processProducer.SerializeUnsigned(buf,
+		payload.ProcessProducerVersion); err != nil {
+		return err
+	}
+	processProducer.Signature, err = owner.Sign(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return fundAndSendTransaction(c, client, newSpecialTransaction(
+		types.CancelProducer, processProducer, []*types.Output{}))
+}
+
+// returnDeposit spends all the UTXOs of the deposit address after the lockup
+// blocks since the producer is canceled, the penalty of the producer is
+// deducted from the returned amount.
+func returnDeposit(c *cli.Context, client *account.Client) error {
+	owner, ownerPublicKey, err := getOwnerAccount(c, client)
+	if err != nil {
+		return err
+	}
+	producer, err := getProducer(ownerPublicKey)
+	if err != nil {
+		return err
+	}
+	if producer.State != state.Canceled.String() {
+		return errors.New("producer must be canceled before return deposit, " +
+			"the current state is " + producer.State)
+	}
+	height, err := getCurrentHeight()
+	if err != nil {
+		return err
+	}
+	if height-producer.CancelHeight < blockchain.DepositLockupBlocks {
+		return fmt.Errorf("deposit can be returned after %d blocks",
+			blockchain.DepositLockupBlocks-(height-producer.CancelHeight))
+	}
+
+	depositAddress, err := getDepositAddress(ownerPublicKey)
+	if err != nil {
+		return err
+	}
+	utxos, _, err := getAddressUTXOs(depositAddress)
+	if err != nil {
+		return err
+	}
+	if len(utxos) == 0 {
+		return errors.New("no deposit found in " + depositAddress)
+	}
+
+	to := c.String("to")
+	if to == "" {
+		to = owner.Address
+	}
+	recipient, err := common.Uint168FromAddress(to)
+	if err != nil {
+		return errors.New("invalid receiver address: " + to)
+	}
+	output := &types.Output{
+		AssetID:     *account.SystemAssetID,
+		OutputLock:  0,
+		ProgramHash: *recipient,
+		Type:        types.OTNone,
+		Payload:     &outputpayload.DefaultOutput{},
+	}
+
+	txn := newSpecialTransaction(types.ReturnDepositCoin,
+		&payload.ReturnDepositCoin{}, []*types.Output{output})
+	txn.Programs = createPrograms(owner.RedeemScript)
+
+	opts, err := parseSelectOptions(c)
+	if err != nil {
+		return err
+	}
+	fee := opts.Fee
+	if fee == nil {
+		baseFee, inputFee, _, err := estimateFees(txn, &account.AccountData{
+			Address:      depositAddress,
+			RedeemScript: common.BytesToHexString(owner.RedeemScript),
+		}, output, opts.FeeRate)
+		if err != nil {
+			return err
+		}
+		value := baseFee + inputFee*common.Fixed64(len(utxos))
+		fee = &value
+	}
+
+	var total common.Fixed64
+	for _, u := range utxos {
+		txID, err := parseTxID(u.TxID)
+		if err != nil {
+			return err
+		}
+		amount, err := common.StringToFixed64(u.Amount)
+		if err != nil {
+			return err
+		}
+		total += *amount
+		txn.Inputs = append(txn.Inputs, &types.Input{
+			Previous: types.OutPoint{TxID: *txID, Index: uint16(u.VOut)},
+			Sequence: math.MaxUint32,
+		})
+	}
+
+	penalty, err := common.StringToFixed64(producer.Penalty)
+	if err != nil {
+		return errors.New("invalid producer penalty " + producer.Penalty)
+	}
+	output.Value = total - *penalty - *fee
+	if amountStr := c.String("amount"); amountStr != "" {
+		amount, err := common.StringToFixed64(amountStr)
+		if err != nil {
+			return errors.New("invalid transaction amount")
+		}
+		if *amount > output.Value {
+			return errors.New("amount can not be more than the deposit " +
+				total.String() + " minus the penalty " + penalty.String() +
+				" and the fee " + fee.String())
+		}
+		output.Value = *amount
+	}
+	if output.Value <= 0 {
+		return errors.New("deposit " + total.String() +
+			" is not enough to pay the penalty " + penalty.String() +
+			" and the fee " + fee.String())
+	}
+
+	return signAndSendTransaction(client, txn)
+}
+
+func newSpecialTransaction(txType types.TxType, txPayload types.Payload,
+	outputs []*types.Output) *types.Transaction {
+	txAttr := types.NewAttribute(types.Nonce,
+		[]byte(strconv.FormatInt(rand.Int63(), 10)))
+	return &types.Transaction{
+		Version:    types.TxVersion09,
+		TxType:     txType,
+		Payload:    txPayload,
+		Attributes: []*types.Attribute{&txAttr},
+		Outputs:    outputs,
+		Programs:   []*pg.Program{},
+		LockTime:   0,
+	}
+}
+
+// fundAndSendTransaction pays the outputs and the fee of the transaction from
+// the sender, then signs and sends the transaction.
+func fundAndSendTransaction(c *cli.Context, client *account.Client,
+	txn *types.Transaction) error {
+	opts, err := parseSelectOptions(c)
+	if err != nil {
+		return err
+	}
+	sender, err := getSender(c.String("wallet"), c.String("from"))
+	if err != nil {
+		return err
+	}
+	redeemScript, err := common.HexStringToBytes(sender.RedeemScript)
+	if err != nil {
+		return err
+	}
+	txn.Programs = createPrograms(redeemScript)
+	if err := fundTransaction(txn, sender, opts); err != nil {
+		return err
+	}
+	return signAndSendTransaction(client, txn)
+}
+
+// signAndSendTransaction signs the transaction by the wallet and sends it if
+// it's fully signed.
+func signAndSendTransaction(client *account.Client,
+	txn *types.Transaction) error {
+	if err := signFully(client, txn); err != nil {
+		return err
+	}
+	txID, err := sendTransaction(txn)
+	if err != nil {
+		return err
+	}
+	fmt.Println(txID)
+	return nil
+}
+
+// signFully signs the transaction by the wallet, an error is returned if the
+// transaction is not fully signed.
+func signFully(client *account.Client, txn *types.Transaction) error {
+	if len(txn.Programs) == 0 {
+		return errors.New("redeem script of the sender is unknown")
+	}
+	if _, err := client.Sign(txn); err != nil {
+		return err
+	}
+	for _, program := range txn.Programs {
+		haveSign, needSign, err := crypto.GetSignStatus(program.Code,
+			program.Parameter)
+		if err != nil {
+			return err
+		}
+		if haveSign < needSign {
+			return fmt.Errorf("transaction is not fully signed [ %d / %d ]",
+				haveSign, needSign)
+		}
+	}
+	return nil
+}
+
+func sendTransaction(txn *types.Transaction) (string, error) {
+	buf := new(bytes.Buffer)
+	if err := txn.Serialize(buf); err != nil {
+		return "", err
+	}
+	result, err := cmdcom.RPCCall("sendrawtransaction", http.Params{
+		"data": common.BytesToHexString(buf.Bytes()),
+	})
+	if err != nil {
+		return "", err
+	}
+	txID, ok := result.(string)
+	if !ok {
+		return "", errors.New("invalid send result")
+	}
+	return txID, nil
+}
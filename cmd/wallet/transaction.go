@@ -22,14 +22,18 @@ var txCommand = []cli.Command{
 		Category:    "Transaction",
 		Name:        "buildtx",
 		Usage:       "Build a transaction",
-		Description: "use --to --amount --fee to create a transaction",
+		Description: "use --to --amount --fee (or --feerate) to create a transaction",
 		Flags: []cli.Flag{
 			cmdcom.TransactionFromFlag,
 			cmdcom.TransactionToFlag,
 			cmdcom.TransactionToManyFlag,
 			cmdcom.TransactionAmountFlag,
 			cmdcom.TransactionFeeFlag,
+			cmdcom.TransactionFeeRateFlag,
+			cmdcom.TransactionStrategyFlag,
+			cmdcom.TransactionInputsFlag,
 			//TransactionLockFlag,
+			cmdcom.TransactionOfflineFlag,
 			cmdcom.AccountWalletFlag,
 		},
 		Subcommands: buildTxCommand,
@@ -69,6 +73,36 @@ var txCommand = []cli.Command{
 		},
 		Action: showTx,
 	},
+	{
+		Category:    "Transaction",
+		Name:        "createptx",
+		Usage:       "Create a partially signed transaction file from a raw transaction",
+		Description: "use --file or --hex to specify the raw transaction file path or content",
+		Flags: []cli.Flag{
+			cmdcom.TransactionHexFlag,
+			cmdcom.TransactionFileFlag,
+			cmdcom.AccountWalletFlag,
+		},
+		Action: createPartialTx,
+	},
+	{
+		Category:  "Transaction",
+		Name:      "mergeptx",
+		Usage:     "Merge the signatures of partially signed transaction files",
+		ArgsUsage: "<file> <file> [file...]",
+		Action:    mergePartialTx,
+	},
+	{
+		Category:    "Transaction",
+		Name:        "finalizeptx",
+		Usage:       "Finalize a fully signed partially signed transaction file into a raw transaction",
+		Description: "use --file or --hex to specify the partially signed transaction file path or content",
+		Flags: []cli.Flag{
+			cmdcom.TransactionHexFlag,
+			cmdcom.TransactionFileFlag,
+		},
+		Action: finalizePartialTx,
+	},
 }
 
 var buildTxCommand = []cli.Command{
@@ -100,6 +134,10 @@ var buildTxCommand = []cli.Command{
 			cmdcom.TransactionAmountFlag,
 			cmdcom.TransactionFromFlag,
 			cmdcom.TransactionFeeFlag,
+			cmdcom.TransactionFeeRateFlag,
+			cmdcom.TransactionStrategyFlag,
+			cmdcom.TransactionInputsFlag,
+			cmdcom.TransactionOfflineFlag,
 			cmdcom.AccountWalletFlag,
 			cmdcom.AccountPasswordFlag,
 		},
@@ -115,6 +153,35 @@ var buildTxCommand = []cli.Command{
 			return nil
 		},
 	},
+	{
+		Name:        "crosschain",
+		Usage:       "Build a tx to deposit ELA to a side chain",
+		Description: "use --sidechain --to --amount --fee (or --feerate) to deposit to the recipient on the side chain",
+		Flags: []cli.Flag{
+			cmdcom.CrossChainSideChainFlag,
+			cmdcom.TransactionToFlag,
+			cmdcom.TransactionAmountFlag,
+			cmdcom.CrossChainFeeFlag,
+			cmdcom.TransactionFromFlag,
+			cmdcom.TransactionFeeFlag,
+			cmdcom.TransactionFeeRateFlag,
+			cmdcom.TransactionStrategyFlag,
+			cmdcom.TransactionInputsFlag,
+			cmdcom.TransactionOfflineFlag,
+			cmdcom.AccountWalletFlag,
+		},
+		Action: func(c *cli.Context) error {
+			if c.NumFlags() == 0 {
+				cli.ShowSubcommandHelp(c)
+				return nil
+			}
+			if err := CreateCrossChainTransaction(c); err != nil {
+				fmt.Println("error:", err)
+				os.Exit(1)
+			}
+			return nil
+		},
+	},
 }
 
 func getTransactionHex(c *cli.Context) (string, error) {
@@ -162,6 +229,9 @@ func signTx(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if isPartialTx(txHex) {
+		return signPartialTx(client, txHex)
+	}
 	rawData, err := common.HexStringToBytes(txHex)
 	if err != nil {
 		return errors.New("decode transaction content failed")
@@ -198,6 +268,116 @@ func signTx(c *cli.Context) error {
 	return nil
 }
 
+func signPartialTx(client *account.Client, content string) error {
+	p, err := parsePartialTx(content)
+	if err != nil {
+		return err
+	}
+	if p.Completed() {
+		return errors.New("transaction was fully signed, no need more sign")
+	}
+	if err := ShowPartialTx(p); err != nil {
+		return err
+	}
+
+	if _, err := client.SignPartialTx(p); err != nil {
+		return err
+	}
+	haveSign, needSign := p.SignStatus()
+	fmt.Println("[", haveSign, "/", needSign, "] Transaction was successfully signed")
+
+	if !p.Completed() {
+		return OutputPartialTx(p)
+	}
+	txn, err := p.Finalize()
+	if err != nil {
+		return err
+	}
+	return OutputTx(haveSign, needSign, txn)
+}
+
+func createPartialTx(c *cli.Context) error {
+	if c.NumFlags() == 0 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+
+	txHex, err := getTransactionHex(c)
+	if err != nil {
+		return err
+	}
+	txBytes, err := common.HexStringToBytes(txHex)
+	if err != nil {
+		return errors.New("decode transaction content failed")
+	}
+	var txn types.Transaction
+	if err := txn.Deserialize(bytes.NewReader(txBytes)); err != nil {
+		return errors.New("deserialize transaction failed")
+	}
+
+	p, err := newPartialTx(c.String("wallet"), &txn)
+	if err != nil {
+		return err
+	}
+	if err := ShowPartialTx(p); err != nil {
+		return err
+	}
+	return OutputPartialTx(p)
+}
+
+func mergePartialTx(c *cli.Context) error {
+	if c.NArg() < 2 {
+		cmdcom.PrintErrorMsg("Missing argument. Two partially signed transaction files expected at least.")
+		cli.ShowCommandHelpAndExit(c, "mergeptx", 1)
+	}
+
+	var merged *account.PartialTx
+	for _, filePath := range c.Args() {
+		content, err := cmdcom.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		p, err := parsePartialTx(content)
+		if err != nil {
+			return errors.New(filePath + ": " + err.Error())
+		}
+		if merged == nil {
+			merged = p
+			continue
+		}
+		if err := merged.Combine(p); err != nil {
+			return errors.New(filePath + ": " + err.Error())
+		}
+	}
+
+	if err := ShowPartialTx(merged); err != nil {
+		return err
+	}
+	return OutputPartialTx(merged)
+}
+
+func finalizePartialTx(c *cli.Context) error {
+	if c.NumFlags() == 0 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+
+	content, err := getTransactionHex(c)
+	if err != nil {
+		return err
+	}
+	p, err := parsePartialTx(content)
+	if err != nil {
+		return err
+	}
+	txn, err := p.Finalize()
+	if err != nil {
+		return err
+	}
+	haveSign, needSign := p.SignStatus()
+	return OutputTx(haveSign, needSign, txn)
+}
+
 func sendTx(c *cli.Context) error {
 	if c.NumFlags() == 0 {
 		cli.ShowSubcommandHelp(c)
@@ -208,6 +388,21 @@ func sendTx(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if isPartialTx(txHex) {
+		p, err := parsePartialTx(txHex)
+		if err != nil {
+			return err
+		}
+		txn, err := p.Finalize()
+		if err != nil {
+			return err
+		}
+		buf := new(bytes.Buffer)
+		if err := txn.Serialize(buf); err != nil {
+			return err
+		}
+		txHex = common.BytesToHexString(buf.Bytes())
+	}
 
 	result, err := cmdcom.RPCCall("sendrawtransaction", http.Params{"data": txHex})
 	if err != nil {
@@ -228,6 +423,14 @@ func showTx(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if isPartialTx(txHex) {
+		p, err := parsePartialTx(txHex)
+		if err != nil {
+			return err
+		}
+		fmt.Println(p.Transaction.String())
+		return ShowPartialTx(p)
+	}
 
 	txBytes, err := common.HexStringToBytes(txHex)
 	if err != nil {
@@ -2,7 +2,6 @@ package wallet
 
 import (
 	"bytes"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -28,14 +27,9 @@ type OutputInfo struct {
 func CreateTransaction(c *cli.Context) error {
 	walletPath := c.String("wallet")
 
-	feeStr := c.String("fee")
-	if feeStr == "" {
-		return errors.New("use --fee to specify transfer fee")
-	}
-
-	fee, err := common.StringToFixed64(feeStr)
+	opts, err := parseSelectOptions(c)
 	if err != nil {
-		return errors.New("invalid transaction fee")
+		return err
 	}
 
 	from := c.String("from")
@@ -79,14 +73,34 @@ func CreateTransaction(c *cli.Context) error {
 	}
 
 	var txn *types.Transaction
-	txn, err = createTransaction(walletPath, from, *fee, uint32(lock), outputs...)
+	txn, err = createTransaction(walletPath, from, opts, uint32(lock), outputs...)
 	if err != nil {
 		return errors.New("create transaction failed: " + err.Error())
 	}
 
-	OutputTx(0, 1, txn)
+	return outputUnsignedTx(c, txn)
+}
+
+// outputUnsignedTx outputs the transaction to be signed, a partially signed
+// transaction file with the referenced transactions is written to sign on an
+// offline machine if the offline flag is set.
+func outputUnsignedTx(c *cli.Context, txn *types.Transaction) error {
+	if !c.Bool("offline") {
+		if len(txn.Programs) == 0 {
+			return errors.New("redeem script of the sender is unknown, " +
+				"use --offline to build a partially signed transaction")
+		}
+		return OutputTx(0, 1, txn)
+	}
 
-	return nil
+	p, err := newPartialTx(c.String("wallet"), txn)
+	if err != nil {
+		return err
+	}
+	if err := ShowPartialTx(p); err != nil {
+		return err
+	}
+	return OutputPartialTx(p)
 }
 
 func getSender(walletPath string, from string) (*account.AccountData, error) {
@@ -118,68 +132,13 @@ func getSender(walletPath string, from string) (*account.AccountData, error) {
 	return sender, nil
 }
 
-func createInputs(sender *account.AccountData, totalAmount common.Fixed64) ([]*types.Input, []*types.Output, error) {
-	UTXOs, err := getUTXOsByAmount(sender.Address, totalAmount)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var txInputs []*types.Input
-	var changeOutputs []*types.Output
-	for _, utxo := range UTXOs {
-		txIDReverse, _ := hex.DecodeString(utxo.TxID)
-		txID, _ := common.Uint256FromBytes(common.BytesReverse(txIDReverse))
-		input := &types.Input{
-			Previous: types.OutPoint{
-				TxID:  *txID,
-				Index: uint16(utxo.VOut),
-			},
-			Sequence: 4294967295,
-		}
-		txInputs = append(txInputs, input)
-		amount, err := common.StringToFixed64(utxo.Amount)
-		if err != nil {
-			return nil, nil, err
-		}
-		programHash, err := common.Uint168FromAddress(sender.Address)
-		if err != nil {
-			return nil, nil, err
-		}
-		if *amount < totalAmount {
-			totalAmount -= *amount
-		} else if *amount == totalAmount {
-			totalAmount = 0
-			break
-		} else if *amount > totalAmount {
-			change := &types.Output{
-				AssetID:     *account.SystemAssetID,
-				Value:       *amount - totalAmount,
-				OutputLock:  uint32(0),
-				ProgramHash: *programHash,
-				Type:        types.OTNone,
-				Payload:     &outputpayload.DefaultOutput{},
-			}
-			changeOutputs = append(changeOutputs, change)
-			totalAmount = 0
-			break
-		}
-	}
-	if totalAmount > 0 {
-		return nil, nil, errors.New("[Wallet], Available token is not enough")
-	}
-
-	return txInputs, changeOutputs, nil
-}
-
-func createNormalOutputs(outputs []*OutputInfo, fee common.Fixed64, lockedUntil uint32) ([]*types.Output, common.Fixed64, error) {
-	var totalAmount = common.Fixed64(0) // The total amount will be spend
-	var txOutputs []*types.Output       // The outputs in transaction
-	totalAmount += fee                  // Add transaction fee
+func createNormalOutputs(outputs []*OutputInfo, lockedUntil uint32) ([]*types.Output, error) {
+	var txOutputs []*types.Output // The outputs in transaction
 
 	for _, output := range outputs {
 		recipient, err := common.Uint168FromAddress(output.Recipient)
 		if err != nil {
-			return nil, 0, errors.New(fmt.Sprint("invalid receiver address: ", output.Recipient, ", error: ", err))
+			return nil, errors.New(fmt.Sprint("invalid receiver address: ", output.Recipient, ", error: ", err))
 		}
 
 		txOutput := &types.Output{
@@ -190,11 +149,10 @@ func createNormalOutputs(outputs []*OutputInfo, fee common.Fixed64, lockedUntil
 			Type:        types.OTNone,
 			Payload:     &outputpayload.DefaultOutput{},
 		}
-		totalAmount += *output.Amount
 		txOutputs = append(txOutputs, txOutput)
 	}
 
-	return txOutputs, totalAmount, nil
+	return txOutputs, nil
 }
 
 func createVoteOutputs(output *OutputInfo, candidateList []string) ([]*types.Output, error) {
@@ -237,7 +195,7 @@ func createVoteOutputs(output *OutputInfo, candidateList []string) ([]*types.Out
 	return txOutputs, nil
 }
 
-func createTransaction(walletPath string, from string, fee common.Fixed64, lockedUntil uint32, outputs ...*OutputInfo) (*types.Transaction, error) {
+func createTransaction(walletPath string, from string, opts *selectOptions, lockedUntil uint32, outputs ...*OutputInfo) (*types.Transaction, error) {
 	// check output
 	if len(outputs) == 0 {
 		return nil, errors.New("invalid transaction target")
@@ -250,18 +208,28 @@ func createTransaction(walletPath string, from string, fee common.Fixed64, locke
 	}
 
 	// create outputs
-	txOutputs, totalAmount, err := createNormalOutputs(outputs, fee, lockedUntil)
+	txOutputs, err := createNormalOutputs(outputs, lockedUntil)
 	if err != nil {
 		return nil, err
 	}
 
-	// create inputs
-	txInputs, changeOutputs, err := createInputs(sender, totalAmount)
+	txn, err := newTransferTransaction(sender, txOutputs)
 	if err != nil {
 		return nil, err
 	}
-	txOutputs = append(txOutputs, changeOutputs...)
 
+	// create inputs and change
+	if err := fundTransaction(txn, sender, opts); err != nil {
+		return nil, err
+	}
+
+	return txn, nil
+}
+
+// newTransferTransaction creates the transfer transaction of the outputs to
+// be signed by the sender, the inputs and change are not funded yet.
+func newTransferTransaction(sender *account.AccountData,
+	outputs []*types.Output) (*types.Transaction, error) {
 	redeemScript, err := common.HexStringToBytes(sender.RedeemScript)
 	if err != nil {
 		return nil, err
@@ -271,24 +239,30 @@ func createTransaction(walletPath string, from string, fee common.Fixed64, locke
 	txAttributes := make([]*types.Attribute, 0)
 	txAttributes = append(txAttributes, &txAttr)
 
-	// create program
-	var txProgram = &pg.Program{
-		Code:      redeemScript,
-		Parameter: nil,
-	}
-
 	return &types.Transaction{
 		Version:    types.TxVersion09,
 		TxType:     types.TransferAsset,
 		Payload:    &payload.TransferAsset{},
 		Attributes: txAttributes,
-		Inputs:     txInputs,
-		Outputs:    txOutputs,
-		Programs:   []*pg.Program{txProgram},
+		Outputs:    outputs,
+		Programs:   createPrograms(redeemScript),
 		LockTime:   0,
 	}, nil
 }
 
+// createPrograms creates the program to be signed of the redeem script, the
+// redeem script of an account watched by address is unknown, the program is
+// added by the signer of the partially signed transaction.
+func createPrograms(redeemScript []byte) []*pg.Program {
+	if len(redeemScript) == 0 {
+		return []*pg.Program{}
+	}
+	return []*pg.Program{{
+		Code:      redeemScript,
+		Parameter: nil,
+	}}
+}
+
 func CreateActivateProducerTransaction(c *cli.Context) error {
 	walletPath := c.String("wallet")
 	password, err := cmdcom.GetFlagPassword(c)
@@ -346,16 +320,11 @@ func CreateActivateProducerTransaction(c *cli.Context) error {
 func CreateVoteTransaction(c *cli.Context) error {
 	walletPath := c.String("wallet")
 
-	feeStr := c.String("fee")
-	if feeStr == "" {
-		return errors.New("use --fee to specify transfer fee")
-	}
-	fee, err := common.StringToFixed64(feeStr)
+	opts, err := parseSelectOptions(c)
 	if err != nil {
-		return errors.New("invalid transaction fee")
+		return err
 	}
 
-	// calculate total amount
 	amountStr := c.String("amount")
 	if amountStr == "" {
 		return errors.New("use --amount to specify transfer amount")
@@ -364,7 +333,6 @@ func CreateVoteTransaction(c *cli.Context) error {
 	if err != nil {
 		return errors.New("invalid transaction amount")
 	}
-	totalAmount := *fee + *amount
 
 	// get sender from wallet by from address
 	from := c.String("from")
@@ -389,41 +357,15 @@ func CreateVoteTransaction(c *cli.Context) error {
 		return err
 	}
 
-	// create inputs
-	txInputs, changeOutputs, err := createInputs(sender, totalAmount)
+	txn, err := newTransferTransaction(sender, txOutputs)
 	if err != nil {
 		return err
 	}
-	txOutputs = append(txOutputs, changeOutputs...)
 
-	redeemScript, err := common.HexStringToBytes(sender.RedeemScript)
-	if err != nil {
+	// create inputs and change
+	if err := fundTransaction(txn, sender, opts); err != nil {
 		return err
 	}
 
-	// create attributes
-	txAttr := types.NewAttribute(types.Nonce, []byte(strconv.FormatInt(rand.Int63(), 10)))
-	txAttributes := make([]*types.Attribute, 0)
-	txAttributes = append(txAttributes, &txAttr)
-
-	// create program
-	var txProgram = &pg.Program{
-		Code:      redeemScript,
-		Parameter: nil,
-	}
-
-	txn := &types.Transaction{
-		Version:    types.TxVersion09,
-		TxType:     types.TransferAsset,
-		Payload:    &payload.TransferAsset{},
-		Attributes: txAttributes,
-		Inputs:     txInputs,
-		Outputs:    txOutputs,
-		Programs:   []*pg.Program{txProgram},
-		LockTime:   0,
-	}
-
-	OutputTx(0, 1, txn)
-
-	return nil
+	return outputUnsignedTx(c, txn)
 }
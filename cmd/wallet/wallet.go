@@ -8,6 +8,10 @@ func NewCommand() *cli.Command {
 	var subCommands []cli.Command
 	subCommands = append(subCommands, txCommand...)
 	subCommands = append(subCommands, accountCommand...)
+	subCommands = append(subCommands, historyCommand...)
+	subCommands = append(subCommands, producerCommand...)
+	subCommands = append(subCommands, crossChainCommand...)
+	subCommands = append(subCommands, daemonCommand...)
 
 	return &cli.Command{
 		Name:        "wallet",
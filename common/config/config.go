@@ -22,49 +22,81 @@ type PowConfiguration struct {
 
 // RpcConfiguration defines the JSON-RPC authenticate parameters.
 type RpcConfiguration struct {
-	User        string   `json:"User"`
-	Pass        string   `json:"Pass"`
-	WhiteIPList []string `json:"WhiteIPList"`
+	User        string    `json:"User"`
+	Pass        string    `json:"Pass"`
+	WhiteIPList []string  `json:"WhiteIPList"`
+	Users       []RpcUser `json:"Users"`
+}
+
+// RpcUser defines a JSON-RPC credential and the roles or methods it's allowed
+// to call.
+type RpcUser struct {
+	User    string   `json:"User"`
+	Pass    string   `json:"Pass"`
+	Roles   []string `json:"Roles"`
+	Methods []string `json:"Methods"`
+}
+
+// RateLimits defines the rate limits and concurrency caps of the JSON-RPC,
+// REST and websocket APIs.
+type RateLimits struct {
+	IP          Limits         `json:"IP"`
+	User        Limits         `json:"User"`
+	MethodCosts map[string]int `json:"MethodCosts"`
+}
+
+// Limits defines the rate limit and concurrency cap of each client.
+type Limits struct {
+	Rate          float64 `json:"Rate"`
+	Burst         float64 `json:"Burst"`
+	MaxConcurrent int     `json:"MaxConcurrent"`
 }
 
 // Configuration defines the configurable parameters to run a ELA node.
 type Configuration struct {
-	ActiveNet          string            `json:"ActiveNet"`
-	Magic              uint32            `json:"Magic"`
-	DNSSeeds           []string          `json:"DNSSeeds"`
-	DisableDNS         bool              `json:"DisableDNS"`
-	PermanentPeers     []string          `json:"PermanentPeers"`
-	HttpInfoPort       uint16            `json:"HttpInfoPort"`
-	HttpInfoStart      bool              `json:"HttpInfoStart"`
-	HttpRestPort       int               `json:"HttpRestPort"`
-	HttpRestStart      bool              `json:"HttpRestStart"`
-	HttpWsPort         int               `json:"HttpWsPort"`
-	HttpWsStart        bool              `json:"HttpWsStart"`
-	HttpJsonPort       int               `json:"HttpJsonPort"`
-	EnableRPC          bool              `json:"EnableRPC"`
-	NodePort           uint16            `json:"NodePort"`
-	PrintLevel         elalog.Level      `json:"PrintLevel"`
-	MaxLogsSize        int64             `json:"MaxLogsSize"`
-	MaxPerLogSize      int64             `json:"MaxPerLogSize"`
-	RestCertPath       string            `json:"RestCertPath"`
-	RestKeyPath        string            `json:"RestKeyPath"`
-	MinCrossChainTxFee common.Fixed64    `json:"MinCrossChainTxFee"`
-	FoundationAddress  string            `json:"FoundationAddress"`
-	CRCAddress         string            `json:"CRCAddress"`
-	PowConfiguration   PowConfiguration  `json:"PowConfiguration"`
-	RpcConfiguration   RpcConfiguration  `json:"RpcConfiguration"`
-	DPoSConfiguration  DPoSConfiguration `json:"DPoSConfiguration"`
-	CheckAddressHeight uint32            `json:"CheckAddressHeight"`
-	VoteStartHeight    uint32            `json:"VoteStartHeight"`
-	CRCOnlyDPOSHeight  uint32            `json:"CRCOnlyDPOSHeight"`
-	PublicDPOSHeight   uint32            `json:"PublicDPOSHeight"`
-	ProfilePort        uint32            `json:"ProfilePort"`
-	MaxBlockSize       uint32            `json"MaxBlockSize"`
+	ActiveNet             string            `json:"ActiveNet"`
+	Magic                 uint32            `json:"Magic"`
+	DNSSeeds              []string          `json:"DNSSeeds"`
+	DisableDNS            bool              `json:"DisableDNS"`
+	PermanentPeers        []string          `json:"PermanentPeers"`
+	HttpInfoPort          uint16            `json:"HttpInfoPort"`
+	HttpInfoStart         bool              `json:"HttpInfoStart"`
+	HttpRestPort          int               `json:"HttpRestPort"`
+	HttpRestStart         bool              `json:"HttpRestStart"`
+	HttpWsPort            int               `json:"HttpWsPort"`
+	HttpWsStart           bool              `json:"HttpWsStart"`
+	HttpJsonPort          int               `json:"HttpJsonPort"`
+	EnableRPC             bool              `json:"EnableRPC"`
+	GRPCPort              int               `json:"GRPCPort"`
+	EnableGRPC            bool              `json:"EnableGRPC"`
+	GRPCAddress           string            `json:"GRPCAddress"`
+	NodePort              uint16            `json:"NodePort"`
+	PrintLevel            elalog.Level      `json:"PrintLevel"`
+	MaxLogsSize           int64             `json:"MaxLogsSize"`
+	MaxPerLogSize         int64             `json:"MaxPerLogSize"`
+	RestCertPath          string            `json:"RestCertPath"`
+	RestKeyPath           string            `json:"RestKeyPath"`
+	MinCrossChainTxFee    common.Fixed64    `json:"MinCrossChainTxFee"`
+	FoundationAddress     string            `json:"FoundationAddress"`
+	CRCAddress            string            `json:"CRCAddress"`
+	PowConfiguration      PowConfiguration  `json:"PowConfiguration"`
+	RpcConfiguration      RpcConfiguration  `json:"RpcConfiguration"`
+	RateLimit             RateLimits        `json:"RateLimit"`
+	DPoSConfiguration     DPoSConfiguration `json:"DPoSConfiguration"`
+	CheckAddressHeight    uint32            `json:"CheckAddressHeight"`
+	VoteStartHeight       uint32            `json:"VoteStartHeight"`
+	CRCOnlyDPOSHeight     uint32            `json:"CRCOnlyDPOSHeight"`
+	PublicDPOSHeight      uint32            `json:"PublicDPOSHeight"`
+	NodeKeyRotationHeight uint32            `json:"NodeKeyRotationHeight"`
+	ProfilePort           uint32            `json:"ProfilePort"`
+	MaxBlockSize          uint32            `json"MaxBlockSize"`
 }
 
 // DPoSConfiguration defines the DPoS consensus parameters.
 type DPoSConfiguration struct {
 	EnableArbiter            bool           `json:"EnableArbiter"`
+	EnableEventRecord        bool           `json:"EnableEventRecord"`
+	SignerSocket             string         `json:"SignerSocket"`
 	Magic                    uint32         `json:"Magic"`
 	IPAddress                string         `json:"IPAddress"`
 	DPoSPort                 uint16         `json:"DPoSPort"`
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"math"
 	"math/big"
 	"time"
 
@@ -151,6 +152,7 @@ var DefaultParams = Params{
 	VoteStartHeight:          290000,
 	CRCOnlyDPOSHeight:        343400,
 	PublicDPOSHeight:         402680,
+	NodeKeyRotationHeight:    math.MaxUint32,
 	ToleranceDuration:        5 * time.Second,
 	MaxInactiveRounds:        720 * 2,
 	InactivePenalty:          0, //there will be no penalty in this version
@@ -202,6 +204,7 @@ func (p *Params) TestNet() *Params {
 	copy.VoteStartHeight = 200000
 	copy.CRCOnlyDPOSHeight = 246700
 	copy.PublicDPOSHeight = 300000
+	copy.NodeKeyRotationHeight = 520000
 	return &copy
 }
 
@@ -248,6 +251,7 @@ func (p *Params) RegNet() *Params {
 	copy.VoteStartHeight = 170000
 	copy.CRCOnlyDPOSHeight = 211000
 	copy.PublicDPOSHeight = 234000
+	copy.NodeKeyRotationHeight = 480000
 	return &copy
 }
 
@@ -341,6 +345,11 @@ type Params struct {
 	// elected producers participate in DPOS consensus.
 	PublicDPOSHeight uint32
 
+	// NodeKeyRotationHeight indicates the height from which a node public key
+	// changed by UpdateProducer replaces the old one in the arbiters and
+	// candidates immediately, instead of after the old one quit the arbiters.
+	NodeKeyRotationHeight uint32
+
 	// CRCArbiters defines the fixed CRC arbiters producing the block.
 	CRCArbiters []string
 
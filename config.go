@@ -128,6 +128,9 @@ func loadConfigParams(cfg *config.Configuration) (*config.Configuration, error)
 	if cfg.PublicDPOSHeight > 0 {
 		activeNetParams.PublicDPOSHeight = cfg.PublicDPOSHeight
 	}
+	if cfg.NodeKeyRotationHeight > 0 {
+		activeNetParams.NodeKeyRotationHeight = cfg.NodeKeyRotationHeight
+	}
 
 	// When arbiter service enabled, IP address must be set.
 	if cfg.DPoSConfiguration.EnableArbiter {
@@ -223,6 +226,9 @@ func mainNetDefault(cfg *config.Configuration) {
 	if cfg.HttpJsonPort == 0 {
 		cfg.HttpJsonPort = 20336
 	}
+	if cfg.GRPCPort == 0 {
+		cfg.GRPCPort = 20337
+	}
 }
 
 // testNetDefault set the default parameters for test net usage.
@@ -239,6 +245,9 @@ func testNetDefault(cfg *config.Configuration) {
 	if cfg.HttpJsonPort == 0 {
 		cfg.HttpJsonPort = 21336
 	}
+	if cfg.GRPCPort == 0 {
+		cfg.GRPCPort = 21337
+	}
 }
 
 // regNetDefault set the default parameters for reg net usage.
@@ -255,4 +264,7 @@ func regNetDefault(cfg *config.Configuration) {
 	if cfg.HttpJsonPort == 0 {
 		cfg.HttpJsonPort = 22336
 	}
+	if cfg.GRPCPort == 0 {
+		cfg.GRPCPort = 22337
+	}
 }
@@ -69,6 +69,10 @@ func Sign(priKey []byte, data []byte) ([]byte, error) {
 	privateKey.Curve = DefaultCurve
 	privateKey.D = big.NewInt(0)
 	privateKey.D.SetBytes(priKey)
+	// the public key is required by ecdsa.Sign of newer Go versions, which
+	// panics on the nil coordinates.
+	privateKey.PublicKey.X, privateKey.PublicKey.Y =
+		DefaultCurve.ScalarBaseMult(priKey)
 
 	r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
 	if err != nil {
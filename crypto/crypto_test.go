@@ -53,3 +53,27 @@ func TestEncryptDecrypt(t *testing.T) {
 
 	assert.Equal(t, message, m)
 }
+
+func TestSignVerify(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("Hello World!")
+
+	signature, err := Sign(priKey, message)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, signature, SignatureLength)
+	assert.NoError(t, Verify(*pubKey, message, signature))
+
+	// the signature of another message or by another key is invalid
+	assert.Error(t, Verify(*pubKey, []byte("Hello"), signature))
+	_, otherKey, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, Verify(*otherKey, message, signature))
+	assert.Error(t, Verify(*pubKey, message, signature[1:]))
+}
@@ -0,0 +1,240 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// HardenedKeyStart is the index of the first hardened child key.
+	HardenedKeyStart = 0x80000000
+
+	// PrivateKeyLength is the length of a private key.
+	PrivateKeyLength = 32
+
+	// maxKeyDepth is the maximum depth of an extended key.
+	maxKeyDepth = 255
+)
+
+// masterKeyHMACKey is the HMAC key to generate the master key from a seed,
+// it's the same as BIP32 to be compatible with the other ELA wallets.
+var masterKeyHMACKey = []byte("Bitcoin seed")
+
+var (
+	// ErrInvalidChildKey is returned if the child key of an index is invalid,
+	// the caller should use the next index, the probability is lower than
+	// 1 in 2^127.
+	ErrInvalidChildKey = errors.New("invalid child key, use the next index")
+
+	// ErrDeriveHardenedFromPublic is returned when deriving a hardened child
+	// key from a public extended key.
+	ErrDeriveHardenedFromPublic = errors.New("can not derive a hardened " +
+		"child key from a public key")
+)
+
+// ExtendedKey is a BIP32 hierarchical deterministic key on the curve of ELA,
+// the private key is nil for a public extended key.
+type ExtendedKey struct {
+	privateKey []byte
+	publicKey  *PublicKey
+	chainCode  []byte
+	depth      uint8
+	index      uint32
+}
+
+// NewMasterKey creates the master extended key from the seed.
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errors.New("seed length must be between 16 and 64 bytes")
+	}
+	mac := hmac.New(sha512.New, masterKeyHMACKey)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	k := new(big.Int).SetBytes(sum[:32])
+	if k.Sign() == 0 || k.Cmp(DefaultParams.N) >= 0 {
+		return nil, errors.New("unusable seed")
+	}
+	return &ExtendedKey{
+		privateKey: sum[:32],
+		publicKey:  NewPubKey(sum[:32]),
+		chainCode:  sum[32:],
+	}, nil
+}
+
+// NewExtendedPublicKey creates a public extended key which can derive the
+// public keys of the non-hardened children.
+func NewExtendedPublicKey(publicKey *PublicKey, chainCode []byte, depth uint8,
+	index uint32) (*ExtendedKey, error) {
+	if len(chainCode) != 32 {
+		return nil, errors.New("invalid chain code, length not equal to 32")
+	}
+	return &ExtendedKey{
+		publicKey: publicKey,
+		chainCode: chainCode,
+		depth:     depth,
+		index:     index,
+	}, nil
+}
+
+// IsPrivate returns if the extended key holds the private key.
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.privateKey != nil
+}
+
+// PrivateKey returns the 32 bytes private key, nil for a public extended key.
+func (k *ExtendedKey) PrivateKey() []byte {
+	return k.privateKey
+}
+
+// PublicKey returns the public key.
+func (k *ExtendedKey) PublicKey() *PublicKey {
+	return k.publicKey
+}
+
+// ChainCode returns the chain code.
+func (k *ExtendedKey) ChainCode() []byte {
+	return k.chainCode
+}
+
+// Depth returns the depth of the key, 0 for the master key.
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// Index returns the child index of the key.
+func (k *ExtendedKey) Index() uint32 {
+	return k.index
+}
+
+// Public returns the public extended key of the key.
+func (k *ExtendedKey) Public() *ExtendedKey {
+	return &ExtendedKey{
+		publicKey: k.publicKey,
+		chainCode: k.chainCode,
+		depth:     k.depth,
+		index:     k.index,
+	}
+}
+
+// Child derives the child extended key of the index, indexes from
+// HardenedKeyStart are hardened children which can only be derived from a
+// private extended key.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if k.depth == maxKeyDepth {
+		return nil, errors.New("the extended key is at the maximum depth")
+	}
+
+	hardened := index >= HardenedKeyStart
+	if hardened && !k.IsPrivate() {
+		return nil, ErrDeriveHardenedFromPublic
+	}
+
+	data := make([]byte, 0, 37)
+	if hardened {
+		data = append(data, 0x00)
+		data = append(data, k.privateKey...)
+	} else {
+		publicKey, err := k.publicKey.EncodePoint(true)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, publicKey...)
+	}
+	var indexBytes [4]byte
+	binary.BigEndian.PutUint32(indexBytes[:], index)
+	data = append(data, indexBytes[:]...)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := DefaultParams.N
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, ErrInvalidChildKey
+	}
+
+	child := &ExtendedKey{
+		chainCode: sum[32:],
+		depth:     k.depth + 1,
+		index:     index,
+	}
+	if k.IsPrivate() {
+		d := new(big.Int).SetBytes(k.privateKey)
+		d.Add(d, il)
+		d.Mod(d, n)
+		if d.Sign() == 0 {
+			return nil, ErrInvalidChildKey
+		}
+		child.privateKey = make([]byte, PrivateKeyLength)
+		dBytes := d.Bytes()
+		copy(child.privateKey[PrivateKeyLength-len(dBytes):], dBytes)
+		child.publicKey = NewPubKey(child.privateKey)
+		return child, nil
+	}
+
+	x, y := DefaultCurve.ScalarBaseMult(sum[:32])
+	x, y = DefaultCurve.Add(x, y, k.publicKey.X, k.publicKey.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, ErrInvalidChildKey
+	}
+	child.publicKey = &PublicKey{X: x, Y: y}
+	return child, nil
+}
+
+// Derive derives the descendant extended key of the path.
+func (k *ExtendedKey) Derive(path []uint32) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		var err error
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// ParseDerivationPath parses the derivation path like "m/44'/2305'/0'/0/0"
+// into the child indexes, the hardened indexes end with ' or h.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	elements := strings.Split(strings.TrimSpace(path), "/")
+	if elements[0] != "m" {
+		return nil, errors.New("derivation path must start with m")
+	}
+
+	indexes := make([]uint32, 0, len(elements)-1)
+	for _, e := range elements[1:] {
+		var offset uint32
+		if strings.HasSuffix(e, "'") || strings.HasSuffix(e, "h") {
+			offset = HardenedKeyStart
+			e = e[:len(e)-1]
+		}
+		index, err := strconv.ParseUint(e, 10, 32)
+		if err != nil || index >= HardenedKeyStart {
+			return nil, errors.New("invalid derivation path element: " + e)
+		}
+		indexes = append(indexes, uint32(index)+offset)
+	}
+	return indexes, nil
+}
+
+// FormatDerivationPath formats the child indexes into a derivation path.
+func FormatDerivationPath(path []uint32) string {
+	elements := make([]string, 0, len(path)+1)
+	elements = append(elements, "m")
+	for _, index := range path {
+		if index >= HardenedKeyStart {
+			elements = append(elements,
+				strconv.FormatUint(uint64(index-HardenedKeyStart), 10)+"'")
+			continue
+		}
+		elements = append(elements, strconv.FormatUint(uint64(index), 10))
+	}
+	return strings.Join(elements, "/")
+}
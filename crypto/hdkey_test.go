@@ -0,0 +1,83 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMasterKey(t *testing.T) {
+	// the master key and the hardened child of the test vector 1 of BIP32
+	// are not affected by the curve.
+	seed, _ := common.HexStringToBytes("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMasterKey(seed)
+	assert.NoError(t, err)
+	assert.Equal(t, "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+		common.BytesToHexString(master.PrivateKey()))
+	assert.Equal(t, "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+		common.BytesToHexString(master.ChainCode()))
+	assert.Equal(t, NewPubKey(master.PrivateKey()), master.PublicKey())
+
+	child, err := master.Child(HardenedKeyStart)
+	assert.NoError(t, err)
+	assert.Equal(t, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		common.BytesToHexString(child.PrivateKey()))
+	assert.Equal(t, "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+		common.BytesToHexString(child.ChainCode()))
+	assert.Equal(t, uint8(1), child.Depth())
+	assert.Equal(t, uint32(HardenedKeyStart), child.Index())
+
+	_, err = NewMasterKey(seed[:8])
+	assert.Error(t, err)
+}
+
+func TestExtendedKey_Child(t *testing.T) {
+	seed, _ := common.HexStringToBytes("000102030405060708090a0b0c0d0e0f")
+	master, _ := NewMasterKey(seed)
+	account, err := master.Derive([]uint32{44 + HardenedKeyStart,
+		2305 + HardenedKeyStart, HardenedKeyStart})
+	assert.NoError(t, err)
+
+	// the public children derived from the public key equal to the ones
+	// derived from the private key.
+	public := account.Public()
+	assert.False(t, public.IsPrivate())
+	for i := uint32(0); i < 5; i++ {
+		private, err := account.Derive([]uint32{0, i})
+		assert.NoError(t, err)
+		assert.Equal(t, PrivateKeyLength, len(private.PrivateKey()))
+		assert.Equal(t, NewPubKey(private.PrivateKey()), private.PublicKey())
+
+		child, err := public.Derive([]uint32{0, i})
+		assert.NoError(t, err)
+		assert.Nil(t, child.PrivateKey())
+		assert.True(t, Equal(private.PublicKey(), child.PublicKey()))
+		assert.Equal(t, private.ChainCode(), child.ChainCode())
+	}
+
+	_, err = public.Child(HardenedKeyStart)
+	assert.Equal(t, ErrDeriveHardenedFromPublic, err)
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	path, err := ParseDerivationPath("m/44'/2305'/0'/1/5")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{44 + HardenedKeyStart, 2305 + HardenedKeyStart,
+		HardenedKeyStart, 1, 5}, path)
+	assert.Equal(t, "m/44'/2305'/0'/1/5", FormatDerivationPath(path))
+
+	path, err = ParseDerivationPath("m/0h")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{HardenedKeyStart}, path)
+
+	path, err = ParseDerivationPath("m")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{}, path)
+
+	for _, p := range []string{"", "44'/0", "m/x", "m/2147483648", "m//1"} {
+		_, err = ParseDerivationPath(p)
+		assert.Error(t, err, p)
+	}
+}
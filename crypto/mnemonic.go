@@ -0,0 +1,131 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MinEntropyBits is the minimum entropy bits of a mnemonic.
+	MinEntropyBits = 128
+
+	// MaxEntropyBits is the maximum entropy bits of a mnemonic.
+	MaxEntropyBits = 256
+
+	// SeedLength is the length of the seed generated from a mnemonic.
+	SeedLength = 64
+
+	// seedIterations is the PBKDF2 iterations to generate the seed.
+	seedIterations = 2048
+)
+
+// wordIndexes maps the words of the word list to their indexes.
+var wordIndexes = func() map[string]int {
+	indexes := make(map[string]int, len(wordList))
+	for i, word := range wordList {
+		indexes[word] = i
+	}
+	return indexes
+}()
+
+// NewEntropy generates the random entropy of a mnemonic, the bits must be a
+// multiple of 32 between 128 and 256.
+func NewEntropy(bits int) ([]byte, error) {
+	if err := checkEntropyBits(bits); err != nil {
+		return nil, err
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+// NewMnemonic returns the BIP39 mnemonic of the entropy.
+func NewMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if err := checkEntropyBits(bits); err != nil {
+		return "", err
+	}
+
+	// append the checksum bits of the first bits of the entropy hash.
+	checksumBits := uint(bits / 32)
+	hash := sha256.Sum256(entropy)
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, checksumBits)
+	data.Or(data, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	count := (bits + int(checksumBits)) / 11
+	words := make([]string, count)
+	mask := big.NewInt(2047)
+	index := new(big.Int)
+	for i := count - 1; i >= 0; i-- {
+		index.And(data, mask)
+		words[i] = wordList[index.Int64()]
+		data.Rsh(data, 11)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy returns the entropy of the mnemonic, an error is returned
+// if the mnemonic contains an unknown word or the checksum is wrong.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	count := len(words)
+	if count%3 != 0 || count < MinEntropyBits*3/32 ||
+		count > MaxEntropyBits*3/32 {
+		return nil, errors.New("invalid mnemonic words count")
+	}
+
+	data := new(big.Int)
+	for _, word := range words {
+		index, ok := wordIndexes[word]
+		if !ok {
+			return nil, errors.New("invalid mnemonic word: " + word)
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(count / 3)
+	checksum := byte(new(big.Int).And(data,
+		big.NewInt(1<<checksumBits-1)).Int64())
+	data.Rsh(data, checksumBits)
+
+	entropy := make([]byte, int(checksumBits)*4)
+	dataBytes := data.Bytes()
+	copy(entropy[len(entropy)-len(dataBytes):], dataBytes)
+	hash := sha256.Sum256(entropy)
+	if hash[0]>>(8-checksumBits) != checksum {
+		return nil, errors.New("invalid mnemonic checksum")
+	}
+	return entropy, nil
+}
+
+// NewSeed returns the BIP39 seed of the mnemonic protected by the passphrase,
+// the passphrase can be empty.
+func NewSeed(mnemonic, passphrase string) ([]byte, error) {
+	if _, err := MnemonicToEntropy(mnemonic); err != nil {
+		return nil, err
+	}
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	password := norm.NFKD.Bytes([]byte(mnemonic))
+	salt := norm.NFKD.Bytes([]byte("mnemonic" + passphrase))
+	return pbkdf2.Key(password, salt, seedIterations, SeedLength,
+		sha512.New), nil
+}
+
+func checkEntropyBits(bits int) error {
+	if bits%32 != 0 || bits < MinEntropyBits || bits > MaxEntropyBits {
+		return errors.New("entropy bits must be a multiple of 32 " +
+			"between 128 and 256")
+	}
+	return nil
+}
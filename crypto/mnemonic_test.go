@@ -0,0 +1,96 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMnemonic(t *testing.T) {
+	// the test vectors of BIP39 with the passphrase "TREZOR".
+	vectors := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			"80808080808080808080808080808080",
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+			"d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+		},
+		{
+			"ffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+		},
+		{
+			"9e885d952ad362caeb4efe34a8e91bd2",
+			"ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic",
+			"274ddc525802f7c828d8ef7ddbcdc5304e87ac3535913611fbbfa986d0c9e5476c91689f9c8a54fd55bd38606aa6a8595ad213d4c9c9f9aca3fb217069a41028",
+		},
+	}
+
+	for _, v := range vectors {
+		entropy, _ := common.HexStringToBytes(v.entropy)
+		mnemonic, err := NewMnemonic(entropy)
+		assert.NoError(t, err)
+		assert.Equal(t, v.mnemonic, mnemonic)
+
+		decoded, err := MnemonicToEntropy(mnemonic)
+		assert.NoError(t, err)
+		assert.Equal(t, entropy, decoded)
+
+		seed, err := NewSeed(mnemonic, "TREZOR")
+		assert.NoError(t, err)
+		assert.Equal(t, v.seed, common.BytesToHexString(seed))
+	}
+}
+
+func TestMnemonicToEntropy(t *testing.T) {
+	// random entropy of each length
+	for bits := MinEntropyBits; bits <= MaxEntropyBits; bits += 32 {
+		entropy, err := NewEntropy(bits)
+		assert.NoError(t, err)
+		mnemonic, err := NewMnemonic(entropy)
+		assert.NoError(t, err)
+		assert.Equal(t, bits*3/32, len(strings.Fields(mnemonic)))
+		decoded, err := MnemonicToEntropy(mnemonic)
+		assert.NoError(t, err)
+		assert.Equal(t, entropy, decoded)
+	}
+
+	_, err := NewEntropy(100)
+	assert.Error(t, err)
+
+	// wrong checksum
+	_, err = MnemonicToEntropy("abandon abandon abandon abandon abandon " +
+		"abandon abandon abandon abandon abandon abandon abandon")
+	assert.EqualError(t, err, "invalid mnemonic checksum")
+
+	// unknown word
+	_, err = MnemonicToEntropy("abandon abandon abandon abandon abandon " +
+		"abandon abandon abandon abandon abandon abandon elastos")
+	assert.EqualError(t, err, "invalid mnemonic word: elastos")
+
+	// wrong words count
+	_, err = MnemonicToEntropy("abandon abandon abandon")
+	assert.EqualError(t, err, "invalid mnemonic words count")
+}
@@ -2,6 +2,8 @@ package account
 
 import (
 	"bytes"
+	"errors"
+	"sync"
 
 	"github.com/elastos/Elastos.ELA/account"
 	"github.com/elastos/Elastos.ELA/core/types"
@@ -12,29 +14,48 @@ import (
 type Account interface {
 	PublicKey() *crypto.PublicKey
 	PublicKeyBytes() []byte
-	SignProposal(proposal *payload.DPOSProposal) ([]byte, error)
-	SignVote(vote *payload.DPOSProposalVote) ([]byte, error)
+	SignProposal(height uint32, proposal *payload.DPOSProposal) ([]byte,
+		error)
+	SignVote(height, viewOffset uint32, vote *payload.DPOSProposalVote) ([]byte,
+		error)
 	Sign(data []byte) []byte
 	SignTx(tx *types.Transaction) ([]byte, error)
 	DecryptAddr(cipher []byte) (addr string, err error)
+
+	// SwitchKey switches the account to the key pair of the given public key,
+	// the key pair will be loaded from keystore if it is not loaded yet.
+	SwitchKey(publicKey []byte) error
 }
 
 type dAccount struct {
+	client *account.Client
+
+	mtx sync.RWMutex
 	*account.Account
 	pubKey []byte
 }
 
 func (a *dAccount) PublicKey() *crypto.PublicKey {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
 	return a.Account.PublicKey
 }
 
 func (a *dAccount) PublicKeyBytes() []byte {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
 	return a.pubKey
 }
 
-func (a *dAccount) SignProposal(proposal *payload.DPOSProposal) ([]byte,
-	error) {
-	privateKey := a.PrivKey()
+func (a *dAccount) privateKey() []byte {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.PrivKey()
+}
+
+func (a *dAccount) SignProposal(height uint32,
+	proposal *payload.DPOSProposal) ([]byte, error) {
+	privateKey := a.privateKey()
 
 	signature, err := crypto.Sign(privateKey, proposal.Data())
 	if err != nil {
@@ -44,8 +65,9 @@ func (a *dAccount) SignProposal(proposal *payload.DPOSProposal) ([]byte,
 	return signature, nil
 }
 
-func (a *dAccount) SignVote(vote *payload.DPOSProposalVote) ([]byte, error) {
-	privateKey := a.PrivKey()
+func (a *dAccount) SignVote(height, viewOffset uint32,
+	vote *payload.DPOSProposalVote) ([]byte, error) {
+	privateKey := a.privateKey()
 
 	signature, err := crypto.Sign(privateKey, vote.Data())
 	if err != nil {
@@ -56,7 +78,7 @@ func (a *dAccount) SignVote(vote *payload.DPOSProposalVote) ([]byte, error) {
 }
 
 func (a *dAccount) Sign(data []byte) []byte {
-	privateKey := a.PrivKey()
+	privateKey := a.privateKey()
 	sign, err := crypto.Sign(privateKey, data)
 	if err != nil {
 		return nil
@@ -70,15 +92,52 @@ func (a *dAccount) SignTx(tx *types.Transaction) ([]byte, error) {
 		return nil, err
 	}
 
-	privateKey := a.PrivKey()
+	privateKey := a.privateKey()
 	return crypto.Sign(privateKey, buf.Bytes())
 }
 
 func (a *dAccount) DecryptAddr(cipher []byte) (addr string, err error) {
-	data, err := crypto.Decrypt(a.PrivateKey, cipher)
+	data, err := crypto.Decrypt(a.privateKey(), cipher)
 	return string(data), err
 }
 
+func (a *dAccount) SwitchKey(publicKey []byte) error {
+	if bytes.Equal(a.PublicKeyBytes(), publicKey) {
+		return nil
+	}
+	if a.client == nil {
+		return errors.New("account not opened from keystore")
+	}
+
+	pubKey, err := crypto.DecodePoint(publicKey)
+	if err != nil {
+		return err
+	}
+	ac, err := a.client.GetAccount(pubKey)
+	if err != nil {
+		return err
+	}
+
+	// The key pair may be added into keystore after the account opened.
+	if ac == nil {
+		if err := a.client.LoadAccounts(); err != nil {
+			return err
+		}
+		if ac, err = a.client.GetAccount(pubKey); err != nil {
+			return err
+		}
+	}
+	if ac == nil {
+		return errors.New("key pair not found in keystore")
+	}
+
+	a.mtx.Lock()
+	a.Account = ac
+	a.pubKey = publicKey
+	a.mtx.Unlock()
+	return nil
+}
+
 func Open(password []byte) (Account, error) {
 	client, err := account.Open(account.KeystoreFileName, password)
 	if err != nil {
@@ -90,7 +149,7 @@ func Open(password []byte) (Account, error) {
 		return nil, err
 	}
 
-	return &dAccount{Account: a, pubKey: pk}, nil
+	return &dAccount{client: client, Account: a, pubKey: pk}, nil
 }
 
 func New(a *account.Account) Account {
@@ -0,0 +1,98 @@
+package account
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/account"
+	"github.com/elastos/Elastos.ELA/crypto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var password = []byte("password")
+
+func newTestAccount(t *testing.T) (*dAccount, string) {
+	path := filepath.Join(t.TempDir(), account.KeystoreFileName)
+	client, err := account.Create(path, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := client.GetMainAccount()
+	pk, err := a.PublicKey.EncodePoint(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dAccount{client: client, Account: a, pubKey: pk}, path
+}
+
+func assertSignedBy(t *testing.T, a Account, publicKey []byte) {
+	data := []byte("data to sign")
+	signature := a.Sign(data)
+	pk, err := crypto.DecodePoint(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, crypto.Verify(*pk, data, signature))
+}
+
+func TestSwitchKey(t *testing.T) {
+	a, path := newTestAccount(t)
+	oldKey := a.PublicKeyBytes()
+
+	// switch to the current key is a no-op
+	assert.NoError(t, a.SwitchKey(oldKey))
+	assert.True(t, bytes.Equal(oldKey, a.PublicKeyBytes()))
+
+	// add the new key into the keystore after the account opened
+	other, err := account.Open(path, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newAccount, err := other.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := newAccount.PublicKey.EncodePoint(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, a.SwitchKey(newKey))
+	assert.True(t, bytes.Equal(newKey, a.PublicKeyBytes()))
+	pk, err := a.PublicKey().EncodePoint(true)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(newKey, pk))
+	assertSignedBy(t, a, newKey)
+
+	// switch back to the previous key which is still in the keystore
+	assert.NoError(t, a.SwitchKey(oldKey))
+	assertSignedBy(t, a, oldKey)
+}
+
+func TestSwitchKey_Errors(t *testing.T) {
+	a, _ := newTestAccount(t)
+	oldKey := a.PublicKeyBytes()
+
+	// invalid public key
+	assert.Error(t, a.SwitchKey([]byte{1, 2, 3}))
+
+	// key pair not in the keystore
+	_, unknown, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknownKey, err := unknown.EncodePoint(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, a.SwitchKey(unknownKey))
+
+	// the account is not changed by the failed switches
+	assert.True(t, bytes.Equal(oldKey, a.PublicKeyBytes()))
+	assertSignedBy(t, a, oldKey)
+
+	// the account not opened from keystore can not switch key
+	assert.Error(t, New(a.Account).SwitchKey(unknownKey))
+}
@@ -0,0 +1,167 @@
+package account
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/elastos/Elastos.ELA/common/log"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/crypto"
+	"github.com/elastos/Elastos.ELA/dpos/signer"
+	"github.com/elastos/Elastos.ELA/events"
+)
+
+// remoteAccount is an Account implementation that the key pairs are held by
+// the signer daemon, all signatures are requested through the signer client.
+type remoteAccount struct {
+	client *signer.Client
+
+	mtx       sync.RWMutex
+	publicKey *crypto.PublicKey
+	pubKey    []byte
+
+	heightMtx sync.Mutex
+	height    uint32
+	wake      chan struct{}
+}
+
+func (a *remoteAccount) PublicKey() *crypto.PublicKey {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.publicKey
+}
+
+func (a *remoteAccount) PublicKeyBytes() []byte {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.pubKey
+}
+
+func (a *remoteAccount) SignProposal(height uint32,
+	proposal *payload.DPOSProposal) ([]byte, error) {
+	return a.client.SignProposal(height, proposal)
+}
+
+func (a *remoteAccount) SignVote(height, viewOffset uint32,
+	vote *payload.DPOSProposalVote) ([]byte, error) {
+	return a.client.SignVote(height, viewOffset, vote)
+}
+
+func (a *remoteAccount) Sign(data []byte) []byte {
+	sign, err := a.client.Sign(a.PublicKeyBytes(), data)
+	if err != nil {
+		return nil
+	}
+	return sign
+}
+
+func (a *remoteAccount) SignTx(tx *types.Transaction) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := tx.SerializeUnsigned(buf); err != nil {
+		return nil, err
+	}
+
+	return a.client.Sign(a.PublicKeyBytes(), buf.Bytes())
+}
+
+func (a *remoteAccount) DecryptAddr(cipher []byte) (addr string, err error) {
+	data, err := a.client.Decrypt(a.PublicKeyBytes(), cipher)
+	return string(data), err
+}
+
+func (a *remoteAccount) SwitchKey(publicKey []byte) error {
+	if bytes.Equal(a.PublicKeyBytes(), publicKey) {
+		return nil
+	}
+
+	// Make sure the key pair exists in the signer.
+	pk, err := a.client.PublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	pubKey, err := crypto.DecodePoint(pk)
+	if err != nil {
+		return err
+	}
+
+	a.mtx.Lock()
+	a.publicKey = pubKey
+	a.pubKey = pk
+	a.mtx.Unlock()
+	return nil
+}
+
+// blockConnected records the height of the connected block and wakes up the
+// height loop, it never blocks the chain.
+func (a *remoteAccount) blockConnected(e *events.Event) {
+	if e.Type != events.ETBlockConnected {
+		return
+	}
+	block, ok := e.Data.(*types.Block)
+	if !ok {
+		return
+	}
+
+	a.heightMtx.Lock()
+	if block.Height > a.height {
+		a.height = block.Height
+	}
+	a.heightMtx.Unlock()
+
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+// heightLoop pushes the best height of the verified chain to the signer, so
+// that the sign requests are not refused after the arbiter has not signed for
+// a while. Only the latest height is pushed if the signer falls behind.
+func (a *remoteAccount) heightLoop() {
+	var pushed uint32
+	for range a.wake {
+		a.heightMtx.Lock()
+		height := a.height
+		a.heightMtx.Unlock()
+		if height <= pushed {
+			continue
+		}
+
+		// a failed height will be retried by the next block
+		if err := a.client.AdvanceHeight(height); err != nil {
+			log.Warn("[AdvanceHeight] push height to signer failed:", err)
+			continue
+		}
+		pushed = height
+	}
+}
+
+// OpenRemote connects to the signer daemon listening on the given Unix socket
+// and returns an Account using the main account key pair of the signer.
+func OpenRemote(socket string) (Account, error) {
+	client, err := signer.Dial(socket)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := client.PublicKey(nil)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	publicKey, err := crypto.DecodePoint(pk)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	a := &remoteAccount{
+		client:    client,
+		publicKey: publicKey,
+		pubKey:    pk,
+		wake:      make(chan struct{}, 1),
+	}
+	events.Subscribe(a.blockConnected)
+	go a.heightLoop()
+	return a, nil
+}
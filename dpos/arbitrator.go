@@ -14,6 +14,7 @@ import (
 	"github.com/elastos/Elastos.ELA/dpos/manager"
 	dp2p "github.com/elastos/Elastos.ELA/dpos/p2p"
 	"github.com/elastos/Elastos.ELA/dpos/p2p/peer"
+	"github.com/elastos/Elastos.ELA/dpos/signer"
 	"github.com/elastos/Elastos.ELA/dpos/state"
 	"github.com/elastos/Elastos.ELA/dpos/store"
 	"github.com/elastos/Elastos.ELA/elanet"
@@ -34,6 +35,7 @@ type Config struct {
 	ChainParams       *config.Params
 	Broadcast         func(msg p2p.Message)
 	AnnounceAddr      func()
+	UpdatePID         func(pid []byte)
 }
 
 type Arbitrator struct {
@@ -42,6 +44,7 @@ type Arbitrator struct {
 	enableViewLoop bool
 	network        *network
 	dposManager    *manager.DPOSManager
+	metrics        *store.ArbiterMetrics
 }
 
 func (a *Arbitrator) Start() {
@@ -76,6 +79,17 @@ func (a *Arbitrator) GetArbiterPeersInfo() []*dp2p.PeerInfo {
 	return a.network.p2pServer.DumpPeersInfo()
 }
 
+// GetPublicKey returns the node public key of the arbiter.
+func (a *Arbitrator) GetPublicKey() []byte {
+	return a.account.PublicKeyBytes()
+}
+
+// GetArbiterMetrics returns the performance metrics of arbiters in recent
+// arbitration rounds, from the oldest to the current one.
+func (a *Arbitrator) GetArbiterMetrics() []*store.MetricsWindow {
+	return a.metrics.GetWindows()
+}
+
 func (a *Arbitrator) OnIllegalBlockTxReceived(p *payload.DPOSIllegalBlocks) {
 	log.Info("[OnIllegalBlockTxReceived] listener received illegal block tx")
 	if p.CoinType != payload.ELACoin {
@@ -176,6 +190,13 @@ func NewArbitrator(account account.Account, cfg Config) (*Arbitrator, error) {
 		eventMonitor.RegisterListener(eventLogs)
 	}
 
+	metrics := store.NewArbiterMetrics(store.ArbiterMetricsConfig{
+		Arbitrators:       cfg.Arbitrators,
+		Store:             cfg.Store,
+		ToleranceDuration: cfg.ChainParams.ToleranceDuration,
+	})
+	eventMonitor.RegisterListener(metrics)
+
 	if cfg.EnableEventRecord {
 		eventRecorder := &store.EventRecord{}
 		eventRecorder.Initialize(cfg.Store)
@@ -190,6 +211,12 @@ func NewArbitrator(account account.Account, cfg Config) (*Arbitrator, error) {
 		TimeSource:  medianTime,
 	})
 
+	signGuard, err := signer.NewJournalGuard(cfg.Store)
+	if err != nil {
+		log.Error("Load sign journal error")
+		return nil, err
+	}
+
 	consensus := manager.NewConsensus(dposManager, cfg.ChainParams.ToleranceDuration, dposHandlerSwitch)
 	proposalDispatcher, illegalMonitor := manager.NewDispatcherAndIllegalMonitor(
 		manager.ProposalDispatcherConfig{
@@ -198,6 +225,7 @@ func NewArbitrator(account account.Account, cfg Config) (*Arbitrator, error) {
 			Network:      network,
 			Manager:      dposManager,
 			Account:      account,
+			SignGuard:    signGuard,
 			ChainParams:  cfg.ChainParams,
 			TimeSource:   medianTime,
 			EventStoreAnalyzerConfig: store.EventStoreAnalyzerConfig{
@@ -213,7 +241,9 @@ func NewArbitrator(account account.Account, cfg Config) (*Arbitrator, error) {
 		ProposalDispatcher: proposalDispatcher,
 		Store:              cfg.Store,
 		PublicKey:          account.PublicKeyBytes(),
+		Arbitrators:        cfg.Arbitrators,
 		AnnounceAddr:       cfg.AnnounceAddr,
+		UpdatePID:          cfg.UpdatePID,
 	})
 
 	cfg.Store.StartEventRecord()
@@ -224,6 +254,7 @@ func NewArbitrator(account account.Account, cfg Config) (*Arbitrator, error) {
 		enableViewLoop: true,
 		dposManager:    dposManager,
 		network:        network,
+		metrics:        metrics,
 	}
 
 	events.Subscribe(func(e *events.Event) {
@@ -238,6 +269,9 @@ func NewArbitrator(account account.Account, cfg Config) (*Arbitrator, error) {
 		case events.ETDirectPeersChanged:
 			a.OnPeersChanged(e.Data.([]peer.PID))
 
+		case events.ETNodeKeyRotated:
+			a.network.nodeKeyRotated(e.Data.(*state.NodeKeyRotation))
+
 		case events.ETTransactionAccepted:
 			tx := e.Data.(*types.Transaction)
 			if tx.IsIllegalBlockTx() {
@@ -48,6 +48,10 @@ func (c *Consensus) SetOnDuty(onDuty bool) {
 	c.currentView.SetOnDuty(onDuty)
 }
 
+func (c *Consensus) SetPublicKey(publicKey []byte) {
+	c.currentView.setPublicKey(publicKey)
+}
+
 func (c *Consensus) SetRunning() {
 	c.consensusStatus = consensusRunning
 	c.resetViewOffset()
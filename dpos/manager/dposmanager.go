@@ -3,6 +3,7 @@ package manager
 import (
 	"bytes"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/elastos/Elastos.ELA/blockchain"
@@ -33,7 +34,9 @@ type DPOSNetworkConfig struct {
 	ProposalDispatcher *ProposalDispatcher
 	Store              store.IDposStore
 	PublicKey          []byte
+	Arbitrators        state.Arbitrators
 	AnnounceAddr       func()
+	UpdatePID          func(pid []byte)
 }
 
 type DPOSNetwork interface {
@@ -85,6 +88,8 @@ type NetworkEventListener interface {
 	OnResponseInactiveArbitratorsReceived(txHash *common.Uint256,
 		Signer []byte, Sign []byte)
 	OnInactiveArbitratorsAccepted(p *payload.InactiveArbitrators)
+
+	OnNodeKeyChanged(publicKey []byte)
 }
 
 type AbnormalRecovering interface {
@@ -101,6 +106,9 @@ type DPOSManagerConfig struct {
 }
 
 type DPOSManager struct {
+	// publicKey is changed by the node key rotation, the key lock protects it
+	// from the readers outside of the network goroutine.
+	keyLock    sync.RWMutex
 	publicKey  []byte
 	blockCache *ConsensusBlockCache
 
@@ -174,9 +182,21 @@ func (d *DPOSManager) Broadcast(msg p2p.Message) {
 }
 
 func (d *DPOSManager) GetPublicKey() []byte {
+	d.keyLock.RLock()
+	defer d.keyLock.RUnlock()
 	return d.publicKey
 }
 
+// OnNodeKeyChanged changes the node public key used in consensus, it's invoked
+// while the block rotating the node key is processed, before the consensus of
+// the next height.
+func (d *DPOSManager) OnNodeKeyChanged(publicKey []byte) {
+	d.keyLock.Lock()
+	d.publicKey = publicKey
+	d.keyLock.Unlock()
+	d.consensus.SetPublicKey(publicKey)
+}
+
 func (d *DPOSManager) GetBlockCache() *ConsensusBlockCache {
 	return d.blockCache
 }
@@ -186,11 +206,11 @@ func (d *DPOSManager) GetArbitrators() state.Arbitrators {
 }
 
 func (d *DPOSManager) isCurrentArbiter() bool {
-	return d.arbitrators.IsArbitrator(d.publicKey)
+	return d.arbitrators.IsArbitrator(d.GetPublicKey())
 }
 
 func (d *DPOSManager) isCRCArbiter() bool {
-	return d.arbitrators.IsCRCArbitrator(d.publicKey)
+	return d.arbitrators.IsCRCArbitrator(d.GetPublicKey())
 }
 
 func (d *DPOSManager) ProcessHigherBlock(b *types.Block) {
@@ -620,7 +640,7 @@ func (d *DPOSManager) changeHeight() {
 
 func (d *DPOSManager) changeOnDuty() {
 	currentArbiter := d.arbitrators.GetNextOnDutyArbitrator(0)
-	onDuty := bytes.Equal(d.publicKey, currentArbiter)
+	onDuty := bytes.Equal(d.GetPublicKey(), currentArbiter)
 
 	if onDuty {
 		log.Info("[onDutyArbitratorChanged] onduty")
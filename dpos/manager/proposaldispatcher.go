@@ -17,6 +17,7 @@ import (
 	"github.com/elastos/Elastos.ELA/dpos/log"
 	dmsg "github.com/elastos/Elastos.ELA/dpos/p2p/msg"
 	"github.com/elastos/Elastos.ELA/dpos/p2p/peer"
+	"github.com/elastos/Elastos.ELA/dpos/signer"
 	"github.com/elastos/Elastos.ELA/dpos/state"
 	"github.com/elastos/Elastos.ELA/dpos/store"
 )
@@ -28,6 +29,7 @@ type ProposalDispatcherConfig struct {
 	Network      DPOSNetwork
 	Manager      *DPOSManager
 	Account      account.Account
+	SignGuard    *signer.Guard
 	ChainParams  *config.Params
 	TimeSource   dtime.MedianTimeSource
 }
@@ -101,6 +103,44 @@ func (p *ProposalDispatcher) AddPendingVote(v *payload.DPOSProposalVote) {
 	p.pendingVotes[v.Hash()] = v
 }
 
+// advanceSignGuard moves the guarded height of the sign journal to the height
+// of the chain.
+func (p *ProposalDispatcher) advanceSignGuard() error {
+	return p.cfg.SignGuard.AdvanceHeight(
+		blockchain.DefaultLedger.Blockchain.GetHeight())
+}
+
+// signProposal signs the proposal after it has been checked and recorded by
+// the sign journal.
+func (p *ProposalDispatcher) signProposal(height uint32,
+	proposal *payload.DPOSProposal) ([]byte, error) {
+	if p.cfg.SignGuard != nil {
+		if err := p.advanceSignGuard(); err != nil {
+			return nil, err
+		}
+		if err := p.cfg.SignGuard.CheckProposal(height, proposal); err != nil {
+			return nil, err
+		}
+	}
+	return p.cfg.Account.SignProposal(height, proposal)
+}
+
+// signVote signs the vote after it has been checked and recorded by the sign
+// journal.
+func (p *ProposalDispatcher) signVote(height, viewOffset uint32,
+	vote *payload.DPOSProposalVote) ([]byte, error) {
+	if p.cfg.SignGuard != nil {
+		if err := p.advanceSignGuard(); err != nil {
+			return nil, err
+		}
+		if err := p.cfg.SignGuard.CheckVote(height, viewOffset,
+			vote); err != nil {
+			return nil, err
+		}
+	}
+	return p.cfg.Account.SignVote(height, viewOffset, vote)
+}
+
 func (p *ProposalDispatcher) StartProposal(b *types.Block) {
 	log.Info("[StartProposal] start")
 	defer log.Info("[StartProposal] end")
@@ -115,7 +155,7 @@ func (p *ProposalDispatcher) StartProposal(b *types.Block) {
 	proposal := &payload.DPOSProposal{Sponsor: p.cfg.Manager.GetPublicKey(),
 		BlockHash: b.Hash(), ViewOffset: p.cfg.Consensus.GetViewOffset()}
 	var err error
-	proposal.Sign, err = p.cfg.Account.SignProposal(proposal)
+	proposal.Sign, err = p.signProposal(b.Height, proposal)
 	if err != nil {
 		log.Error("[StartProposal] start proposal failed:", err.Error())
 		return
@@ -668,9 +708,10 @@ func (p *ProposalDispatcher) acceptProposal(d *payload.DPOSProposal) {
 	vote := &payload.DPOSProposalVote{ProposalHash: d.Hash(),
 		Signer: p.cfg.Manager.GetPublicKey(), Accept: true}
 	var err error
-	vote.Sign, err = p.cfg.Account.SignVote(vote)
+	vote.Sign, err = p.signVote(
+		blockchain.DefaultLedger.Blockchain.GetHeight()+1, d.ViewOffset, vote)
 	if err != nil {
-		log.Error("[acceptProposal] sign failed")
+		log.Error("[acceptProposal] sign failed:", err)
 		return
 	}
 	voteMsg := &dmsg.Vote{Command: dmsg.CmdAcceptVote, Vote: *vote}
@@ -692,9 +733,10 @@ func (p *ProposalDispatcher) rejectProposal(d *payload.DPOSProposal) {
 	vote := &payload.DPOSProposalVote{ProposalHash: d.Hash(),
 		Signer: p.cfg.Manager.GetPublicKey(), Accept: false}
 	var err error
-	vote.Sign, err = p.cfg.Account.SignVote(vote)
+	vote.Sign, err = p.signVote(
+		blockchain.DefaultLedger.Blockchain.GetHeight()+1, d.ViewOffset, vote)
 	if err != nil {
-		log.Error("[rejectProposal] sign failed")
+		log.Error("[rejectProposal] sign failed:", err)
 		return
 	}
 	msg := &dmsg.Vote{Command: dmsg.CmdRejectVote, Vote: *vote}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"bytes"
+	"sync"
 	"time"
 
 	"github.com/elastos/Elastos.ELA/common"
@@ -14,7 +15,11 @@ type ViewListener interface {
 }
 
 type view struct {
-	publicKey     []byte
+	// publicKey may be changed by the node key rotation while a block is
+	// processed, so it's protected by the key lock.
+	keyLock   sync.RWMutex
+	publicKey []byte
+
 	signTolerance time.Duration
 	viewStartTime time.Time
 	isDposOnDuty  bool
@@ -23,6 +28,18 @@ type view struct {
 	listener ViewListener
 }
 
+func (v *view) getPublicKey() []byte {
+	v.keyLock.RLock()
+	defer v.keyLock.RUnlock()
+	return v.publicKey
+}
+
+func (v *view) setPublicKey(publicKey []byte) {
+	v.keyLock.Lock()
+	v.publicKey = publicKey
+	v.keyLock.Unlock()
+}
+
 func (v *view) IsOnDuty() bool {
 	return v.isDposOnDuty
 }
@@ -47,7 +64,7 @@ func (v *view) ChangeView(viewOffset *uint32, now time.Time) {
 	if offset > 0 {
 		currentArbiter := v.arbitrators.GetNextOnDutyArbitrator(*viewOffset)
 
-		v.isDposOnDuty = bytes.Equal(currentArbiter, v.publicKey)
+		v.isDposOnDuty = bytes.Equal(currentArbiter, v.getPublicKey())
 		log.Info("current onduty arbiter:",
 			common.BytesToHexString(currentArbiter))
 
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/elastos/Elastos.ELA/blockchain"
+	"github.com/elastos/Elastos.ELA/common"
 	"github.com/elastos/Elastos.ELA/common/config"
 	"github.com/elastos/Elastos.ELA/core/types"
 	"github.com/elastos/Elastos.ELA/core/types/payload"
@@ -16,6 +17,7 @@ import (
 	"github.com/elastos/Elastos.ELA/dpos/p2p"
 	"github.com/elastos/Elastos.ELA/dpos/p2p/msg"
 	"github.com/elastos/Elastos.ELA/dpos/p2p/peer"
+	"github.com/elastos/Elastos.ELA/dpos/state"
 	"github.com/elastos/Elastos.ELA/dpos/store"
 	elap2p "github.com/elastos/Elastos.ELA/p2p"
 	elamsg "github.com/elastos/Elastos.ELA/p2p/msg"
@@ -38,8 +40,17 @@ type network struct {
 	proposalDispatcher *manager.ProposalDispatcher
 	peersLock          sync.Mutex
 	store              store.IDposStore
-	publicKey          []byte
+	account            account.Account
+	arbitrators        state.Arbitrators
 	announceAddr       func()
+	updatePID          func(pid []byte)
+
+	// switchLock serializes the node key switches, keyLock protects the
+	// public key from the readers outside of the switch.
+	switchLock     sync.Mutex
+	keyLock        sync.RWMutex
+	publicKey      []byte
+	ownerPublicKey []byte
 
 	p2pServer    p2p.Server
 	messageQueue chan *messageItem
@@ -60,7 +71,9 @@ func (n *network) Initialize(dnConfig manager.DPOSNetworkConfig) {
 	n.proposalDispatcher = dnConfig.ProposalDispatcher
 	n.store = dnConfig.Store
 	n.publicKey = dnConfig.PublicKey
+	n.arbitrators = dnConfig.Arbitrators
 	n.announceAddr = dnConfig.AnnounceAddr
+	n.updatePID = dnConfig.UpdatePID
 }
 
 func (n *network) Start() {
@@ -102,11 +115,20 @@ func (n *network) Stop() error {
 	return n.p2pServer.Stop()
 }
 
+func (n *network) getPublicKey() []byte {
+	n.keyLock.RLock()
+	defer n.keyLock.RUnlock()
+	return n.publicKey
+}
+
 func (n *network) UpdatePeers(peers []peer.PID) {
 	log.Info("[UpdatePeers] peers:", len(peers), " height: ",
 		blockchain.DefaultLedger.Blockchain.GetHeight())
+	n.checkNodeKey()
+
+	publicKey := n.getPublicKey()
 	for _, p := range peers {
-		if bytes.Equal(n.publicKey, p[:]) {
+		if bytes.Equal(publicKey, p[:]) {
 			n.p2pServer.ConnectPeers(peers)
 			return
 		}
@@ -294,6 +316,91 @@ func (n *network) confirmReceived(p *payload.Confirm) {
 	n.listener.OnConfirmReceived(p)
 }
 
+// nodeKeyRotated handles the node public key of an arbiter rotated by an
+// UpdateProducer transaction.  It's invoked while the block of the rotation is
+// processed, before the arbiters change is notified, so the local arbiter
+// switches to the new key from the next height, and the connection to a
+// rotated peer is kept under the new PID.
+func (n *network) nodeKeyRotated(r *state.NodeKeyRotation) {
+	n.switchLock.Lock()
+	defer n.switchLock.Unlock()
+
+	if bytes.Equal(n.getPublicKey(), r.OldNodePublicKey) {
+		if err := n.switchNodeKey(r.NewNodePublicKey); err != nil {
+			log.Error("[nodeKeyRotated] switch node key error: ", err)
+		}
+		return
+	}
+
+	var oldPID, newPID peer.PID
+	copy(oldPID[:], r.OldNodePublicKey)
+	copy(newPID[:], r.NewNodePublicKey)
+	n.p2pServer.ReplacePeer(oldPID, newPID)
+}
+
+// checkNodeKey switches the node key of the arbiter when the node public key
+// of the local producer has been changed by an UpdateProducer transaction, but
+// the previous node key has quit from both current and next arbiters.  It
+// covers the rotations before the NodeKeyRotationHeight, and retries the
+// switch failed on rotation, such as the new key was not in the keystore yet.
+func (n *network) checkNodeKey() {
+	n.switchLock.Lock()
+	defer n.switchLock.Unlock()
+
+	publicKey := n.getPublicKey()
+	if n.ownerPublicKey == nil {
+		producer := n.arbitrators.GetProducer(publicKey)
+		if producer == nil {
+			return
+		}
+		n.ownerPublicKey = producer.OwnerPublicKey()
+	}
+
+	producer := n.arbitrators.GetProducer(n.ownerPublicKey)
+	if producer == nil || bytes.Equal(producer.NodePublicKey(), publicKey) {
+		return
+	}
+	if n.arbitrators.IsArbitrator(publicKey) {
+		return
+	}
+	for _, a := range n.arbitrators.GetNextArbitrators() {
+		if bytes.Equal(a, publicKey) {
+			return
+		}
+	}
+
+	if err := n.switchNodeKey(producer.NodePublicKey()); err != nil {
+		log.Error("[checkNodeKey] switch node key error: ", err)
+	}
+}
+
+// switchNodeKey switches the account and the PID of the P2P server to the
+// given node key, the connected peers are kept.  It must be called with the
+// switch lock held.
+func (n *network) switchNodeKey(publicKey []byte) error {
+	log.Info("[switchNodeKey] switch node key from ",
+		common.BytesToHexString(n.getPublicKey()), " to ",
+		common.BytesToHexString(publicKey))
+	if err := n.account.SwitchKey(publicKey); err != nil {
+		return err
+	}
+
+	var pid peer.PID
+	copy(pid[:], publicKey)
+	n.p2pServer.SetPID(pid)
+
+	n.keyLock.Lock()
+	n.publicKey = publicKey
+	n.keyLock.Unlock()
+
+	n.listener.OnNodeKeyChanged(publicKey)
+
+	if n.updatePID != nil {
+		n.updatePID(publicKey)
+	}
+	return nil
+}
+
 func (n *network) illegalBlocksReceived(i *payload.DPOSIllegalBlocks) {
 	n.listener.OnIllegalBlocksTxReceived(i)
 }
@@ -333,6 +440,7 @@ func NewDposNetwork(account account.Account, medianTime dtime.MedianTimeSource,
 
 	var pid peer.PID
 	copy(pid[:], account.PublicKeyBytes())
+	network.account = account
 	server, err := p2p.NewServer(&p2p.Config{
 		DataDir:          dataPathDPoS,
 		PID:              pid,
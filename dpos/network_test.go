@@ -0,0 +1,240 @@
+package dpos
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/blockchain"
+	"github.com/elastos/Elastos.ELA/common/config"
+	elalog "github.com/elastos/Elastos.ELA/common/log"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/dpos/account"
+	"github.com/elastos/Elastos.ELA/dpos/log"
+	"github.com/elastos/Elastos.ELA/dpos/manager"
+	"github.com/elastos/Elastos.ELA/dpos/p2p"
+	"github.com/elastos/Elastos.ELA/dpos/p2p/peer"
+	"github.com/elastos/Elastos.ELA/dpos/state"
+	"github.com/elastos/Elastos.ELA/utils/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockServer struct {
+	p2p.Server
+	pid       peer.PID
+	replaced  map[peer.PID]peer.PID
+	connected []peer.PID
+}
+
+func (s *mockServer) SetPID(pid peer.PID) { s.pid = pid }
+func (s *mockServer) ReplacePeer(oldPID, newPID peer.PID) {
+	s.replaced[oldPID] = newPID
+}
+func (s *mockServer) ConnectPeers(peers []peer.PID) {
+	s.connected = peers
+}
+
+type mockAccount struct {
+	account.Account
+	publicKey []byte
+	err       error
+}
+
+func (a *mockAccount) SwitchKey(publicKey []byte) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.publicKey = publicKey
+	return nil
+}
+
+type mockListener struct {
+	manager.NetworkEventListener
+	nodeKey []byte
+}
+
+func (l *mockListener) OnConfirmReceived(p *payload.Confirm) {}
+
+func (l *mockListener) OnNodeKeyChanged(publicKey []byte) {
+	l.nodeKey = publicKey
+}
+
+func initLedger(t *testing.T) {
+	elalog.NewDefault(test.NodeLogPath, 0, 0, 0)
+	log.Init(0, 0, 0)
+	params := &config.DefaultParams
+	chainStore, err := blockchain.NewChainStore(t.TempDir(),
+		params.GenesisBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := blockchain.New(chainStore, params, state.NewState(params,
+		nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := blockchain.DefaultLedger
+	blockchain.DefaultLedger = &blockchain.Ledger{
+		Blockchain: chain,
+		Store:      chainStore,
+	}
+	t.Cleanup(func() {
+		chainStore.Close()
+		blockchain.DefaultLedger = original
+	})
+}
+
+func newTestNetwork(publicKey []byte, arbiters *state.ArbitratorsMock) (
+	*network, *mockAccount, *mockListener, *mockServer) {
+	server := &mockServer{replaced: make(map[peer.PID]peer.PID)}
+	copy(server.pid[:], publicKey)
+
+	ac := &mockAccount{publicKey: publicKey}
+	listener := &mockListener{}
+	n := &network{
+		listener:    listener,
+		account:     ac,
+		arbitrators: arbiters,
+		publicKey:   publicKey,
+		p2pServer:   server,
+	}
+	return n, ac, listener, server
+}
+
+func TestNetwork_NodeKeyRotated(t *testing.T) {
+	initLedger(t)
+
+	oldKey := bytes.Repeat([]byte{2}, 33)
+	newKey := bytes.Repeat([]byte{3}, 33)
+	otherKey := bytes.Repeat([]byte{4}, 33)
+	otherNewKey := bytes.Repeat([]byte{5}, 33)
+
+	arbiters := state.NewArbitratorsMock([][]byte{oldKey, otherKey}, 0, 2)
+	n, ac, listener, server := newTestNetwork(oldKey, arbiters)
+	var updatedPID []byte
+	n.updatePID = func(pid []byte) { updatedPID = pid }
+
+	// the connection to a rotated peer is kept under the new PID
+	var otherPID, otherNewPID peer.PID
+	copy(otherPID[:], otherKey)
+	copy(otherNewPID[:], otherNewKey)
+	n.nodeKeyRotated(&state.NodeKeyRotation{
+		Height:           20,
+		OldNodePublicKey: otherKey,
+		NewNodePublicKey: otherNewKey,
+	})
+	assert.Equal(t, map[peer.PID]peer.PID{otherPID: otherNewPID},
+		server.replaced)
+	assert.Equal(t, oldKey, n.getPublicKey())
+	assert.Nil(t, listener.nodeKey)
+
+	// the local arbiter switches the key at the rotation height, the server
+	// keeps running with the new PID
+	n.nodeKeyRotated(&state.NodeKeyRotation{
+		Height:           21,
+		OldNodePublicKey: oldKey,
+		NewNodePublicKey: newKey,
+	})
+	assert.Equal(t, newKey, n.getPublicKey())
+	assert.Equal(t, newKey, ac.publicKey)
+	assert.Equal(t, newKey, listener.nodeKey)
+	assert.Equal(t, newKey, updatedPID)
+	assert.Equal(t, newKey, server.pid[:])
+	assert.Len(t, server.replaced, 1)
+}
+
+func TestNetwork_CheckNodeKey(t *testing.T) {
+	initLedger(t)
+
+	owner := bytes.Repeat([]byte{1}, 33)
+	oldKey := bytes.Repeat([]byte{2}, 33)
+	newKey := bytes.Repeat([]byte{3}, 33)
+	otherKey := bytes.Repeat([]byte{4}, 33)
+
+	arbiters := state.NewArbitratorsMock([][]byte{oldKey, otherKey}, 0, 2)
+	arbiters.SetNextArbitrators([][]byte{oldKey, otherKey})
+	arbiters.SetProducer(payload.ProducerInfo{
+		OwnerPublicKey: owner,
+		NodePublicKey:  oldKey,
+	})
+
+	n, ac, listener, server := newTestNetwork(oldKey, arbiters)
+	var newPID, otherPID peer.PID
+	copy(newPID[:], newKey)
+	copy(otherPID[:], otherKey)
+	peers := []peer.PID{newPID, otherPID}
+
+	assertNotSwitched := func() {
+		n.UpdatePeers(peers)
+		assert.Equal(t, oldKey, n.getPublicKey())
+		assert.Equal(t, oldKey, ac.publicKey)
+		assert.Equal(t, oldKey, server.pid[:])
+		assert.Nil(t, listener.nodeKey)
+		assert.Nil(t, server.connected)
+	}
+
+	// the node key of the producer is not changed
+	assertNotSwitched()
+	assert.Equal(t, owner, n.ownerPublicKey)
+
+	// the node key changed before the rotation height, but the old key is
+	// still in current arbiters
+	arbiters.SetProducer(payload.ProducerInfo{
+		OwnerPublicKey: owner,
+		NodePublicKey:  newKey,
+	})
+	assertNotSwitched()
+
+	// the old key quit from current arbiters, but still in next arbiters
+	arbiters.SetArbitrators([][]byte{newKey, otherKey})
+	assertNotSwitched()
+
+	// the old key quit from both current and next arbiters
+	arbiters.SetNextArbitrators([][]byte{newKey, otherKey})
+	n.UpdatePeers(peers)
+	assert.Equal(t, newKey, n.getPublicKey())
+	assert.Equal(t, newKey, ac.publicKey)
+	assert.Equal(t, newKey, listener.nodeKey)
+	assert.Equal(t, newKey, server.pid[:])
+	assert.Equal(t, peers, server.connected)
+}
+
+func TestNetwork_CheckNodeKey_SwitchFailed(t *testing.T) {
+	initLedger(t)
+
+	owner := bytes.Repeat([]byte{1}, 33)
+	oldKey := bytes.Repeat([]byte{2}, 33)
+	newKey := bytes.Repeat([]byte{3}, 33)
+
+	arbiters := state.NewArbitratorsMock([][]byte{oldKey}, 0, 1)
+	arbiters.SetProducer(payload.ProducerInfo{
+		OwnerPublicKey: owner,
+		NodePublicKey:  oldKey,
+	})
+	n, ac, listener, server := newTestNetwork(oldKey, arbiters)
+	n.checkNodeKey()
+	arbiters.SetProducer(payload.ProducerInfo{
+		OwnerPublicKey: owner,
+		NodePublicKey:  newKey,
+	})
+
+	// the new key is not in the keystore, keep running with the old key
+	ac.err = errors.New("key not found")
+	n.nodeKeyRotated(&state.NodeKeyRotation{
+		Height:           20,
+		OldNodePublicKey: oldKey,
+		NewNodePublicKey: newKey,
+	})
+	assert.Equal(t, oldKey, n.getPublicKey())
+	assert.Equal(t, oldKey, server.pid[:])
+	assert.Nil(t, listener.nodeKey)
+
+	// retried on the next check after the key is imported
+	arbiters.SetArbitrators([][]byte{newKey})
+	ac.err = nil
+	n.checkNodeKey()
+	assert.Equal(t, newKey, n.getPublicKey())
+	assert.Equal(t, newKey, listener.nodeKey)
+	assert.Equal(t, newKey, server.pid[:])
+}
@@ -95,6 +95,13 @@ type unregister struct {
 	pid [33]byte
 }
 
+// replace is used to replace the PID of a connection request.
+type replace struct {
+	oldPID [33]byte
+	newPID [33]byte
+	done   chan *ConnReq
+}
+
 // handleConnected is used to queue a successful connection.
 type handleConnected struct {
 	c    *ConnReq
@@ -191,6 +198,33 @@ out:
 					connReq.conn.Close()
 				}
 
+			case replace:
+				connReq, ok := reqs[msg.oldPID]
+				if !ok {
+					msg.done <- nil
+					continue
+				}
+
+				// The connection request is replaced by a new one, so the
+				// lingering attempt of the old request will be ignored.
+				newReq := &ConnReq{
+					PID:        msg.newPID,
+					Addr:       connReq.Addr,
+					retryCount: connReq.retryCount,
+				}
+				delete(reqs, msg.oldPID)
+				reqs[msg.newPID] = newReq
+
+				if c, ok := conns[msg.oldPID]; ok {
+					newReq.conn = c.conn
+					delete(conns, msg.oldPID)
+					conns[msg.newPID] = newReq
+				} else {
+					go cm.connect(newReq)
+				}
+				log.Debugf("Replaced %v with %v", connReq, newReq)
+				msg.done <- newReq
+
 			case handleConnected:
 				connReq := msg.c
 
@@ -331,6 +365,29 @@ func (cm *ConnManager) Disconnect(pid [33]byte) {
 	}
 }
 
+// Replace replaces the PID of the connection request without closing the
+// connection, it returns the new connection request or nil if the old PID
+// has not been registered.
+func (cm *ConnManager) Replace(oldPID, newPID [33]byte) *ConnReq {
+	if atomic.LoadInt32(&cm.stop) != 0 {
+		return nil
+	}
+
+	done := make(chan *ConnReq)
+	select {
+	case cm.requests <- replace{oldPID, newPID, done}:
+	case <-cm.quit:
+		return nil
+	}
+
+	select {
+	case c := <-done:
+		return c
+	case <-cm.quit:
+		return nil
+	}
+}
+
 // Remove removes the connection corresponding to the given connection id from
 // known connections.
 //
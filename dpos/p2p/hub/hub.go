@@ -6,6 +6,7 @@ package hub
 import (
 	"io"
 	"net"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -115,11 +116,12 @@ type inbound *Conn
 type outbound *Conn
 
 type Hub struct {
-	magic uint32
-	pid   peer.PID
-	admgr *addrmgr.AddrManager
-	queue chan interface{}
-	quit  chan struct{}
+	magic   uint32
+	pidLock sync.RWMutex
+	pid     peer.PID
+	admgr   *addrmgr.AddrManager
+	queue   chan interface{}
+	quit    chan struct{}
 }
 
 // createPipe creates a pipe between inlet connection and the network address.
@@ -249,7 +251,7 @@ func (h *Hub) Intercept(conn net.Conn) net.Conn {
 	}
 
 	// The connection come from our own service.
-	if h.pid.Equal(c.PID()) {
+	if h.getPID().Equal(c.PID()) {
 		h.queue <- outbound(c)
 		return nil
 	}
@@ -259,6 +261,20 @@ func (h *Hub) Intercept(conn net.Conn) net.Conn {
 	return nil
 }
 
+// SetPID changes the arbiter PID of the hub, it's invoked when the node key of
+// the arbiter changed.
+func (h *Hub) SetPID(pid peer.PID) {
+	h.pidLock.Lock()
+	h.pid = pid
+	h.pidLock.Unlock()
+}
+
+func (h *Hub) getPID() peer.PID {
+	h.pidLock.RLock()
+	defer h.pidLock.RUnlock()
+	return h.pid
+}
+
 // New creates a new Hub instance with the main network magic, arbiter PID and
 // DPOS network AddrManager.
 func New(magic uint32, pid [33]byte, admgr *addrmgr.AddrManager) *Hub {
@@ -96,6 +96,14 @@ type Server interface {
 	// peers and the main listener.
 	Stop() error
 
+	// SetPID changes the PID of the server, the connected peers are kept and
+	// the new PID is used by the following handshakes.
+	SetPID(pid peer.PID)
+
+	// ReplacePeer replaces the PID of the peer in connect list and the
+	// connected peers with the new one, without closing the connections.
+	ReplacePeer(oldPID, newPID peer.PID)
+
 	// AddAddr adds an arbiter address into AddrManager.
 	AddAddr(pid peer.PID, addr string)
 
@@ -233,6 +233,23 @@ func (p *Peer) PID() PID {
 	return pid
 }
 
+// SetPID changes the public key id of the peer, it's invoked when the node key
+// of the remote arbiter has been rotated.
+//
+// This function is safe for concurrent access.
+func (p *Peer) SetPID(pid PID) error {
+	pk, err := crypto.DecodePoint(pid[:])
+	if err != nil {
+		return err
+	}
+
+	p.flagsMtx.Lock()
+	p.pk = pk
+	p.pid = pid
+	p.flagsMtx.Unlock()
+	return nil
+}
+
 // PK returns the peer public key.
 //
 // This function is safe for concurrent access.
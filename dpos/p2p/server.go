@@ -131,6 +131,8 @@ type server struct {
 	startupTime   int64
 
 	cfg         Config
+	pidLock     sync.RWMutex
+	pid         peer.PID
 	hubService  *hub.Hub
 	addrManager *addrmgr.AddrManager
 	connManager *connmgr.ConnManager
@@ -328,6 +330,12 @@ type dumpPeersInfoMsg struct {
 	reply chan []*PeerInfo
 }
 
+type replacePeerMsg struct {
+	oldPID peer.PID
+	newPID peer.PID
+	reply  chan struct{}
+}
+
 // handleQuery is the central handler for all queries and commands from other
 // goroutines related to peer state.
 func (s *server) handleQuery(state *peerState, querymsg interface{}) {
@@ -342,7 +350,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		// Loop through the new received connect peer addresses.
 		for _, pid := range msg.peers {
 			// Do not create connection to self.
-			if pid.Equal(s.cfg.PID) {
+			if pid.Equal(s.getPID()) {
 				continue
 			}
 
@@ -477,6 +485,43 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 			}
 		}
 		msg.reply <- sortPeersInfo(peers)
+
+	case replacePeerMsg:
+		// Rename the peer in connect list, so it will not be disconnected by
+		// the following connectPeers message.
+		if _, ok := state.connectPeers[msg.oldPID]; ok {
+			delete(state.connectPeers, msg.oldPID)
+			state.connectPeers[msg.newPID] = struct{}{}
+		}
+
+		// The rotated arbiter is still listening on the same address.
+		if na := s.addrManager.GetAddress(msg.oldPID); na != nil {
+			s.addrManager.AddAddress(msg.newPID, na)
+		}
+
+		connReq := s.connManager.Replace(msg.oldPID, msg.newPID)
+		state.forAllPeers(func(sp *serverPeer) {
+			if !sp.PID().Equal(msg.oldPID) {
+				return
+			}
+			if err := sp.SetPID(msg.newPID); err != nil {
+				log.Warnf("Disconnecting peer %v - %v", sp, err)
+				sp.Disconnect()
+				return
+			}
+			if sp.connReq != nil && connReq != nil {
+				sp.connReq = connReq
+			}
+
+			// Notify peer state change for the renamed peer, the new one
+			// first so the connected count does not drop.
+			if s.cfg.StateNotifier != nil {
+				s.cfg.StateNotifier.OnNewPeer(msg.newPID)
+				s.cfg.StateNotifier.OnDonePeer(msg.oldPID)
+			}
+		})
+
+		msg.reply <- struct{}{}
 	}
 }
 
@@ -509,7 +554,7 @@ func (s *server) pongNonce(pid peer.PID) uint64 {
 // newPeerConfig returns the configuration for the given serverPeer.
 func newPeerConfig(sp *serverPeer) *peer.Config {
 	return &peer.Config{
-		PID:              sp.server.cfg.PID,
+		PID:              sp.server.getPID(),
 		Magic:            sp.server.cfg.MagicNumber,
 		Port:             sp.server.cfg.DefaultPort,
 		PingInterval:     sp.server.cfg.PingInterval,
@@ -679,6 +724,33 @@ func (s *server) ConnectedCount() int32 {
 	return <-replyChan
 }
 
+// getPID returns the current PID of the server.
+func (s *server) getPID() peer.PID {
+	s.pidLock.RLock()
+	defer s.pidLock.RUnlock()
+	return s.pid
+}
+
+// SetPID changes the PID of the server, the connected peers are kept and the
+// new PID is used by the following handshakes.
+func (s *server) SetPID(pid peer.PID) {
+	s.pidLock.Lock()
+	s.pid = pid
+	s.pidLock.Unlock()
+
+	if s.hubService != nil {
+		s.hubService.SetPID(pid)
+	}
+}
+
+// ReplacePeer replaces the PID of the peer in connect list and the connected
+// peers with the new one, without closing the connections.
+func (s *server) ReplacePeer(oldPID, newPID peer.PID) {
+	reply := make(chan struct{})
+	s.query <- replacePeerMsg{oldPID: oldPID, newPID: newPID, reply: reply}
+	<-reply
+}
+
 // ConnectPeers let server connect the peers in the given peers, and
 // disconnect peers that not in the peers.
 func (s *server) ConnectPeers(peers []peer.PID) {
@@ -852,6 +924,7 @@ func NewServer(origCfg *Config) (*server, error) {
 
 	s := server{
 		cfg:         cfg,
+		pid:         cfg.PID,
 		hubService:  hubService,
 		addrManager: admgr,
 		peerQueue:   make(chan interface{}, maxPeers),
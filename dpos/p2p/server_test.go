@@ -704,6 +704,85 @@ func TestServer_DumpPeersInfo(t *testing.T) {
 	}
 }
 
+func TestServer_ReplacePeer(t *testing.T) {
+	// Start peer-to-peer server
+	pid := peer.PID{}
+	priKey, pubKey, _ := crypto.GenerateKeyPair()
+	ePubKey, _ := pubKey.EncodePoint(true)
+	copy(pid[:], ePubKey)
+	server, err := NewServer(&Config{
+		PID:         pid,
+		MagicNumber: 123123,
+		DefaultPort: 20339,
+		TimeSource:  dtime.NewMedianTime(),
+		Sign: func(nonce []byte) []byte {
+			sign, _ := crypto.Sign(priKey, nonce)
+			return sign
+		},
+		MakeEmptyMessage: makeEmptyMessage,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer server.Stop()
+	server.Start()
+
+	peerChan := make(chan *peer.Peer)
+	msgChan := make(chan p2p.Message)
+
+	// Mock a remote peer and wait for it connected.
+	var oldPID, newPID peer.PID
+	priKey, pubKey, _ = crypto.GenerateKeyPair()
+	ePubKey, _ = pubKey.EncodePoint(true)
+	copy(oldPID[:], ePubKey)
+	server.AddAddr(oldPID, "127.0.0.1:20120")
+	err = mockRemotePeer(oldPID, priKey, 20120, peerChan, msgChan)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	server.ConnectPeers([]peer.PID{oldPID})
+	select {
+	case <-peerChan:
+	case <-time.After(time.Minute):
+		t.Fatalf("Connect peers timeout")
+	}
+
+	// The node key of the remote peer rotated.
+	_, pubKey, _ = crypto.GenerateKeyPair()
+	ePubKey, _ = pubKey.EncodePoint(true)
+	copy(newPID[:], ePubKey)
+	server.ReplacePeer(oldPID, newPID)
+	server.ConnectPeers([]peer.PID{newPID})
+
+	// The connection is kept under the new PID.
+	peers := server.ConnectedPeers()
+	if !assert.Len(t, peers, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, newPID, peers[0].PID())
+	info := server.DumpPeersInfo()
+	if assert.Len(t, info, 1) {
+		assert.Equal(t, newPID, info[0].PID)
+		assert.Equal(t, CSOutboundOnly, info[0].State)
+	}
+	assert.NoError(t, server.SendMessageToPeer(newPID, &message{pid: newPID}))
+	select {
+	case m := <-msgChan:
+		assert.Equal(t, newPID, m.(*message).pid)
+	case <-time.After(time.Minute):
+		t.Fatalf("Send message timeout")
+	}
+	assert.Error(t, server.SendMessageToPeer(oldPID, &message{}))
+
+	// The server PID is changed without dropping the connected peers.
+	_, pubKey, _ = crypto.GenerateKeyPair()
+	ePubKey, _ = pubKey.EncodePoint(true)
+	copy(pid[:], ePubKey)
+	server.SetPID(pid)
+	assert.Equal(t, pid, server.getPID())
+	assert.Len(t, server.ConnectedPeers(), 1)
+}
+
 func makeEmptyMessage(cmd string) (m p2p.Message, e error) {
 	switch cmd {
 	case p2p.CmdReject:
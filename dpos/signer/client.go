@@ -0,0 +1,123 @@
+package signer
+
+import (
+	"net/rpc"
+	"sync"
+
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+)
+
+// Client is the client to send sign requests to the signer daemon.
+type Client struct {
+	socket string
+
+	mtx    sync.Mutex
+	client *rpc.Client
+}
+
+// call invokes the signer service method, the connection will be re-dialed
+// once if it has been shutdown, in case of the signer daemon restarted.
+func (c *Client) call(method string, args interface{}, reply *Reply) error {
+	c.mtx.Lock()
+	client := c.client
+	c.mtx.Unlock()
+
+	err := client.Call(serviceName+"."+method, args, reply)
+	if err != rpc.ErrShutdown {
+		return err
+	}
+
+	client, err = c.redial(client)
+	if err != nil {
+		return err
+	}
+	return client.Call(serviceName+"."+method, args, reply)
+}
+
+// redial replaces the shutdown client with a new connection, the connection
+// re-dialed by another caller will be returned if it has been replaced.
+func (c *Client) redial(shutdown *rpc.Client) (*rpc.Client, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.client != shutdown {
+		return c.client, nil
+	}
+	client, err := rpc.Dial("unix", c.socket)
+	if err != nil {
+		return nil, err
+	}
+	c.client.Close()
+	c.client = client
+	return client, nil
+}
+
+// PublicKey returns the public key if the key pair exists in the signer, or
+// the public key of main account if the given public key is empty.
+func (c *Client) PublicKey(publicKey []byte) ([]byte, error) {
+	var reply Reply
+	err := c.call("PublicKey", &KeyArgs{PublicKey: publicKey}, &reply)
+	return reply.Data, err
+}
+
+// SignProposal signs the proposal of the given height.
+func (c *Client) SignProposal(height uint32,
+	proposal *payload.DPOSProposal) ([]byte, error) {
+	var reply Reply
+	err := c.call("SignProposal", &ProposalArgs{
+		Height:   height,
+		Proposal: proposal.Data(),
+	}, &reply)
+	return reply.Data, err
+}
+
+// SignVote signs the vote of the given height and view offset.
+func (c *Client) SignVote(height, viewOffset uint32,
+	vote *payload.DPOSProposalVote) ([]byte, error) {
+	var reply Reply
+	err := c.call("SignVote", &VoteArgs{
+		Height:     height,
+		ViewOffset: viewOffset,
+		Vote:       vote.Data(),
+	}, &reply)
+	return reply.Data, err
+}
+
+// AdvanceHeight moves the guarded height of the signer to the given best
+// height of the node.
+func (c *Client) AdvanceHeight(height uint32) error {
+	var reply Reply
+	return c.call("AdvanceHeight", &HeightArgs{Height: height}, &reply)
+}
+
+// Sign signs the data with the key pair of the given public key.
+func (c *Client) Sign(publicKey, data []byte) ([]byte, error) {
+	var reply Reply
+	err := c.call("Sign", &DataArgs{PublicKey: publicKey, Data: data},
+		&reply)
+	return reply.Data, err
+}
+
+// Decrypt decrypts the cipher with the key pair of the given public key.
+func (c *Client) Decrypt(publicKey, cipher []byte) ([]byte, error) {
+	var reply Reply
+	err := c.call("Decrypt", &DataArgs{PublicKey: publicKey, Data: cipher},
+		&reply)
+	return reply.Data, err
+}
+
+// Close closes the connection to the signer daemon.
+func (c *Client) Close() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.client.Close()
+}
+
+// Dial connects to the signer daemon listening on the given Unix socket.
+func Dial(socket string) (*Client, error) {
+	client, err := rpc.Dial("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{socket: socket, client: client}, nil
+}
@@ -0,0 +1,258 @@
+package signer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+)
+
+const (
+	// MaxGuardedHeights indicates how many recent heights of signed records
+	// will be kept in Guard, sign requests lower than these heights will be
+	// refused.
+	MaxGuardedHeights = 100
+
+	// MaxHeightAdvance indicates how many heights a sign request can be above
+	// the guarded best height. The heights of sign requests are not verified,
+	// a request far above the best height will be refused so that it can not
+	// move the guarded heights away from the chain.
+	MaxHeightAdvance = 10
+)
+
+// record is the key of a signed proposal or vote.
+type record struct {
+	publicKey  string
+	height     uint32
+	viewOffset uint32
+}
+
+// blockRecord is the key of a signed proposal by the proposed block, a block
+// can only be proposed at the height of it.
+type blockRecord struct {
+	publicKey  string
+	viewOffset uint32
+	blockHash  common.Uint256
+}
+
+// SignRecord is a signed proposal or vote persisted in Journal.
+type SignRecord struct {
+	Vote       bool
+	PublicKey  []byte
+	Height     uint32
+	ViewOffset uint32
+	Hash       common.Uint256
+
+	// BlockHash is the hash of the proposed block of a proposal.
+	BlockHash common.Uint256
+}
+
+// Journal persists the sign records of a Guard, so that the signed proposals
+// and votes survive restarts.
+type Journal interface {
+	// LoadSignRecords returns all persisted sign records.
+	LoadSignRecords() ([]*SignRecord, error)
+
+	// AppendSignRecord persists a new sign record.
+	AppendSignRecord(r *SignRecord) error
+
+	// PruneSignRecords removes the sign records lower than or equal to the
+	// given height.
+	PruneSignRecords(height uint32) error
+}
+
+// Guard records the signed proposals and votes, and refuses to sign a
+// different proposal or vote of the same signer at the same height and view.
+type Guard struct {
+	mtx        sync.Mutex
+	journal    Journal
+	bestHeight uint32
+	proposals  map[record]common.Uint256
+	votes      map[record]common.Uint256
+	blocks     map[blockRecord]uint32
+}
+
+// AdvanceHeight moves the guarded best height to the given height of a
+// verified chain, sign requests up to MaxHeightAdvance above it are allowed.
+func (g *Guard) AdvanceHeight(height uint32) error {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if height <= g.bestHeight {
+		return nil
+	}
+	g.bestHeight = height
+	return g.prune()
+}
+
+// CheckProposal checks if the proposal can be signed at the given height, the
+// proposal will be recorded if no error returned.
+func (g *Guard) CheckProposal(height uint32, p *payload.DPOSProposal) error {
+	r := record{
+		publicKey:  common.BytesToHexString(p.Sponsor),
+		height:     height,
+		viewOffset: p.ViewOffset,
+	}
+	b := blockRecord{
+		publicKey:  r.publicKey,
+		viewOffset: p.ViewOffset,
+		blockHash:  p.BlockHash,
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	// The height is claimed by the request, the same block proposed at
+	// another height is a different proposal of the block's height.
+	if signed, ok := g.blocks[b]; ok && signed != height {
+		return fmt.Errorf("refuse to sign proposal of block %s at height %d,"+
+			" it has been proposed at height %d view %d", p.BlockHash,
+			height, signed, p.ViewOffset)
+	}
+	if err := g.check(g.proposals, r, &SignRecord{
+		PublicKey:  p.Sponsor,
+		Height:     height,
+		ViewOffset: p.ViewOffset,
+		Hash:       p.Hash(),
+		BlockHash:  p.BlockHash,
+	}); err != nil {
+		return err
+	}
+	g.blocks[b] = height
+	return nil
+}
+
+// CheckVote checks if the vote can be signed at the given height and view
+// offset, the vote will be recorded if no error returned.
+func (g *Guard) CheckVote(height, viewOffset uint32,
+	v *payload.DPOSProposalVote) error {
+	r := record{
+		publicKey:  common.BytesToHexString(v.Signer),
+		height:     height,
+		viewOffset: viewOffset,
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	return g.check(g.votes, r, &SignRecord{
+		Vote:       true,
+		PublicKey:  v.Signer,
+		Height:     height,
+		ViewOffset: viewOffset,
+		Hash:       v.Hash(),
+	})
+}
+
+// check checks and records the sign record, the mutex should be held.
+func (g *Guard) check(records map[record]common.Uint256, r record,
+	sr *SignRecord) error {
+	kind := "proposal"
+	if sr.Vote {
+		kind = "vote"
+	}
+
+	if g.bestHeight >= MaxGuardedHeights &&
+		r.height <= g.bestHeight-MaxGuardedHeights {
+		return fmt.Errorf("%s height %d is lower than guarded heights",
+			kind, r.height)
+	}
+	if r.height > g.bestHeight+MaxHeightAdvance {
+		return fmt.Errorf("%s height %d is more than %d heights above the "+
+			"guarded height %d", kind, r.height, MaxHeightAdvance,
+			g.bestHeight)
+	}
+
+	if signed, ok := records[r]; ok {
+		if signed.IsEqual(sr.Hash) {
+			return nil
+		}
+		return fmt.Errorf("refuse to sign %s %s, another %s %s has been "+
+			"signed at height %d view %d", kind, sr.Hash, kind, signed,
+			r.height, r.viewOffset)
+	}
+	// The record must be persisted before signing.
+	if g.journal != nil {
+		if err := g.journal.AppendSignRecord(sr); err != nil {
+			return err
+		}
+	}
+	records[r] = sr.Hash
+
+	if r.height > g.bestHeight {
+		g.bestHeight = r.height
+		return g.prune()
+	}
+	return nil
+}
+
+// prune removes the records lower than guarded heights.
+func (g *Guard) prune() error {
+	if g.bestHeight < MaxGuardedHeights {
+		return nil
+	}
+	minHeight := g.bestHeight - MaxGuardedHeights
+	for r := range g.proposals {
+		if r.height <= minHeight {
+			delete(g.proposals, r)
+		}
+	}
+	for r := range g.votes {
+		if r.height <= minHeight {
+			delete(g.votes, r)
+		}
+	}
+	for b, height := range g.blocks {
+		if height <= minHeight {
+			delete(g.blocks, b)
+		}
+	}
+
+	if g.journal != nil {
+		return g.journal.PruneSignRecords(minHeight)
+	}
+	return nil
+}
+
+// NewGuard creates and returns a Guard instance.
+func NewGuard() *Guard {
+	return &Guard{
+		proposals: make(map[record]common.Uint256),
+		votes:     make(map[record]common.Uint256),
+		blocks:    make(map[blockRecord]uint32),
+	}
+}
+
+// NewJournalGuard creates a Guard instance with the sign records loaded from
+// the given journal, and new sign records will be persisted into the journal.
+func NewJournalGuard(journal Journal) (*Guard, error) {
+	records, err := journal.LoadSignRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewGuard()
+	for _, sr := range records {
+		r := record{
+			publicKey:  common.BytesToHexString(sr.PublicKey),
+			height:     sr.Height,
+			viewOffset: sr.ViewOffset,
+		}
+		if sr.Vote {
+			g.votes[r] = sr.Hash
+		} else {
+			g.proposals[r] = sr.Hash
+			g.blocks[blockRecord{
+				publicKey:  r.publicKey,
+				viewOffset: sr.ViewOffset,
+				blockHash:  sr.BlockHash,
+			}] = sr.Height
+		}
+		if sr.Height > g.bestHeight {
+			g.bestHeight = sr.Height
+		}
+	}
+	g.journal = journal
+	return g, nil
+}
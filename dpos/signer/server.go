@@ -0,0 +1,133 @@
+/*
+This package provides the DPoS signer daemon, it holds the arbiter key pairs in
+an isolated process and signs consensus messages for arbiters over a Unix
+socket, with double sign protection.
+*/
+package signer
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"os"
+	"path/filepath"
+
+	"github.com/elastos/Elastos.ELA/account"
+)
+
+// Config defines the parameters to create a signer Server.
+type Config struct {
+	// Socket is the Unix socket path the signer listens on.
+	Socket string
+
+	// Client is the keystore client holds the arbiter key pairs.
+	Client *account.Client
+
+	// Journal persists the signed proposals and votes, so that the double
+	// sign guard still works after the signer restarted.
+	Journal Journal
+
+	// Height is the current height of the chain given by the operator, the
+	// sign requests are allowed up to MaxHeightAdvance above the height or
+	// the signed records in journal.
+	Height uint32
+}
+
+// Server is the signer daemon serving sign requests over a Unix socket.
+type Server struct {
+	socket   string
+	listener net.Listener
+	server   *rpc.Server
+}
+
+// Start starts accepting sign requests.
+func (s *Server) Start() {
+	go s.server.Accept(s.listener)
+}
+
+// Stop stops accepting sign requests and removes the socket file.
+func (s *Server) Stop() error {
+	if err := s.listener.Close(); err != nil {
+		return err
+	}
+	return os.Remove(s.socket)
+}
+
+// listen listens on the Unix socket only the owner can connect to. The socket
+// is created in a private directory and moved to the path after its mode is
+// changed, so that it's never accessible by the other users.
+func listen(socket string) (net.Listener, error) {
+	dir, err := ioutil.TempDir(filepath.Dir(socket), ".signer")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(socket))
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	// The socket file is moved, it's removed by Stop instead of the listener.
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(path, socket); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// NewServer creates and returns a signer Server instance.
+func NewServer(cfg *Config) (*Server, error) {
+	if cfg.Client == nil {
+		return nil, errors.New("keystore client not specified")
+	}
+	if cfg.Journal == nil {
+		return nil, errors.New("sign journal not specified")
+	}
+	guard, err := NewJournalGuard(cfg.Journal)
+	if err != nil {
+		return nil, err
+	}
+	if err := guard.AdvanceHeight(cfg.Height); err != nil {
+		return nil, err
+	}
+
+	// Remove the socket file left by a previous signer, refuse to start if
+	// the socket is still in use.
+	if _, err := os.Stat(cfg.Socket); err == nil {
+		if conn, err := net.Dial("unix", cfg.Socket); err == nil {
+			conn.Close()
+			return nil, errors.New("signer socket " + cfg.Socket +
+				" is in use")
+		}
+		if err := os.Remove(cfg.Socket); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the owner of the signer process can send sign requests.
+	listener, err := listen(cfg.Socket)
+	if err != nil {
+		return nil, err
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &service{
+		client: cfg.Client,
+		guard:  guard,
+	}); err != nil {
+		listener.Close()
+		os.Remove(cfg.Socket)
+		return nil, err
+	}
+
+	return &Server{socket: cfg.Socket, listener: listener, server: server},
+		nil
+}
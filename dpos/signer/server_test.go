@@ -0,0 +1,129 @@
+package signer_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/account"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/crypto"
+	"github.com/elastos/Elastos.ELA/dpos/signer"
+	"github.com/elastos/Elastos.ELA/dpos/store"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Restart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := account.Create(filepath.Join(dir, "keystore.dat"),
+		[]byte("password"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	socket := filepath.Join(dir, "signer.sock")
+	dataDir := filepath.Join(dir, "data")
+
+	// start the signer daemon at the height, and stop it after the given
+	// requests
+	run := func(height uint32, requests func(c *signer.Client)) {
+		journal, err := store.NewDposStore(dataDir)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer journal.Close()
+		server, err := signer.NewServer(&signer.Config{
+			Socket:  socket,
+			Client:  client,
+			Journal: journal,
+			Height:  height,
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+		server.Start()
+		defer server.Stop()
+
+		c, err := signer.Dial(socket)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer c.Close()
+		requests(c)
+	}
+
+	var pk []byte
+	var p1, p2 *payload.DPOSProposal
+	var accept, reject *payload.DPOSProposalVote
+	run(0, func(c *signer.Client) {
+		pk, err = c.PublicKey(nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		p1 = &payload.DPOSProposal{Sponsor: pk, BlockHash: common.Uint256{1}}
+		p2 = &payload.DPOSProposal{Sponsor: pk, BlockHash: common.Uint256{2}}
+		accept = &payload.DPOSProposalVote{ProposalHash: p1.Hash(),
+			Signer: pk, Accept: true}
+		reject = &payload.DPOSProposalVote{ProposalHash: p1.Hash(),
+			Signer: pk, Accept: false}
+
+		_, err := c.SignProposal(10, p1)
+		assert.NoError(t, err)
+		_, err = c.SignVote(10, 0, accept)
+		assert.NoError(t, err)
+	})
+
+	// the conflicting proposal and vote are refused after restarted
+	run(0, func(c *signer.Client) {
+		_, err := c.SignProposal(10, p2)
+		assert.Error(t, err)
+		_, err = c.SignVote(10, 0, reject)
+		assert.Error(t, err)
+
+		// the signed ones can be signed again
+		publicKey, err := crypto.DecodePoint(pk)
+		if !assert.NoError(t, err) {
+			return
+		}
+		sign, err := c.SignProposal(10, p1)
+		assert.NoError(t, err)
+		assert.NoError(t, crypto.Verify(*publicKey, p1.Data(), sign))
+		sign, err = c.SignVote(10, 0, accept)
+		assert.NoError(t, err)
+		assert.NoError(t, crypto.Verify(*publicKey, accept.Data(), sign))
+
+		// the next view is not guarded by the previous records
+		p2.ViewOffset = 1
+		_, err = c.SignProposal(10, p2)
+		assert.NoError(t, err)
+
+		// the same block at another height and the heights far above the
+		// signed heights are refused
+		_, err = c.SignProposal(11, p1)
+		assert.Error(t, err)
+		p3 := &payload.DPOSProposal{Sponsor: pk, BlockHash: common.Uint256{3}}
+		_, err = c.SignProposal(11+signer.MaxHeightAdvance, p3)
+		assert.Error(t, err)
+
+		// the best height pushed by the node moves the guarded heights
+		assert.NoError(t, c.AdvanceHeight(11))
+		_, err = c.SignProposal(11+signer.MaxHeightAdvance, p3)
+		assert.NoError(t, err)
+	})
+
+	// the height given by the operator moves the guarded heights
+	run(1000, func(c *signer.Client) {
+		p3 := &payload.DPOSProposal{Sponsor: pk, BlockHash: common.Uint256{3}}
+		_, err := c.SignProposal(1000+signer.MaxHeightAdvance, p3)
+		assert.NoError(t, err)
+		_, err = c.SignProposal(10, p2)
+		assert.Error(t, err)
+	})
+}
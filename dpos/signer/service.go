@@ -0,0 +1,185 @@
+package signer
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/elastos/Elastos.ELA/account"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/crypto"
+)
+
+// serviceName is the registered name of the signer RPC service.
+const serviceName = "Signer"
+
+// KeyArgs is the arguments to query a public key.
+type KeyArgs struct {
+	// PublicKey is the public key to query, empty to query the main account.
+	PublicKey []byte
+}
+
+// ProposalArgs is the arguments to sign a proposal.
+type ProposalArgs struct {
+	Height   uint32
+	Proposal []byte // unsigned proposal data
+}
+
+// VoteArgs is the arguments to sign a vote.
+type VoteArgs struct {
+	Height     uint32
+	ViewOffset uint32
+	Vote       []byte // unsigned vote data
+}
+
+// DataArgs is the arguments to sign or decrypt data with the key pair of the
+// given public key.
+type DataArgs struct {
+	PublicKey []byte
+	Data      []byte
+}
+
+// HeightArgs is the arguments to advance the guarded height.
+type HeightArgs struct {
+	Height uint32
+}
+
+// Reply is the reply of signer RPC service methods.
+type Reply struct {
+	Data []byte
+}
+
+// service implements the signer RPC service, key pairs are loaded from the
+// keystore and all consensus messages are checked by the double sign guard
+// before signing.
+type service struct {
+	mtx    sync.Mutex
+	client *account.Client
+	guard  *Guard
+}
+
+func (s *service) getAccount(publicKey []byte) (*account.Account, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if len(publicKey) == 0 {
+		return s.client.GetMainAccount(), nil
+	}
+
+	pubKey, err := crypto.DecodePoint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	ac, err := s.client.GetAccount(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key pair may be added into keystore after the signer started.
+	if ac == nil {
+		if err := s.client.LoadAccounts(); err != nil {
+			return nil, err
+		}
+		if ac, err = s.client.GetAccount(pubKey); err != nil {
+			return nil, err
+		}
+	}
+	if ac == nil {
+		return nil, errors.New("key pair not found in keystore")
+	}
+	return ac, nil
+}
+
+func (s *service) PublicKey(args *KeyArgs, reply *Reply) error {
+	ac, err := s.getAccount(args.PublicKey)
+	if err != nil {
+		return err
+	}
+	reply.Data, err = ac.PublicKey.EncodePoint(true)
+	return err
+}
+
+func (s *service) SignProposal(args *ProposalArgs, reply *Reply) error {
+	var proposal payload.DPOSProposal
+	if err := proposal.DeserializeUnSigned(
+		bytes.NewReader(args.Proposal)); err != nil {
+		return err
+	}
+	ac, err := s.getAccount(proposal.Sponsor)
+	if err != nil {
+		return err
+	}
+	if err := s.guard.CheckProposal(args.Height, &proposal); err != nil {
+		return err
+	}
+
+	reply.Data, err = crypto.Sign(ac.PrivKey(), proposal.Data())
+	return err
+}
+
+func (s *service) SignVote(args *VoteArgs, reply *Reply) error {
+	var vote payload.DPOSProposalVote
+	if err := vote.DeserializeUnsigned(
+		bytes.NewReader(args.Vote)); err != nil {
+		return err
+	}
+	ac, err := s.getAccount(vote.Signer)
+	if err != nil {
+		return err
+	}
+	if err := s.guard.CheckVote(args.Height, args.ViewOffset,
+		&vote); err != nil {
+		return err
+	}
+
+	reply.Data, err = crypto.Sign(ac.PrivKey(), vote.Data())
+	return err
+}
+
+// AdvanceHeight moves the guarded height to the best height of the node, so
+// that the guarded heights follow the chain even if the arbiter has not signed
+// for a while.
+func (s *service) AdvanceHeight(args *HeightArgs, reply *Reply) error {
+	return s.guard.AdvanceHeight(args.Height)
+}
+
+func (s *service) Sign(args *DataArgs, reply *Reply) error {
+	// Proposals and votes must be signed through SignProposal and SignVote,
+	// or the double sign guard will be bypassed.
+	if isConsensusData(args.Data) {
+		return errors.New("refuse to sign consensus data without guard")
+	}
+	ac, err := s.getAccount(args.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	reply.Data, err = crypto.Sign(ac.PrivKey(), args.Data)
+	return err
+}
+
+func (s *service) Decrypt(args *DataArgs, reply *Reply) error {
+	ac, err := s.getAccount(args.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	reply.Data, err = crypto.Decrypt(ac.PrivKey(), args.Data)
+	return err
+}
+
+// isConsensusData returns if the data is an unsigned proposal or vote.
+func isConsensusData(data []byte) bool {
+	r := bytes.NewReader(data)
+	var proposal payload.DPOSProposal
+	if err := proposal.DeserializeUnSigned(r); err == nil && r.Len() == 0 {
+		return true
+	}
+
+	r = bytes.NewReader(data)
+	var vote payload.DPOSProposalVote
+	if err := vote.DeserializeUnsigned(r); err == nil && r.Len() == 0 {
+		return true
+	}
+	return false
+}
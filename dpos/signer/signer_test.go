@@ -0,0 +1,194 @@
+package signer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/account"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/crypto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memJournal is a Journal keeps the sign records in memory.
+type memJournal struct {
+	records []*SignRecord
+}
+
+func (j *memJournal) LoadSignRecords() ([]*SignRecord, error) {
+	return j.records, nil
+}
+
+func (j *memJournal) AppendSignRecord(r *SignRecord) error {
+	j.records = append(j.records, r)
+	return nil
+}
+
+func (j *memJournal) PruneSignRecords(height uint32) error {
+	records := j.records[:0]
+	for _, r := range j.records {
+		if r.Height > height {
+			records = append(records, r)
+		}
+	}
+	j.records = records
+	return nil
+}
+
+func TestGuard(t *testing.T) {
+	guard := NewGuard()
+	sponsor := []byte{0x02, 0x01}
+
+	p1 := &payload.DPOSProposal{Sponsor: sponsor,
+		BlockHash: common.Uint256{1}}
+	p2 := &payload.DPOSProposal{Sponsor: sponsor,
+		BlockHash: common.Uint256{2}}
+	assert.NoError(t, guard.CheckProposal(10, p1))
+
+	// sign the same proposal again is allowed
+	assert.NoError(t, guard.CheckProposal(10, p1))
+
+	// different proposal at the same height and view
+	assert.Error(t, guard.CheckProposal(10, p2))
+
+	// different proposal at the next view or height
+	p2.ViewOffset = 1
+	assert.NoError(t, guard.CheckProposal(10, p2))
+	p3 := &payload.DPOSProposal{Sponsor: sponsor,
+		BlockHash: common.Uint256{3}}
+	assert.NoError(t, guard.CheckProposal(11, p3))
+
+	// the same block can not be proposed at another height
+	assert.Error(t, guard.CheckProposal(11, p1))
+	assert.Error(t, guard.CheckProposal(12, p3))
+
+	// accept and reject on the same proposal
+	accept := &payload.DPOSProposalVote{ProposalHash: p1.Hash(),
+		Signer: sponsor, Accept: true}
+	reject := &payload.DPOSProposalVote{ProposalHash: p1.Hash(),
+		Signer: sponsor, Accept: false}
+	assert.NoError(t, guard.CheckVote(10, 0, accept))
+	assert.NoError(t, guard.CheckVote(10, 0, accept))
+	assert.Error(t, guard.CheckVote(10, 0, reject))
+
+	// votes of another signer are guarded separately
+	reject.Signer = []byte{0x02, 0x02}
+	assert.NoError(t, guard.CheckVote(10, 0, reject))
+
+	// heights far above the signed heights are refused
+	p4 := &payload.DPOSProposal{Sponsor: sponsor,
+		BlockHash: common.Uint256{4}}
+	assert.Error(t, guard.CheckProposal(12+MaxHeightAdvance, p4))
+	assert.Error(t, guard.CheckVote(12+MaxHeightAdvance, 0, accept))
+	assert.Equal(t, uint32(11), guard.bestHeight)
+	assert.NoError(t, guard.CheckProposal(11+MaxHeightAdvance, p4))
+	assert.Equal(t, uint32(11+MaxHeightAdvance), guard.bestHeight)
+
+	// heights lower than guarded heights are refused
+	assert.NoError(t, guard.AdvanceHeight(10+MaxGuardedHeights))
+	assert.Error(t, guard.CheckProposal(10, p1))
+	assert.Error(t, guard.CheckVote(10, 0, accept))
+	assert.Equal(t, 2, len(guard.proposals))
+	assert.Equal(t, 0, len(guard.votes))
+	assert.Equal(t, 2, len(guard.blocks))
+
+	// the guarded height is not moved back
+	assert.NoError(t, guard.AdvanceHeight(10))
+	assert.Equal(t, uint32(10+MaxGuardedHeights), guard.bestHeight)
+}
+
+func TestServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := account.Create(filepath.Join(dir, "keystore.dat"),
+		[]byte("password"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	socket := filepath.Join(dir, "signer.sock")
+
+	// the sign journal is required
+	_, err = NewServer(&Config{Socket: socket, Client: client})
+	assert.Error(t, err)
+
+	journal := &memJournal{}
+	server, err := NewServer(&Config{Socket: socket, Client: client,
+		Journal: journal})
+	if !assert.NoError(t, err) {
+		return
+	}
+	server.Start()
+	defer server.Stop()
+
+	// only the owner can connect to the socket once it's created, and no
+	// temporary file is left
+	info, err := os.Stat(socket)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.ModeSocket|0600, info.Mode())
+	}
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// another signer can not listen on the same socket
+	_, err = NewServer(&Config{Socket: socket, Client: client,
+		Journal: journal})
+	assert.Error(t, err)
+
+	c, err := Dial(socket)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	pk, err := c.PublicKey(nil)
+	assert.NoError(t, err)
+	publicKey, err := crypto.DecodePoint(pk)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	p1 := &payload.DPOSProposal{Sponsor: pk, BlockHash: common.Uint256{1}}
+	p2 := &payload.DPOSProposal{Sponsor: pk, BlockHash: common.Uint256{2}}
+	sign, err := c.SignProposal(1, p1)
+	assert.NoError(t, err)
+	assert.NoError(t, crypto.Verify(*publicKey, p1.Data(), sign))
+	_, err = c.SignProposal(1, p2)
+	assert.Error(t, err)
+	assert.Equal(t, 1, len(journal.records))
+
+	vote := &payload.DPOSProposalVote{ProposalHash: p1.Hash(), Signer: pk,
+		Accept: true}
+	sign, err = c.SignVote(1, 0, vote)
+	assert.NoError(t, err)
+	assert.NoError(t, crypto.Verify(*publicKey, vote.Data(), sign))
+
+	// consensus data can not be signed without guard
+	_, err = c.Sign(pk, p2.Data())
+	assert.Error(t, err)
+	_, err = c.Sign(pk, vote.Data())
+	assert.Error(t, err)
+
+	data := []byte("data")
+	sign, err = c.Sign(pk, data)
+	assert.NoError(t, err)
+	assert.NoError(t, crypto.Verify(*publicKey, data, sign))
+
+	cipher, err := crypto.Encrypt(publicKey, data)
+	assert.NoError(t, err)
+	plain, err := c.Decrypt(pk, cipher)
+	assert.NoError(t, err)
+	assert.Equal(t, data, plain)
+
+	// key pairs not in keystore
+	_, err = c.PublicKey(p1.Hash().Bytes())
+	assert.Error(t, err)
+}
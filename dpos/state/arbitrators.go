@@ -82,6 +82,16 @@ type arbitrators struct {
 	snapshots            map[uint32][]*KeyFrame
 	snapshotKeysDesc     []uint32
 	lastCheckPointHeight uint32
+
+	nodeKeyRotations []*NodeKeyRotation
+}
+
+// NodeKeyRotation records the node public key of an arbiter replaced by an
+// UpdateProducer transaction at the height.
+type NodeKeyRotation struct {
+	Height           uint32
+	OldNodePublicKey []byte
+	NewNodePublicKey []byte
 }
 
 func (a *arbitrators) Start() {
@@ -114,10 +124,137 @@ func (a *arbitrators) RecoverFromCheckPoints(height uint32) (uint32, error) {
 }
 
 func (a *arbitrators) ProcessBlock(block *types.Block, confirm *payload.Confirm) {
+	rotations := a.getNodeKeyRotations(block)
 	a.State.ProcessBlock(block, confirm)
+	a.rotateNodeKeys(block.Height, rotations)
 	a.IncreaseChainHeight(block)
 }
 
+// getNodeKeyRotations returns the node public keys of the producers changed by
+// the UpdateProducer transactions of the block, it must be called before the
+// block processed by the state.
+func (a *arbitrators) getNodeKeyRotations(block *types.Block) []*NodeKeyRotation {
+	if block.Height < a.chainParams.NodeKeyRotationHeight {
+		return nil
+	}
+
+	var rotations []*NodeKeyRotation
+	owners := make(map[string]*NodeKeyRotation)
+	for _, tx := range block.Transactions {
+		if tx.TxType != types.UpdateProducer {
+			continue
+		}
+		p, ok := tx.Payload.(*payload.ProducerInfo)
+		if !ok {
+			continue
+		}
+
+		owner := hex.EncodeToString(p.OwnerPublicKey)
+		if r, ok := owners[owner]; ok {
+			// the node key may be updated more than once in one block
+			r.NewNodePublicKey = p.NodePublicKey
+			continue
+		}
+		producer := a.GetProducer(p.OwnerPublicKey)
+		if producer == nil {
+			continue
+		}
+		r := &NodeKeyRotation{
+			Height:           block.Height,
+			OldNodePublicKey: producer.NodePublicKey(),
+			NewNodePublicKey: p.NodePublicKey,
+		}
+		owners[owner] = r
+		rotations = append(rotations, r)
+	}
+
+	result := make([]*NodeKeyRotation, 0, len(rotations))
+	for _, r := range rotations {
+		if !bytes.Equal(r.OldNodePublicKey, r.NewNodePublicKey) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// rotateNodeKeys replaces the old node public keys with the new ones in the
+// current and next arbiters and candidates, so the rotated arbiter works with
+// the new key from the next height.
+func (a *arbitrators) rotateNodeKeys(height uint32,
+	rotations []*NodeKeyRotation) {
+	if len(rotations) == 0 {
+		return
+	}
+
+	a.mtx.Lock()
+	a.snapshot(height)
+	for _, r := range rotations {
+		a.replaceNodeKey(r.OldNodePublicKey, r.NewNodePublicKey)
+		a.nodeKeyRotations = append(a.nodeKeyRotations, r)
+	}
+
+	// only keep the rotations which can be rolled back
+	for len(a.nodeKeyRotations) > 0 && a.nodeKeyRotations[0].Height+
+		maxHistoryCapacity < height {
+		a.nodeKeyRotations = a.nodeKeyRotations[1:]
+	}
+	a.mtx.Unlock()
+
+	if a.started {
+		for _, r := range rotations {
+			events.Notify(events.ETNodeKeyRotated, r)
+		}
+		events.Notify(events.ETDirectPeersChanged, a.GetNeedConnectArbiters())
+	}
+}
+
+// rollbackNodeKeys restores the node public keys rotated above the height.
+func (a *arbitrators) rollbackNodeKeys(height uint32) {
+	a.mtx.Lock()
+	var rollbacks []*NodeKeyRotation
+	for i := len(a.nodeKeyRotations) - 1; i >= 0; i-- {
+		r := a.nodeKeyRotations[i]
+		if r.Height <= height {
+			break
+		}
+		a.replaceNodeKey(r.NewNodePublicKey, r.OldNodePublicKey)
+		a.nodeKeyRotations = a.nodeKeyRotations[:i]
+		rollbacks = append(rollbacks, &NodeKeyRotation{
+			Height:           height,
+			OldNodePublicKey: r.NewNodePublicKey,
+			NewNodePublicKey: r.OldNodePublicKey,
+		})
+	}
+	a.mtx.Unlock()
+
+	if a.started && len(rollbacks) > 0 {
+		for _, r := range rollbacks {
+			events.Notify(events.ETNodeKeyRotated, r)
+		}
+		events.Notify(events.ETDirectPeersChanged, a.GetNeedConnectArbiters())
+	}
+}
+
+// replaceNodeKey replaces the node public key in the arbiters and candidates,
+// the lists are copied since they may be referenced by the snapshots.
+func (a *arbitrators) replaceNodeKey(oldKey, newKey []byte) {
+	a.CurrentArbitrators = replaceNodeKey(a.CurrentArbitrators, oldKey, newKey)
+	a.currentCandidates = replaceNodeKey(a.currentCandidates, oldKey, newKey)
+	a.nextArbitrators = replaceNodeKey(a.nextArbitrators, oldKey, newKey)
+	a.nextCandidates = replaceNodeKey(a.nextCandidates, oldKey, newKey)
+}
+
+func replaceNodeKey(keys [][]byte, oldKey, newKey []byte) [][]byte {
+	for i, k := range keys {
+		if bytes.Equal(k, oldKey) {
+			result := copyByteList(keys)
+			result[i] = newKey
+			return result
+		}
+	}
+	return keys
+}
+
 func (a *arbitrators) CheckDPOSIllegalTx(block *types.Block) error {
 
 	a.mtx.Lock()
@@ -409,6 +546,7 @@ func (a *arbitrators) distributeWithNormalArbitrators(
 
 func (a *arbitrators) DecreaseChainHeight(height uint32) error {
 	a.degradation.RollbackTo(height)
+	a.rollbackNodeKeys(height)
 
 	heightOffset := int(a.history.height - height)
 	if a.dutyIndex == 0 || a.dutyIndex < heightOffset {
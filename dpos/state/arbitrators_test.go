@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/elastos/Elastos.ELA/common/config"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -121,3 +123,73 @@ func randomFakePK() []byte {
 	rand.Read(pk)
 	return pk
 }
+
+func TestArbitrators_RotateNodeKeys(t *testing.T) {
+	params := config.DefaultParams
+	params.NodeKeyRotationHeight = 20
+	arbitrators, _ := NewArbitrators(&params, nil,
+		func() uint32 { return 20 }, nil, nil)
+
+	owner := randomFakePK()
+	oldKey := randomFakePK()
+	newKey := randomFakePK()
+	otherKey := randomFakePK()
+	arbitrators.State.ProcessBlock(mockBlock(10, mockRegisterProducerTx(
+		&payload.ProducerInfo{
+			OwnerPublicKey: owner,
+			NodePublicKey:  oldKey,
+			NickName:       "producer",
+		})), nil)
+	// the state history requires continuous heights
+	for height := uint32(11); height < 20; height++ {
+		arbitrators.State.ProcessBlock(mockBlock(height), nil)
+	}
+	arbitrators.CurrentArbitrators = [][]byte{otherKey, oldKey}
+	arbitrators.nextArbitrators = [][]byte{oldKey, otherKey}
+	arbitrators.currentCandidates = [][]byte{oldKey}
+	arbitrators.nextCandidates = [][]byte{otherKey}
+	current := arbitrators.CurrentArbitrators
+
+	update := func(nodePublicKey []byte) *types.Transaction {
+		return mockUpdateProducerTx(&payload.ProducerInfo{
+			OwnerPublicKey: owner,
+			NodePublicKey:  nodePublicKey,
+			NickName:       "producer",
+		})
+	}
+
+	// the node key is not rotated before the rotation height
+	block := mockBlock(19, update(newKey))
+	assert.Empty(t, arbitrators.getNodeKeyRotations(block))
+
+	// the last node key of the producer in the block is used
+	block = mockBlock(20, update(otherKey), update(newKey))
+	rotations := arbitrators.getNodeKeyRotations(block)
+	if !assert.Len(t, rotations, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, uint32(20), rotations[0].Height)
+	assert.Equal(t, oldKey, rotations[0].OldNodePublicKey)
+	assert.Equal(t, newKey, rotations[0].NewNodePublicKey)
+
+	arbitrators.State.ProcessBlock(block, nil)
+	arbitrators.rotateNodeKeys(block.Height, rotations)
+	assert.Equal(t, [][]byte{otherKey, newKey}, arbitrators.CurrentArbitrators)
+	assert.Equal(t, [][]byte{newKey, otherKey}, arbitrators.nextArbitrators)
+	assert.Equal(t, [][]byte{newKey}, arbitrators.currentCandidates)
+	assert.Equal(t, [][]byte{otherKey}, arbitrators.nextCandidates)
+
+	// the arbiters before the rotation are kept in the snapshot
+	assert.Equal(t, [][]byte{otherKey, oldKey}, current)
+	frames := arbitrators.GetSnapshot(20)
+	if assert.Len(t, frames, 1) {
+		assert.Equal(t, current, frames[0].CurrentArbitrators)
+	}
+
+	// the rotation is restored on rollback
+	arbitrators.rollbackNodeKeys(19)
+	assert.Equal(t, [][]byte{otherKey, oldKey}, arbitrators.CurrentArbitrators)
+	assert.Equal(t, [][]byte{oldKey, otherKey}, arbitrators.nextArbitrators)
+	assert.Equal(t, [][]byte{oldKey}, arbitrators.currentCandidates)
+	assert.Empty(t, arbitrators.nodeKeyRotations)
+}
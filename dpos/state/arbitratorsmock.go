@@ -26,6 +26,7 @@ func NewArbitratorsMock(arbitersByte [][]byte, changeCount, majorityCount int) *
 		OwnerVotesInRound:           make(map[common.Uint168]common.Fixed64),
 		ArbitersRoundReward:         make(map[common.Uint168]common.Fixed64),
 		CRCArbitratorsMap:           make(map[string]*Producer),
+		Producers:                   make(map[string]*Producer),
 		ActiveProducer:              make([][]byte, 0),
 		TotalVotesInRound:           0,
 		DutyChangedCount:            0,
@@ -49,6 +50,7 @@ type ArbitratorsMock struct {
 	ArbitersRoundReward         map[common.Uint168]common.Fixed64
 	OwnerVotesInRound           map[common.Uint168]common.Fixed64
 	CRCArbitratorsMap           map[string]*Producer
+	Producers                   map[string]*Producer
 	TotalVotesInRound           common.Fixed64
 	DutyChangedCount            int
 	MajorityCount               int
@@ -122,7 +124,7 @@ func (a *ArbitratorsMock) GetDutyIndexByHeight(uint32) int {
 }
 
 func (a *ArbitratorsMock) GetDutyIndex() int {
-	panic("implement me")
+	return a.DutyChangedCount
 }
 
 func (a *ArbitratorsMock) ProcessSpecialTxPayload(p types.Payload, height uint32) error {
@@ -167,6 +169,25 @@ func (a *ArbitratorsMock) TryEnterEmergency(blockTime uint32) bool {
 	panic("implement me")
 }
 
+func (a *ArbitratorsMock) GetProducer(publicKey []byte) *Producer {
+	return a.Producers[common.BytesToHexString(publicKey)]
+}
+
+// SetProducer sets the active producer of the info, which can be got by
+// either the owner or the node public key. The previous node public key of the
+// owner is removed.
+func (a *ArbitratorsMock) SetProducer(info payload.ProducerInfo) {
+	if a.Producers == nil {
+		a.Producers = make(map[string]*Producer)
+	}
+	if p, ok := a.Producers[common.BytesToHexString(info.OwnerPublicKey)]; ok {
+		delete(a.Producers, common.BytesToHexString(p.NodePublicKey()))
+	}
+	p := &Producer{info: info, state: Active}
+	a.Producers[common.BytesToHexString(info.OwnerPublicKey)] = p
+	a.Producers[common.BytesToHexString(info.NodePublicKey)] = p
+}
+
 func (a *ArbitratorsMock) GetCRCProducer(publicKey []byte) *Producer {
 	panic("implement me")
 }
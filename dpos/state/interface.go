@@ -32,6 +32,7 @@ type Arbitrators interface {
 	IsUnderstaffedMode() bool
 
 	GetCRCArbiters() [][]byte
+	GetProducer(publicKey []byte) *Producer
 	GetCRCProducer(publicKey []byte) *Producer
 	GetCRCArbitrators() map[string]*Producer
 	IsCRCArbitrator(pk []byte) bool
@@ -109,6 +109,10 @@ func (p *Producer) InactiveSince() uint32 {
 	return p.inactiveSince
 }
 
+func (p *Producer) InactiveCountingHeight() uint32 {
+	return p.inactiveCountingHeight
+}
+
 func (p *Producer) IllegalHeight() uint32 {
 	return p.illegalHeight
 }
@@ -0,0 +1,438 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/dpos/log"
+	"github.com/elastos/Elastos.ELA/dpos/state"
+)
+
+const (
+	// DefaultMaxMetricsWindows is the default count of arbitration rounds
+	// kept by ArbiterMetrics.
+	DefaultMaxMetricsWindows = 10
+)
+
+// ArbiterCounters holds the performance counters of an arbiter.
+type ArbiterCounters struct {
+	// Proposals is the count of proposals sponsored by the arbiter.
+	Proposals uint32
+
+	// VotesOnTime is the count of votes arrived within the sign tolerance
+	// since the proposal arrived.
+	VotesOnTime uint32
+
+	// VotesLate is the count of votes arrived after the sign tolerance.
+	VotesLate uint32
+
+	// RoundsMissed is the count of consensus the arbiter neither sponsored
+	// nor voted for.
+	RoundsMissed uint32
+
+	// ViewChanges is the count of view changes happened because the arbiter
+	// did not propose while on duty.
+	ViewChanges uint32
+
+	// InactivityStrikes is the count of consensus the arbiter was on duty but
+	// finished by the proposal of another arbiter, the inactive counting of
+	// the arbiter is not reset by these consensus.
+	InactivityStrikes uint32
+}
+
+// MetricsWindow holds the counters of arbiters within an arbitration round.
+type MetricsWindow struct {
+	StartHeight uint32
+	EndHeight   uint32
+	Counters    map[string]*ArbiterCounters // hex string of public key as key
+}
+
+func (w *MetricsWindow) counters(publicKey string) *ArbiterCounters {
+	c, ok := w.Counters[publicKey]
+	if !ok {
+		c = &ArbiterCounters{}
+		w.Counters[publicKey] = c
+	}
+	return c
+}
+
+func (w *MetricsWindow) copy() *MetricsWindow {
+	result := &MetricsWindow{
+		StartHeight: w.StartHeight,
+		EndHeight:   w.EndHeight,
+		Counters:    make(map[string]*ArbiterCounters, len(w.Counters)),
+	}
+	for k, v := range w.Counters {
+		c := *v
+		result.Counters[k] = &c
+	}
+	return result
+}
+
+type ArbiterMetricsConfig struct {
+	Arbitrators       state.Arbitrators
+	Store             IDposStore
+	ToleranceDuration time.Duration
+	MaxWindows        int
+}
+
+// ArbiterMetrics is a consensus event listener counting the performance of
+// each arbiter in rolling windows of arbitration rounds.
+type ArbiterMetrics struct {
+	cfg ArbiterMetricsConfig
+
+	mtx     sync.RWMutex
+	windows []*MetricsWindow
+
+	// proposals records the arrived time of proposals in current consensus.
+	proposals map[common.Uint256]time.Time
+	// voters records the sponsor and signers participated current consensus.
+	voters map[string]struct{}
+	// missed records the arbiters missed last consensus, they will be counted
+	// when next consensus started unless a late vote arrived.
+	missed         map[string]struct{}
+	finishedHashes map[common.Uint256]struct{}
+	viewHeight     uint32
+	viewOnDuty     string
+	// onDuty records the on duty arbiters of the views at the view height,
+	// and accepted is the sponsor of the accepted proposal.
+	onDuty   map[string]struct{}
+	accepted string
+	// rebuilt indicates if the windows before the first consensus event have
+	// been rebuilt from the recorded events.
+	rebuilt bool
+}
+
+func (m *ArbiterMetrics) OnProposalArrived(prop *log.ProposalEvent) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if _, ok := m.proposals[prop.ProposalHash]; ok {
+		return
+	}
+	m.proposals[prop.ProposalHash] = prop.ReceivedTime
+	m.voters[prop.Sponsor] = struct{}{}
+	if w := m.currentWindow(); w != nil {
+		w.counters(prop.Sponsor).Proposals++
+	}
+}
+
+func (m *ArbiterMetrics) OnProposalFinished(prop *log.ProposalEvent) {
+	if !prop.Result {
+		return
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.accepted = prop.Sponsor
+}
+
+func (m *ArbiterMetrics) OnVoteArrived(vote *log.VoteEvent) {
+	if vote.RawData == nil {
+		return
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	w := m.currentWindow()
+	if w == nil {
+		return
+	}
+
+	// vote of the finished consensus arrived before next consensus started
+	if _, ok := m.finishedHashes[vote.RawData.ProposalHash]; ok {
+		if _, ok := m.missed[vote.Signer]; ok {
+			delete(m.missed, vote.Signer)
+			w.counters(vote.Signer).VotesLate++
+		}
+		return
+	}
+
+	arrived, ok := m.proposals[vote.RawData.ProposalHash]
+	if !ok {
+		return
+	}
+	if _, ok := m.voters[vote.Signer]; ok {
+		return
+	}
+	m.voters[vote.Signer] = struct{}{}
+	if vote.ReceivedTime.Sub(arrived) <= m.cfg.ToleranceDuration {
+		w.counters(vote.Signer).VotesOnTime++
+	} else {
+		w.counters(vote.Signer).VotesLate++
+	}
+}
+
+func (m *ArbiterMetrics) OnViewStarted(view *log.ViewEvent) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.rebuild(view.Height)
+	w := m.updateWindow(view.Height)
+	if view.Height == m.viewHeight && view.Offset > 0 && m.viewOnDuty != "" {
+		w.counters(m.viewOnDuty).ViewChanges++
+	}
+	if view.Height != m.viewHeight {
+		m.onDuty = make(map[string]struct{})
+	}
+	m.onDuty[view.OnDutyArbitrator] = struct{}{}
+	m.viewHeight = view.Height
+	m.viewOnDuty = view.OnDutyArbitrator
+}
+
+func (m *ArbiterMetrics) OnConsensusStarted(cons *log.ConsensusEvent) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.rebuild(cons.Height)
+	m.countMissed()
+	m.updateWindow(cons.Height)
+}
+
+func (m *ArbiterMetrics) OnConsensusFinished(cons *log.ConsensusEvent) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.countMissed()
+	for _, a := range m.cfg.Arbitrators.GetArbitrators() {
+		key := common.BytesToHexString(a)
+		if _, ok := m.voters[key]; !ok {
+			m.missed[key] = struct{}{}
+		}
+	}
+	if w := m.currentWindow(); w != nil {
+		for k := range m.onDuty {
+			if k != m.accepted {
+				w.counters(k).InactivityStrikes++
+			}
+		}
+	}
+	for hash := range m.proposals {
+		m.finishedHashes[hash] = struct{}{}
+	}
+	m.proposals = make(map[common.Uint256]time.Time)
+	m.voters = make(map[string]struct{})
+	m.onDuty = make(map[string]struct{})
+	m.accepted = ""
+}
+
+// countMissed counts the arbiters missed last finished consensus.
+func (m *ArbiterMetrics) countMissed() {
+	if w := m.currentWindow(); w != nil {
+		for k := range m.missed {
+			w.counters(k).RoundsMissed++
+		}
+	}
+	m.missed = make(map[string]struct{})
+	m.finishedHashes = make(map[common.Uint256]struct{})
+}
+
+func (m *ArbiterMetrics) currentWindow() *MetricsWindow {
+	if len(m.windows) == 0 {
+		return nil
+	}
+	return m.windows[len(m.windows)-1]
+}
+
+// roundStartHeight returns the start height of the arbitration round of the
+// given height.
+func (m *ArbiterMetrics) roundStartHeight(height uint32) uint32 {
+	if index := uint32(m.cfg.Arbitrators.GetDutyIndex()); index <= height {
+		return height - index
+	}
+	return height
+}
+
+// updateWindow returns the window of the given height, a new window will be
+// created if the height belongs to a new arbitration round.
+func (m *ArbiterMetrics) updateWindow(height uint32) *MetricsWindow {
+	startHeight := m.roundStartHeight(height)
+	w := m.currentWindow()
+	if w == nil || startHeight > w.StartHeight {
+		w = m.appendWindow(startHeight, height)
+		for _, a := range m.cfg.Arbitrators.GetArbitrators() {
+			w.counters(common.BytesToHexString(a))
+		}
+	}
+	if height > w.EndHeight {
+		w.EndHeight = height
+	}
+	return w
+}
+
+func (m *ArbiterMetrics) appendWindow(startHeight,
+	height uint32) *MetricsWindow {
+	w := &MetricsWindow{
+		StartHeight: startHeight,
+		EndHeight:   height,
+		Counters:    make(map[string]*ArbiterCounters),
+	}
+	m.windows = append(m.windows, w)
+	if len(m.windows) > m.cfg.MaxWindows {
+		m.windows = m.windows[len(m.windows)-m.cfg.MaxWindows:]
+	}
+	return w
+}
+
+// rebuild restores the windows before the given height from the recorded
+// consensus events once the first consensus event arrived, so that the
+// metrics are kept after restart. The past arbitration rounds are assumed to
+// have the same count of arbiters as the current one.
+func (m *ArbiterMetrics) rebuild(height uint32) {
+	if m.rebuilt {
+		return
+	}
+	m.rebuilt = true
+	size := uint32(len(m.cfg.Arbitrators.GetArbitrators()))
+	if m.cfg.Store == nil || size == 0 {
+		return
+	}
+
+	// the start height of the oldest window to be rebuilt
+	currentStart := m.roundStartHeight(height)
+	startHeight := uint32(0)
+	if span := uint32(m.cfg.MaxWindows-1) * size; currentStart > span {
+		startHeight = currentStart - span
+	}
+
+	var rounds []*ConsensusRound
+	for begin := startHeight; begin < height; begin += MaxConsensusQueryRange {
+		end := begin + MaxConsensusQueryRange - 1
+		if end >= height {
+			end = height - 1
+		}
+		result, err := m.cfg.Store.GetConsensusRounds(begin, end)
+		if err != nil {
+			log.Warn("[ArbiterMetrics] rebuild windows failed:", err)
+			return
+		}
+		rounds = append(rounds, result...)
+	}
+
+	// group the consensus rounds by the arbitration rounds
+	var windowRounds [][]*ConsensusRound
+	for _, r := range rounds {
+		start := currentStart
+		if r.Height < currentStart {
+			span := (currentStart - r.Height + size - 1) / size * size
+			start = 0
+			if span < currentStart {
+				start = currentStart - span
+			}
+		}
+		w := m.currentWindow()
+		if w == nil || start > w.StartHeight {
+			w = m.appendWindow(start, r.Height)
+			windowRounds = append(windowRounds, nil)
+		}
+		w.EndHeight = r.Height
+		windowRounds[len(windowRounds)-1] = append(
+			windowRounds[len(windowRounds)-1], r)
+	}
+
+	// the windows may be dropped by the max windows
+	offset := len(windowRounds) - len(m.windows)
+	for i, w := range m.windows {
+		if w.StartHeight == currentStart {
+			for _, a := range m.cfg.Arbitrators.GetArbitrators() {
+				w.counters(common.BytesToHexString(a))
+			}
+		}
+		m.replayWindow(w, windowRounds[offset+i])
+	}
+}
+
+// replayWindow counts the recorded consensus rounds into the window as they
+// were counted by the consensus events.
+func (m *ArbiterMetrics) replayWindow(w *MetricsWindow,
+	rounds []*ConsensusRound) {
+	// arbiters participated any consensus of the window are counted
+	for _, r := range rounds {
+		for _, p := range r.Proposals {
+			w.counters(p.Sponsor)
+			for _, v := range p.Votes {
+				w.counters(v.Signer)
+			}
+		}
+		for _, v := range r.Views {
+			w.counters(v.OnDutyArbitrator)
+		}
+	}
+
+	for _, r := range rounds {
+		voters := make(map[string]struct{})
+		var accepted string
+		for _, p := range r.Proposals {
+			w.counters(p.Sponsor).Proposals++
+			voters[p.Sponsor] = struct{}{}
+			if p.Result {
+				accepted = p.Sponsor
+			}
+			for _, v := range p.Votes {
+				if _, ok := voters[v.Signer]; ok {
+					continue
+				}
+				voters[v.Signer] = struct{}{}
+				if v.ReceivedTime.Sub(p.ReceivedTime) <=
+					m.cfg.ToleranceDuration {
+					w.counters(v.Signer).VotesOnTime++
+				} else {
+					w.counters(v.Signer).VotesLate++
+				}
+			}
+		}
+
+		onDuty := make(map[string]struct{})
+		for i, v := range r.Views {
+			if i > 0 && v.Offset > 0 {
+				w.counters(r.Views[i-1].OnDutyArbitrator).ViewChanges++
+			}
+			onDuty[v.OnDutyArbitrator] = struct{}{}
+		}
+
+		// the unfinished consensus is not counted as missed
+		if r.EndTime.IsZero() {
+			continue
+		}
+		for k, c := range w.Counters {
+			if _, ok := voters[k]; !ok {
+				c.RoundsMissed++
+			}
+		}
+		for k := range onDuty {
+			if k != accepted {
+				w.counters(k).InactivityStrikes++
+			}
+		}
+	}
+}
+
+// GetWindows returns the copies of the metrics windows from the oldest to the
+// current arbitration round.
+func (m *ArbiterMetrics) GetWindows() []*MetricsWindow {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	result := make([]*MetricsWindow, 0, len(m.windows))
+	for _, w := range m.windows {
+		result = append(result, w.copy())
+	}
+	return result
+}
+
+func NewArbiterMetrics(cfg ArbiterMetricsConfig) *ArbiterMetrics {
+	if cfg.MaxWindows <= 0 {
+		cfg.MaxWindows = DefaultMaxMetricsWindows
+	}
+	return &ArbiterMetrics{
+		cfg:            cfg,
+		proposals:      make(map[common.Uint256]time.Time),
+		voters:         make(map[string]struct{}),
+		missed:         make(map[string]struct{}),
+		finishedHashes: make(map[common.Uint256]struct{}),
+		onDuty:         make(map[string]struct{}),
+	}
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/dpos/log"
+	"github.com/elastos/Elastos.ELA/dpos/state"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArbiterMetrics(t *testing.T) {
+	arbiters := [][]byte{randomPkBytes(), randomPkBytes(), randomPkBytes()}
+	keys := make([]string, 0, len(arbiters))
+	for _, a := range arbiters {
+		keys = append(keys, common.BytesToHexString(a))
+	}
+	arbitrators := state.NewArbitratorsMock(arbiters, 0, 2)
+	metrics := NewArbiterMetrics(ArbiterMetricsConfig{
+		Arbitrators:       arbitrators,
+		ToleranceDuration: 5 * time.Second,
+	})
+
+	now := time.Now()
+	// arbiter 0 did not propose on duty and the view changed to arbiter 1
+	metrics.OnViewStarted(&log.ViewEvent{OnDutyArbitrator: keys[0],
+		StartTime: now, Offset: 0, Height: 10})
+	metrics.OnConsensusStarted(&log.ConsensusEvent{StartTime: now,
+		Height: 10})
+	metrics.OnViewStarted(&log.ViewEvent{OnDutyArbitrator: keys[1],
+		StartTime: now, Offset: 1, Height: 10})
+
+	proposalHash := common.Uint256{1}
+	metrics.OnProposalArrived(&log.ProposalEvent{Sponsor: keys[1],
+		ReceivedTime: now, ProposalHash: proposalHash})
+	metrics.OnVoteArrived(&log.VoteEvent{Signer: keys[0],
+		ReceivedTime: now.Add(time.Second), Result: true,
+		RawData: &payload.DPOSProposalVote{ProposalHash: proposalHash}})
+	metrics.OnProposalFinished(&log.ProposalEvent{Sponsor: keys[1],
+		EndTime: now, Result: true})
+	metrics.OnConsensusFinished(&log.ConsensusEvent{EndTime: now,
+		Height: 10})
+
+	// arbiter 2 missed the consensus at height 10
+	arbitrators.SetDutyChangeCount(1)
+	metrics.OnConsensusStarted(&log.ConsensusEvent{StartTime: now,
+		Height: 11})
+
+	windows := metrics.GetWindows()
+	if !assert.Equal(t, 1, len(windows)) {
+		return
+	}
+	w := windows[0]
+	assert.Equal(t, uint32(10), w.StartHeight)
+	assert.Equal(t, uint32(11), w.EndHeight)
+	assert.Equal(t, ArbiterCounters{VotesOnTime: 1, ViewChanges: 1,
+		InactivityStrikes: 1}, *w.Counters[keys[0]])
+	assert.Equal(t, ArbiterCounters{Proposals: 1}, *w.Counters[keys[1]])
+	assert.Equal(t, ArbiterCounters{RoundsMissed: 1}, *w.Counters[keys[2]])
+
+	// a new arbitration round starts a new window
+	arbitrators.SetDutyChangeCount(0)
+	metrics.OnConsensusStarted(&log.ConsensusEvent{StartTime: now,
+		Height: 13})
+	windows = metrics.GetWindows()
+	assert.Equal(t, 2, len(windows))
+	assert.Equal(t, uint32(13), windows[1].StartHeight)
+}
+
+// roundsStore is a DPoS store returns the recorded consensus rounds.
+type roundsStore struct {
+	IDposStore
+	rounds []*ConsensusRound
+}
+
+func (s *roundsStore) GetConsensusRounds(startHeight,
+	endHeight uint32) ([]*ConsensusRound, error) {
+	var rounds []*ConsensusRound
+	for _, r := range s.rounds {
+		if r.Height >= startHeight && r.Height <= endHeight {
+			rounds = append(rounds, r)
+		}
+	}
+	return rounds, nil
+}
+
+func TestArbiterMetrics_Rebuild(t *testing.T) {
+	arbiters := [][]byte{randomPkBytes(), randomPkBytes(), randomPkBytes()}
+	keys := make([]string, 0, len(arbiters))
+	for _, a := range arbiters {
+		keys = append(keys, common.BytesToHexString(a))
+	}
+	now := time.Now()
+	vote := func(signer string, delay time.Duration) *VoteRecord {
+		return &VoteRecord{Signer: signer, Accept: true,
+			ReceivedTime: now.Add(delay)}
+	}
+	round := func(height uint32, sponsor string, views []*ViewRecord,
+		votes ...*VoteRecord) *ConsensusRound {
+		return &ConsensusRound{
+			Height:    height,
+			StartTime: now,
+			EndTime:   now,
+			Proposals: []*ProposalRecord{{Sponsor: sponsor,
+				ReceivedTime: now, Result: true, Votes: votes}},
+			Views: views,
+		}
+	}
+	store := &roundsStore{rounds: []*ConsensusRound{
+		// the round before the rebuilt windows
+		round(9, keys[0], []*ViewRecord{{OnDutyArbitrator: keys[0]}}),
+		round(12, keys[1], []*ViewRecord{{OnDutyArbitrator: keys[0]},
+			{OnDutyArbitrator: keys[1], Offset: 1}},
+			vote(keys[0], time.Second), vote(keys[2], 10*time.Second)),
+		round(13, keys[2], []*ViewRecord{{OnDutyArbitrator: keys[2]}},
+			vote(keys[0], time.Second)),
+		round(15, keys[0], []*ViewRecord{{OnDutyArbitrator: keys[0]}},
+			vote(keys[1], time.Second), vote(keys[2], time.Second)),
+	}}
+
+	// the arbitration rounds start at height 12 and 15
+	arbitrators := state.NewArbitratorsMock(arbiters, 0, 2)
+	arbitrators.SetDutyChangeCount(1)
+	metrics := NewArbiterMetrics(ArbiterMetricsConfig{
+		Arbitrators:       arbitrators,
+		Store:             store,
+		ToleranceDuration: 5 * time.Second,
+		MaxWindows:        2,
+	})
+	metrics.OnViewStarted(&log.ViewEvent{OnDutyArbitrator: keys[1],
+		StartTime: now, Height: 16})
+
+	windows := metrics.GetWindows()
+	if !assert.Equal(t, 2, len(windows)) {
+		return
+	}
+	w := windows[0]
+	assert.Equal(t, uint32(12), w.StartHeight)
+	assert.Equal(t, uint32(13), w.EndHeight)
+	assert.Equal(t, ArbiterCounters{VotesOnTime: 2, ViewChanges: 1,
+		InactivityStrikes: 1}, *w.Counters[keys[0]])
+	assert.Equal(t, ArbiterCounters{Proposals: 1, RoundsMissed: 1},
+		*w.Counters[keys[1]])
+	assert.Equal(t, ArbiterCounters{Proposals: 1, VotesLate: 1},
+		*w.Counters[keys[2]])
+
+	w = windows[1]
+	assert.Equal(t, uint32(15), w.StartHeight)
+	assert.Equal(t, uint32(16), w.EndHeight)
+	assert.Equal(t, ArbiterCounters{Proposals: 1}, *w.Counters[keys[0]])
+	assert.Equal(t, ArbiterCounters{VotesOnTime: 1}, *w.Counters[keys[1]])
+	assert.Equal(t, ArbiterCounters{VotesOnTime: 1}, *w.Counters[keys[2]])
+}
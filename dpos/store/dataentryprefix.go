@@ -14,4 +14,5 @@ const (
 	DPOSCurrentReward      DataEntryPrefix = 0x16
 	DPOSNextReward         DataEntryPrefix = 0x17
 	DPOSState              DataEntryPrefix = 0x18
+	DPOSSignRecord         DataEntryPrefix = 0x19
 )
@@ -435,3 +435,107 @@ func (s *DposStore) deleteTable(table *DBTable) error {
 
 	return batch.Commit()
 }
+
+// getTable returns the schema of the table stored in database.
+func (s *DposStore) getTable(name string) (*DBTable, error) {
+	data, err := s.db.Get(GetTableKey(name))
+	if err != nil {
+		return nil, err
+	}
+	var table DBTable
+	if err := table.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// upgradeTable updates the stored schema of an existing table to the given
+// one, the indexes added since the table was created will be built from the
+// rows already stored, and the indexes removed will be deleted. New fields can
+// only be appended to the end of the table.
+func (s *DposStore) upgradeTable(table *DBTable) error {
+	stored, err := s.getTable(table.Name)
+	if err != nil {
+		return err
+	}
+	if stored.PrimaryKey != table.PrimaryKey ||
+		len(stored.Fields) > len(table.Fields) {
+		return fmt.Errorf("incompatible schema of table %s", table.Name)
+	}
+	for i, f := range stored.Fields {
+		if table.Fields[i] != f {
+			return fmt.Errorf("incompatible schema of table %s", table.Name)
+		}
+	}
+
+	oldIndexes := make(map[uint64]struct{})
+	for _, index := range stored.Indexes {
+		oldIndexes[index] = struct{}{}
+	}
+	newIndexes := make(map[uint64]struct{})
+	for _, index := range table.Indexes {
+		newIndexes[index] = struct{}{}
+	}
+	// indexes to be built or deleted, true means to be built
+	changes := make(map[uint64]bool)
+	for index := range newIndexes {
+		if _, ok := oldIndexes[index]; !ok {
+			changes[index] = true
+		}
+	}
+	for index := range oldIndexes {
+		if _, ok := newIndexes[index]; !ok {
+			changes[index] = false
+		}
+	}
+	if len(changes) == 0 && len(stored.Fields) == len(table.Fields) {
+		return nil
+	}
+
+	idBytes, err := s.db.Get(GetTableIDKey(table.Name))
+	if err != nil {
+		return err
+	}
+	rowCount := BytesToUint64(idBytes)
+
+	// key: columnValue, value: [rowID1,rowID2,rowID3,...]
+	values := make(map[uint64]map[string][]uint64)
+	for index := range changes {
+		values[index] = make(map[string][]uint64)
+	}
+	for rowID := uint64(1); rowID <= rowCount; rowID++ {
+		fields, err := s.getFieldsByRowID(table, rowID)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if rows, ok := values[table.Column(f.Name)]; ok {
+				data := string(f.Data())
+				rows[data] = append(rows[data], rowID)
+			}
+		}
+	}
+
+	batch := s.db.NewBatch()
+	for index, build := range changes {
+		for data, rowIDs := range values[index] {
+			key := GetIndexKey(table.Name, index, []byte(data))
+			if !build {
+				batch.Delete(key)
+				continue
+			}
+			indexListBytes, err := Uint64ListToBytes(rowIDs)
+			if err != nil {
+				return err
+			}
+			batch.Put(key, indexListBytes)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := table.Serialize(buf); err != nil {
+		return err
+	}
+	batch.Put(GetTableKey(table.Name), buf.Bytes())
+	return batch.Commit()
+}
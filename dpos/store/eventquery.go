@@ -0,0 +1,286 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+
+	"github.com/syndtr/goleveldb/leveldb/errors"
+)
+
+const (
+	// MaxConsensusQueryRange is the maximum count of heights can be queried
+	// by one GetConsensusRounds call.
+	MaxConsensusQueryRange = 100
+)
+
+// ConsensusRound contains the recorded events of the consensus at a height.
+type ConsensusRound struct {
+	Height    uint32
+	StartTime time.Time
+	EndTime   time.Time
+	Proposals []*ProposalRecord
+	Views     []*ViewRecord
+}
+
+// Duration returns the time cost of the consensus, zero will be returned if
+// the consensus has not finished.
+func (c *ConsensusRound) Duration() time.Duration {
+	if c.StartTime.IsZero() || c.EndTime.IsZero() {
+		return 0
+	}
+	return c.EndTime.Sub(c.StartTime)
+}
+
+// ViewChanges returns the count of view changes happened in the consensus.
+func (c *ConsensusRound) ViewChanges() int {
+	if len(c.Views) == 0 {
+		return 0
+	}
+	return len(c.Views) - 1
+}
+
+// ProposalRecord contains the recorded proposal and votes on it.
+type ProposalRecord struct {
+	Sponsor      string
+	BlockHash    common.Uint256
+	ProposalHash common.Uint256
+	ViewOffset   uint32
+	ReceivedTime time.Time
+	EndTime      time.Time
+	Result       bool
+	Votes        []*VoteRecord
+}
+
+// AcceptCount returns the count of accept votes on the proposal.
+func (p *ProposalRecord) AcceptCount() int {
+	count := 0
+	for _, v := range p.Votes {
+		if v.Accept {
+			count++
+		}
+	}
+	return count
+}
+
+// RejectCount returns the count of reject votes on the proposal.
+func (p *ProposalRecord) RejectCount() int {
+	return len(p.Votes) - p.AcceptCount()
+}
+
+// VoteRecord contains the recorded vote of a proposal.
+type VoteRecord struct {
+	Signer       string
+	Accept       bool
+	ReceivedTime time.Time
+}
+
+// ViewRecord contains the recorded view of a consensus.
+type ViewRecord struct {
+	OnDutyArbitrator string
+	Offset           uint32
+	StartTime        time.Time
+}
+
+// GetConsensusRounds returns the recorded consensus rounds from startHeight
+// to endHeight, heights without any consensus record will be skipped.
+func (s *DposStore) GetConsensusRounds(startHeight,
+	endHeight uint32) ([]*ConsensusRound, error) {
+	if endHeight < startHeight {
+		return nil, fmt.Errorf("invalid height range [%d, %d]",
+			startHeight, endHeight)
+	}
+	if endHeight-startHeight >= MaxConsensusQueryRange {
+		return nil, fmt.Errorf("height range exceeds max query range %d",
+			MaxConsensusQueryRange)
+	}
+
+	var rounds []*ConsensusRound
+	for height := startHeight; height <= endHeight; height++ {
+		round, err := s.getConsensusRound(height)
+		if err != nil {
+			return nil, err
+		}
+		if round != nil {
+			rounds = append(rounds, round)
+		}
+
+		// avoid overflow when endHeight is the max value of uint32
+		if height == endHeight {
+			break
+		}
+	}
+	return rounds, nil
+}
+
+func (s *DposStore) getConsensusRound(height uint32) (*ConsensusRound, error) {
+	consRows, _, err := s.selectRows(ConsensusEventTable,
+		&Field{"Height", height})
+	if err != nil {
+		return nil, err
+	}
+	if len(consRows) == 0 {
+		return nil, nil
+	}
+
+	// there may be more than one consensus record at the same height when the
+	// consensus was restarted, merge them into one round.
+	round := &ConsensusRound{Height: height}
+	blockHashes := make(map[common.Uint256]struct{})
+	for _, row := range consRows {
+		fields := fieldsMap(row)
+		startTime := fieldTime(fields, "StartTime")
+		if !startTime.IsZero() && (round.StartTime.IsZero() ||
+			startTime.Before(round.StartTime)) {
+			round.StartTime = startTime
+		}
+		endTime := fieldTime(fields, "EndTime")
+		if endTime.After(round.EndTime) {
+			round.EndTime = endTime
+		}
+
+		if data, ok := fields["RawData"].([]byte); ok {
+			var header types.Header
+			if err := header.Deserialize(bytes.NewReader(data)); err == nil {
+				blockHashes[header.Hash()] = struct{}{}
+			}
+		}
+	}
+
+	for hash := range blockHashes {
+		proposals, err := s.getProposalRecords(hash)
+		if err != nil {
+			return nil, err
+		}
+		round.Proposals = append(round.Proposals, proposals...)
+	}
+	sort.Slice(round.Proposals, func(i, j int) bool {
+		return round.Proposals[i].ReceivedTime.Before(
+			round.Proposals[j].ReceivedTime)
+	})
+
+	round.Views, err = s.getViewRecords(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return round, nil
+}
+
+func (s *DposStore) getProposalRecords(
+	blockHash common.Uint256) ([]*ProposalRecord, error) {
+	rows, ids, err := s.selectRows(ProposalEventTable,
+		&Field{"BlockHash", blockHash.Bytes()})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*ProposalRecord, 0, len(rows))
+	for i, row := range rows {
+		fields := fieldsMap(row)
+		record := &ProposalRecord{
+			BlockHash:    blockHash,
+			ReceivedTime: fieldTime(fields, "ReceivedTime"),
+			EndTime:      fieldTime(fields, "EndTime"),
+		}
+		record.Sponsor, _ = fields["Sponsor"].(string)
+		record.ProposalHash, _ = fields["ProposalHash"].(common.Uint256)
+		record.Result, _ = fields["Result"].(bool)
+		if data, ok := fields["RawData"].([]byte); ok {
+			var proposal payload.DPOSProposal
+			if err := proposal.Deserialize(
+				bytes.NewReader(data)); err == nil {
+				record.ViewOffset = proposal.ViewOffset
+			}
+		}
+
+		record.Votes, err = s.getVoteRecords(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (s *DposStore) getVoteRecords(proposalID uint64) ([]*VoteRecord, error) {
+	rows, _, err := s.selectRows(VoteEventTable,
+		&Field{"ProposalID", proposalID})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*VoteRecord, 0, len(rows))
+	for _, row := range rows {
+		fields := fieldsMap(row)
+		record := &VoteRecord{ReceivedTime: fieldTime(fields, "ReceivedTime")}
+		record.Signer, _ = fields["Signer"].(string)
+		record.Accept, _ = fields["Result"].(bool)
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ReceivedTime.Before(records[j].ReceivedTime)
+	})
+	return records, nil
+}
+
+func (s *DposStore) getViewRecords(height uint32) ([]*ViewRecord, error) {
+	rows, _, err := s.selectRows(ViewEventTable, &Field{"Height", height})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*ViewRecord, 0, len(rows))
+	for _, row := range rows {
+		fields := fieldsMap(row)
+		record := &ViewRecord{StartTime: fieldTime(fields, "StartTime")}
+		record.OnDutyArbitrator, _ = fields["OnDutyArbitrator"].(string)
+		record.Offset, _ = fields["Offset"].(uint32)
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartTime.Before(records[j].StartTime)
+	})
+	return records, nil
+}
+
+// selectRows returns the rows and row IDs matched by the given indexed field
+// in row ID order, no error will be returned if nothing matched.
+func (s *DposStore) selectRows(table *DBTable,
+	field *Field) ([][]*Field, []uint64, error) {
+	ids, err := s.SelectID(table, []*Field{field})
+	if err != nil {
+		if err == errors.ErrNotFound {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	rows, err := s.selectValuesFromRowIDs(table, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, ids, nil
+}
+
+func fieldsMap(fields []*Field) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		result[f.Name] = f.Value
+	}
+	return result
+}
+
+func fieldTime(fields map[string]interface{}, name string) time.Time {
+	nano, ok := fields[name].(int64)
+	if !ok || nano <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nano)
+}
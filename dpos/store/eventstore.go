@@ -40,7 +40,7 @@ var ProposalEventTable = &DBTable{
 var VoteEventTable = &DBTable{
 	Name:       "VoteEvent",
 	PrimaryKey: 0,
-	Indexes:    nil,
+	Indexes:    []uint64{1},
 	Fields: []string{
 		"ProposalID",
 		"Signer",
@@ -53,12 +53,13 @@ var VoteEventTable = &DBTable{
 var ViewEventTable = &DBTable{
 	Name:       "ViewEvent",
 	PrimaryKey: 0,
-	Indexes:    nil,
+	Indexes:    []uint64{5},
 	Fields: []string{
 		"ConsensusID",
 		"OnDutyArbitrator",
 		"StartTime",
 		"Offset",
+		"Height",
 	},
 }
 
@@ -206,11 +207,26 @@ func (s *DposStore) StartEventRecord() {
 	if err != nil {
 		log.Debug("create ViewEvent table failed:", err.Error())
 	}
+	if err := s.upgradeEventTables(); err != nil {
+		log.Warn("upgrade event tables failed:", err.Error())
+	}
 
 	s.wg.Add(1)
 	go s.eventLoop()
 }
 
+// upgradeEventTables upgrades the event tables created by earlier versions to
+// the current schema.
+func (s *DposStore) upgradeEventTables() error {
+	for _, table := range []*DBTable{ConsensusEventTable, ProposalEventTable,
+		VoteEventTable} {
+		if err := s.upgradeTable(table); err != nil {
+			return err
+		}
+	}
+	return s.upgradeViewEventTable()
+}
+
 func (s *DposStore) createConsensusEventTable() error {
 	result := s.Create(ConsensusEventTable)
 	return result
@@ -364,6 +380,56 @@ func (s *DposStore) createViewEventTable() error {
 	return result
 }
 
+// upgradeViewEventTable fills the height of view events recorded before the
+// Height column was added from the consensus events they belong to, so that
+// they can be found by the Height index.
+func (s *DposStore) upgradeViewEventTable() error {
+	stored, err := s.getTable(ViewEventTable.Name)
+	if err != nil {
+		return err
+	}
+	if stored.Column("Height") == 0 {
+		idBytes, err := s.db.Get(GetTableIDKey(ViewEventTable.Name))
+		if err != nil {
+			return err
+		}
+		batch := s.db.NewBatch()
+		for rowID := uint64(1); rowID <= BytesToUint64(idBytes); rowID++ {
+			fields, err := s.getFieldsByRowID(ViewEventTable, rowID)
+			if err != nil {
+				return err
+			}
+			row := fieldsMap(fields)
+			if _, ok := row["Height"]; ok {
+				continue
+			}
+			consensusID, ok := row["ConsensusID"].(uint64)
+			if !ok || consensusID == math.MaxInt64 {
+				continue
+			}
+			consFields, err := s.getFieldsByRowID(ConsensusEventTable,
+				consensusID)
+			if err != nil {
+				continue
+			}
+			height, ok := fieldsMap(consFields)["Height"]
+			if !ok {
+				continue
+			}
+			data, err := ViewEventTable.Data(append(fields,
+				&Field{"Height", height}))
+			if err != nil {
+				return err
+			}
+			batch.Put(GetRowKey(ViewEventTable.Name, rowID), data)
+		}
+		if err := batch.Commit(); err != nil {
+			return err
+		}
+	}
+	return s.upgradeTable(ViewEventTable)
+}
+
 func (s *DposStore) AddViewEvent(event interface{}) error {
 	e, ok := event.(*log.ViewEvent)
 	if !ok {
@@ -392,5 +458,6 @@ func (s *DposStore) addViewEvent(event *log.ViewEvent) (uint64, error) {
 		{"OnDutyArbitrator", event.OnDutyArbitrator},
 		{"StartTime", event.StartTime.UnixNano()},
 		{"Offset", event.Offset},
+		{"Height", event.Height},
 	})
 }
@@ -2,6 +2,8 @@ package store
 
 import (
 	"crypto/rand"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
@@ -10,6 +12,8 @@ import (
 	"github.com/elastos/Elastos.ELA/core/types/payload"
 	"github.com/elastos/Elastos.ELA/dpos/log"
 	"github.com/elastos/Elastos.ELA/utils/test"
+
+	"github.com/stretchr/testify/assert"
 )
 
 var eventStore *DposStore
@@ -144,6 +148,80 @@ func TestEventStore_AddVoteEvent(t *testing.T) {
 	}
 }
 
+func TestEventStore_GetConsensusRounds(t *testing.T) {
+	header := &types.Header{Height: 1}
+	startTime := time.Unix(100, 0)
+	_, err := eventStore.addConsensusEvent(&log.ConsensusEvent{
+		StartTime: startTime,
+		Height:    1,
+		RawData:   header,
+	})
+	assert.NoError(t, err)
+	_, err = eventStore.addViewEvent(&log.ViewEvent{
+		OnDutyArbitrator: "B",
+		StartTime:        startTime,
+		Offset:           0,
+		Height:           1,
+	})
+	assert.NoError(t, err)
+
+	proposal := &payload.DPOSProposal{
+		Sponsor:    randomPkBytes(),
+		BlockHash:  header.Hash(),
+		Sign:       []byte{1, 2, 3},
+		ViewOffset: 0,
+	}
+	_, err = eventStore.addProposalEvent(&log.ProposalEvent{
+		Sponsor:      "B",
+		BlockHash:    header.Hash(),
+		ReceivedTime: startTime.Add(time.Second),
+		ProposalHash: proposal.Hash(),
+		RawData:      proposal,
+	})
+	assert.NoError(t, err)
+	for _, accept := range []bool{true, true, false} {
+		_, err = eventStore.addVoteEvent(&log.VoteEvent{
+			Signer:       "C",
+			ReceivedTime: startTime.Add(2 * time.Second),
+			Result:       accept,
+			RawData: &payload.DPOSProposalVote{
+				ProposalHash: proposal.Hash(),
+				Signer:       randomPkBytes(),
+				Accept:       accept,
+				Sign:         []byte{1, 2, 3},
+			},
+		})
+		assert.NoError(t, err)
+	}
+	_, err = eventStore.updateConsensusEvent(&log.ConsensusEvent{
+		EndTime: startTime.Add(3 * time.Second),
+		Height:  1,
+	})
+	assert.NoError(t, err)
+
+	rounds, err := eventStore.GetConsensusRounds(1, 2)
+	assert.NoError(t, err)
+	if !assert.Equal(t, 1, len(rounds)) {
+		return
+	}
+	round := rounds[0]
+	assert.Equal(t, uint32(1), round.Height)
+	assert.Equal(t, 3*time.Second, round.Duration())
+	assert.Equal(t, 0, round.ViewChanges())
+	if !assert.Equal(t, 1, len(round.Proposals)) {
+		return
+	}
+	assert.Equal(t, "B", round.Proposals[0].Sponsor)
+	assert.Equal(t, proposal.Hash(), round.Proposals[0].ProposalHash)
+	assert.Equal(t, 2, round.Proposals[0].AcceptCount())
+	assert.Equal(t, 1, round.Proposals[0].RejectCount())
+
+	_, err = eventStore.GetConsensusRounds(2, 1)
+	assert.Error(t, err)
+	_, err = eventStore.GetConsensusRounds(0, MaxConsensusQueryRange)
+	assert.Error(t, err)
+}
+
 func TestEventStore_Close(t *testing.T) {
 	eventStore.deleteTable(ProposalEventTable)
 	eventStore.deleteTable(ConsensusEventTable)
@@ -152,6 +230,114 @@ func TestEventStore_Close(t *testing.T) {
 	eventStore.Close()
 }
 
+func TestEventStore_UpgradeTables(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventstore")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewDposStore(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+
+	// the vote and view event tables created by earlier versions
+	oldVoteEventTable := &DBTable{
+		Name:       VoteEventTable.Name,
+		PrimaryKey: VoteEventTable.PrimaryKey,
+		Fields:     VoteEventTable.Fields,
+	}
+	oldViewEventTable := &DBTable{
+		Name:       ViewEventTable.Name,
+		PrimaryKey: ViewEventTable.PrimaryKey,
+		Fields:     ViewEventTable.Fields[:4],
+	}
+	for _, table := range []*DBTable{ConsensusEventTable, ProposalEventTable,
+		oldVoteEventTable, oldViewEventTable} {
+		assert.NoError(t, store.Create(table))
+	}
+
+	header := &types.Header{Height: 1}
+	startTime := time.Unix(100, 0)
+	consensusID, err := store.addConsensusEvent(&log.ConsensusEvent{
+		StartTime: startTime,
+		Height:    1,
+		RawData:   header,
+	})
+	assert.NoError(t, err)
+	_, err = store.Insert(oldViewEventTable, []*Field{
+		{"ConsensusID", consensusID},
+		{"OnDutyArbitrator", "B"},
+		{"StartTime", startTime.UnixNano()},
+		{"Offset", uint32(0)},
+	})
+	assert.NoError(t, err)
+
+	proposal := &payload.DPOSProposal{
+		Sponsor:   randomPkBytes(),
+		BlockHash: header.Hash(),
+		Sign:      []byte{1, 2, 3},
+	}
+	proposalID, err := store.addProposalEvent(&log.ProposalEvent{
+		Sponsor:      "B",
+		BlockHash:    header.Hash(),
+		ReceivedTime: startTime.Add(time.Second),
+		ProposalHash: proposal.Hash(),
+		RawData:      proposal,
+	})
+	assert.NoError(t, err)
+	for _, accept := range []bool{true, false} {
+		_, err = store.Insert(oldVoteEventTable, []*Field{
+			{"ProposalID", proposalID},
+			{"Signer", "C"},
+			{"ReceivedTime", startTime.Add(2 * time.Second).UnixNano()},
+			{"Result", accept},
+			{"RawData", []byte{1, 2, 3}},
+		})
+		assert.NoError(t, err)
+	}
+
+	// the records can not be found before upgrading
+	rounds, err := store.GetConsensusRounds(1, 1)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(rounds)) {
+		assert.Equal(t, 0, len(rounds[0].Views))
+		if assert.Equal(t, 1, len(rounds[0].Proposals)) {
+			assert.Equal(t, 0, len(rounds[0].Proposals[0].Votes))
+		}
+	}
+
+	store.StartEventRecord()
+	for _, table := range []*DBTable{VoteEventTable, ViewEventTable} {
+		stored, err := store.getTable(table.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, table, stored)
+	}
+
+	rounds, err = store.GetConsensusRounds(1, 1)
+	assert.NoError(t, err)
+	if !assert.Equal(t, 1, len(rounds)) {
+		return
+	}
+	if assert.Equal(t, 1, len(rounds[0].Views)) {
+		assert.Equal(t, "B", rounds[0].Views[0].OnDutyArbitrator)
+	}
+	if assert.Equal(t, 1, len(rounds[0].Proposals)) {
+		assert.Equal(t, 1, rounds[0].Proposals[0].AcceptCount())
+		assert.Equal(t, 1, rounds[0].Proposals[0].RejectCount())
+	}
+
+	// upgrading again changes nothing
+	assert.NoError(t, store.upgradeEventTables())
+	rounds, err = store.GetConsensusRounds(1, 1)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(rounds)) {
+		assert.Equal(t, 1, len(rounds[0].Views))
+	}
+}
+
 func randomPkBytes() []byte {
 	pk := make([]byte, 33)
 	rand.Read(pk)
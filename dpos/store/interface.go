@@ -2,6 +2,7 @@ package store
 
 import (
 	"github.com/elastos/Elastos.ELA/blockchain"
+	"github.com/elastos/Elastos.ELA/dpos/signer"
 	"github.com/elastos/Elastos.ELA/dpos/state"
 )
 
@@ -46,9 +47,15 @@ type IEventRecord interface {
 	UpdateConsensusEvent(event interface{}) error
 }
 
+type IEventQuery interface {
+	GetConsensusRounds(startHeight, endHeight uint32) ([]*ConsensusRound, error)
+}
+
 // IDposStore provides func for dpos
 type IDposStore interface {
 	IDBOperator
 	IEventRecord
+	IEventQuery
+	signer.Journal
 	state.IArbitratorsRecord
 }
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/crypto"
+	"github.com/elastos/Elastos.ELA/dpos/signer"
+)
+
+// Ensure DposStore implement the signer.Journal interface.
+var _ signer.Journal = (*DposStore)(nil)
+
+// signRecordKey returns the key of a sign record, the height is encoded in big
+// endian so that the records are iterated in height order.
+func signRecordKey(r *signer.SignRecord) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(byte(DPOSSignRecord))
+	binary.Write(buf, binary.BigEndian, r.Height)
+	if r.Vote {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+	binary.Write(buf, binary.BigEndian, r.ViewOffset)
+	common.WriteVarBytes(buf, r.PublicKey)
+	return buf.Bytes()
+}
+
+func parseSignRecord(key, value []byte) (*signer.SignRecord, error) {
+	r := bytes.NewReader(key[1:])
+	var record signer.SignRecord
+	if err := binary.Read(r, binary.BigEndian, &record.Height); err != nil {
+		return nil, err
+	}
+	vote, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	record.Vote = vote == 1
+	if err := binary.Read(r, binary.BigEndian,
+		&record.ViewOffset); err != nil {
+		return nil, err
+	}
+	if record.PublicKey, err = common.ReadVarBytes(r,
+		crypto.NegativeBigLength, "public key"); err != nil {
+		return nil, err
+	}
+	r = bytes.NewReader(value)
+	if err := record.Hash.Deserialize(r); err != nil {
+		return nil, err
+	}
+	// the block hash of a proposal is appended to the hash
+	if !record.Vote {
+		if err := record.BlockHash.Deserialize(r); err != nil {
+			return nil, err
+		}
+	}
+	return &record, nil
+}
+
+func (s *DposStore) LoadSignRecords() ([]*signer.SignRecord, error) {
+	iter := s.db.NewIterator([]byte{byte(DPOSSignRecord)})
+	defer iter.Release()
+
+	var records []*signer.SignRecord
+	for iter.Next() {
+		record, err := parseSignRecord(iter.Key(), iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (s *DposStore) AppendSignRecord(r *signer.SignRecord) error {
+	buf := new(bytes.Buffer)
+	if err := r.Hash.Serialize(buf); err != nil {
+		return err
+	}
+	if !r.Vote {
+		if err := r.BlockHash.Serialize(buf); err != nil {
+			return err
+		}
+	}
+	return s.db.Put(signRecordKey(r), buf.Bytes())
+}
+
+func (s *DposStore) PruneSignRecords(height uint32) error {
+	iter := s.db.NewIterator([]byte{byte(DPOSSignRecord)})
+	defer iter.Release()
+
+	batch := s.db.NewBatch()
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) < 5 || binary.BigEndian.Uint32(key[1:5]) > height {
+			break
+		}
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	return batch.Commit()
+}
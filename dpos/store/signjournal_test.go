@@ -0,0 +1,70 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/dpos/signer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signjournal")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	sponsor := []byte{0x02, 0x01}
+	p1 := &payload.DPOSProposal{Sponsor: sponsor,
+		BlockHash: common.Uint256{1}}
+	p2 := &payload.DPOSProposal{Sponsor: sponsor,
+		BlockHash: common.Uint256{2}}
+	vote := &payload.DPOSProposalVote{ProposalHash: p1.Hash(),
+		Signer: sponsor, Accept: true}
+
+	store, err := NewDposStore(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	guard, err := signer.NewJournalGuard(store)
+	assert.NoError(t, err)
+	assert.NoError(t, guard.CheckProposal(10, p1))
+	assert.NoError(t, guard.CheckVote(10, 0, vote))
+	store.Close()
+
+	// sign records survive restarts
+	store, err = NewDposStore(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	records, err := store.LoadSignRecords()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(records))
+	guard, err = signer.NewJournalGuard(store)
+	assert.NoError(t, err)
+	assert.NoError(t, guard.CheckProposal(10, p1))
+	assert.Error(t, guard.CheckProposal(10, p2))
+	reject := &payload.DPOSProposalVote{ProposalHash: p1.Hash(),
+		Signer: sponsor, Accept: false}
+	assert.Error(t, guard.CheckVote(10, 0, reject))
+	assert.Error(t, guard.CheckProposal(11, p1))
+
+	// records lower than guarded heights are pruned
+	assert.NoError(t, guard.AdvanceHeight(9+signer.MaxGuardedHeights))
+	assert.NoError(t, guard.CheckProposal(10+signer.MaxGuardedHeights, p2))
+	records, err = store.LoadSignRecords()
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(records)) {
+		assert.Equal(t, uint32(10+signer.MaxGuardedHeights), records[0].Height)
+		assert.Equal(t, sponsor, records[0].PublicKey)
+		assert.Equal(t, p2.Hash(), records[0].Hash)
+		assert.Equal(t, p2.BlockHash, records[0].BlockHash)
+		assert.False(t, records[0].Vote)
+	}
+	store.Close()
+}
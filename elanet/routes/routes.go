@@ -96,6 +96,8 @@ type dAddrMsg struct {
 	msg  *msg.DAddr
 }
 
+type pidMsg dp.PID
+
 // Routes is the DPOS routes implementation.
 type Routes struct {
 	pid  dp.PID
@@ -156,6 +158,9 @@ out:
 
 			case peersMsg:
 				r.handlePeersMsg(state, m.peers)
+
+			case pidMsg:
+				r.handlePID(state, dp.PID(m))
 			}
 
 		// Handle the announce request.
@@ -351,6 +356,37 @@ func (r *Routes) handlePeersMsg(state *state, peers []dp.PID) {
 	}
 }
 
+func (r *Routes) handlePID(s *state, pid dp.PID) {
+	if r.pid.Equal(pid) {
+		return
+	}
+	r.pid = pid
+
+	// Notify the known DPOS addresses encoded to the new PID.
+	var addrs []*msg.DAddr
+	r.addrMtx.RLock()
+	for _, index := range r.addrIndex {
+		hash, ok := index[pid]
+		if !ok {
+			continue
+		}
+		if addr, ok := r.knownAddr[hash]; ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	r.addrMtx.RUnlock()
+	if r.cfg.OnCipherAddr != nil {
+		for _, addr := range addrs {
+			r.cfg.OnCipherAddr(addr.PID, addr.Cipher)
+		}
+	}
+
+	// Announce address of the new PID if it is an arbiter.
+	if _, ok := s.peers[pid]; ok {
+		r.announceAddr()
+	}
+}
+
 func (r *Routes) handleInv(s *state, p *peer.Peer, m *msg.Inv) {
 	c, exists := s.peerCache[p]
 	if !exists {
@@ -563,6 +599,14 @@ func (r *Routes) AnnounceAddr() {
 	r.announceAddr()
 }
 
+// SetPID changes the PID of this peer, it used to switch to the new node key
+// when the arbiter's node public key has been changed.
+func (r *Routes) SetPID(pid []byte) {
+	var id dp.PID
+	copy(id[:], pid)
+	r.queue <- pidMsg(id)
+}
+
 // New creates and return a Routes instance.
 func New(cfg *Config) *Routes {
 	var pid dp.PID
@@ -32,6 +32,7 @@ const (
 	InvalidMethod        ErrCode = 42001
 	InvalidParams        ErrCode = 42002
 	InvalidToken         ErrCode = 42003
+	RateLimited          ErrCode = 42004
 	InvalidTransaction   ErrCode = 43001
 	InvalidAsset         ErrCode = 43002
 	UnknownTransaction   ErrCode = 44001
@@ -49,6 +50,7 @@ var ErrMap = map[ErrCode]string{
 	InvalidMethod:             "Invalid method",
 	InvalidParams:             "Invalid Params",
 	InvalidToken:              "Verify token error",
+	RateLimited:               "Rate limit exceeded",
 	InvalidTransaction:        "Invalid transaction",
 	InvalidAsset:              "Invalid asset",
 	UnknownTransaction:        "Unknown Transaction",
@@ -30,6 +30,7 @@ func TestErrCode_Message(t *testing.T) {
 		InvalidMethod,
 		InvalidParams,
 		InvalidToken,
+		RateLimited,
 		InvalidTransaction,
 		InvalidAsset,
 		UnknownTransaction,
@@ -47,6 +47,10 @@ const (
 
 	// ETIllegalEvidence indicates a illegal block received.
 	ETIllegalBlockEvidence
+
+	// ETNodeKeyRotated indicates the node public key of an arbiter was
+	// replaced by an UpdateProducer transaction.
+	ETNodeKeyRotated
 )
 
 // notificationTypeStrings is a map of notification types back to their constant
@@ -59,6 +63,7 @@ var notificationTypeStrings = map[EventType]string{
 	ETNewBlockReceived:    "ETNewBlockReceived",
 	ETConfirmAccepted:     "ETConfirmAccepted",
 	ETDirectPeersChanged:  "ETDirectPeersChanged",
+	ETNodeKeyRotated:      "ETNodeKeyRotated",
 }
 
 // String returns the EventType in human-readable form.
@@ -76,6 +81,7 @@ func (n EventType) String() string {
 // 	- ETBlockConnected:    *types.Block
 // 	- ETBlockDisconnected: *types.Block
 // 	- ETTransactionAccepted: *types.Transaction
+// 	- ETNodeKeyRotated:    *state.NodeKeyRotation
 type Event struct {
 	Type EventType
 	Data interface{}
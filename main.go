@@ -26,6 +26,7 @@ import (
 	"github.com/elastos/Elastos.ELA/p2p/msg"
 	"github.com/elastos/Elastos.ELA/pow"
 	"github.com/elastos/Elastos.ELA/servers"
+	"github.com/elastos/Elastos.ELA/servers/grpcapi"
 	"github.com/elastos/Elastos.ELA/servers/httpjsonrpc"
 	"github.com/elastos/Elastos.ELA/servers/httpnodeinfo"
 	"github.com/elastos/Elastos.ELA/servers/httprestful"
@@ -119,11 +120,17 @@ func startNode(c *cli.Context) {
 
 	var act account.Account
 	if cfg.DPoSConfiguration.EnableArbiter {
-		password, err := cmdcom.GetFlagPassword(c)
-		if err != nil {
-			printErrorAndExit(err)
+		var err error
+		if socket := cfg.DPoSConfiguration.SignerSocket; socket != "" {
+			act, err = account.OpenRemote(socket)
+		} else {
+			var password []byte
+			password, err = cmdcom.GetFlagPassword(c)
+			if err != nil {
+				printErrorAndExit(err)
+			}
+			act, err = account.Open(password)
 		}
-		act, err = account.Open(password)
 		if err != nil {
 			printErrorAndExit(err)
 		}
@@ -224,7 +231,7 @@ func startNode(c *cli.Context) {
 		dlog.Init(dcfg.PrintLevel, dcfg.MaxPerLogSize, dcfg.MaxLogsSize)
 		arbitrator, err = dpos.NewArbitrator(act, dpos.Config{
 			EnableEventLog:    true,
-			EnableEventRecord: false,
+			EnableEventRecord: cfg.DPoSConfiguration.EnableEventRecord,
 			Localhost:         cfg.DPoSConfiguration.IPAddress,
 			ChainParams:       activeNetParams,
 			Arbitrators:       arbiters,
@@ -236,6 +243,7 @@ func startNode(c *cli.Context) {
 				server.BroadcastMessage(msg)
 			},
 			AnnounceAddr: route.AnnounceAddr,
+			UpdatePID:    route.SetPID,
 		})
 		if err != nil {
 			printErrorAndExit(err)
@@ -248,11 +256,13 @@ func startNode(c *cli.Context) {
 
 	servers.Compile = Version
 	servers.Config = cfg
+	servers.ChainParams = activeNetParams
 	servers.Chain = chain
 	servers.Store = chainStore
 	servers.TxMemPool = txMemPool
 	servers.Server = server
 	servers.Arbiters = arbiters
+	servers.DposStore = dposStore
 	servers.Pow = pow.NewService(&pow.Config{
 		PayToAddr:   cfg.PowConfiguration.PayToAddr,
 		MinerInfo:   cfg.PowConfiguration.MinerInfo,
@@ -279,9 +289,13 @@ func startNode(c *cli.Context) {
 	defer server.Stop()
 
 	log.Info("Start services")
+	servers.InitLimiters(&cfg.RateLimit)
 	if cfg.EnableRPC {
 		go httpjsonrpc.StartRPCServer()
 	}
+	if cfg.EnableGRPC {
+		go grpcapi.StartServer()
+	}
 	if cfg.HttpRestStart {
 		go httprestful.StartServer()
 	}
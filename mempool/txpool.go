@@ -18,6 +18,28 @@ import (
 	"github.com/elastos/Elastos.ELA/events"
 )
 
+// Names of the checks to append a transaction to the pool.
+const (
+	CheckDuplicate = "duplicate"
+	CheckCoinbase  = "coinbase"
+	CheckSanity    = "sanity"
+	CheckContext   = "context"
+	CheckTxPool    = "txpool"
+	CheckPoolSize  = "poolsize"
+)
+
+// CheckResult is the result of a check to append a transaction to the pool.
+type CheckResult struct {
+	// Check is the name of the check.
+	Check string
+
+	// Code is the error code of the check, Success if the check passed.
+	Code ErrCode
+
+	// Err is the reason why the check failed, nil if the check passed.
+	Err error
+}
+
 type TxPool struct {
 	chainParams *config.Params
 
@@ -76,18 +98,78 @@ func (mp *TxPool) appendToTxPool(tx *Transaction) ErrCode {
 		return errCode
 	}
 
-	size := tx.GetSize()
-	if mp.txnListSize+size > pact.MaxTxPoolSize {
+	if err := mp.checkTxPoolSize(tx, nil); err != nil {
 		log.Warn("TxPool check transactions size failed", tx.Hash())
 		return ErrTransactionPoolSize
 	}
 	// Add the transaction to mem pool
 	mp.txnList[txHash] = tx
-	mp.txnListSize += size
+	mp.txnListSize += tx.GetSize()
 
 	return Success
 }
 
+// TestAcceptTransaction runs the checks to append the transaction to the pool
+// without adding it, the checks stop at the first failed one. The SideChainPow
+// transactions to be replaced by the transaction are excluded from the checks
+// as if they have been removed from the pool.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) TestAcceptTransaction(tx *Transaction) []CheckResult {
+	mp.RLock()
+	defer mp.RUnlock()
+
+	var results []CheckResult
+	check := func(name string, code ErrCode, err error) bool {
+		if code != Success && err == nil {
+			err = code
+		}
+		results = append(results, CheckResult{Check: name, Code: code, Err: err})
+		return code == Success
+	}
+
+	if _, ok := mp.txnList[tx.Hash()]; ok {
+		check(CheckDuplicate, ErrTransactionDuplicate,
+			errors.New("transaction already in the pool"))
+		return results
+	}
+	check(CheckDuplicate, Success, nil)
+
+	if tx.IsCoinBaseTx() {
+		check(CheckCoinbase, ErrIneffectiveCoinbase,
+			errors.New("coinbase tx cannot be added into transaction pool"))
+		return results
+	}
+	check(CheckCoinbase, Success, nil)
+
+	chain := blockchain.DefaultLedger.Blockchain
+	bestHeight := chain.GetHeight()
+	if errCode, err := chain.CheckTransactionSanityDetail(bestHeight+1,
+		tx); !check(CheckSanity, errCode, err) {
+		return results
+	}
+	if errCode, err := chain.CheckTransactionContextDetail(bestHeight+1,
+		tx); !check(CheckContext, errCode, err) {
+		return results
+	}
+	var replaced []*Transaction
+	if tx.IsSideChainPowTx() {
+		replaced = mp.duplicateSideChainPowTxs(tx)
+	}
+	if errCode, err := mp.checkTransactionWithTxnPool(tx,
+		replaced); !check(CheckTxPool, errCode, err) {
+		return results
+	}
+
+	if err := mp.checkTxPoolSize(tx, replaced); err != nil {
+		check(CheckPoolSize, ErrTransactionPoolSize, err)
+		return results
+	}
+	check(CheckPoolSize, Success, nil)
+
+	return results
+}
+
 // HaveTransaction returns if a transaction is in transaction pool by the given
 // transaction id. If no transaction match the transaction id, return false
 func (mp *TxPool) HaveTransaction(txId Uint256) bool {
@@ -302,69 +384,72 @@ func (mp *TxPool) verifyTransactionWithTxnPool(txn *Transaction) ErrCode {
 	if txn.IsSideChainPowTx() {
 		// check and replace the duplicate sidechainpow tx
 		mp.replaceDuplicateSideChainPowTx(txn)
-	} else if txn.IsWithdrawFromSideChainTx() {
+	}
+
+	if errCode, err := mp.checkTransactionWithTxnPool(txn, nil); err != nil {
+		log.Warn(err)
+		return errCode
+	}
+
+	if txn.IsWithdrawFromSideChainTx() {
+		mp.addSidechainTx(txn)
+	}
+	for _, input := range txn.Inputs {
+		mp.addInputUTXOList(txn, input)
+	}
+	mp.addProducerRelatedTx(txn)
+
+	return Success
+}
+
+// checkTransactionWithTxnPool checks the transaction with txnpool without
+// changing the pool, the transactions to be replaced by txn are excluded.
+func (mp *TxPool) checkTransactionWithTxnPool(txn *Transaction,
+	replaced []*Transaction) (ErrCode, error) {
+	if txn.IsWithdrawFromSideChainTx() {
 		// check if the withdraw transaction includes duplicate sidechain tx in pool
-		if err := mp.verifyDuplicateSidechainTx(txn); err != nil {
-			log.Warn(err)
-			return ErrSidechainTxDuplicate
+		if err := mp.checkDuplicateSidechainTx(txn); err != nil {
+			return ErrSidechainTxDuplicate, err
 		}
 	}
 
 	// check if the transaction includes double spent UTXO inputs
-	if err := mp.verifyDoubleSpend(txn); err != nil {
-		log.Warn(err)
-		return ErrDoubleSpend
+	if err := mp.checkDoubleSpend(txn, replaced); err != nil {
+		return ErrDoubleSpend, err
 	}
 
-	return mp.verifyProducerRelatedTx(txn)
+	return mp.checkProducerRelatedTx(txn)
 }
 
-//verify producer related transaction with txnpool
-func (mp *TxPool) verifyProducerRelatedTx(txn *Transaction) ErrCode {
+//check producer related transaction with txnpool
+func (mp *TxPool) checkProducerRelatedTx(txn *Transaction) (ErrCode, error) {
 	switch txn.TxType {
-	case RegisterProducer:
-		payload, ok := txn.Payload.(*payload.ProducerInfo)
-		if !ok {
-			log.Error("register producer payload cast failed, tx:", txn.Hash())
-		}
-		if err := mp.verifyDuplicateOwner(BytesToHexString(payload.OwnerPublicKey)); err != nil {
-			log.Warn(err)
-			return ErrProducerProcessing
-		}
-		if err := mp.verifyDuplicateNode(BytesToHexString(payload.NodePublicKey)); err != nil {
-			log.Warn(err)
-			return ErrProducerNodeProcessing
-		}
-	case UpdateProducer:
+	case RegisterProducer, UpdateProducer:
 		payload, ok := txn.Payload.(*payload.ProducerInfo)
 		if !ok {
-			log.Error("update producer payload cast failed, tx:", txn.Hash())
+			log.Error("producer info payload cast failed, tx:", txn.Hash())
 		}
-		if err := mp.verifyDuplicateOwner(BytesToHexString(payload.OwnerPublicKey)); err != nil {
-			log.Warn(err)
-			return ErrProducerProcessing
+		if err := mp.checkDuplicateOwner(BytesToHexString(payload.OwnerPublicKey)); err != nil {
+			return ErrProducerProcessing, err
 		}
-		if err := mp.verifyDuplicateNode(BytesToHexString(payload.NodePublicKey)); err != nil {
-			log.Warn(err)
-			return ErrProducerNodeProcessing
+		if err := mp.checkDuplicateNode(BytesToHexString(payload.NodePublicKey)); err != nil {
+			return ErrProducerNodeProcessing, err
 		}
 	case CancelProducer:
 		payload, ok := txn.Payload.(*payload.ProcessProducer)
 		if !ok {
 			log.Error("cancel producer payload cast failed, tx:", txn.Hash())
 		}
-		if err := mp.verifyDuplicateOwner(BytesToHexString(payload.OwnerPublicKey)); err != nil {
-			log.Warn(err)
-			return ErrProducerProcessing
+		if err := mp.checkDuplicateOwner(BytesToHexString(payload.OwnerPublicKey)); err != nil {
+			return ErrProducerProcessing, err
 		}
 	case ActivateProducer:
 		payload, ok := txn.Payload.(*payload.ActivateProducer)
 		if !ok {
 			log.Error("activate producer payload cast failed, tx:", txn.Hash())
 		}
-		if err := mp.verifyDuplicateNode(BytesToHexString(payload.NodePublicKey)); err != nil {
-			log.Warn(err)
-			return ErrProducerNodeProcessing
+		if err := mp.checkDuplicateNode(BytesToHexString(payload.NodePublicKey)); err != nil {
+			return ErrProducerNodeProcessing, err
 		}
 	case IllegalProposalEvidence, IllegalVoteEvidence, IllegalBlockEvidence,
 		IllegalSidechainEvidence, InactiveArbitrators:
@@ -373,13 +458,32 @@ func (mp *TxPool) verifyProducerRelatedTx(txn *Transaction) ErrCode {
 			log.Error("special tx payload cast failed, tx:", txn.Hash())
 		}
 		hash := illegalData.Hash()
-		if err := mp.verifyDuplicateSpecialTx(&hash); err != nil {
-			log.Warn(err)
-			return ErrProducerProcessing
+		if err := mp.checkDuplicateSpecialTx(&hash); err != nil {
+			return ErrProducerProcessing, err
 		}
 	}
 
-	return Success
+	return Success, nil
+}
+
+//add the producer related transaction to txnpool
+func (mp *TxPool) addProducerRelatedTx(txn *Transaction) {
+	switch txn.TxType {
+	case RegisterProducer, UpdateProducer:
+		payload := txn.Payload.(*payload.ProducerInfo)
+		mp.addOwnerPublicKey(BytesToHexString(payload.OwnerPublicKey))
+		mp.addNodePublicKey(BytesToHexString(payload.NodePublicKey))
+	case CancelProducer:
+		payload := txn.Payload.(*payload.ProcessProducer)
+		mp.addOwnerPublicKey(BytesToHexString(payload.OwnerPublicKey))
+	case ActivateProducer:
+		payload := txn.Payload.(*payload.ActivateProducer)
+		mp.addNodePublicKey(BytesToHexString(payload.NodePublicKey))
+	case IllegalProposalEvidence, IllegalVoteEvidence, IllegalBlockEvidence,
+		IllegalSidechainEvidence, InactiveArbitrators:
+		hash := txn.Payload.(payload.DPOSIllegalData).Hash()
+		mp.addSpecialTx(&hash)
+	}
 }
 
 //remove from associated map
@@ -401,23 +505,21 @@ func (mp *TxPool) removeTransaction(tx *Transaction) {
 	}
 }
 
-//check and add to utxo list pool
-func (mp *TxPool) verifyDoubleSpend(txn *Transaction) error {
+//check double spend with utxo list pool, the inputs spent by the excluded
+//transactions are not taken as double spent.
+func (mp *TxPool) checkDoubleSpend(txn *Transaction,
+	excluded []*Transaction) error {
 	reference, err := blockchain.DefaultLedger.Store.GetTxReference(txn)
 	if err != nil {
 		return err
 	}
-	inputs := []*Input{}
 	for k := range reference {
-		if txn := mp.getInputUTXOList(k); txn != nil {
+		if txn := mp.getInputUTXOList(k); txn != nil &&
+			!containsTransaction(excluded, txn) {
 			return fmt.Errorf("double spent UTXO inputs detected, "+
 				"transaction hash: %s, input: %s, index: %d",
 				txn.Hash(), k.Previous.TxID, k.Previous.Index)
 		}
-		inputs = append(inputs, k)
-	}
-	for _, v := range inputs {
-		mp.addInputUTXOList(txn, v)
 	}
 
 	return nil
@@ -432,6 +534,30 @@ func (mp *TxPool) IsDuplicateSidechainTx(sidechainTxHash Uint256) bool {
 
 //check and add to sidechain tx pool
 func (mp *TxPool) verifyDuplicateSidechainTx(txn *Transaction) error {
+	if err := mp.checkDuplicateSidechainTx(txn); err != nil {
+		return err
+	}
+	mp.addSidechainTx(txn)
+
+	return nil
+}
+
+// checkTxPoolSize checks if the pool has room for the transaction after the
+// replaced transactions removed.
+func (mp *TxPool) checkTxPoolSize(tx *Transaction,
+	replaced []*Transaction) error {
+	size := mp.txnListSize + tx.GetSize()
+	for _, r := range replaced {
+		size -= r.GetSize()
+	}
+	if size > pact.MaxTxPoolSize {
+		return errors.New("transaction pool size exceeded")
+	}
+	return nil
+}
+
+//check duplicate sidechain tx with sidechain tx pool
+func (mp *TxPool) checkDuplicateSidechainTx(txn *Transaction) error {
 	withPayload, ok := txn.Payload.(*payload.WithdrawFromSideChain)
 	if !ok {
 		return errors.New("convert the payload of withdraw tx failed")
@@ -443,17 +569,15 @@ func (mp *TxPool) verifyDuplicateSidechainTx(txn *Transaction) error {
 			return errors.New("duplicate sidechain tx detected")
 		}
 	}
-	mp.addSidechainTx(txn)
 
 	return nil
 }
 
-func (mp *TxPool) verifyDuplicateOwner(ownerPublicKey string) error {
+func (mp *TxPool) checkDuplicateOwner(ownerPublicKey string) error {
 	_, ok := mp.ownerPublicKeys[ownerPublicKey]
 	if ok {
 		return errors.New("this producer in being processed")
 	}
-	mp.addOwnerPublicKey(ownerPublicKey)
 
 	return nil
 }
@@ -466,12 +590,11 @@ func (mp *TxPool) delOwnerPublicKey(publicKey string) {
 	delete(mp.ownerPublicKeys, publicKey)
 }
 
-func (mp *TxPool) verifyDuplicateNode(nodePublicKey string) error {
+func (mp *TxPool) checkDuplicateNode(nodePublicKey string) error {
 	_, ok := mp.nodePublicKeys[nodePublicKey]
 	if ok {
 		return errors.New("this producer node in being processed")
 	}
-	mp.addNodePublicKey(nodePublicKey)
 
 	return nil
 }
@@ -492,17 +615,27 @@ func (mp *TxPool) delSpecialTx(hash *Uint256) {
 	delete(mp.specialTxList, *hash)
 }
 
-func (mp *TxPool) verifyDuplicateSpecialTx(hash *Uint256) error {
+func (mp *TxPool) checkDuplicateSpecialTx(hash *Uint256) error {
 	if _, ok := mp.specialTxList[*hash]; ok {
 		return errors.New("this special tx has being processed")
 	}
-	mp.addSpecialTx(hash)
 
 	return nil
 }
 
 // check and replace the duplicate sidechainpow tx
 func (mp *TxPool) replaceDuplicateSideChainPowTx(txn *Transaction) {
+	for _, tx := range mp.duplicateSideChainPowTxs(txn) {
+		txid := tx.Hash()
+		log.Info("replace sidechainpow transaction, txid=", txid.String())
+		mp.removeTransaction(tx)
+	}
+}
+
+// duplicateSideChainPowTxs returns the sidechainpow txs in the pool of the
+// same side chain with the given sidechainpow tx, which will be replaced by
+// it.
+func (mp *TxPool) duplicateSideChainPowTxs(txn *Transaction) []*Transaction {
 	var replaceList []*Transaction
 
 	for _, v := range mp.txnList {
@@ -518,12 +651,18 @@ func (mp *TxPool) replaceDuplicateSideChainPowTx(txn *Transaction) {
 			}
 		}
 	}
+	return replaceList
+}
 
-	for _, txn := range replaceList {
-		txid := txn.Hash()
-		log.Info("replace sidechainpow transaction, txid=", txid.String())
-		mp.removeTransaction(txn)
+// containsTransaction returns if the transaction is in the given list.
+func containsTransaction(txs []*Transaction, tx *Transaction) bool {
+	hash := tx.Hash()
+	for _, t := range txs {
+		if t.Hash().IsEqual(hash) {
+			return true
+		}
 	}
+	return false
 }
 
 // clean the sidechain tx pool
@@ -17,6 +17,7 @@ import (
 	"github.com/elastos/Elastos.ELA/core/types/payload"
 	dplog "github.com/elastos/Elastos.ELA/dpos/log"
 	"github.com/elastos/Elastos.ELA/dpos/state"
+	"github.com/elastos/Elastos.ELA/elanet/pact"
 	"github.com/elastos/Elastos.ELA/errors"
 	"github.com/elastos/Elastos.ELA/utils/test"
 
@@ -118,6 +119,43 @@ func TestTxPool_VerifyDuplicateSidechainTx(t *testing.T) {
 	}
 }
 
+func TestTxPool_CheckDuplicateSidechainTx(t *testing.T) {
+	pool := NewTxPool(&config.DefaultParams)
+	var hash1, hash2, hash3 common.Uint256
+	rand.Read(hash1[:])
+	rand.Read(hash2[:])
+	rand.Read(hash3[:])
+	newWithdrawTx := func(hashes ...common.Uint256) *types.Transaction {
+		return &types.Transaction{
+			TxType: types.WithdrawFromSideChain,
+			Payload: &payload.WithdrawFromSideChain{
+				BlockHeight:                100,
+				SideChainTransactionHashes: hashes,
+			},
+		}
+	}
+
+	// the check does not add the sidechain txs to the pool
+	txn1 := newWithdrawTx(hash1, hash2)
+	assert.NoError(t, pool.checkDuplicateSidechainTx(txn1))
+	assert.NoError(t, pool.checkDuplicateSidechainTx(txn1))
+	assert.False(t, pool.IsDuplicateSidechainTx(hash1))
+	assert.False(t, pool.IsDuplicateSidechainTx(hash2))
+
+	// any of the sidechain txs in the pool is duplicate
+	pool.addSidechainTx(txn1)
+	assert.Error(t, pool.checkDuplicateSidechainTx(txn1))
+	assert.Error(t, pool.checkDuplicateSidechainTx(newWithdrawTx(hash3,
+		hash2)))
+	assert.NoError(t, pool.checkDuplicateSidechainTx(newWithdrawTx(hash3)))
+
+	// the payload should be a withdraw payload
+	assert.Error(t, pool.checkDuplicateSidechainTx(&types.Transaction{
+		TxType:  types.WithdrawFromSideChain,
+		Payload: &payload.TransferAsset{},
+	}))
+}
+
 func TestTxPool_CleanSidechainTx(t *testing.T) {
 	hashStr1 := "300db7783393a6f60533c1223108445df57de4fb4842f84f55d07df57caa0c7d"
 	hashBytes1, _ := common.HexStringToBytes(hashStr1)
@@ -284,6 +322,119 @@ func TestTxPool_AppendToTxnPool(t *testing.T) {
 
 }
 
+func TestTxPool_TestAcceptTransaction(t *testing.T) {
+	pool := NewTxPool(&config.DefaultParams)
+
+	// coinbase transaction should be rejected by the coinbase check
+	tx := new(types.Transaction)
+	txBytes, _ := hex.DecodeString("000403454c41010008803e6306563b26de010" +
+		"000000000000000000000000000000000000000000000000000000000000000ffff" +
+		"ffffffff02b037db964a231458d2d6ffd5ea18944c4f90e63d547c5d3b9874df66a" +
+		"4ead0a39becdc01000000000000000012c8a2e0677227144df822b7d9246c58df68" +
+		"eb11ceb037db964a231458d2d6ffd5ea18944c4f90e63d547c5d3b9874df66a4ead" +
+		"0a3c1d258040000000000000000129e9cf1c5f336fcf3a6c954444ed482c5d916e5" +
+		"06dd00000000")
+	tx.Deserialize(bytes.NewReader(txBytes))
+	results := pool.TestAcceptTransaction(tx)
+	assert.Equal(t, 2, len(results))
+	assert.Equal(t, CheckDuplicate, results[0].Check)
+	assert.Equal(t, errors.Success, results[0].Code)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, CheckCoinbase, results[1].Check)
+	assert.Equal(t, errors.ErrIneffectiveCoinbase, results[1].Code)
+	assert.Error(t, results[1].Err)
+
+	// checking with the pool should not add the producer to the pool
+	ownerPublicKey := make([]byte, 33)
+	nodePublicKey := make([]byte, 33)
+	rand.Read(ownerPublicKey)
+	rand.Read(nodePublicKey)
+	tx = &types.Transaction{
+		TxType: types.RegisterProducer,
+		Payload: &payload.ProducerInfo{
+			OwnerPublicKey: ownerPublicKey,
+			NodePublicKey:  nodePublicKey,
+		},
+	}
+	for i := 0; i < 2; i++ {
+		errCode, err := pool.checkTransactionWithTxnPool(tx, nil)
+		assert.Equal(t, errors.Success, errCode)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, errors.Success, pool.verifyTransactionWithTxnPool(tx))
+	errCode, err := pool.checkTransactionWithTxnPool(tx, nil)
+	assert.Equal(t, errors.ErrProducerProcessing, errCode)
+	assert.Error(t, err)
+}
+
+func TestTxPool_TestAcceptSideChainPowTx(t *testing.T) {
+	pool := NewTxPool(&config.DefaultParams)
+	genesisTx := config.DefaultParams.GenesisBlock.Transactions[0]
+	input := &types.Input{
+		Previous: types.OutPoint{TxID: genesisTx.Hash(), Index: 0},
+	}
+
+	var sideGenesisHash, otherGenesisHash common.Uint256
+	rand.Read(sideGenesisHash[:])
+	rand.Read(otherGenesisHash[:])
+	newSideChainPowTx := func(genesisHash common.Uint256) *types.Transaction {
+		var sideBlockHash common.Uint256
+		rand.Read(sideBlockHash[:])
+		return &types.Transaction{
+			TxType: types.SideChainPow,
+			Payload: &payload.SideChainPow{
+				SideBlockHash:   sideBlockHash,
+				SideGenesisHash: genesisHash,
+				BlockHeight:     100,
+			},
+			Inputs: []*types.Input{input},
+		}
+	}
+
+	txn1 := newSideChainPowTx(sideGenesisHash)
+	pool.txnList[txn1.Hash()] = txn1
+	pool.addInputUTXOList(txn1, input)
+	pool.txnListSize += txn1.GetSize()
+
+	// the sidechainpow tx of another side chain spending the same input is
+	// a double spend
+	txn2 := newSideChainPowTx(otherGenesisHash)
+	replaced := pool.duplicateSideChainPowTxs(txn2)
+	assert.Empty(t, replaced)
+	errCode, err := pool.checkTransactionWithTxnPool(txn2, replaced)
+	assert.Equal(t, errors.ErrDoubleSpend, errCode)
+	assert.Error(t, err)
+
+	// the sidechainpow tx of the same side chain replaces txn1, so the input
+	// spent by txn1 is not taken as double spent
+	txn3 := newSideChainPowTx(sideGenesisHash)
+	replaced = pool.duplicateSideChainPowTxs(txn3)
+	if assert.Len(t, replaced, 1) {
+		assert.Equal(t, txn1.Hash(), replaced[0].Hash())
+	}
+	errCode, err = pool.checkTransactionWithTxnPool(txn3, nil)
+	assert.Equal(t, errors.ErrDoubleSpend, errCode)
+	assert.Error(t, err)
+	errCode, err = pool.checkTransactionWithTxnPool(txn3, replaced)
+	assert.Equal(t, errors.Success, errCode)
+	assert.NoError(t, err)
+
+	// the size of the replaced tx is released from the pool
+	pool.txnListSize = pact.MaxTxPoolSize - txn3.GetSize() + 1
+	assert.Error(t, pool.checkTxPoolSize(txn3, nil))
+	assert.NoError(t, pool.checkTxPoolSize(txn3, replaced))
+
+	// the pool is not changed by the checks
+	assert.NotNil(t, pool.GetTransaction(txn1.Hash()))
+	assert.Equal(t, txn1, pool.getInputUTXOList(input))
+
+	// the real path replaces txn1 with the same result
+	pool.txnListSize = 0
+	assert.Equal(t, errors.Success, pool.verifyTransactionWithTxnPool(txn3))
+	assert.Nil(t, pool.GetTransaction(txn1.Hash()))
+	assert.Equal(t, txn3, pool.getInputUTXOList(input))
+}
+
 func TestTxPool_CleanSubmittedTransactions(t *testing.T) {
 	txPool = NewTxPool(&config.DefaultParams)
 	var input *types.Input
@@ -5,6 +5,7 @@ import (
 	. "github.com/elastos/Elastos.ELA/core/types"
 	"github.com/elastos/Elastos.ELA/core/types/outputpayload"
 	"github.com/elastos/Elastos.ELA/core/types/payload"
+	. "github.com/elastos/Elastos.ELA/errors"
 )
 
 const TlsPort = 443
@@ -44,6 +45,13 @@ type VoteOutputInfo struct {
 	Contents []VoteContentInfo `json:"contents"`
 }
 
+type MappingOutputInfo struct {
+	Version        byte   `json:"version"`
+	OwnerPublicKey string `json:"ownerpublickey"`
+	SideProducerID string `json:"sideproducerid"`
+	Signature      string `json:"signature"`
+}
+
 type ProgramInfo struct {
 	Code      string `json:"code"`
 	Parameter string `json:"parameter"`
@@ -67,6 +75,7 @@ type TransactionInfo struct {
 	Payload        PayloadInfo        `json:"payload"`
 	Attributes     []AttributeInfo    `json:"attributes"`
 	Programs       []ProgramInfo      `json:"programs"`
+	Finality       *FinalityInfo      `json:"finality,omitempty"`
 }
 
 type BlockInfo struct {
@@ -104,6 +113,26 @@ type ConfirmInfo struct {
 	Votes      []VoteInfo `json:"votes"`
 }
 
+// FinalityInfo is the DPoS finality of the block containing a transaction.
+type FinalityInfo struct {
+	Confirmed  bool     `json:"confirmed"`  // The block is confirmed by the arbiters
+	Finalized  bool     `json:"finalized"`  // The block or a later block is confirmed
+	Sponsor    string   `json:"sponsor"`    // The sponsor of the block confirm
+	ViewOffset uint32   `json:"viewoffset"` // The view offset of the block confirm
+	Signers    []string `json:"signers"`    // The arbiters accepted the block
+}
+
+// TransactionStatusInfo is the status of a transaction in the mempool or the
+// chain.
+type TransactionStatusInfo struct {
+	TxID          string `json:"txid"`
+	Status        string `json:"status"`
+	Height        uint32 `json:"height"`
+	BlockHash     string `json:"blockhash"`
+	Confirmations uint32 `json:"confirmations"`
+	FinalityInfo
+}
+
 type ServerInfo struct {
 	Compile   string      `json:"compile"`   // The compile version of this server node
 	Height    uint32      `json:"height"`    // The ServerNode latest block height
@@ -137,6 +166,63 @@ type ArbitratorGroupInfo struct {
 	Arbitrators           []string `json:"arbitrators"`
 }
 
+type ConsensusVoteInfo struct {
+	Signer       string `json:"signer"`
+	Accept       bool   `json:"accept"`
+	ReceivedTime int64  `json:"receivedtime"`
+}
+
+type ConsensusProposalInfo struct {
+	Sponsor      string              `json:"sponsor"`
+	BlockHash    string              `json:"blockhash"`
+	ProposalHash string              `json:"proposalhash"`
+	ViewOffset   uint32              `json:"viewoffset"`
+	ReceivedTime int64               `json:"receivedtime"`
+	EndTime      int64               `json:"endtime"`
+	Result       bool                `json:"result"`
+	AcceptVotes  int                 `json:"acceptvotes"`
+	RejectVotes  int                 `json:"rejectvotes"`
+	Votes        []ConsensusVoteInfo `json:"votes"`
+}
+
+type ConsensusViewInfo struct {
+	OnDutyArbiter string `json:"ondutyarbiter"`
+	Offset        uint32 `json:"offset"`
+	StartTime     int64  `json:"starttime"`
+}
+
+type ConsensusRoundInfo struct {
+	Height      uint32                  `json:"height"`
+	StartTime   int64                   `json:"starttime"`
+	EndTime     int64                   `json:"endtime"`
+	Duration    float64                 `json:"duration"`
+	ViewChanges int                     `json:"viewchanges"`
+	Proposals   []ConsensusProposalInfo `json:"proposals"`
+	Views       []ConsensusViewInfo     `json:"views"`
+}
+
+type ArbiterCountersInfo struct {
+	PublicKey         string `json:"publickey"`
+	Proposals         uint32 `json:"proposals"`
+	VotesOnTime       uint32 `json:"votesontime"`
+	VotesLate         uint32 `json:"voteslate"`
+	RoundsMissed      uint32 `json:"roundsmissed"`
+	ViewChanges       uint32 `json:"viewchanges"`
+	InactivityStrikes uint32 `json:"inactivitystrikes"`
+}
+
+type ArbiterMetricsWindowInfo struct {
+	StartHeight uint32                `json:"startheight"`
+	EndHeight   uint32                `json:"endheight"`
+	Arbiters    []ArbiterCountersInfo `json:"arbiters"`
+}
+
+type ArbiterMetricsInfo struct {
+	MaxInactiveRounds uint32                     `json:"maxinactiverounds"`
+	InactiveRounds    map[string]uint32          `json:"inactiverounds"`
+	Rounds            []ArbiterMetricsWindowInfo `json:"rounds"`
+}
+
 type PayloadInfo interface{}
 
 type CoinbaseInfo struct {
@@ -188,6 +274,96 @@ type ActivateProducerInfo struct {
 	Signature     string `json:"signature"`
 }
 
+type RecordInfo struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+type UpdateVersionInfo struct {
+	StartHeight uint32 `json:"startheight"`
+	EndHeight   uint32 `json:"endheight"`
+}
+
+type InactiveArbitratorsInfo struct {
+	Sponsor     string   `json:"sponsor"`
+	Arbitrators []string `json:"arbitrators"`
+	BlockHeight uint32   `json:"blockheight"`
+}
+
+type BlockEvidenceInfo struct {
+	Header       string   `json:"header"`
+	BlockConfirm string   `json:"blockconfirm"`
+	Signers      []string `json:"signers"`
+}
+
+type DPOSIllegalBlocksInfo struct {
+	CoinType        uint32            `json:"cointype"`
+	BlockHeight     uint32            `json:"blockheight"`
+	Evidence        BlockEvidenceInfo `json:"evidence"`
+	CompareEvidence BlockEvidenceInfo `json:"compareevidence"`
+}
+
+type DPOSProposalInfo struct {
+	Sponsor    string `json:"sponsor"`
+	BlockHash  string `json:"blockhash"`
+	ViewOffset uint32 `json:"viewoffset"`
+	Sign       string `json:"sign"`
+}
+
+type ProposalEvidenceInfo struct {
+	Proposal    DPOSProposalInfo `json:"proposal"`
+	BlockHeader string           `json:"blockheader"`
+	BlockHeight uint32           `json:"blockheight"`
+}
+
+type DPOSIllegalProposalsInfo struct {
+	Evidence        ProposalEvidenceInfo `json:"evidence"`
+	CompareEvidence ProposalEvidenceInfo `json:"compareevidence"`
+}
+
+type DPOSProposalVoteInfo struct {
+	ProposalHash string `json:"proposalhash"`
+	Signer       string `json:"signer"`
+	Accept       bool   `json:"accept"`
+	Sign         string `json:"sign"`
+}
+
+type VoteEvidenceInfo struct {
+	ProposalEvidenceInfo
+	Vote DPOSProposalVoteInfo `json:"vote"`
+}
+
+type DPOSIllegalVotesInfo struct {
+	Evidence        VoteEvidenceInfo `json:"evidence"`
+	CompareEvidence VoteEvidenceInfo `json:"compareevidence"`
+}
+
+type ScriptInfo struct {
+	Type           string   `json:"type"`
+	ReqSigs        uint32   `json:"reqsigs"`
+	PublicKeys     []string `json:"publickeys"`
+	Address        string   `json:"address"`
+	DepositAddress string   `json:"depositaddress,omitempty"`
+}
+
+type MempoolCheckInfo struct {
+	Check      string  `json:"check"`
+	Passed     bool    `json:"passed"`
+	RejectCode ErrCode `json:"rejectcode,omitempty"`
+	Reason     string  `json:"reason,omitempty"`
+}
+
+type MempoolAcceptInfo struct {
+	TxID         string             `json:"txid"`
+	Allowed      bool               `json:"allowed"`
+	Size         uint32             `json:"size"`
+	Fee          string             `json:"fee"`
+	FeeRate      string             `json:"feerate"`
+	Checks       []MempoolCheckInfo `json:"checks"`
+	RejectCode   ErrCode            `json:"rejectcode,omitempty"`
+	RejectReason string             `json:"rejectreason,omitempty"`
+}
+
 type UTXOInfo struct {
 	TxType        byte   `json:"txtype"`
 	TxID          string `json:"txid"`
@@ -208,3 +384,15 @@ type SidechainIllegalDataInfo struct {
 	GenesisBlockAddress string   `json:"genesisblockaddress"`
 	Signs               []string `json:"signs"`
 }
+
+type LimiterStatsInfo struct {
+	Clients   int    `json:"clients"`
+	Allowed   uint64 `json:"allowed"`
+	Throttled uint64 `json:"throttled"`
+	Rejected  uint64 `json:"rejected"`
+}
+
+type ThrottleStatsInfo struct {
+	IP   LimiterStatsInfo `json:"ip"`
+	User LimiterStatsInfo `json:"user"`
+}
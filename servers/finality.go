@@ -0,0 +1,170 @@
+package servers
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/elastos/Elastos.ELA/common"
+	. "github.com/elastos/Elastos.ELA/core/types"
+	. "github.com/elastos/Elastos.ELA/errors"
+)
+
+const (
+	// TxStatusPending is the status of a transaction in the mempool.
+	TxStatusPending = "pending"
+
+	// TxStatusMined is the status of a transaction packed in a block which
+	// may still be reverted.
+	TxStatusMined = "mined"
+
+	// TxStatusFinalized is the status of a transaction packed in a block
+	// which can not be reverted since it's confirmed by DPoS.
+	TxStatusFinalized = "finalized"
+
+	// TxStatusDropped is the status of a pending transaction removed from
+	// the mempool without being packed.
+	TxStatusDropped = "dropped"
+
+	// TxStatusReorged is the status of a mined transaction of which the
+	// block is disconnected from the main chain.
+	TxStatusReorged = "reorged"
+)
+
+// lastConfirmed caches the last confirmed block found on the main chain, so
+// that only the blocks after it are scanned for the finality.
+var lastConfirmed struct {
+	sync.Mutex
+	height uint32
+	hash   common.Uint256
+}
+
+// GetFinalityInfo returns the DPoS finality of the block of the header. A
+// block is finalized if it or a later block on the main chain is confirmed by
+// the arbiters, because the chain will not be reorganized before a confirmed
+// block.
+func GetFinalityInfo(header *Header) *FinalityInfo {
+	info := &FinalityInfo{Signers: make([]string, 0)}
+	confirm, err := Store.GetConfirm(header.Hash())
+	if err != nil {
+		info.Finalized = confirmedAfter(header.Height)
+		return info
+	}
+
+	info.Confirmed = true
+	info.Finalized = true
+	info.Sponsor = common.BytesToHexString(confirm.Proposal.Sponsor)
+	info.ViewOffset = confirm.Proposal.ViewOffset
+	for _, vote := range confirm.Votes {
+		if vote.Accept {
+			info.Signers = append(info.Signers,
+				common.BytesToHexString(vote.Signer))
+		}
+	}
+	return info
+}
+
+// confirmedAfter returns if any block on the main chain after the given
+// height is confirmed, the blocks before DPoS have no confirm. The blocks after
+// the given height are only scanned if the cached block is not after it, and
+// the scan from the tip stops at the first confirmed block.
+func confirmedAfter(height uint32) bool {
+	lastConfirmed.Lock()
+	defer lastConfirmed.Unlock()
+
+	start := height + 1
+	if start < ChainParams.CRCOnlyDPOSHeight {
+		start = ChainParams.CRCOnlyDPOSHeight
+	}
+	if !lastConfirmed.hash.IsEqual(common.EmptyHash) {
+		if isConfirmed(lastConfirmed.height, lastConfirmed.hash) {
+			if lastConfirmed.height >= start {
+				return true
+			}
+		} else {
+			// the cached block is disconnected from the main chain
+			lastConfirmed.height = 0
+			lastConfirmed.hash = common.EmptyHash
+		}
+	}
+
+	for h := Store.GetHeight(); h >= start; h-- {
+		hash, err := Store.GetBlockHash(h)
+		if err != nil {
+			return false
+		}
+		if _, err := Store.GetConfirm(hash); err == nil {
+			lastConfirmed.height = h
+			lastConfirmed.hash = hash
+			return true
+		}
+	}
+	return false
+}
+
+// isConfirmed returns if the block of the hash is at the height of the main
+// chain and confirmed.
+func isConfirmed(height uint32, hash common.Uint256) bool {
+	mainHash, err := Store.GetBlockHash(height)
+	if err != nil || !mainHash.IsEqual(hash) {
+		return false
+	}
+	_, err = Store.GetConfirm(hash)
+	return err == nil
+}
+
+// GetTransactionStatusInfo returns the status of the transaction in the chain
+// or the mempool.
+func GetTransactionStatusInfo(hash common.Uint256) (*TransactionStatusInfo,
+	ErrCode) {
+	info := &TransactionStatusInfo{
+		TxID:         ToReversedString(hash),
+		FinalityInfo: FinalityInfo{Signers: make([]string, 0)},
+	}
+	_, height, err := Store.GetTransaction(hash)
+	if err != nil {
+		if !TxMemPool.HaveTransaction(hash) {
+			return nil, UnknownTransaction
+		}
+		info.Status = TxStatusPending
+		return info, Success
+	}
+
+	blockHash, err := Store.GetBlockHash(height)
+	if err != nil {
+		return nil, UnknownBlock
+	}
+	header, err := Chain.GetHeader(blockHash)
+	if err != nil {
+		return nil, UnknownBlock
+	}
+	info.Height = height
+	info.BlockHash = ToReversedString(blockHash)
+	info.Confirmations = Store.GetHeight() - height + 1
+	info.FinalityInfo = *GetFinalityInfo(header)
+	info.Status = TxStatusMined
+	if info.Finalized {
+		info.Status = TxStatusFinalized
+	}
+	return info, Success
+}
+
+func GetTransactionStatus(param Params) map[string]interface{} {
+	str, ok := param.String("txid")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a string parameter named txid")
+	}
+	hashBytes, err := FromReversedString(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "invalid txid")
+	}
+	var hash common.Uint256
+	if err := hash.Deserialize(bytes.NewReader(hashBytes)); err != nil {
+		return ResponsePack(InvalidParams, "invalid txid")
+	}
+
+	info, code := GetTransactionStatusInfo(hash)
+	if code != Success {
+		return ResponsePack(code, "")
+	}
+	return ResponsePack(Success, info)
+}
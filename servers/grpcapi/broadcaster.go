@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+	"sync"
+
+	"github.com/elastos/Elastos.ELA/events"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// streamBufferSize is the maximum messages waiting to be sent to a
+	// stream, the stream will be closed if the client can not catch up.
+	streamBufferSize = 128
+)
+
+// stream is the subscriber of a broadcaster.
+type stream struct {
+	queue    chan interface{}
+	overflow chan struct{}
+}
+
+// broadcaster sends the data of an event type to all subscribed streams.
+type broadcaster struct {
+	mtx     sync.Mutex
+	streams map[*stream]struct{}
+}
+
+// publish sends the data to the subscribed streams, a stream is removed if the
+// buffer of it is full.
+func (b *broadcaster) publish(data interface{}) {
+	b.mtx.Lock()
+	for s := range b.streams {
+		select {
+		case s.queue <- data:
+		default:
+			delete(b.streams, s)
+			close(s.overflow)
+		}
+	}
+	b.mtx.Unlock()
+}
+
+func (b *broadcaster) subscribe() *stream {
+	s := &stream{
+		queue:    make(chan interface{}, streamBufferSize),
+		overflow: make(chan struct{}),
+	}
+	b.mtx.Lock()
+	b.streams[s] = struct{}{}
+	b.mtx.Unlock()
+	return s
+}
+
+func (b *broadcaster) unsubscribe(s *stream) {
+	b.mtx.Lock()
+	delete(b.streams, s)
+	b.mtx.Unlock()
+}
+
+// serve sends the published data by the send function until the stream is
+// canceled by the client or an error occurred.
+func (b *broadcaster) serve(ctx context.Context,
+	send func(interface{}) error) error {
+	s := b.subscribe()
+	defer b.unsubscribe(s)
+
+	for {
+		select {
+		case data := <-s.queue:
+			if err := send(data); err != nil {
+				return err
+			}
+
+		case <-s.overflow:
+			return status.Error(codes.ResourceExhausted,
+				"stream can not catch up with the notifications")
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// newBroadcaster creates a broadcaster publishing the data of the given event
+// type.
+func newBroadcaster(typ events.EventType) *broadcaster {
+	b := &broadcaster{streams: make(map[*stream]struct{})}
+	events.Subscribe(func(e *events.Event) {
+		if e.Type == typ {
+			b.publish(e.Data)
+		}
+	})
+	return b
+}
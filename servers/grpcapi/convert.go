@@ -0,0 +1,123 @@
+package grpcapi
+
+import (
+	"bytes"
+
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/servers"
+	"github.com/elastos/Elastos.ELA/servers/grpcapi/pb"
+)
+
+// newTransaction converts the transaction into the protobuf message, header
+// is nil if the transaction is in the mempool.
+func newTransaction(header *types.Header, tx *types.Transaction) *pb.Transaction {
+	info := servers.GetTransactionInfo(header, tx)
+	buf := new(bytes.Buffer)
+	tx.Serialize(buf)
+
+	result := &pb.Transaction{
+		Txid:           info.TxID,
+		Size:           info.Size,
+		Version:        uint32(info.Version),
+		Type:           uint32(info.TxType),
+		PayloadVersion: uint32(info.PayloadVersion),
+		LockTime:       info.LockTime,
+		Inputs:         make([]*pb.Input, 0, len(info.Inputs)),
+		Outputs:        make([]*pb.Output, 0, len(info.Outputs)),
+		BlockHash:      info.BlockHash,
+		Confirmations:  info.Confirmations,
+		BlockTime:      info.BlockTime,
+		Raw:            buf.Bytes(),
+	}
+	for _, i := range info.Inputs {
+		result.Inputs = append(result.Inputs, &pb.Input{
+			Txid:     i.TxID,
+			Vout:     uint32(i.VOut),
+			Sequence: i.Sequence,
+		})
+	}
+	for _, o := range info.Outputs {
+		result.Outputs = append(result.Outputs, &pb.Output{
+			Value:      o.Value,
+			N:          o.Index,
+			Address:    o.Address,
+			AssetId:    o.AssetID,
+			OutputLock: o.OutputLock,
+			Type:       o.OutputType,
+		})
+	}
+	return result
+}
+
+// newBlock converts the block into the protobuf message, the transactions are
+// converted if verbose is true, otherwise only the transaction ids.
+func newBlock(block *types.Block, verbose bool) *pb.Block {
+	info := servers.GetBlockInfo(block, false)
+	result := &pb.Block{
+		Hash:              info.Hash,
+		Height:            info.Height,
+		Version:           info.Version,
+		PreviousBlockHash: info.PreviousBlockHash,
+		NextBlockHash:     info.NextBlockHash,
+		MerkleRoot:        info.MerkleRoot,
+		Time:              info.Time,
+		Nonce:             info.Nonce,
+		Bits:              info.Bits,
+		Difficulty:        info.Difficulty,
+		Size:              info.Size,
+		Confirmations:     info.Confirmations,
+		MinerInfo:         info.MinerInfo,
+		Txids:             make([]string, 0, len(block.Transactions)),
+	}
+	for _, tx := range block.Transactions {
+		result.Txids = append(result.Txids, servers.ToReversedString(tx.Hash()))
+		if verbose {
+			result.Transactions = append(result.Transactions,
+				newTransaction(&block.Header, tx))
+		}
+	}
+	return result
+}
+
+func newUTXOs(utxos []servers.UTXOInfo) []*pb.UTXO {
+	result := make([]*pb.UTXO, 0, len(utxos))
+	for _, u := range utxos {
+		result = append(result, &pb.UTXO{
+			Txid:          u.TxID,
+			Vout:          u.VOut,
+			TxType:        uint32(u.TxType),
+			AssetId:       u.AssetID,
+			Address:       u.Address,
+			Amount:        u.Amount,
+			OutputLock:    u.OutputLock,
+			Confirmations: u.Confirmations,
+		})
+	}
+	return result
+}
+
+func newProducers(producers *servers.Producers) *pb.ListProducersResponse {
+	result := &pb.ListProducersResponse{
+		Producers:   make([]*pb.Producer, 0, len(producers.Producers)),
+		TotalVotes:  producers.TotalVotes,
+		TotalCounts: producers.TotalCounts,
+	}
+	for _, p := range producers.Producers {
+		result.Producers = append(result.Producers, &pb.Producer{
+			OwnerPublicKey: p.OwnerPublicKey,
+			NodePublicKey:  p.NodePublicKey,
+			Nickname:       p.Nickname,
+			Url:            p.Url,
+			Location:       p.Location,
+			Active:         p.Active,
+			Votes:          p.Votes,
+			State:          p.State,
+			RegisterHeight: p.RegisterHeight,
+			CancelHeight:   p.CancelHeight,
+			InactiveHeight: p.InactiveHeight,
+			IllegalHeight:  p.IllegalHeight,
+			Index:          p.Index,
+		})
+	}
+	return result
+}
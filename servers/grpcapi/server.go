@@ -0,0 +1,374 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/config"
+	"github.com/elastos/Elastos.ELA/common/log"
+	"github.com/elastos/Elastos.ELA/core/types"
+	elaErr "github.com/elastos/Elastos.ELA/errors"
+	"github.com/elastos/Elastos.ELA/events"
+	"github.com/elastos/Elastos.ELA/servers"
+	"github.com/elastos/Elastos.ELA/servers/grpcapi/pb"
+	"github.com/elastos/Elastos.ELA/servers/httpjsonrpc"
+	"github.com/elastos/Elastos.ELA/utils/http/jsonrpc"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultAddress is the address the gRPC server listens on if the
+	// GRPCAddress is not configured.
+	defaultAddress = "127.0.0.1"
+)
+
+// Ensure server implement the pb.NodeServer interface.
+var _ pb.NodeServer = (*server)(nil)
+
+// methodRoles are the roles of the methods other than the RoleRead, the same
+// as the roles of the JSON-RPC methods.
+var methodRoles = map[string]string{
+	"sendrawtransaction": jsonrpc.RoleWallet,
+}
+
+// server implements the gRPC service of node.
+type server struct {
+	auth   *jsonrpc.Authenticator
+	blocks *broadcaster
+	txs    *broadcaster
+}
+
+// StartServer starts the gRPC server on the configured address and port.
+func StartServer() {
+	address := config.Parameters.GRPCAddress
+	if len(address) == 0 {
+		address = defaultAddress
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(address,
+		strconv.Itoa(config.Parameters.GRPCPort)))
+	if err != nil {
+		log.Fatal("gRPC listen: ", err.Error())
+	}
+
+	s := newServer(&server{
+		auth:   httpjsonrpc.NewAuthenticator(),
+		blocks: newBroadcaster(events.ETBlockConnected),
+		txs:    newBroadcaster(events.ETTransactionAccepted),
+	})
+	if err := s.Serve(listener); err != nil {
+		log.Fatal("gRPC serve: ", err.Error())
+	}
+}
+
+// newServer creates the gRPC server of the node service, the requests are
+// authorized by the same users and white list as the JSON-RPC service.
+func newServer(s *server) *grpc.Server {
+	gs := grpc.NewServer(
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.streamInterceptor),
+	)
+	pb.RegisterNodeServer(gs, s)
+	return gs
+}
+
+func (s *server) GetBestBlock(ctx context.Context,
+	req *pb.GetBestBlockRequest) (*pb.GetBestBlockResponse, error) {
+	height := servers.Store.GetHeight()
+	hash, err := servers.Store.GetBlockHash(height)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.GetBestBlockResponse{
+		Height: height,
+		Hash:   servers.ToReversedString(hash),
+	}, nil
+}
+
+func (s *server) GetBlock(ctx context.Context,
+	req *pb.GetBlockRequest) (*pb.Block, error) {
+	var hash common.Uint256
+	switch id := req.Id.(type) {
+	case *pb.GetBlockRequest_Hash:
+		h, err := parseHash(id.Hash)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument,
+				"invalid block hash")
+		}
+		hash = *h
+
+	case *pb.GetBlockRequest_Height:
+		h, err := servers.Store.GetBlockHash(id.Height)
+		if err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		hash = h
+
+	default:
+		return nil, status.Error(codes.InvalidArgument,
+			"block hash or height not found")
+	}
+
+	block, err := servers.Store.GetBlock(hash)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return newBlock(block, req.Verbose), nil
+}
+
+func (s *server) GetTransaction(ctx context.Context,
+	req *pb.GetTransactionRequest) (*pb.Transaction, error) {
+	hash, err := parseHash(req.Txid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid txid")
+	}
+
+	tx, height, err := servers.Store.GetTransaction(*hash)
+	if err != nil {
+		// try to find transaction in transaction pool.
+		tx = servers.TxMemPool.GetTransaction(*hash)
+		if tx == nil {
+			return nil, status.Error(codes.NotFound, "cannot find "+
+				"transaction in blockchain and transactionpool")
+		}
+		return newTransaction(nil, tx), nil
+	}
+
+	blockHash, err := servers.Store.GetBlockHash(height)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	header, err := servers.Chain.GetHeader(blockHash)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return newTransaction(header, tx), nil
+}
+
+func (s *server) GetMempool(ctx context.Context,
+	req *pb.GetMempoolRequest) (*pb.GetMempoolResponse, error) {
+	txs := servers.TxMemPool.GetTxsInPool()
+	result := &pb.GetMempoolResponse{
+		Transactions: make([]*pb.Transaction, 0, len(txs)),
+	}
+	for _, tx := range txs {
+		result.Transactions = append(result.Transactions,
+			newTransaction(nil, tx))
+	}
+	return result, nil
+}
+
+func (s *server) ListUnspent(ctx context.Context,
+	req *pb.ListUnspentRequest) (*pb.ListUnspentResponse, error) {
+	addresses := make([]interface{}, 0, len(req.Addresses))
+	for _, addr := range req.Addresses {
+		addresses = append(addresses, addr)
+	}
+	params := servers.Params{"addresses": addresses}
+	if len(req.UtxoType) > 0 {
+		params["utxotype"] = req.UtxoType
+	}
+
+	result, err := call(servers.ListUnspent, params)
+	if err != nil {
+		return nil, err
+	}
+	utxos, _ := result.([]servers.UTXOInfo)
+	return &pb.ListUnspentResponse{Utxos: newUTXOs(utxos)}, nil
+}
+
+func (s *server) GetBalance(ctx context.Context,
+	req *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	result, err := call(servers.GetBalanceByAddr,
+		servers.Params{"addr": req.Address})
+	if err != nil {
+		return nil, err
+	}
+	balance, _ := result.(string)
+	return &pb.GetBalanceResponse{Balance: balance}, nil
+}
+
+func (s *server) ListProducers(ctx context.Context,
+	req *pb.ListProducersRequest) (*pb.ListProducersResponse, error) {
+	params := servers.Params{"start": float64(req.Start)}
+	if req.Limit > 0 {
+		params["limit"] = float64(req.Limit)
+	}
+	if len(req.State) > 0 {
+		params["state"] = req.State
+	}
+
+	result, err := call(servers.ListProducers, params)
+	if err != nil {
+		return nil, err
+	}
+	return newProducers(result.(*servers.Producers)), nil
+}
+
+func (s *server) GetProducerStatus(ctx context.Context,
+	req *pb.GetProducerStatusRequest) (*pb.GetProducerStatusResponse, error) {
+	result, err := call(servers.ProducerStatus,
+		servers.Params{"publickey": req.PublicKey})
+	if err != nil {
+		return nil, err
+	}
+	state, _ := result.(string)
+	return &pb.GetProducerStatusResponse{State: state}, nil
+}
+
+func (s *server) SendRawTransaction(ctx context.Context,
+	req *pb.SendRawTransactionRequest) (*pb.SendRawTransactionResponse, error) {
+	result, err := call(servers.SendRawTransaction,
+		servers.Params{"data": common.BytesToHexString(req.Data)})
+	if err != nil {
+		return nil, err
+	}
+	txID, _ := result.(string)
+	return &pb.SendRawTransactionResponse{Txid: txID}, nil
+}
+
+func (s *server) SubscribeBlocks(req *pb.SubscribeBlocksRequest,
+	stream pb.Node_SubscribeBlocksServer) error {
+	return s.blocks.serve(stream.Context(), func(v interface{}) error {
+		return stream.Send(newBlock(v.(*types.Block), req.Verbose))
+	})
+}
+
+func (s *server) SubscribeTransactions(req *pb.SubscribeTransactionsRequest,
+	stream pb.Node_SubscribeTransactionsServer) error {
+	return s.txs.serve(stream.Context(), func(v interface{}) error {
+		return stream.Send(newTransaction(nil, v.(*types.Transaction)))
+	})
+}
+
+// call calls the RPC method, the error code of a failed call is converted
+// into the gRPC status.
+func call(method func(servers.Params) map[string]interface{},
+	params servers.Params) (interface{}, error) {
+	resp := method(params)
+	code, _ := resp["Error"].(elaErr.ErrCode)
+	if code == elaErr.Success {
+		return resp["Result"], nil
+	}
+	message, ok := resp["Result"].(string)
+	if !ok || len(message) == 0 {
+		message = elaErr.ErrMap[code]
+	}
+	return nil, status.Error(statusCode(code), message)
+}
+
+// statusCode returns the gRPC status code of the ELA error code.
+func statusCode(code elaErr.ErrCode) codes.Code {
+	switch code {
+	case elaErr.IllegalDataFormat, elaErr.InvalidParams,
+		elaErr.InvalidTransaction, elaErr.InvalidAsset:
+		return codes.InvalidArgument
+	case elaErr.UnknownTransaction, elaErr.UnknownAsset,
+		elaErr.UnknownBlock:
+		return codes.NotFound
+	case elaErr.RateLimited:
+		return codes.ResourceExhausted
+	case elaErr.Error, elaErr.InternalError:
+		return codes.Internal
+	}
+	// the transaction is rejected by the mempool.
+	return codes.FailedPrecondition
+}
+
+// methodName returns the lowercase method name of the full gRPC method, which
+// is the same as the name of the JSON-RPC method.
+func methodName(fullMethod string) string {
+	return strings.ToLower(fullMethod[strings.LastIndex(fullMethod, "/")+1:])
+}
+
+// peerAddr returns the address of the client.
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// authorize checks the client IP and the basic authorization in the
+// "authorization" metadata the same as the JSON-RPC requests.
+func (s *server) authorize(ctx context.Context, method string) error {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	role, ok := methodRoles[method]
+	if !ok {
+		role = jsonrpc.RoleRead
+	}
+
+	switch err := s.auth.Authorize(peerAddr(ctx), authorization, method,
+		role); err {
+	case nil:
+		return nil
+	case jsonrpc.ErrAuthFailed:
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+}
+
+// acquire authorizes the client and takes the cost of the method from the
+// limiter of the client IP, the cost of a method is weighted by the same name
+// of the JSON-RPC method.
+func (s *server) acquire(ctx context.Context, fullMethod string) (func(),
+	error) {
+	method := methodName(fullMethod)
+	if err := s.authorize(ctx, method); err != nil {
+		return nil, err
+	}
+	release, code := servers.AcquireIP(peerAddr(ctx), method)
+	if code != elaErr.Success {
+		return nil, status.Error(codes.ResourceExhausted,
+			elaErr.ErrMap[code])
+	}
+	return release, nil
+}
+
+func (s *server) unaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	release, err := s.acquire(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return handler(ctx, req)
+}
+
+// streamInterceptor limits the stream requests, the concurrency is released
+// after the stream opened so that a long running stream will not occupy it.
+func (s *server) streamInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	release, err := s.acquire(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	release()
+	return handler(srv, ss)
+}
+
+func parseHash(str string) (*common.Uint256, error) {
+	hashBytes, err := servers.FromReversedString(str)
+	if err != nil {
+		return nil, err
+	}
+	var hash common.Uint256
+	if err := hash.Deserialize(bytes.NewReader(hashBytes)); err != nil {
+		return nil, err
+	}
+	return &hash, nil
+}
@@ -0,0 +1,294 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/elastos/Elastos.ELA/blockchain"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/config"
+	"github.com/elastos/Elastos.ELA/common/log"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/dpos/state"
+	"github.com/elastos/Elastos.ELA/servers"
+	"github.com/elastos/Elastos.ELA/servers/grpcapi/pb"
+	"github.com/elastos/Elastos.ELA/utils/http/jsonrpc"
+	"github.com/elastos/Elastos.ELA/utils/test"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// mockStore is a chain store keeps the blocks of the main chain in memory.
+type mockStore struct {
+	blockchain.IChainStore
+	blocks []*types.Block
+}
+
+func (s *mockStore) GetHeight() uint32 {
+	return uint32(len(s.blocks) - 1)
+}
+
+func (s *mockStore) GetBlockHash(height uint32) (common.Uint256, error) {
+	if int(height) >= len(s.blocks) {
+		return common.EmptyHash, errors.New("block not found")
+	}
+	return s.blocks[height].Hash(), nil
+}
+
+func (s *mockStore) GetHeader(hash common.Uint256) (*types.Header, error) {
+	block, err := s.GetBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+	return &block.Header, nil
+}
+
+func (s *mockStore) GetBlock(hash common.Uint256) (*types.Block, error) {
+	for _, block := range s.blocks {
+		if block.Hash().IsEqual(hash) {
+			return block, nil
+		}
+	}
+	return nil, errors.New("block not found")
+}
+
+// remoteAddrConn is a connection from the given remote address.
+type remoteAddrConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c *remoteAddrConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// remoteAddrListener accepts the connections as they are from the remote
+// address, so that the white list is checked on the in-memory connections.
+type remoteAddrListener struct {
+	*bufconn.Listener
+	addr net.Addr
+}
+
+func (l *remoteAddrListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &remoteAddrConn{Conn: conn, addr: l.addr}, nil
+}
+
+// testServer is the gRPC server and the client connected in memory.
+type testServer struct {
+	*server
+	client pb.NodeClient
+	store  *mockStore
+}
+
+// newTestServer starts the gRPC server with the JSON-RPC configuration, the
+// requests are from the client IP.
+func newTestServer(t *testing.T, cfg *jsonrpc.Config,
+	clientIP string) *testServer {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+
+	params := config.DefaultParams
+	store := &mockStore{blocks: []*types.Block{params.GenesisBlock}}
+	chain, err := blockchain.New(store, &params, state.NewState(&params, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainParams, originChain, originStore := servers.ChainParams,
+		servers.Chain, servers.Store
+	servers.ChainParams = &params
+	servers.Chain = chain
+	servers.Store = store
+	t.Cleanup(func() {
+		servers.ChainParams, servers.Chain, servers.Store = chainParams,
+			originChain, originStore
+	})
+
+	s := &server{
+		auth:   jsonrpc.NewAuthenticator(cfg),
+		blocks: &broadcaster{streams: make(map[*stream]struct{})},
+		txs:    &broadcaster{streams: make(map[*stream]struct{})},
+	}
+	listener := bufconn.Listen(1 << 20)
+	gs := newServer(s)
+	go gs.Serve(&remoteAddrListener{
+		Listener: listener,
+		addr:     &net.TCPAddr{IP: net.ParseIP(clientIP), Port: 30000},
+	})
+	t.Cleanup(gs.Stop)
+
+	// the static window disables the dynamic window of the flow control, so
+	// that a client not receiving blocks the stream.
+	conn, err := grpc.Dial("bufconn", grpc.WithInsecure(),
+		grpc.WithInitialWindowSize(1<<16),
+		grpc.WithInitialConnWindowSize(1<<16),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testServer{server: s, client: pb.NewNodeClient(conn),
+		store: store}
+}
+
+// withAuth returns a context of the basic authorization metadata.
+func withAuth(user, pass string) context.Context {
+	auth := "Basic " + base64.StdEncoding.EncodeToString(
+		[]byte(user+":"+pass))
+	return metadata.AppendToOutgoingContext(context.Background(),
+		"authorization", auth)
+}
+
+func TestServer_Authorize(t *testing.T) {
+	cfg := &jsonrpc.Config{
+		User:      "admin",
+		Pass:      "admin123",
+		WhiteList: []string{"10.0.0.1"},
+		Users: []jsonrpc.User{
+			{Name: "reader", Pass: "reader123",
+				Roles: []string{jsonrpc.RoleRead}},
+		},
+	}
+	invalidTx := &pb.SendRawTransactionRequest{Data: []byte{1, 2, 3}}
+
+	tests := []struct {
+		name     string
+		clientIP string
+		ctx      context.Context
+		read     codes.Code
+		send     codes.Code
+	}{
+		{"no credential", "127.0.0.1", context.Background(),
+			codes.Unauthenticated, codes.Unauthenticated},
+		{"wrong password", "127.0.0.1", withAuth("admin", "wrong"),
+			codes.Unauthenticated, codes.Unauthenticated},
+		// the transaction is authorized and rejected by the format
+		{"admin", "127.0.0.1", withAuth("admin", "admin123"),
+			codes.OK, codes.InvalidArgument},
+		{"read role", "127.0.0.1", withAuth("reader", "reader123"),
+			codes.OK, codes.PermissionDenied},
+		{"white list", "10.0.0.1", withAuth("admin", "admin123"),
+			codes.OK, codes.InvalidArgument},
+		{"not in white list", "10.0.0.2", withAuth("admin", "admin123"),
+			codes.PermissionDenied, codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestServer(t, cfg, test.clientIP)
+			_, err := s.client.GetBestBlock(test.ctx, &pb.GetBestBlockRequest{})
+			assert.Equal(t, test.read, status.Code(err))
+			_, err = s.client.SendRawTransaction(test.ctx, invalidTx)
+			assert.Equal(t, test.send, status.Code(err))
+
+			// the streams are authorized the same as the unary methods
+			stream, err := s.client.SubscribeBlocks(test.ctx,
+				&pb.SubscribeBlocksRequest{})
+			if test.read != codes.OK {
+				if assert.NoError(t, err) {
+					_, err = stream.Recv()
+					assert.Equal(t, test.read, status.Code(err))
+				}
+			}
+		})
+	}
+}
+
+func TestServer_ErrorCodes(t *testing.T) {
+	s := newTestServer(t, &jsonrpc.Config{}, "127.0.0.1")
+	ctx := context.Background()
+	genesis := s.store.blocks[0]
+
+	block, err := s.client.GetBlock(ctx, &pb.GetBlockRequest{
+		Id: &pb.GetBlockRequest_Height{Height: 0}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, servers.ToReversedString(genesis.Hash()), block.Hash)
+	}
+	block, err = s.client.GetBlock(ctx, &pb.GetBlockRequest{
+		Id: &pb.GetBlockRequest_Hash{
+			Hash: servers.ToReversedString(genesis.Hash())}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint32(0), block.Height)
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.GetBlockRequest
+		code codes.Code
+	}{
+		{"no id", &pb.GetBlockRequest{}, codes.InvalidArgument},
+		{"invalid hash", &pb.GetBlockRequest{
+			Id: &pb.GetBlockRequest_Hash{Hash: "xyz"}}, codes.InvalidArgument},
+		{"unknown hash", &pb.GetBlockRequest{
+			Id: &pb.GetBlockRequest_Hash{Hash: servers.ToReversedString(
+				common.Uint256{1})}}, codes.NotFound},
+		{"unknown height", &pb.GetBlockRequest{
+			Id: &pb.GetBlockRequest_Height{Height: 1}}, codes.NotFound},
+	}
+	for _, test := range tests {
+		_, err := s.client.GetBlock(ctx, test.req)
+		assert.Equal(t, test.code, status.Code(err), test.name)
+	}
+
+	// the error codes of the JSON-RPC method are converted
+	_, err = s.client.SendRawTransaction(ctx,
+		&pb.SendRawTransactionRequest{Data: []byte{1, 2, 3}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.client.SendRawTransaction(ctx, &pb.SendRawTransactionRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_StreamOverflow(t *testing.T) {
+	s := newTestServer(t, &jsonrpc.Config{}, "127.0.0.1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := s.client.SubscribeBlocks(ctx, &pb.SubscribeBlocksRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscribed := func() int {
+		s.blocks.mtx.Lock()
+		defer s.blocks.mtx.Unlock()
+		return len(s.blocks.streams)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for subscribed() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !assert.Equal(t, 1, subscribed()) {
+		return
+	}
+
+	// the stream is closed once the client can not catch up, the blocks are
+	// published more than the buffer and the flow control window.
+	genesis := s.store.blocks[0]
+	for i := 0; i < 10*streamBufferSize && subscribed() > 0; i++ {
+		s.blocks.publish(genesis)
+	}
+	assert.Equal(t, 0, subscribed())
+
+	var received int
+	for {
+		block, err := stream.Recv()
+		if err != nil {
+			assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+			break
+		}
+		assert.Equal(t, servers.ToReversedString(genesis.Hash()), block.Hash)
+		received++
+	}
+	assert.True(t, received < 10*streamBufferSize)
+}
@@ -1,297 +1,123 @@
 package httpjsonrpc
 
 import (
-	"crypto/sha256"
-	"crypto/subtle"
-	"encoding/base64"
-	"encoding/json"
-	"io/ioutil"
-	"mime"
 	"net"
-	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/elastos/Elastos.ELA/common/config"
 	"github.com/elastos/Elastos.ELA/common/log"
 	elaErr "github.com/elastos/Elastos.ELA/errors"
 	. "github.com/elastos/Elastos.ELA/servers"
-)
-
-//an instance of the multiplexer
-var mainMux map[string]func(Params) map[string]interface{}
-
-const (
-	// JSON-RPC protocol error codes.
-	ParseError     = -32700
-	InvalidRequest = -32600
-	MethodNotFound = -32601
-	InvalidParams  = -32602
-	InternalError  = -32603
-	//-32000 to -32099	Server error, waiting for defining
-
-	// IOTimeout is the maximum duration for JSON-RPC reading or writing
-	// timeout.
-	IOTimeout = 60 * time.Second
+	htp "github.com/elastos/Elastos.ELA/utils/http"
+	"github.com/elastos/Elastos.ELA/utils/http/jsonrpc"
 )
 
 func StartRPCServer() {
-	mainMux = make(map[string]func(Params) map[string]interface{})
+	s := jsonrpc.NewServer(&jsonrpc.Config{
+		ServePort:   uint16(config.Parameters.HttpJsonPort),
+		User:        config.Parameters.RpcConfiguration.User,
+		Pass:        config.Parameters.RpcConfiguration.Pass,
+		WhiteList:   config.Parameters.RpcConfiguration.WhiteIPList,
+		Users:       rpcUsers(config.Parameters.RpcConfiguration.Users),
+		IPLimiter:   IPLimiter,
+		UserLimiter: UserLimiter,
+		NetListen: func(port uint16) (net.Listener, error) {
+			return net.Listen("tcp4", ":"+strconv.Itoa(int(port)))
+		},
+	})
 
-	mainMux["setloglevel"] = SetLogLevel
-	mainMux["getinfo"] = GetInfo
-	mainMux["getblock"] = GetBlockByHash
-	mainMux["getconfirmbyheight"] = GetConfirmByHeight
-	mainMux["getconfirmbyhash"] = GetConfirmByHash
-	mainMux["getcurrentheight"] = GetBlockHeight
-	mainMux["getblockhash"] = GetBlockHash
-	mainMux["getconnectioncount"] = GetConnectionCount
-	mainMux["getrawmempool"] = GetTransactionPool
-	mainMux["getrawtransaction"] = GetRawTransaction
-	mainMux["getneighbors"] = GetNeighbors
-	mainMux["getnodestate"] = GetNodeState
-	mainMux["sendrawtransaction"] = SendRawTransaction
-	mainMux["getarbitratorgroupbyheight"] = GetArbitratorGroupByHeight
-	mainMux["getbestblockhash"] = GetBestBlockHash
-	mainMux["getblockcount"] = GetBlockCount
-	mainMux["getblockbyheight"] = GetBlockByHeight
-	mainMux["getexistwithdrawtransactions"] = GetExistWithdrawTransactions
-	mainMux["listunspent"] = ListUnspent
-	mainMux["getutxosbyamount"] = GetUTXOsByAmount
-	mainMux["getamountbyinputs"] = GetAmountByInputs
-	mainMux["getreceivedbyaddress"] = GetReceivedByAddress
+	s.RegisterRoleAction(jsonrpc.RoleAdmin, "setloglevel", action(SetLogLevel), "level")
+	s.RegisterAction("getinfo", action(GetInfo))
+	s.RegisterAction("getblock", action(GetBlockByHash), "blockhash", "verbosity")
+	s.RegisterAction("getconfirmbyheight", action(GetConfirmByHeight), "height", "verbosity")
+	s.RegisterAction("getconfirmbyhash", action(GetConfirmByHash), "blockhash", "verbosity")
+	s.RegisterAction("getcurrentheight", action(GetBlockHeight))
+	s.RegisterAction("getblockhash", action(GetBlockHash), "height")
+	s.RegisterAction("getconnectioncount", action(GetConnectionCount))
+	s.RegisterAction("getrawmempool", action(GetTransactionPool))
+	s.RegisterAction("getrawtransaction", action(GetRawTransaction), "txid", "verbose")
+	s.RegisterAction("gettransactionstatus", action(GetTransactionStatus), "txid")
+	s.RegisterAction("getneighbors", action(GetNeighbors))
+	s.RegisterAction("getnodestate", action(GetNodeState))
+	s.RegisterRoleAction(jsonrpc.RoleWallet, "sendrawtransaction", action(SendRawTransaction), "data")
+	s.RegisterAction("testmempoolaccept", action(TestMempoolAccept), "data")
+	s.RegisterAction("decoderawtransaction", action(DecodeRawTransaction), "data")
+	s.RegisterAction("createrawtransaction", action(CreateRawTransaction), "inputs", "outputs", "locktime", "type", "payloadversion", "payload", "redeemscripts")
+	s.RegisterAction("decodescript", action(DecodeScript), "script")
+	s.RegisterAction("getarbitratorgroupbyheight", action(GetArbitratorGroupByHeight), "height")
+	s.RegisterAction("getbestblockhash", action(GetBestBlockHash))
+	s.RegisterAction("getblockcount", action(GetBlockCount))
+	s.RegisterAction("getblockbyheight", action(GetBlockByHeight), "height")
+	s.RegisterAction("getexistwithdrawtransactions", action(GetExistWithdrawTransactions), "txs")
+	s.RegisterAction("listunspent", action(ListUnspent), "addresses", "utxotype")
+	s.RegisterAction("getutxosbyamount", action(GetUTXOsByAmount), "address", "amount", "utxotype")
+	s.RegisterAction("getamountbyinputs", action(GetAmountByInputs), "inputs")
+	s.RegisterAction("getreceivedbyaddress", action(GetReceivedByAddress), "address")
 	// aux interfaces
-	mainMux["help"] = AuxHelp
-	mainMux["submitauxblock"] = SubmitAuxBlock
-	mainMux["createauxblock"] = CreateAuxBlock
+	s.RegisterAction("help", action(AuxHelp))
+	s.RegisterRoleAction(jsonrpc.RoleMining, "submitauxblock", action(SubmitAuxBlock), "blockhash", "auxpow")
+	s.RegisterRoleAction(jsonrpc.RoleMining, "createauxblock", action(CreateAuxBlock), "paytoaddress")
 	// mining interfaces
-	mainMux["togglemining"] = ToggleMining
-	mainMux["discretemining"] = DiscreteMining
+	s.RegisterRoleAction(jsonrpc.RoleMining, "togglemining", action(ToggleMining), "mining")
+	s.RegisterRoleAction(jsonrpc.RoleMining, "discretemining", action(DiscreteMining), "count")
 	// vote interfaces
-	mainMux["listproducers"] = ListProducers
-	mainMux["producerstatus"] = ProducerStatus
-	mainMux["votestatus"] = VoteStatus
+	s.RegisterAction("listproducers", action(ListProducers), "start", "limit", "state")
+	s.RegisterAction("producerstatus", action(ProducerStatus), "publickey")
+	s.RegisterAction("votestatus", action(VoteStatus), "address")
 	// for cross-chain arbiter
-	mainMux["submitsidechainillegaldata"] = SubmitSidechainIllegalData
-	mainMux["getarbiterpeersinfo"] = GetArbiterPeersInfo
+	s.RegisterRoleAction(jsonrpc.RoleAdmin, "submitsidechainillegaldata", action(SubmitSidechainIllegalData), "illegaldata")
+	s.RegisterAction("getarbiterpeersinfo", action(GetArbiterPeersInfo))
 
-	mainMux["estimatesmartfee"] = EstimateSmartFee
-	mainMux["getdepositcoin"] = GetDepositCoin
-	mainMux["getarbitersinfo"] = GetArbitersInfo
+	s.RegisterAction("estimatesmartfee", action(EstimateSmartFee), "confirmations")
+	s.RegisterAction("getdepositcoin", action(GetDepositCoin), "ownerpublickey")
+	s.RegisterAction("getarbitersinfo", action(GetArbitersInfo))
+	s.RegisterAction("getconsensusrounds", action(GetConsensusRounds), "startheight", "endheight")
+	s.RegisterAction("getarbitermetrics", action(GetArbiterMetrics), "publickey", "rounds")
+	s.RegisterAction("getthrottlestats", action(GetThrottleStats))
 
-	rpcServeMux := http.NewServeMux()
-	server := http.Server{
-		Handler:      rpcServeMux,
-		ReadTimeout:  IOTimeout,
-		WriteTimeout: IOTimeout,
-	}
-	rpcServeMux.HandleFunc("/", Handle)
-	l, _ := net.Listen("tcp4", ":"+strconv.Itoa(config.Parameters.HttpJsonPort))
-	err := server.Serve(l)
-	if err != nil {
+	if err := s.Start(); err != nil {
 		log.Fatal("ListenAndServe error: ", err.Error())
 	}
 }
 
-//this is the function that should be called in order to answer an rpc call
-//should be registered like "http.AddMethod("/", httpjsonrpc.Handle)"
-func Handle(w http.ResponseWriter, r *http.Request) {
-
-	//JSON RPC commands should be POSTs
-	isClientAllowed := clientAllowed(r)
-	if !isClientAllowed {
-		log.Warn("HTTP Client ip is not allowd")
-		http.Error(w, "Client ip is not allowd", http.StatusForbidden)
-		return
-	}
-
-	if r.Method != "POST" {
-		log.Warn("HTTP JSON RPC Handle - Method!=\"POST\"")
-		http.Error(w, "JSON RPC protocol only allows POST method", http.StatusMethodNotAllowed)
-		return
-	}
-	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
-	if contentType != "application/json" {
-		http.Error(w, "need content type to be application/json", http.StatusUnsupportedMediaType)
-		return
-	}
-
-	isCheckAuthOk := checkAuth(r)
-	if !isCheckAuthOk {
-		log.Warn("client authenticate failed")
-		RPCError(w, http.StatusUnauthorized, InternalError, "client authenticate failed")
-		return
-	}
-
-	//read the body of the request
-	body, _ := ioutil.ReadAll(r.Body)
-	request := make(map[string]interface{})
-	error := json.Unmarshal(body, &request)
-	if error != nil {
-		log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", error)
-		RPCError(w, http.StatusBadRequest, ParseError, "rpc json parse error:"+error.Error())
-		return
-	}
-	//get the corresponding function
-	requestMethod, ok := request["method"].(string)
-	if !ok {
-		RPCError(w, http.StatusBadRequest, InvalidRequest, "need a method!")
-		return
-	}
-	method, ok := mainMux[requestMethod]
-	if !ok {
-		RPCError(w, http.StatusNotFound, MethodNotFound, "method "+requestMethod+" not found")
-		return
-	}
-
-	requestParams := request["params"]
-	// Json rpc 1.0 support positional parameters while json rpc 2.0 support named parameters.
-	// positional parameters: { "requestParams":[1, 2, 3....] }
-	// named parameters: { "requestParams":{ "a":1, "b":2, "c":3 } }
-	// Here we support both of them.
-	var params Params
-	switch requestParams := requestParams.(type) {
-	case nil:
-	case []interface{}:
-		params = convertParams(requestMethod, requestParams)
-	case map[string]interface{}:
-		params = Params(requestParams)
-	default:
-		RPCError(w, http.StatusBadRequest, InvalidRequest, "params format error, must be an array or a map")
-		return
-	}
-	log.Debug("RPC method:", requestMethod)
-	log.Debug("RPC params:", params)
-
-	response := method(params)
-	var data []byte
-	if response["Error"] != elaErr.ErrCode(0) {
-		data, _ = json.Marshal(map[string]interface{}{
-			"jsonrpc": "2.0",
-			"result":  nil,
-			"error": map[string]interface{}{
-				"code":    response["Error"],
-				"message": response["Result"],
-				"id":      request["id"],
-			},
-		})
+// NewAuthenticator creates an authenticator of the JSON-RPC users and white
+// list, the other RPC services use it to apply the same access control.
+func NewAuthenticator() *jsonrpc.Authenticator {
+	return jsonrpc.NewAuthenticator(&jsonrpc.Config{
+		User:      config.Parameters.RpcConfiguration.User,
+		Pass:      config.Parameters.RpcConfiguration.Pass,
+		WhiteList: config.Parameters.RpcConfiguration.WhiteIPList,
+		Users:     rpcUsers(config.Parameters.RpcConfiguration.Users),
+	})
+}
 
-	} else {
-		data, _ = json.Marshal(map[string]interface{}{
-			"jsonrpc": "2.0",
-			"result":  response["Result"],
-			"id":      request["id"],
-			"error":   nil,
+// rpcUsers converts the configured RPC users into JSON-RPC users.
+func rpcUsers(users []config.RpcUser) []jsonrpc.User {
+	result := make([]jsonrpc.User, 0, len(users))
+	for _, u := range users {
+		result = append(result, jsonrpc.User{
+			Name:    u.User,
+			Pass:    u.Pass,
+			Roles:   u.Roles,
+			Methods: u.Methods,
 		})
 	}
-	w.Header().Set("Content-type", "application/json")
-	w.Write(data)
+	return result
 }
 
-func clientAllowed(r *http.Request) bool {
-	//this ipAbbr  may be  ::1 when request is localhost
-	ipAbbr, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		log.Errorf("RemoteAddr clientAllowed SplitHostPort failure %s \n", r.RemoteAddr)
-		return false
-
-	}
-	//after ParseIP ::1 chg to 0:0:0:0:0:0:0:1 the true ip
-	remoteIp := net.ParseIP(ipAbbr)
-
-	if remoteIp == nil {
-		log.Errorf("clientAllowed ParseIP ipAbbr %s failure  \n", ipAbbr)
-		return false
-	}
-
-	if remoteIp.IsLoopback() {
-		return true
-	}
-
-	for _, cfgIp := range config.Parameters.RpcConfiguration.WhiteIPList {
-		//WhiteIPList have 0.0.0.0  allow all ip in
-		if cfgIp == "0.0.0.0" {
-			return true
+// action wraps a RPC method into a JSON-RPC handler, the error code of a
+// failed call is returned as the JSON-RPC error code.
+func action(method func(Params) map[string]interface{}) jsonrpc.Handler {
+	return func(params htp.Params) (interface{}, error) {
+		response := method(Params(params))
+		code, _ := response["Error"].(elaErr.ErrCode)
+		if code == elaErr.Success {
+			return response["Result"], nil
 		}
-		if cfgIp == remoteIp.String() {
-			return true
+		message, ok := response["Result"].(string)
+		if !ok {
+			message = elaErr.ErrMap[code]
 		}
-
-	}
-	return false
-}
-
-func checkAuth(r *http.Request) bool {
-	if (config.Parameters.RpcConfiguration.User == config.Parameters.RpcConfiguration.Pass) &&
-		(len(config.Parameters.RpcConfiguration.User) == 0) {
-		return true
-	}
-	authHeader := r.Header["Authorization"]
-	if len(authHeader) <= 0 {
-		return false
-	}
-
-	authSha256 := sha256.Sum256([]byte(authHeader[0]))
-
-	login := config.Parameters.RpcConfiguration.User + ":" + config.Parameters.RpcConfiguration.Pass
-	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
-	cfgAuthSha256 := sha256.Sum256([]byte(auth))
-
-	resultCmp := subtle.ConstantTimeCompare(authSha256[:], cfgAuthSha256[:])
-	if resultCmp == 1 {
-		return true
-	}
-
-	// Request's auth doesn't match  user
-	return false
-}
-
-func RPCError(w http.ResponseWriter, httpStatus int, code elaErr.ErrCode, message string) {
-	data, _ := json.Marshal(map[string]interface{}{
-		"jsonrpc": "2.0",
-		"result":  nil,
-		"error": map[string]interface{}{
-			"code":    code,
-			"message": message,
-			"id":      nil,
-		},
-	})
-	w.Header().Set("Content-type", "application/json")
-	w.WriteHeader(httpStatus)
-	w.Write(data)
-}
-
-func convertParams(method string, params []interface{}) Params {
-	switch method {
-	case "createauxblock":
-		return FromArray(params, "paytoaddress")
-	case "submitauxblock":
-		return FromArray(params, "blockhash", "auxpow")
-	case "getblockhash":
-		return FromArray(params, "height")
-	case "getblock":
-		return FromArray(params, "blockhash", "verbosity")
-	case "setloglevel":
-		return FromArray(params, "level")
-	case "getrawtransaction":
-		return FromArray(params, "txid", "verbose")
-	case "getarbitratorgroupbyheight":
-		return FromArray(params, "height")
-	case "togglemining":
-		return FromArray(params, "mining")
-	case "discretemining":
-		return FromArray(params, "count")
-	case "sendrawtransaction":
-		return FromArray(params, "data")
-	case "listunspent":
-		return FromArray(params, "addresses")
-	case "getreceivedbyaddress":
-		return FromArray(params, "address")
-	case "getblockbyheight":
-		return FromArray(params, "height")
-	case "estimatesmartfee":
-		return FromArray(params, "confirmations")
-	default:
-		return Params{}
+		return nil, htp.NewError(int(code), message)
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	chain "github.com/elastos/Elastos.ELA/blockchain"
+	"github.com/elastos/Elastos.ELA/common"
 	"github.com/elastos/Elastos.ELA/common/config"
 	"github.com/elastos/Elastos.ELA/servers"
 )
@@ -21,6 +22,15 @@ type Info struct {
 	HttpJsonPort  int
 	HttpLocalPort int
 	NodePort      uint16
+	Arbiter       *ArbiterInfo
+}
+
+type ArbiterInfo struct {
+	PublicKey      string
+	StartHeight    uint32
+	EndHeight      uint32
+	InactiveRounds uint32
+	Counters       servers.ArbiterCountersInfo
 }
 
 type NgbNodeInfo struct {
@@ -51,6 +61,7 @@ func viewHandler(w http.ResponseWriter, r *http.Request) {
 		HttpWsPort:   config.Parameters.HttpWsPort,
 		HttpJsonPort: config.Parameters.HttpJsonPort,
 		NodePort:     config.Parameters.NodePort,
+		Arbiter:      getArbiterInfo(),
 	}
 
 	err := templates.ExecuteTemplate(w, "info", pageInfo)
@@ -59,6 +70,29 @@ func viewHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func getArbiterInfo() *ArbiterInfo {
+	if servers.Arbiter == nil {
+		return nil
+	}
+
+	publicKey := common.BytesToHexString(servers.Arbiter.GetPublicKey())
+	metrics, err := servers.GetArbiterMetricsInfo(publicKey, 1)
+	if err != nil || len(metrics.Rounds) == 0 {
+		return nil
+	}
+
+	info := &ArbiterInfo{
+		PublicKey:      publicKey,
+		StartHeight:    metrics.Rounds[0].StartHeight,
+		EndHeight:      metrics.Rounds[0].EndHeight,
+		InactiveRounds: metrics.InactiveRounds[publicKey],
+	}
+	if len(metrics.Rounds[0].Arbiters) > 0 {
+		info.Counters = metrics.Rounds[0].Arbiters[0]
+	}
+	return info
+}
+
 func StartServer() {
 	http.HandleFunc("/info", viewHandler)
 	http.ListenAndServe(":"+strconv.Itoa(int(config.Parameters.HttpInfoPort)), nil)
@@ -71,6 +71,34 @@ const page = `
 </td>
 </tr>
 </table>
+{{if .Arbiter}}
+<br><br><br><br>
+
+<table class="bt" width="80%">
+	<tr><th>Arbiter Performance</th></tr>
+</table>
+<br>
+
+<table class="bd" width="80%">
+<tr>
+<td width="20%" >
+	<table class="font" width="100%">
+	<tr><th>Rounds Missed</th></tr>
+	<tr><td align="center"><b><font size="40px">{{.Arbiter.Counters.RoundsMissed}}</font></b></td></tr>
+	</table>
+</td>
+<td width="80%">
+	<table class="font" width="100%">
+	<tr><td width="25%">PublicKey:</td><td class="pk" colspan="3">{{.Arbiter.PublicKey}}</td></tr>
+	<tr><td width="25%">RoundStartHeight:</td><td width="25%">{{.Arbiter.StartHeight}}</td><td width="25%">LastHeight:</td><td width="25%">{{.Arbiter.EndHeight}}</td></tr>
+	<tr><td width="25%">Proposals:</td><td width="25%">{{.Arbiter.Counters.Proposals}}</td><td width="25%">ViewChanges:</td><td width="25%">{{.Arbiter.Counters.ViewChanges}}</td></tr>
+	<tr><td width="25%">VotesOnTime:</td><td width="25%">{{.Arbiter.Counters.VotesOnTime}}</td><td width="25%">VotesLate:</td><td width="25%">{{.Arbiter.Counters.VotesLate}}</td></tr>
+	<tr><td width="25%">InactivityStrikes:</td><td width="25%">{{.Arbiter.Counters.InactivityStrikes}}</td><td width="25%">InactiveRounds:</td><td width="25%">{{.Arbiter.InactiveRounds}}</td></tr>
+	</table>
+</td>
+</tr>
+</table>
+{{end}}
 <br><br><br><br><br><br>
 
 <table class="font" border="0" width="80%">
@@ -1,20 +1,17 @@
 package httprestful
 
 import (
-	"context"
 	"crypto/tls"
 	"encoding/json"
-	"io/ioutil"
 	"net"
-	"net/http"
 	"strconv"
-	"strings"
-	"sync"
 
 	"github.com/elastos/Elastos.ELA/common/config"
 	"github.com/elastos/Elastos.ELA/common/log"
 	. "github.com/elastos/Elastos.ELA/errors"
 	"github.com/elastos/Elastos.ELA/servers"
+	htp "github.com/elastos/Elastos.ELA/utils/http"
+	"github.com/elastos/Elastos.ELA/utils/http/restful"
 )
 
 const (
@@ -22,9 +19,9 @@ const (
 	ApiGetNodeState        = "/api/v1/node/state"
 	ApiGetBlockTxsByHeight = "/api/v1/block/transactions/height/:height"
 	ApiGetBlockByHeight    = "/api/v1/block/details/height/:height"
-	ApiGetBlockByHash      = "/api/v1/block/details/hash/:hash"
+	ApiGetBlockByHash      = "/api/v1/block/details/hash/:blockhash"
 	ApiGetConfirmByHeight  = "/api/v1/confirm/details/height/:height"
-	ApiGetConfirmByHash    = "/api/v1/confirm/details/hash/:hash"
+	ApiGetConfirmByHash    = "/api/v1/confirm/details/hash/:blockhash"
 	ApiGetBlockHeight      = "/api/v1/block/height"
 	ApiGetBlockHash        = "/api/v1/block/hash/:height"
 	ApiGetTransaction      = "/api/v1/transaction/:hash"
@@ -38,25 +35,39 @@ const (
 	ApiRestart             = "/api/v1/restart"
 )
 
-type Action struct {
-	sync.RWMutex
-	name    string
-	handler func(servers.Params) map[string]interface{}
-}
+const (
+	ApiV2ListProducers    = "/api/v2/producers"
+	ApiV2ProducerStatus   = "/api/v2/producers/:publickey/state"
+	ApiV2VoteStatus       = "/api/v2/votes/:address"
+	ApiV2ConfirmByHeight  = "/api/v2/confirms/height/:height"
+	ApiV2ConfirmByHash    = "/api/v2/confirms/hash/:blockhash"
+	ApiV2Arbiters         = "/api/v2/arbiters"
+	ApiV2ArbitersByHeight = "/api/v2/arbiters/height/:height"
+	ApiV2ArbiterPeers     = "/api/v2/arbiters/peers"
+	ApiV2ArbiterMetrics   = "/api/v2/arbiters/metrics"
+	ApiV2ConsensusRounds  = "/api/v2/consensus/rounds"
+	ApiV2TransactionPool  = "/api/v2/mempool"
+	ApiV2OpenAPI          = "/api/v2/openapi.json"
+)
 
-type restServer struct {
-	router   *Router
-	listener net.Listener
-	server   *http.Server
-	postMap  map[string]Action
-	getMap   map[string]Action
-}
+const (
+	tagNode        = "node"
+	tagBlock       = "block"
+	tagTransaction = "transaction"
+	tagAsset       = "asset"
+	tagProducer    = "producer"
+	tagArbiter     = "arbiter"
+)
 
 type ApiServer interface {
 	Start()
 	Stop()
 }
 
+type restServer struct {
+	server *restful.Server
+}
+
 func StartServer() {
 	rest := InitRestServer()
 	rest.Start()
@@ -64,229 +75,230 @@ func StartServer() {
 
 func InitRestServer() ApiServer {
 	rt := &restServer{}
-	rt.router = &Router{}
-	rt.initializeMethod()
-	rt.initGetHandler()
-	rt.initPostHandler()
+	rt.server = restful.NewServer(&restful.Config{
+		ServePort:       uint16(config.Parameters.HttpRestPort),
+		NetListen:       listen,
+		Response:        response,
+		IPLimiter:       servers.IPLimiter,
+		RateLimitedCode: int(RateLimited),
+	})
+	rt.initV1Actions()
+	rt.initV2Actions()
+	rt.server.ServeOpenAPI(ApiV2OpenAPI, restful.DocInfo{
+		Title:       "ELA Node RESTful API",
+		Description: "The RESTful API of the Elastos ELA node.",
+		Version:     servers.Compile,
+	})
 	return rt
 }
 
-func (rt *restServer) Start() {
-	if config.Parameters.HttpRestPort == 0 {
-		log.Fatal("Not configure HttpRestPort port ")
-	}
-
-	if config.Parameters.HttpRestPort%1000 == servers.TlsPort {
-		var err error
-		rt.listener, err = rt.initTlsListen()
-		if err != nil {
-			log.Error("Https Cert: ", err.Error())
-		}
-	} else {
-		var err error
-		rt.listener, err = net.Listen("tcp", ":"+strconv.Itoa(config.Parameters.HttpRestPort))
-		if err != nil {
-			log.Fatal("net.Listen: ", err.Error())
-		}
-	}
-	rt.server = &http.Server{Handler: rt.router}
-	err := rt.server.Serve(rt.listener)
-
-	if err != nil {
-		log.Fatal("ListenAndServe: ", err.Error())
-	}
+func (rt *restServer) initV1Actions() {
+	rt.get(ApiGetConnectionCount, servers.GetConnectionCount, &restful.Doc{
+		Name: "getconnectioncount", Tag: tagNode,
+		Summary: "Returns the number of connected peers",
+	})
+	rt.get(ApiGetNodeState, servers.GetNodeState, &restful.Doc{
+		Name: "getnodestate", Tag: tagNode,
+		Summary: "Returns the status of the node",
+	})
+	rt.get(ApiGetBlockTxsByHeight, servers.GetTransactionsByHeight, &restful.Doc{
+		Name: "getblocktransactionsbyheight", Tag: tagBlock,
+		Summary: "Returns the transaction hashes of the block at the given height",
+	})
+	rt.get(ApiGetBlockByHeight, servers.GetBlockByHeight, &restful.Doc{
+		Name: "getblockbyheight", Tag: tagBlock,
+		Summary: "Returns the block at the given height",
+	})
+	rt.get(ApiGetBlockByHash, servers.GetBlockByHash, &restful.Doc{
+		Name: "getblockbyhash", Tag: tagBlock,
+		Summary: "Returns the block of the given hash",
+		Query:   []string{"verbosity"},
+	})
+	rt.get(ApiGetConfirmByHeight, servers.GetConfirmByHeight, &restful.Doc{
+		Name: "getconfirmbyheight", Tag: tagBlock,
+		Summary: "Returns the confirm of the block at the given height",
+		Query:   []string{"verbosity"},
+	})
+	rt.get(ApiGetConfirmByHash, servers.GetConfirmByHash, &restful.Doc{
+		Name: "getconfirmbyhash", Tag: tagBlock,
+		Summary: "Returns the confirm of the block of the given hash",
+		Query:   []string{"verbosity"},
+	})
+	rt.get(ApiGetBlockHeight, servers.GetBlockHeight, &restful.Doc{
+		Name: "getblockheight", Tag: tagBlock,
+		Summary: "Returns the current block height",
+	})
+	rt.get(ApiGetBlockHash, servers.GetBlockHash, &restful.Doc{
+		Name: "getblockhash", Tag: tagBlock,
+		Summary: "Returns the block hash at the given height",
+	})
+	rt.get(ApiGetTransactionPool, servers.GetTransactionPool, &restful.Doc{
+		Name: "gettransactionpool", Tag: tagTransaction,
+		Summary: "Returns the transactions in the mempool",
+	})
+	rt.get(ApiGetTransaction, servers.GetTransactionByHash, &restful.Doc{
+		Name: "gettransaction", Tag: tagTransaction,
+		Summary: "Returns the transaction of the given hash",
+	})
+	rt.get(ApiGetAsset, servers.GetAssetByHash, &restful.Doc{
+		Name: "getasset", Tag: tagAsset,
+		Summary: "Returns the asset of the given asset id",
+	})
+	rt.get(ApiGetUTXOByAddr, servers.GetUnspends, &restful.Doc{
+		Name: "getutxobyaddr", Tag: tagAsset,
+		Summary: "Returns the UTXOs of the given address",
+	})
+	rt.get(ApiGetUTXOByAsset, servers.GetUnspendOutput, &restful.Doc{
+		Name: "getutxobyasset", Tag: tagAsset,
+		Summary: "Returns the UTXOs of the given address and asset id",
+	})
+	rt.get(ApiGetBalanceByAddr, servers.GetBalanceByAddr, &restful.Doc{
+		Name: "getbalancebyaddr", Tag: tagAsset,
+		Summary: "Returns the ELA balance of the given address",
+	})
+	rt.get(ApiGetBalanceByAsset, servers.GetBalanceByAsset, &restful.Doc{
+		Name: "getbalancebyasset", Tag: tagAsset,
+		Summary: "Returns the balance of the given address and asset id",
+	})
+	rt.get(ApiRestart, rt.Restart, &restful.Doc{
+		Name: "restart", Tag: tagNode,
+		Summary: "Restarts the RESTful server",
+	})
+	rt.post(ApiSendRawTransaction, servers.SendRawTransaction, &restful.Doc{
+		Name: "sendrawtransaction", Tag: tagTransaction,
+		Summary: "Broadcasts the signed raw transaction in the data field",
+	})
 }
 
-func (rt *restServer) initializeMethod() {
+func (rt *restServer) initV2Actions() {
+	rt.get(ApiV2ListProducers, servers.ListProducers, &restful.Doc{
+		Name: "listproducers", Tag: tagProducer,
+		Summary: "Returns the producers sorted by votes",
+		Query:   []string{"state", "start", "limit"},
+	})
+	rt.get(ApiV2ProducerStatus, servers.ProducerStatus, &restful.Doc{
+		Name: "producerstatus", Tag: tagProducer,
+		Summary: "Returns the state of the producer of the given public key",
+	})
+	rt.get(ApiV2VoteStatus, servers.VoteStatus, &restful.Doc{
+		Name: "votestatus", Tag: tagProducer,
+		Summary: "Returns the voting status of the given address",
+	})
+	rt.get(ApiV2ConfirmByHeight, servers.GetConfirmByHeight, &restful.Doc{
+		Name: "getconfirmsbyheight", Tag: tagArbiter,
+		Summary: "Returns the confirm of the block at the given height",
+		Query:   []string{"verbosity"},
+	})
+	rt.get(ApiV2ConfirmByHash, servers.GetConfirmByHash, &restful.Doc{
+		Name: "getconfirmsbyhash", Tag: tagArbiter,
+		Summary: "Returns the confirm of the block of the given hash",
+		Query:   []string{"verbosity"},
+	})
+	rt.get(ApiV2Arbiters, servers.GetArbitersInfo, &restful.Doc{
+		Name: "getarbitersinfo", Tag: tagArbiter,
+		Summary: "Returns the current and next arbiters",
+	})
+	rt.get(ApiV2ArbitersByHeight, servers.GetArbitratorGroupByHeight, &restful.Doc{
+		Name: "getarbitratorgroupbyheight", Tag: tagArbiter,
+		Summary: "Returns the arbiters and on duty index at the given height",
+	})
+	rt.get(ApiV2ArbiterPeers, servers.GetArbiterPeersInfo, &restful.Doc{
+		Name: "getarbiterpeersinfo", Tag: tagArbiter,
+		Summary: "Returns the connection state of the arbiter peers",
+	})
+	rt.get(ApiV2ArbiterMetrics, servers.GetArbiterMetrics, &restful.Doc{
+		Name: "getarbitermetrics", Tag: tagArbiter,
+		Summary: "Returns the arbiter metrics in recent rounds",
+		Query:   []string{"publickey", "rounds"},
+	})
+	rt.get(ApiV2ConsensusRounds, servers.GetConsensusRounds, &restful.Doc{
+		Name: "getconsensusrounds", Tag: tagArbiter,
+		Summary: "Returns the consensus rounds between the given heights",
+		Query:   []string{"startheight", "endheight"},
+	})
+	rt.get(ApiV2TransactionPool, servers.GetTransactionPool, &restful.Doc{
+		Name: "getrawmempool", Tag: tagTransaction,
+		Summary: "Returns the transactions in the mempool",
+	})
+}
 
-	getMethodMap := map[string]Action{
-		ApiGetConnectionCount:  {name: "getconnectioncount", handler: servers.GetConnectionCount},
-		ApiGetNodeState:        {name: "getnodestate", handler: servers.GetNodeState},
-		ApiGetBlockTxsByHeight: {name: "getblocktransactionsbyheight", handler: servers.GetTransactionsByHeight},
-		ApiGetBlockByHeight:    {name: "getblockbyheight", handler: servers.GetBlockByHeight},
-		ApiGetBlockByHash:      {name: "getblockbyhash", handler: servers.GetBlockByHash},
-		ApiGetConfirmByHeight:  {name: "getconfirmbyheight", handler: servers.GetConfirmByHeight},
-		ApiGetConfirmByHash:    {name: "getconfirmbyhash", handler: servers.GetConfirmByHeight},
-		ApiGetBlockHeight:      {name: "getblockheight", handler: servers.GetBlockHeight},
-		ApiGetBlockHash:        {name: "getblockhash", handler: servers.GetBlockHash},
-		ApiGetTransactionPool:  {name: "gettransactionpool", handler: servers.GetTransactionPool},
-		ApiGetTransaction:      {name: "gettransaction", handler: servers.GetTransactionByHash},
-		ApiGetAsset:            {name: "getasset", handler: servers.GetAssetByHash},
-		ApiGetUTXOByAddr:       {name: "getutxobyaddr", handler: servers.GetUnspends},
-		ApiGetUTXOByAsset:      {name: "getutxobyasset", handler: servers.GetUnspendOutput},
-		ApiGetBalanceByAddr:    {name: "getbalancebyaddr", handler: servers.GetBalanceByAddr},
-		ApiGetBalanceByAsset:   {name: "getbalancebyasset", handler: servers.GetBalanceByAsset},
-		ApiRestart:             {name: "restart", handler: rt.Restart},
+// get registers a GET action, the parameters in path and query are passed to
+// the method.
+func (rt *restServer) get(url string,
+	method func(servers.Params) map[string]interface{}, doc *restful.Doc) {
+	handler := func(params htp.Params) (interface{}, error) {
+		return unpack(method(servers.Params(params)))
 	}
-
-	postMethodMap := map[string]Action{
-		ApiSendRawTransaction: {name: "sendrawtransaction", handler: servers.SendRawTransaction},
+	if err := rt.server.RegisterDocAction("GET", url, handler, doc); err != nil {
+		log.Fatal("register RESTful action failed: ", err)
 	}
-	rt.postMap = postMethodMap
-	rt.getMap = getMethodMap
 }
 
-func (rt *restServer) getPath(url string) string {
-
-	if strings.Contains(url, strings.TrimRight(ApiGetBlockTxsByHeight, ":height")) {
-		return ApiGetBlockTxsByHeight
-	} else if strings.Contains(url, strings.TrimRight(ApiGetBlockByHeight, ":height")) {
-		return ApiGetBlockByHeight
-	} else if strings.Contains(url, strings.TrimRight(ApiGetBlockByHash, ":hash")) {
-		return ApiGetBlockByHash
-	} else if strings.Contains(url, strings.TrimRight(ApiGetConfirmByHeight, ":height")) {
-		return ApiGetConfirmByHeight
-	} else if strings.Contains(url, strings.TrimRight(ApiGetConfirmByHash, ":hash")) {
-		return ApiGetConfirmByHash
-	} else if strings.Contains(url, strings.TrimRight(ApiGetBlockHash, ":height")) {
-		return ApiGetBlockHash
-	} else if strings.Contains(url, strings.TrimRight(ApiGetTransaction, ":hash")) {
-		return ApiGetTransaction
-	} else if strings.Contains(url, strings.TrimRight(ApiGetBalanceByAddr, ":addr")) {
-		return ApiGetBalanceByAddr
-	} else if strings.Contains(url, strings.TrimRight(ApiGetBalanceByAsset, ":addr/:assetid")) {
-		return ApiGetBalanceByAsset
-	} else if strings.Contains(url, strings.TrimRight(ApiGetUTXOByAddr, ":addr")) {
-		return ApiGetUTXOByAddr
-	} else if strings.Contains(url, strings.TrimRight(ApiGetUTXOByAsset, ":addr/:assetid")) {
-		return ApiGetUTXOByAsset
-	} else if strings.Contains(url, strings.TrimRight(ApiGetAsset, ":hash")) {
-		return ApiGetAsset
+// post registers a POST action, the request body must be a JSON object which
+// will be passed to the method.
+func (rt *restServer) post(url string,
+	method func(servers.Params) map[string]interface{}, doc *restful.Doc) {
+	handler := func(data []byte) (interface{}, error) {
+		params := make(servers.Params)
+		if err := json.Unmarshal(data, &params); err != nil {
+			return nil, htp.NewError(int(IllegalDataFormat),
+				ErrMap[IllegalDataFormat])
+		}
+		return unpack(method(params))
 	}
-	return url
-}
-
-func (rt *restServer) getParams(r *http.Request, url string, req map[string]interface{}) map[string]interface{} {
-	switch url {
-	case ApiGetConnectionCount:
-
-	case ApiGetNodeState:
-
-	case ApiGetBlockTxsByHeight:
-		req["height"] = getParam(r, "height")
-
-	case ApiGetBlockByHeight:
-		req["height"] = getParam(r, "height")
-
-	case ApiGetBlockByHash:
-		req["blockhash"] = getParam(r, "hash")
-
-	case ApiGetConfirmByHeight:
-		req["height"] = getParam(r, "height")
-
-	case ApiGetConfirmByHash:
-		req["blockhash"] = getParam(r, "hash")
-
-	case ApiGetBlockHeight:
-
-	case ApiGetTransactionPool:
-
-	case ApiGetBlockHash:
-		req["height"] = getParam(r, "height")
-
-	case ApiGetTransaction:
-		req["hash"] = getParam(r, "hash")
-
-	case ApiGetAsset:
-		req["hash"] = getParam(r, "hash")
-
-	case ApiGetBalanceByAsset:
-		req["addr"] = getParam(r, "addr")
-		req["assetid"] = getParam(r, "assetid")
-
-	case ApiGetBalanceByAddr:
-		req["addr"] = getParam(r, "addr")
-
-	case ApiGetUTXOByAddr:
-		req["addr"] = getParam(r, "addr")
-
-	case ApiGetUTXOByAsset:
-		req["addr"] = getParam(r, "addr")
-		req["assetid"] = getParam(r, "assetid")
-
-	case ApiRestart:
-
-	case ApiSendRawTransaction:
-
+	if err := rt.server.RegisterDocAction("POST", url, handler, doc); err != nil {
+		log.Fatal("register RESTful action failed: ", err)
 	}
-	return req
 }
 
-func (rt *restServer) initGetHandler() {
-
-	for k, _ := range rt.getMap {
-		rt.router.Get(k, func(w http.ResponseWriter, r *http.Request) {
-
-			var req = make(map[string]interface{})
-			var resp map[string]interface{}
-
-			url := rt.getPath(r.URL.Path)
-
-			if h, ok := rt.getMap[url]; ok {
-				req = rt.getParams(r, url, req)
-				resp = h.handler(req)
-			} else {
-				resp = servers.ResponsePack(InvalidMethod, "")
-			}
-			rt.response(w, resp)
-		})
+// unpack splits the response of a method into result and error, the result
+// of a failed call is kept so that it's returned along with the error code.
+func unpack(resp map[string]interface{}) (interface{}, error) {
+	code, _ := resp["Error"].(ErrCode)
+	if code == Success {
+		return resp["Result"], nil
 	}
+	return resp["Result"], htp.NewError(int(code), ErrMap[code])
 }
 
-func (rt *restServer) initPostHandler() {
-	for k, _ := range rt.postMap {
-		rt.router.Post(k, func(w http.ResponseWriter, r *http.Request) {
-
-			body, _ := ioutil.ReadAll(r.Body)
-			defer r.Body.Close()
-
-			var req = make(map[string]interface{})
-			var resp map[string]interface{}
-
-			url := rt.getPath(r.URL.Path)
-			if h, ok := rt.postMap[url]; ok {
-				if err := json.Unmarshal(body, &req); err == nil {
-					req = rt.getParams(r, url, req)
-					resp = h.handler(req)
-				} else {
-					resp = servers.ResponsePack(IllegalDataFormat, "")
-				}
-			} else {
-				resp = servers.ResponsePack(InvalidMethod, "")
-			}
-			rt.response(w, resp)
-		})
+// response encodes the result and error into the {Result, Error, Desc}
+// response.
+func response(result interface{}, err error) []byte {
+	code := Success
+	if err != nil {
+		if e, ok := err.(*htp.Error); ok {
+			code = ErrCode(e.Code)
+		} else {
+			code = InternalError
+			result = err.Error()
+		}
 	}
-	//Options
-	for k, _ := range rt.postMap {
-		rt.router.Options(k, func(w http.ResponseWriter, r *http.Request) {
-			rt.write(w, []byte{})
-		})
+	if result == nil {
+		result = ""
 	}
 
+	resp := servers.ResponsePack(code, result)
+	resp["Desc"] = ErrMap[code]
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Error("HTTP Handle - json.Marshal: ", err)
+	}
+	return data
 }
 
-func (rt *restServer) write(w http.ResponseWriter, data []byte) {
-	w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-	w.Header().Set("content-type", "application/json;charset=utf-8")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Write(data)
-}
+func (rt *restServer) Start() {
+	if config.Parameters.HttpRestPort == 0 {
+		log.Fatal("Not configure HttpRestPort port ")
+	}
 
-func (rt *restServer) response(w http.ResponseWriter, resp map[string]interface{}) {
-	resp["Desc"] = ErrMap[resp["Error"].(ErrCode)]
-	data, err := json.Marshal(resp)
-	if err != nil {
-		log.Fatal("HTTP Handle - json.Marshal: %v", err)
-		return
+	if err := rt.server.Start(); err != nil {
+		log.Error("ListenAndServe: ", err.Error())
 	}
-	rt.write(w, data)
 }
 
 func (rt *restServer) Stop() {
-	if rt.server != nil {
-		rt.server.Shutdown(context.Background())
-		log.Error("Close restful ")
+	if err := rt.server.Stop(); err == nil {
+		log.Info("Close restful ")
 	}
 }
 
@@ -299,7 +311,14 @@ func (rt *restServer) Restart(cmd servers.Params) map[string]interface{} {
 	return servers.ResponsePack(Success, "")
 }
 
-func (rt *restServer) initTlsListen() (net.Listener, error) {
+func listen(port uint16) (net.Listener, error) {
+	if port%1000 == servers.TlsPort {
+		return initTlsListen(port)
+	}
+	return net.Listen("tcp", ":"+strconv.Itoa(int(port)))
+}
+
+func initTlsListen(port uint16) (net.Listener, error) {
 
 	CertPath := config.Parameters.RestCertPath
 	KeyPath := config.Parameters.RestKeyPath
@@ -315,8 +334,8 @@ func (rt *restServer) initTlsListen() (net.Listener, error) {
 		Certificates: []tls.Certificate{cert},
 	}
 
-	log.Info("TLS listen port is ", strconv.Itoa(config.Parameters.HttpRestPort))
-	listener, err := tls.Listen("tcp", ":"+strconv.Itoa(config.Parameters.HttpRestPort), tlsConfig)
+	log.Info("TLS listen port is ", strconv.Itoa(int(port)))
+	listener, err := tls.Listen("tcp", ":"+strconv.Itoa(int(port)), tlsConfig)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -0,0 +1,480 @@
+package httpwebsocket
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/log"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/errors"
+	"github.com/elastos/Elastos.ELA/events"
+	"github.com/elastos/Elastos.ELA/servers"
+)
+
+const (
+	// notifierBacklogSize is the count of events waiting to be notified to
+	// log a warning that the notifier falls behind the chain.
+	notifierBacklogSize = 256
+)
+
+// AddressNotification is the notification of the address topic.
+type AddressNotification struct {
+	TxID        string                   `json:"txid"`
+	Status      string                   `json:"status"`
+	Height      uint32                   `json:"height"`
+	Incoming    []string                 `json:"incoming"`
+	Outgoing    []string                 `json:"outgoing"`
+	Transaction *servers.TransactionInfo `json:"transaction,omitempty"`
+}
+
+// TxConfirmationsNotification is the notification of the txconfirmations
+// topic.
+type TxConfirmationsNotification struct {
+	TxID          string `json:"txid"`
+	Height        uint32 `json:"height"`
+	Confirmations uint32 `json:"confirmations"`
+}
+
+// ProducerNotification is the notification of the producer topic.
+type ProducerNotification struct {
+	OwnerPublicKey string `json:"ownerpublickey"`
+	NodePublicKey  string `json:"nodepublickey"`
+	OldState       string `json:"oldstate"`
+	NewState       string `json:"newstate"`
+	Height         uint32 `json:"height"`
+}
+
+// producerState is the state of a producer in the last snapshot.
+type producerState struct {
+	nodePublicKey string
+	state         string
+}
+
+// notifier dispatches the chain events to the subscriptions of sessions.
+type notifier struct {
+	server *Server
+
+	// pending buffers the events waiting to be notified, wake is signaled
+	// when events are added to it.
+	mtx     sync.Mutex
+	pending []*events.Event
+	wake    chan struct{}
+
+	// snapshots to find out the changes of producers and arbiters.
+	producers map[string]producerState
+	arbiters  string
+}
+
+// notify buffers the event to be dispatched by the notifier goroutine. It
+// never blocks since the block events are notified with the chain locked, and
+// no event is dropped. The dispatching never blocks on sessions either, a slow
+// session is closed once the send queue of it is full.
+func (n *notifier) notify(e *events.Event) {
+	switch e.Type {
+	case events.ETTransactionAccepted, events.ETBlockConnected,
+		events.ETBlockDisconnected, events.ETBlockAccepted,
+		events.ETBlockConfirmAccepted, events.ETConfirmAccepted:
+	default:
+		return
+	}
+
+	n.mtx.Lock()
+	n.pending = append(n.pending, e)
+	if len(n.pending) == notifierBacklogSize {
+		log.Warnf("Websocket notifier has %d events waiting",
+			notifierBacklogSize)
+	}
+	n.mtx.Unlock()
+
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (n *notifier) start() {
+	n.producers = n.producerStates()
+	n.arbiters = n.arbitersState()
+	for range n.wake {
+		n.mtx.Lock()
+		pending := n.pending
+		n.pending = nil
+		n.mtx.Unlock()
+
+		for _, e := range pending {
+			n.dispatch(e)
+		}
+	}
+}
+
+// dispatch dispatches the event to the subscriptions.
+func (n *notifier) dispatch(e *events.Event) {
+	switch e.Type {
+	case events.ETTransactionAccepted:
+		if tx, ok := e.Data.(*types.Transaction); ok {
+			n.onTransaction(tx, nil)
+		}
+
+	case events.ETBlockConnected:
+		if block, ok := e.Data.(*types.Block); ok {
+			n.onBlock(block)
+		}
+
+	case events.ETBlockDisconnected:
+		if block, ok := e.Data.(*types.Block); ok {
+			n.onBlockDisconnected(block)
+		}
+
+	// the DPoS state has been updated by the block when it's accepted.
+	case events.ETBlockAccepted:
+		if block, ok := e.Data.(*types.Block); ok {
+			n.onProducersChanged(block.Height)
+			n.onArbitersChanged()
+		}
+
+	case events.ETBlockConfirmAccepted:
+		if block, ok := e.Data.(*types.Block); ok {
+			n.onBlockConfirmed(block)
+			n.onTxFinality(block.Height)
+			n.onProducersChanged(block.Height)
+			n.onArbitersChanged()
+		}
+
+	case events.ETConfirmAccepted:
+		if confirm, ok := e.Data.(*payload.Confirm); ok {
+			n.onConfirm(confirm)
+		}
+	}
+}
+
+// send sends the notification of a subscription to the session.
+func (n *notifier) send(ss *session, sub *subscription, result interface{}) {
+	resp := servers.ResponsePack(errors.Success, result)
+	resp["Action"] = sub.topic
+	resp["Subscription"] = sub.id
+	resp["Desc"] = errors.ErrMap[errors.Success]
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Error("Websocket notify:", err)
+		return
+	}
+	ss.Send(data)
+}
+
+// onTransaction notifies the address subscriptions matching the transaction,
+// header is nil if the transaction is in the mempool.
+func (n *notifier) onTransaction(tx *types.Transaction, header *types.Header) {
+	var outputs, inputs map[common.Uint168]struct{}
+	n.server.sessions.Foreach(func(ss *session) {
+		for _, sub := range ss.subscriptions(TopicAddress) {
+			// the accepted event may arrive after the transaction mined
+			if w, ok := sub.watched[tx.Hash()]; ok && header == nil &&
+				w.status != servers.TxStatusPending {
+				continue
+			}
+			if outputs == nil {
+				outputs, inputs = txProgramHashes(tx)
+			}
+			incoming, outgoing := sub.matchAddresses(outputs, inputs)
+			if len(incoming) == 0 && len(outgoing) == 0 {
+				continue
+			}
+
+			watched := &watchedTx{
+				status:   servers.TxStatusPending,
+				incoming: incoming,
+				outgoing: outgoing,
+			}
+			if header != nil {
+				watched.status = servers.TxStatusMined
+				watched.blockHash = header.Hash()
+				watched.height = header.Height
+			}
+			n.send(ss, sub, &AddressNotification{
+				TxID:        servers.ToReversedString(tx.Hash()),
+				Status:      watched.status,
+				Height:      watched.height,
+				Incoming:    incoming,
+				Outgoing:    outgoing,
+				Transaction: servers.GetTransactionInfo(header, tx),
+			})
+
+			// blocks before DPoS will not be finalized
+			if header != nil &&
+				header.Height < servers.ChainParams.CRCOnlyDPOSHeight {
+				delete(sub.watched, tx.Hash())
+				continue
+			}
+			sub.watch(tx.Hash(), watched)
+		}
+	})
+}
+
+// onWatchedTxs calls f with the watched transactions of all address
+// subscriptions.
+func (n *notifier) onWatchedTxs(f func(ss *session, sub *subscription,
+	txID common.Uint256, tx *watchedTx)) {
+	n.server.sessions.Foreach(func(ss *session) {
+		for _, sub := range ss.subscriptions(TopicAddress) {
+			for txID, tx := range sub.watched {
+				f(ss, sub, txID, tx)
+			}
+		}
+	})
+}
+
+// sendWatched sends the status of a watched transaction.
+func (n *notifier) sendWatched(ss *session, sub *subscription,
+	txID common.Uint256, tx *watchedTx) {
+	n.send(ss, sub, &AddressNotification{
+		TxID:     servers.ToReversedString(txID),
+		Status:   tx.status,
+		Height:   tx.height,
+		Incoming: tx.incoming,
+		Outgoing: tx.outgoing,
+	})
+}
+
+func (n *notifier) onBlock(block *types.Block) {
+	for _, tx := range block.Transactions {
+		n.onTransaction(tx, &block.Header)
+	}
+	n.onTxConfirmations(block.Height)
+	n.onTxsDropped()
+}
+
+// onBlockConfirmed notifies the watched transactions finalized by the block
+// confirmed by DPoS, which are mined in the block or the blocks before it,
+// and stops tracking them.
+func (n *notifier) onBlockConfirmed(block *types.Block) {
+	hash, err := servers.Store.GetBlockHash(block.Height)
+	if err != nil || !hash.IsEqual(block.Hash()) {
+		return
+	}
+	n.onWatchedTxs(func(ss *session, sub *subscription,
+		txID common.Uint256, tx *watchedTx) {
+		if tx.status != servers.TxStatusMined || tx.height > block.Height {
+			return
+		}
+		tx.status = servers.TxStatusFinalized
+		n.sendWatched(ss, sub, txID, tx)
+		delete(sub.watched, txID)
+	})
+}
+
+// onBlockDisconnected notifies the watched transactions in the disconnected
+// block, they are tracked as pending transactions again.
+func (n *notifier) onBlockDisconnected(block *types.Block) {
+	hash := block.Hash()
+	n.onWatchedTxs(func(ss *session, sub *subscription,
+		txID common.Uint256, tx *watchedTx) {
+		if tx.status != servers.TxStatusMined || !tx.blockHash.IsEqual(hash) {
+			return
+		}
+		tx.status = servers.TxStatusReorged
+		n.sendWatched(ss, sub, txID, tx)
+		tx.status = servers.TxStatusPending
+		tx.blockHash = common.EmptyHash
+		tx.height = 0
+	})
+}
+
+// onTxsDropped notifies the pending transactions neither in the mempool nor
+// in the chain, and stops tracking them.
+func (n *notifier) onTxsDropped() {
+	n.onWatchedTxs(func(ss *session, sub *subscription,
+		txID common.Uint256, tx *watchedTx) {
+		if tx.status != servers.TxStatusPending ||
+			servers.TxMemPool.HaveTransaction(txID) {
+			return
+		}
+		if _, _, err := servers.Store.GetTransaction(txID); err == nil {
+			return
+		}
+		tx.status = servers.TxStatusDropped
+		n.sendWatched(ss, sub, txID, tx)
+		delete(sub.watched, txID)
+	})
+}
+
+// onTxConfirmations notifies and removes the txconfirmations subscriptions
+// reaching the confirmations at the given best height.
+func (n *notifier) onTxConfirmations(bestHeight uint32) {
+	n.server.sessions.Foreach(func(ss *session) {
+		for _, sub := range ss.subscriptions(TopicTxConfirmations) {
+			if notification, ok := txConfirmations(sub,
+				bestHeight); ok {
+				n.send(ss, sub, notification)
+				ss.unsubscribe(sub.id, "")
+			}
+		}
+	})
+}
+
+// onTxFinality notifies and removes the txfinality subscriptions of which the
+// transaction is packed at or before the confirmed block height.
+func (n *notifier) onTxFinality(confirmedHeight uint32) {
+	n.server.sessions.Foreach(func(ss *session) {
+		for _, sub := range ss.subscriptions(TopicTxFinality) {
+			_, height, err := servers.Store.GetTransaction(sub.txID)
+			if err != nil || height > confirmedHeight {
+				continue
+			}
+			info, code := servers.GetTransactionStatusInfo(sub.txID)
+			if code != errors.Success || !info.Finalized {
+				continue
+			}
+			n.send(ss, sub, info)
+			ss.unsubscribe(sub.id, "")
+		}
+	})
+}
+
+func (n *notifier) onConfirm(confirm *payload.Confirm) {
+	var info *servers.ConfirmInfo
+	n.server.sessions.Foreach(func(ss *session) {
+		for _, sub := range ss.subscriptions(TopicConfirm) {
+			if info == nil {
+				confirmInfo := servers.GetConfirmInfo(confirm)
+				info = &confirmInfo
+			}
+			n.send(ss, sub, info)
+		}
+	})
+}
+
+// onProducersChanged notifies the producers of which state changed since the
+// last snapshot.
+func (n *notifier) onProducersChanged(height uint32) {
+	producers := n.producerStates()
+	var changes []ProducerNotification
+	for owner, p := range producers {
+		if last, ok := n.producers[owner]; ok && last.state == p.state {
+			continue
+		}
+		changes = append(changes, ProducerNotification{
+			OwnerPublicKey: owner,
+			NodePublicKey:  p.nodePublicKey,
+			OldState:       n.producers[owner].state,
+			NewState:       p.state,
+			Height:         height,
+		})
+	}
+	n.producers = producers
+	if len(changes) == 0 {
+		return
+	}
+
+	n.server.sessions.Foreach(func(ss *session) {
+		for _, sub := range ss.subscriptions(TopicProducer) {
+			for i := range changes {
+				if sub.matchProducer(changes[i].OwnerPublicKey,
+					changes[i].NodePublicKey) {
+					n.send(ss, sub, &changes[i])
+				}
+			}
+		}
+	})
+}
+
+// onArbitersChanged notifies the arbiters information if the arbiters or
+// candidates changed since the last snapshot.
+func (n *notifier) onArbitersChanged() {
+	arbiters := n.arbitersState()
+	if arbiters == n.arbiters {
+		return
+	}
+	n.arbiters = arbiters
+
+	var info interface{}
+	n.server.sessions.Foreach(func(ss *session) {
+		for _, sub := range ss.subscriptions(TopicArbiters) {
+			if info == nil {
+				info = servers.GetArbitersInfo(nil)["Result"]
+			}
+			n.send(ss, sub, info)
+		}
+	})
+}
+
+func (n *notifier) producerStates() map[string]producerState {
+	producers := make(map[string]producerState)
+	if servers.Chain == nil {
+		return producers
+	}
+	for _, p := range servers.Chain.GetState().GetAllProducers() {
+		producers[common.BytesToHexString(p.OwnerPublicKey())] = producerState{
+			nodePublicKey: common.BytesToHexString(p.NodePublicKey()),
+			state:         p.State().String(),
+		}
+	}
+	return producers
+}
+
+// arbitersState returns the current arbiters and candidates as a string to be
+// compared with the last snapshot.
+func (n *notifier) arbitersState() string {
+	if servers.Arbiters == nil {
+		return ""
+	}
+	var keys []string
+	for _, a := range servers.Arbiters.GetArbitrators() {
+		keys = append(keys, common.BytesToHexString(a))
+	}
+	keys = append(keys, "")
+	for _, c := range servers.Arbiters.GetCandidates() {
+		keys = append(keys, common.BytesToHexString(c))
+	}
+	return strings.Join(keys, ",")
+}
+
+// txConfirmations returns the notification if the transaction of the
+// subscription reaches the confirmations at the given best height.
+func txConfirmations(sub *subscription,
+	bestHeight uint32) (*TxConfirmationsNotification, bool) {
+	_, height, err := servers.Store.GetTransaction(sub.txID)
+	if err != nil || height > bestHeight {
+		return nil, false
+	}
+	confirmations := bestHeight - height + 1
+	if confirmations < sub.confirmations {
+		return nil, false
+	}
+	return &TxConfirmationsNotification{
+		TxID:          servers.ToReversedString(sub.txID),
+		Height:        height,
+		Confirmations: confirmations,
+	}, true
+}
+
+// txProgramHashes returns the program hashes of the outputs and the referenced
+// outputs of inputs of the transaction.
+func txProgramHashes(tx *types.Transaction) (outputs,
+	inputs map[common.Uint168]struct{}) {
+	outputs = make(map[common.Uint168]struct{}, len(tx.Outputs))
+	for _, output := range tx.Outputs {
+		outputs[output.ProgramHash] = struct{}{}
+	}
+
+	inputs = make(map[common.Uint168]struct{}, len(tx.Inputs))
+	if tx.IsCoinBaseTx() {
+		return outputs, inputs
+	}
+	for _, input := range tx.Inputs {
+		prev := servers.TxMemPool.GetTransaction(input.Previous.TxID)
+		if prev == nil {
+			var err error
+			prev, _, err = servers.Store.GetTransaction(input.Previous.TxID)
+			if err != nil {
+				continue
+			}
+		}
+		if int(input.Previous.Index) < len(prev.Outputs) {
+			inputs[prev.Outputs[input.Previous.Index].ProgramHash] = struct{}{}
+		}
+	}
+	return outputs, inputs
+}
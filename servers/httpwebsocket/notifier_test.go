@@ -0,0 +1,528 @@
+package httpwebsocket
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elastos/Elastos.ELA/blockchain"
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/config"
+	"github.com/elastos/Elastos.ELA/common/log"
+	"github.com/elastos/Elastos.ELA/core/contract"
+	"github.com/elastos/Elastos.ELA/core/contract/program"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/dpos/state"
+	elaErr "github.com/elastos/Elastos.ELA/errors"
+	"github.com/elastos/Elastos.ELA/events"
+	"github.com/elastos/Elastos.ELA/mempool"
+	"github.com/elastos/Elastos.ELA/servers"
+	"github.com/elastos/Elastos.ELA/utils/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockStore is a chain store keeps the blocks of the main chain in memory.
+type mockStore struct {
+	blockchain.IChainStore
+	headers  []*types.Header
+	txs      map[common.Uint256]*types.Transaction
+	heights  map[common.Uint256]uint32
+	confirms map[common.Uint256]*payload.Confirm
+}
+
+func (s *mockStore) GetHeight() uint32 {
+	return uint32(len(s.headers) - 1)
+}
+
+func (s *mockStore) GetBlockHash(height uint32) (common.Uint256, error) {
+	if int(height) >= len(s.headers) {
+		return common.EmptyHash, errors.New("block not found")
+	}
+	return s.headers[height].Hash(), nil
+}
+
+func (s *mockStore) GetHeader(hash common.Uint256) (*types.Header, error) {
+	for _, header := range s.headers {
+		if header.Hash().IsEqual(hash) {
+			return header, nil
+		}
+	}
+	return nil, errors.New("header not found")
+}
+
+func (s *mockStore) GetTransaction(
+	txID common.Uint256) (*types.Transaction, uint32, error) {
+	tx, ok := s.txs[txID]
+	if !ok {
+		return nil, 0, errors.New("transaction not found")
+	}
+	return tx, s.heights[txID], nil
+}
+
+func (s *mockStore) GetConfirm(hash common.Uint256) (*payload.Confirm, error) {
+	confirm, ok := s.confirms[hash]
+	if !ok {
+		return nil, errors.New("confirm not found")
+	}
+	return confirm, nil
+}
+
+// connect appends a block of the transactions to the main chain.
+func (s *mockStore) connect(txs ...*types.Transaction) *types.Block {
+	block := &types.Block{
+		Header: types.Header{
+			Previous: s.headers[len(s.headers)-1].Hash(),
+			Height:   uint32(len(s.headers)),
+		},
+		Transactions: txs,
+	}
+	s.headers = append(s.headers, &block.Header)
+	for _, tx := range txs {
+		s.txs[tx.Hash()] = tx
+		s.heights[tx.Hash()] = block.Height
+	}
+	return block
+}
+
+// disconnect removes the best block from the main chain.
+func (s *mockStore) disconnect(block *types.Block) {
+	s.headers = s.headers[:len(s.headers)-1]
+	for _, tx := range block.Transactions {
+		delete(s.txs, tx.Hash())
+		delete(s.heights, tx.Hash())
+	}
+}
+
+// newTestNotifier creates a notifier of the chain in the mock store, the
+// blocks before height 1 will not be confirmed.
+func newTestNotifier(t *testing.T) (*notifier, *mockStore) {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+
+	params := config.DefaultParams
+	params.CRCOnlyDPOSHeight = 1
+	store := &mockStore{
+		headers:  []*types.Header{&params.GenesisBlock.Header},
+		txs:      make(map[common.Uint256]*types.Transaction),
+		heights:  make(map[common.Uint256]uint32),
+		confirms: make(map[common.Uint256]*payload.Confirm),
+	}
+	chain, err := blockchain.New(store, &params, state.NewState(&params, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainParams, originChain, originStore := servers.ChainParams,
+		servers.Chain, servers.Store
+	txMemPool, arbiters := servers.TxMemPool, servers.Arbiters
+	servers.ChainParams = &params
+	servers.Chain = chain
+	servers.Store = store
+	servers.TxMemPool = mempool.NewTxPool(&params)
+	servers.Arbiters = nil
+	t.Cleanup(func() {
+		servers.ChainParams, servers.Chain, servers.Store = chainParams,
+			originChain, originStore
+		servers.TxMemPool, servers.Arbiters = txMemPool, arbiters
+	})
+
+	n := &notifier{
+		server: &Server{sessions: &sessions{}},
+		wake:   make(chan struct{}, 1),
+	}
+	return n, store
+}
+
+// dispatch notifies the events and waits until they are dispatched.
+func dispatch(n *notifier, es ...*events.Event) {
+	for _, e := range es {
+		n.notify(e)
+	}
+	close(n.wake)
+	n.start()
+	n.wake = make(chan struct{}, 1)
+}
+
+// newTestSession adds a session subscribed the given topics.
+func newTestSession(t *testing.T, n *notifier, id int64,
+	subs ...servers.Params) *session {
+	ss := newSession(id, nil)
+	for _, params := range subs {
+		resp := n.server.subscribe(ss, params)
+		if !assert.Equal(t, elaErr.Success, resp["Error"]) {
+			t.FailNow()
+		}
+	}
+	n.server.sessions.Store(id, ss)
+	return ss
+}
+
+// notification is a message sent to a session by the notifier.
+type notification struct {
+	Action       string
+	Subscription uint32
+	Result       map[string]interface{}
+}
+
+// received returns the notifications sent to the session since last called.
+func received(t *testing.T, ss *session) []notification {
+	var ns []notification
+	for {
+		select {
+		case data := <-ss.sendQueue:
+			var n notification
+			if err := json.Unmarshal(data, &n); err != nil {
+				t.Fatal(err)
+			}
+			ns = append(ns, n)
+		default:
+			return ns
+		}
+	}
+}
+
+func testAddress(n byte) (common.Uint168, string) {
+	programHash := common.Uint168{byte(contract.PrefixStandard), n}
+	address, _ := programHash.ToAddress()
+	return programHash, address
+}
+
+// newTestTx creates a transaction to the given program hash, the nonce makes
+// the transactions different.
+func newTestTx(nonce uint32, to common.Uint168) *types.Transaction {
+	return &types.Transaction{
+		TxType:   types.TransferAsset,
+		Payload:  &payload.TransferAsset{},
+		LockTime: nonce,
+		Outputs: []*types.Output{{
+			AssetID:     config.ELAAssetID,
+			Value:       common.Fixed64(nonce),
+			ProgramHash: to,
+		}},
+		Attributes: []*types.Attribute{},
+		Programs:   []*program.Program{},
+	}
+}
+
+func TestNotifier_Topics(t *testing.T) {
+	n, store := newTestNotifier(t)
+	hash1, addr1 := testAddress(1)
+	_, addr2 := testAddress(2)
+	tx := newTestTx(1, hash1)
+	txID := servers.ToReversedString(tx.Hash())
+
+	legacy := newTestSession(t, n, 1)
+	address1 := newTestSession(t, n, 2, servers.Params{
+		"topic":     TopicAddress,
+		"addresses": []interface{}{addr1},
+	})
+	address2 := newTestSession(t, n, 3, servers.Params{
+		"topic":     TopicAddress,
+		"addresses": []interface{}{addr2},
+	})
+	outgoing := newTestSession(t, n, 4, servers.Params{
+		"topic":     TopicAddress,
+		"addresses": []interface{}{addr1},
+		"direction": DirectionOutgoing,
+	})
+	confirm := newTestSession(t, n, 5, servers.Params{
+		"topic": TopicConfirm,
+	})
+	confirmations := newTestSession(t, n, 6, servers.Params{
+		"topic":         TopicTxConfirmations,
+		"txid":          txID,
+		"confirmations": float64(2),
+	})
+
+	// the transaction is only notified to the subscribed address and
+	// direction
+	dispatch(n, &events.Event{Type: events.ETTransactionAccepted, Data: tx})
+	ns := received(t, address1)
+	if assert.Len(t, ns, 1) {
+		assert.Equal(t, TopicAddress, ns[0].Action)
+		assert.Equal(t, uint32(1), ns[0].Subscription)
+		assert.Equal(t, txID, ns[0].Result["txid"])
+		assert.Equal(t, servers.TxStatusPending, ns[0].Result["status"])
+		assert.Equal(t, []interface{}{addr1}, ns[0].Result["incoming"])
+	}
+	for _, ss := range []*session{legacy, address2, outgoing, confirm,
+		confirmations} {
+		assert.Empty(t, received(t, ss))
+	}
+
+	// the confirm is only notified to the confirm topic
+	c := &payload.Confirm{Proposal: payload.DPOSProposal{
+		Sponsor:   []byte{0x02, 0x01},
+		BlockHash: common.Uint256{1},
+	}}
+	dispatch(n, &events.Event{Type: events.ETConfirmAccepted, Data: c})
+	ns = received(t, confirm)
+	if assert.Len(t, ns, 1) {
+		assert.Equal(t, TopicConfirm, ns[0].Action)
+		assert.Equal(t, "0201", ns[0].Result["sponsor"])
+	}
+	for _, ss := range []*session{legacy, address1, address2, outgoing,
+		confirmations} {
+		assert.Empty(t, received(t, ss))
+	}
+
+	// the txconfirmations is notified once when it reaches the confirmations
+	dispatch(n, &events.Event{Type: events.ETBlockConnected,
+		Data: store.connect(tx)})
+	assert.Empty(t, received(t, confirmations))
+	dispatch(n, &events.Event{Type: events.ETBlockConnected,
+		Data: store.connect()})
+	ns = received(t, confirmations)
+	if assert.Len(t, ns, 1) {
+		assert.Equal(t, TopicTxConfirmations, ns[0].Action)
+		assert.Equal(t, txID, ns[0].Result["txid"])
+		assert.Equal(t, float64(2), ns[0].Result["confirmations"])
+	}
+	assert.Empty(t, confirmations.subscriptions(""))
+	dispatch(n, &events.Event{Type: events.ETBlockConnected,
+		Data: store.connect()})
+	assert.Empty(t, received(t, confirmations))
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	n, _ := newTestNotifier(t)
+
+	// the events not notified by the websocket are ignored
+	n.notify(&events.Event{Type: events.ETNewBlockReceived})
+	assert.Empty(t, n.pending)
+
+	// the block events are notified with the chain locked, so the notify
+	// returns even if the notifier is not dispatching
+	block := &types.Block{}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < notifierBacklogSize*2; i++ {
+			n.notify(&events.Event{Type: events.ETBlockConnected,
+				Data: block})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notify is blocked by the pending events")
+	}
+
+	// no event is dropped
+	assert.Len(t, n.pending, notifierBacklogSize*2)
+	assert.Len(t, n.wake, 1)
+}
+
+func TestSession_Send(t *testing.T) {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+	ss := newSession(1, nil)
+	for i := 0; i < sessionQueueSize; i++ {
+		assert.NoError(t, ss.Send([]byte("data")))
+	}
+
+	// the slow session is closed instead of blocking the sender
+	assert.Error(t, ss.Send([]byte("data")))
+	select {
+	case <-ss.quit:
+	default:
+		t.Error("slow session is not closed")
+	}
+	assert.Equal(t, errSessionClosed, ss.Send([]byte("data")))
+	assert.Equal(t, sessionQueueSize, len(ss.sendQueue))
+}
+
+func TestNotifier_WatchedAddress(t *testing.T) {
+	n, store := newTestNotifier(t)
+	hash, addr := testAddress(1)
+	ss := newTestSession(t, n, 1, servers.Params{
+		"topic":     TopicAddress,
+		"addresses": []interface{}{addr},
+	})
+	sub := ss.subscriptions(TopicAddress)[0]
+
+	assertStatus := func(tx *types.Transaction, status string,
+		height uint32) {
+		ns := received(t, ss)
+		if assert.Len(t, ns, 1) {
+			assert.Equal(t, servers.ToReversedString(tx.Hash()),
+				ns[0].Result["txid"])
+			assert.Equal(t, status, ns[0].Result["status"])
+			assert.Equal(t, float64(height), ns[0].Result["height"])
+			assert.Equal(t, []interface{}{addr}, ns[0].Result["incoming"])
+		}
+	}
+
+	// pending -> mined -> finalized
+	tx1 := newTestTx(1, hash)
+	dispatch(n, &events.Event{Type: events.ETTransactionAccepted, Data: tx1})
+	assertStatus(tx1, servers.TxStatusPending, 0)
+	block1 := store.connect(tx1)
+	dispatch(n, &events.Event{Type: events.ETBlockConnected, Data: block1})
+	assertStatus(tx1, servers.TxStatusMined, 1)
+
+	// the accepted event arrives after the transaction mined
+	dispatch(n, &events.Event{Type: events.ETTransactionAccepted, Data: tx1})
+	assert.Empty(t, received(t, ss))
+
+	dispatch(n, &events.Event{Type: events.ETBlockConfirmAccepted,
+		Data: block1})
+	assertStatus(tx1, servers.TxStatusFinalized, 1)
+	assert.Empty(t, sub.watched)
+
+	// pending -> dropped, neither in the mempool nor in the chain
+	tx2 := newTestTx(2, hash)
+	dispatch(n, &events.Event{Type: events.ETTransactionAccepted, Data: tx2})
+	assertStatus(tx2, servers.TxStatusPending, 0)
+	dispatch(n, &events.Event{Type: events.ETBlockConnected,
+		Data: store.connect()})
+	assertStatus(tx2, servers.TxStatusDropped, 0)
+	assert.Empty(t, sub.watched)
+
+	// mined -> reorged -> mined again in another block -> finalized
+	tx3 := newTestTx(3, hash)
+	block3 := store.connect(tx3)
+	dispatch(n, &events.Event{Type: events.ETBlockConnected, Data: block3})
+	assertStatus(tx3, servers.TxStatusMined, 3)
+	store.disconnect(block3)
+	dispatch(n, &events.Event{Type: events.ETBlockDisconnected, Data: block3})
+	assertStatus(tx3, servers.TxStatusReorged, 3)
+	if assert.Contains(t, sub.watched, tx3.Hash()) {
+		assert.Equal(t, servers.TxStatusPending,
+			sub.watched[tx3.Hash()].status)
+	}
+
+	// the confirm of the disconnected block changes nothing
+	dispatch(n, &events.Event{Type: events.ETBlockConfirmAccepted,
+		Data: block3})
+	assert.Empty(t, received(t, ss))
+
+	store.connect()
+	block4 := store.connect(tx3)
+	dispatch(n, &events.Event{Type: events.ETBlockConnected, Data: block4})
+	assertStatus(tx3, servers.TxStatusMined, 4)
+	dispatch(n, &events.Event{Type: events.ETBlockConfirmAccepted,
+		Data: block4})
+	assertStatus(tx3, servers.TxStatusFinalized, 4)
+	assert.Empty(t, sub.watched)
+
+	// mined -> reorged -> dropped
+	tx4 := newTestTx(4, hash)
+	block5 := store.connect(tx4)
+	dispatch(n, &events.Event{Type: events.ETBlockConnected, Data: block5})
+	assertStatus(tx4, servers.TxStatusMined, 5)
+	store.disconnect(block5)
+	dispatch(n, &events.Event{Type: events.ETBlockDisconnected, Data: block5})
+	assertStatus(tx4, servers.TxStatusReorged, 5)
+	dispatch(n, &events.Event{Type: events.ETBlockConnected,
+		Data: store.connect()})
+	assertStatus(tx4, servers.TxStatusDropped, 0)
+	assert.Empty(t, sub.watched)
+
+	// mined -> finalized by the confirm of a later block
+	tx5 := newTestTx(5, hash)
+	block6 := store.connect(tx5)
+	dispatch(n, &events.Event{Type: events.ETBlockConnected, Data: block6})
+	assertStatus(tx5, servers.TxStatusMined, 6)
+	dispatch(n, &events.Event{Type: events.ETBlockConfirmAccepted,
+		Data: store.connect()})
+	assertStatus(tx5, servers.TxStatusFinalized, 6)
+	assert.Empty(t, sub.watched)
+
+	// the transactions mined before DPoS are not tracked
+	n2, store2 := newTestNotifier(t)
+	ss2 := newTestSession(t, n2, 1, servers.Params{
+		"topic":     TopicAddress,
+		"addresses": []interface{}{addr},
+	})
+	servers.ChainParams.CRCOnlyDPOSHeight = 2
+	dispatch(n2, &events.Event{Type: events.ETBlockConnected,
+		Data: store2.connect(tx1)})
+	ns := received(t, ss2)
+	if assert.Len(t, ns, 1) {
+		assert.Equal(t, servers.TxStatusMined, ns[0].Result["status"])
+	}
+	assert.Empty(t, ss2.subscriptions(TopicAddress)[0].watched)
+}
+
+func TestNotifier_TxFinality(t *testing.T) {
+	n, store := newTestNotifier(t)
+	hash, _ := testAddress(1)
+	tx1, tx2, tx3 := newTestTx(1, hash), newTestTx(2, hash),
+		newTestTx(3, hash)
+	subscribe := func(id int64, tx *types.Transaction) *session {
+		return newTestSession(t, n, id, servers.Params{
+			"topic": TopicTxFinality,
+			"txid":  servers.ToReversedString(tx.Hash()),
+		})
+	}
+	finality1, finality2, finality3 := subscribe(1, tx1), subscribe(2, tx2),
+		subscribe(3, tx3)
+
+	confirm := func(block *types.Block) {
+		store.confirms[block.Hash()] = &payload.Confirm{
+			Proposal: payload.DPOSProposal{
+				Sponsor:   []byte{0x02, 0x01},
+				BlockHash: block.Hash(),
+			},
+			Votes: []payload.DPOSProposalVote{
+				{Signer: []byte{0x02, 0x01}, Accept: true},
+				{Signer: []byte{0x02, 0x02}, Accept: true},
+				{Signer: []byte{0x02, 0x03}, Accept: false},
+			},
+		}
+		dispatch(n, &events.Event{Type: events.ETBlockConfirmAccepted,
+			Data: block})
+	}
+
+	block1 := store.connect(tx1)
+	block2 := store.connect(tx2)
+	dispatch(n, &events.Event{Type: events.ETBlockConnected, Data: block1},
+		&events.Event{Type: events.ETBlockConnected, Data: block2})
+	for _, ss := range []*session{finality1, finality2, finality3} {
+		assert.Empty(t, received(t, ss))
+	}
+
+	// the transaction in the confirmed block is finalized
+	confirm(block1)
+	ns := received(t, finality1)
+	if assert.Len(t, ns, 1) {
+		assert.Equal(t, TopicTxFinality, ns[0].Action)
+		result := ns[0].Result
+		assert.Equal(t, servers.ToReversedString(tx1.Hash()), result["txid"])
+		assert.Equal(t, servers.TxStatusFinalized, result["status"])
+		assert.Equal(t, float64(1), result["height"])
+		assert.Equal(t, float64(2), result["confirmations"])
+		assert.Equal(t, true, result["confirmed"])
+		assert.Equal(t, true, result["finalized"])
+		assert.Equal(t, "0201", result["sponsor"])
+		assert.Equal(t, []interface{}{"0201", "0202"}, result["signers"])
+	}
+	assert.Empty(t, finality1.subscriptions(""))
+
+	// the transactions after the confirmed block are not finalized
+	assert.Empty(t, received(t, finality2))
+	assert.Len(t, finality2.subscriptions(""), 1)
+
+	// the transaction is finalized by the confirm of a later block
+	block3 := store.connect()
+	dispatch(n, &events.Event{Type: events.ETBlockConnected, Data: block3})
+	confirm(block3)
+	ns = received(t, finality2)
+	if assert.Len(t, ns, 1) {
+		result := ns[0].Result
+		assert.Equal(t, servers.ToReversedString(tx2.Hash()), result["txid"])
+		assert.Equal(t, servers.TxStatusFinalized, result["status"])
+		assert.Equal(t, float64(2), result["height"])
+		assert.Equal(t, false, result["confirmed"])
+		assert.Equal(t, true, result["finalized"])
+		assert.Equal(t, []interface{}{}, result["signers"])
+	}
+	assert.Empty(t, finality2.subscriptions(""))
+
+	// the transaction not in the chain is still being waited
+	assert.Empty(t, received(t, finality1))
+	assert.Empty(t, received(t, finality3))
+	assert.Len(t, finality3.subscriptions(""), 1)
+}
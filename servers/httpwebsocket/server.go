@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -37,18 +38,33 @@ var (
 
 type Handler func(servers.Params) map[string]interface{}
 
+// sessionHandler is the handler of methods bound to the requesting session.
+type sessionHandler func(*session, servers.Params) map[string]interface{}
+
 type Server struct {
 	sync.RWMutex
 	*http.Server
 	net.Listener
 	websocket.Upgrader
 
-	connCount int64
-	sessions  *sessions
-	handlers  map[string]Handler
+	connCount       int64
+	sessions        *sessions
+	handlers        map[string]Handler
+	sessionHandlers map[string]sessionHandler
+	notifier        *notifier
 }
 
 func Start() {
+	instance = &Server{
+		Upgrader: websocket.Upgrader{},
+		sessions: &sessions{},
+	}
+	instance.notifier = &notifier{
+		server: instance,
+		wake:   make(chan struct{}, 1),
+	}
+	go instance.notifier.start()
+
 	events.Subscribe(func(e *events.Event) {
 		switch e.Type {
 		case events.ETBlockConnected:
@@ -57,12 +73,9 @@ func Start() {
 		case events.ETTransactionAccepted:
 			SendTx2Client(e.Data)
 		}
+		instance.notifier.notify(e)
 	})
 
-	instance = &Server{
-		Upgrader: websocket.Upgrader{},
-		sessions: &sessions{},
-	}
 	instance.Start()
 }
 
@@ -108,6 +121,11 @@ func (s *Server) initMethods() {
 		"heartbeat":          s.heartBeat,
 		"getsessioncount":    s.getSessionCount,
 	}
+	s.sessionHandlers = map[string]sessionHandler{
+		"subscribe":        s.subscribe,
+		"unsubscribe":      s.unsubscribe,
+		"getsubscriptions": s.getSubscriptions,
+	}
 }
 
 func (s *Server) heartBeat(cmd servers.Params) map[string]interface{} {
@@ -118,6 +136,35 @@ func (s *Server) getSessionCount(cmd servers.Params) map[string]interface{} {
 	return servers.ResponsePack(errors.Success, s.sessions.Count())
 }
 
+func (s *Server) subscribe(ss *session, cmd servers.Params) map[string]interface{} {
+	sub, err := newSubscription(cmd)
+	if err != nil {
+		return servers.ResponsePack(errors.InvalidParams, err.Error())
+	}
+	if err := ss.subscribe(sub); err != nil {
+		return servers.ResponsePack(errors.InvalidParams, err.Error())
+	}
+	return servers.ResponsePack(errors.Success, sub.info())
+}
+
+func (s *Server) unsubscribe(ss *session, cmd servers.Params) map[string]interface{} {
+	id, _ := cmd.Uint("id")
+	topic, _ := cmd.String("topic")
+	return servers.ResponsePack(errors.Success, ss.unsubscribe(id, topic))
+}
+
+func (s *Server) getSubscriptions(ss *session, cmd servers.Params) map[string]interface{} {
+	subs := ss.subscriptions("")
+	result := make([]SubscriptionInfo, 0, len(subs))
+	for _, sub := range subs {
+		result = append(result, sub.info())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return servers.ResponsePack(errors.Success, result)
+}
+
 func (s *Server) Stop() {
 	s.Shutdown(context.Background())
 	log.Info("Close websocket ")
@@ -154,11 +201,8 @@ func (s *Server) Handler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer conn.Close()
 
-	ss := &session{
-		id:         atomic.AddInt64(&s.connCount, 1),
-		conn:       conn,
-		lastActive: time.Now(),
-	}
+	ss := newSession(atomic.AddInt64(&s.connCount, 1), conn)
+	go ss.writeHandler()
 	s.sessions.Store(ss.id, ss)
 
 	defer func() {
@@ -202,6 +246,8 @@ func (s *Server) IsValidMsg(action string, reqMsg map[string]interface{}) bool {
 		}
 	case "sendrawtransaction":
 		_, valid = reqMsg["data"]
+	case "subscribe":
+		_, valid = reqMsg["topic"]
 	}
 	return valid
 }
@@ -223,9 +269,15 @@ func (s *Server) handle(ss *session, bysMsg []byte, r *http.Request) bool {
 	}
 	handler, ok := s.handlers[action]
 	if !ok {
-		resp := servers.ResponsePack(errors.InvalidMethod, "")
-		s.response(ss, resp)
-		return false
+		sessionHandler, ok := s.sessionHandlers[action]
+		if !ok {
+			resp := servers.ResponsePack(errors.InvalidMethod, "")
+			s.response(ss, resp)
+			return false
+		}
+		handler = func(cmd servers.Params) map[string]interface{} {
+			return sessionHandler(ss, cmd)
+		}
 	}
 	if !s.IsValidMsg(action, req) {
 		resp := servers.ResponsePack(errors.InvalidParams, "")
@@ -233,6 +285,14 @@ func (s *Server) handle(ss *session, bysMsg []byte, r *http.Request) bool {
 		return true
 	}
 
+	release, code := servers.AcquireIP(r.RemoteAddr, action)
+	if code != errors.Success {
+		resp := servers.ResponsePack(code, "")
+		resp["Action"] = action
+		s.response(ss, resp)
+		return true
+	}
+	defer release()
 	resp := handler(req)
 	resp["Action"] = action
 
@@ -311,8 +371,12 @@ func (s *Server) PushResult(action string, v interface{}) {
 		return
 	}
 
-	// Broadcast message to all connected clients.
+	// Broadcast message to the clients without subscriptions and the clients
+	// subscribed the action.
 	s.sessions.Foreach(func(v *session) {
+		if v.subscribed() && len(v.subscriptions(action)) == 0 {
+			return
+		}
 		v.Send(data)
 	})
 }
@@ -5,25 +5,168 @@ import (
 	"sync"
 	"time"
 
+	"github.com/elastos/Elastos.ELA/common/log"
+
 	"github.com/gorilla/websocket"
 )
 
+const (
+	// sessionQueueSize is the size of the messages queue waiting to be sent
+	// to a session, the session is taken as a slow client and closed if the
+	// queue is full.
+	sessionQueueSize = 256
+
+	// writeTimeout is the timeout of writing a message to a session.
+	writeTimeout = 10 * time.Second
+)
+
+// errSessionClosed is returned when sending to a closed session.
+var errSessionClosed = errors.New("session closed")
+
 type session struct {
-	mtx        sync.Mutex
 	id         int64
 	conn       *websocket.Conn
 	lastActive time.Time
+
+	sendQueue chan []byte
+	quit      chan struct{}
+	closeOnce sync.Once
+
+	subMtx    sync.RWMutex
+	subs      map[uint32]*subscription
+	lastSubID uint32
 }
 
+// Send queues the message to be sent to the session, it never blocks. The
+// session will be closed if the messages are not sent in time.
 func (s *session) Send(data []byte) error {
-	if s.conn == nil {
-		return errors.New("WebSocket is null")
+	select {
+	case <-s.quit:
+		return errSessionClosed
+	default:
+	}
+
+	select {
+	case s.sendQueue <- data:
+		return nil
+	default:
+		log.Warnf("websocket session %d is too slow, close it", s.id)
+		s.close()
+		return errors.New("send queue is full")
+	}
+}
+
+// writeHandler writes the queued messages to the connection, and closes the
+// connection after the session closed.
+func (s *session) writeHandler() {
+	defer s.conn.Close()
+	for {
+		select {
+		case data := <-s.sendQueue:
+			if err := s.write(data); err != nil {
+				s.close()
+				return
+			}
+
+		case <-s.quit:
+			// flush the messages queued before closed, such as the session
+			// expired response.
+			for {
+				select {
+				case data := <-s.sendQueue:
+					if err := s.write(data); err != nil {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *session) write(data []byte) error {
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// close stops sending messages to the session, the connection will be closed
+// by the write handler.
+func (s *session) close() {
+	s.closeOnce.Do(func() {
+		close(s.quit)
+	})
+}
+
+func newSession(id int64, conn *websocket.Conn) *session {
+	return &session{
+		id:         id,
+		conn:       conn,
+		lastActive: time.Now(),
+		sendQueue:  make(chan []byte, sessionQueueSize),
+		quit:       make(chan struct{}),
+	}
+}
+
+// subscribe adds the subscription to the session and assigns it an id.
+func (s *session) subscribe(sub *subscription) error {
+	s.subMtx.Lock()
+	defer s.subMtx.Unlock()
+
+	if len(s.subs) >= maxSubscriptions {
+		return errors.New("too many subscriptions")
+	}
+	if s.subs == nil {
+		s.subs = make(map[uint32]*subscription)
 	}
+	s.lastSubID++
+	sub.id = s.lastSubID
+	s.subs[sub.id] = sub
+	return nil
+}
+
+// unsubscribe removes the subscriptions matching the given id and topic, zero
+// id or empty topic matches all. The count of removed subscriptions will be
+// returned.
+func (s *session) unsubscribe(id uint32, topic string) int {
+	s.subMtx.Lock()
+	defer s.subMtx.Unlock()
+
+	count := 0
+	for k, sub := range s.subs {
+		if id != 0 && sub.id != id {
+			continue
+		}
+		if topic != "" && sub.topic != topic {
+			continue
+		}
+		delete(s.subs, k)
+		count++
+	}
+	return count
+}
+
+// subscriptions returns the subscriptions of the given topic, all
+// subscriptions will be returned if topic is empty.
+func (s *session) subscriptions(topic string) []*subscription {
+	s.subMtx.RLock()
+	defer s.subMtx.RUnlock()
+
+	subs := make([]*subscription, 0, len(s.subs))
+	for _, sub := range s.subs {
+		if topic == "" || sub.topic == topic {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
 
-	s.mtx.Lock()
-	err := s.conn.WriteMessage(websocket.TextMessage, data)
-	s.mtx.Unlock()
-	return err
+// subscribed returns if the session has any subscription, a session without
+// subscription receives all legacy pushes.
+func (s *session) subscribed() bool {
+	s.subMtx.RLock()
+	defer s.subMtx.RUnlock()
+	return len(s.subs) > 0
 }
 
 type sessions struct {
@@ -36,7 +179,7 @@ func (ss *sessions) Load(id int64) (*session, bool) {
 }
 
 func (ss *sessions) Delete(s *session) {
-	s.conn.Close()
+	s.close()
 	ss.Map.Delete(s.id)
 }
 
@@ -0,0 +1,254 @@
+package httpwebsocket
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/servers"
+)
+
+const (
+	// TopicAddress notifies the transactions sending to or spending from the
+	// subscribed addresses.
+	TopicAddress = "address"
+
+	// TopicTxConfirmations notifies once when the subscribed transaction
+	// reaches the given confirmations.
+	TopicTxConfirmations = "txconfirmations"
+
+	// TopicTxFinality notifies once when the block of the subscribed
+	// transaction is finalized by DPoS.
+	TopicTxFinality = "txfinality"
+
+	// TopicConfirm notifies the DPoS confirms of new blocks.
+	TopicConfirm = "confirm"
+
+	// TopicProducer notifies the state changes of producers.
+	TopicProducer = "producer"
+
+	// TopicArbiters notifies the changes of the arbiters and candidates.
+	TopicArbiters = "arbiters"
+)
+
+const (
+	// DirectionAll matches both incoming and outgoing transactions.
+	DirectionAll = "all"
+
+	// DirectionIncoming matches the transactions with outputs to the address.
+	DirectionIncoming = "incoming"
+
+	// DirectionOutgoing matches the transactions with inputs from the address.
+	DirectionOutgoing = "outgoing"
+)
+
+const (
+	// maxSubscriptions is the maximum subscriptions of a session.
+	maxSubscriptions = 64
+
+	// maxSubscribedAddresses is the maximum addresses of a subscription.
+	maxSubscribedAddresses = 1000
+
+	// maxWatchedTxs is the maximum transactions tracked by an address
+	// subscription until they are confirmed or dropped.
+	maxWatchedTxs = 10000
+)
+
+// legacyTopics is the legacy push actions which can be subscribed as topics.
+var legacyTopics = map[string]struct{}{
+	"sendrawblock":          {},
+	"sendblocktransactions": {},
+	"sendnewtransaction":    {},
+}
+
+// subscription is a topic subscribed by a session with the filters.
+type subscription struct {
+	id    uint32
+	topic string
+
+	// filters of the address topic.
+	addresses map[common.Uint168]string
+	direction string
+	watched   map[common.Uint256]*watchedTx
+
+	// filters of the txconfirmations and txfinality topics.
+	txID          common.Uint256
+	confirmations uint32
+
+	// filters of the producer topic, empty means all producers.
+	publicKeys map[string]struct{}
+}
+
+// watchedTx is a transaction of the subscribed addresses tracked until it's
+// confirmed or dropped, it's only accessed by the notifier.
+type watchedTx struct {
+	status    string
+	blockHash common.Uint256
+	height    uint32
+	incoming  []string
+	outgoing  []string
+}
+
+// watch tracks the transaction with the given status, a transaction will not
+// be tracked if too many transactions are being tracked.
+func (sub *subscription) watch(txID common.Uint256, tx *watchedTx) {
+	if _, ok := sub.watched[txID]; !ok && len(sub.watched) >= maxWatchedTxs {
+		return
+	}
+	sub.watched[txID] = tx
+}
+
+// SubscriptionInfo is the information of a subscription.
+type SubscriptionInfo struct {
+	ID            uint32   `json:"id"`
+	Topic         string   `json:"topic"`
+	Addresses     []string `json:"addresses,omitempty"`
+	Direction     string   `json:"direction,omitempty"`
+	TxID          string   `json:"txid,omitempty"`
+	Confirmations uint32   `json:"confirmations,omitempty"`
+	PublicKeys    []string `json:"publickeys,omitempty"`
+}
+
+func (sub *subscription) info() SubscriptionInfo {
+	info := SubscriptionInfo{
+		ID:        sub.id,
+		Topic:     sub.topic,
+		Direction: sub.direction,
+	}
+	for _, addr := range sub.addresses {
+		info.Addresses = append(info.Addresses, addr)
+	}
+	sort.Strings(info.Addresses)
+	switch sub.topic {
+	case TopicTxConfirmations:
+		info.TxID = servers.ToReversedString(sub.txID)
+		info.Confirmations = sub.confirmations
+	case TopicTxFinality:
+		info.TxID = servers.ToReversedString(sub.txID)
+	}
+	for pk := range sub.publicKeys {
+		info.PublicKeys = append(info.PublicKeys, pk)
+	}
+	sort.Strings(info.PublicKeys)
+	return info
+}
+
+// matchAddresses returns the subscribed addresses in the given outputs and
+// inputs according to the direction.
+func (sub *subscription) matchAddresses(outputs,
+	inputs map[common.Uint168]struct{}) (incoming, outgoing []string) {
+	for hash, addr := range sub.addresses {
+		if sub.direction != DirectionOutgoing {
+			if _, ok := outputs[hash]; ok {
+				incoming = append(incoming, addr)
+			}
+		}
+		if sub.direction != DirectionIncoming {
+			if _, ok := inputs[hash]; ok {
+				outgoing = append(outgoing, addr)
+			}
+		}
+	}
+	sort.Strings(incoming)
+	sort.Strings(outgoing)
+	return incoming, outgoing
+}
+
+// matchProducer returns if the producer of the public keys is subscribed.
+func (sub *subscription) matchProducer(ownerPublicKey,
+	nodePublicKey string) bool {
+	if len(sub.publicKeys) == 0 {
+		return true
+	}
+	_, owner := sub.publicKeys[ownerPublicKey]
+	_, node := sub.publicKeys[nodePublicKey]
+	return owner || node
+}
+
+// newSubscription creates a subscription of the topic from the request params.
+func newSubscription(params servers.Params) (*subscription, error) {
+	topic, ok := params.String("topic")
+	if !ok {
+		return nil, errors.New("need a string parameter named topic")
+	}
+
+	sub := &subscription{topic: topic}
+	switch topic {
+	case TopicAddress:
+		addresses, ok := params.ArrayString("addresses")
+		if !ok || len(addresses) == 0 {
+			return nil, errors.New("need an array parameter named addresses")
+		}
+		if len(addresses) > maxSubscribedAddresses {
+			return nil, errors.New("too many addresses")
+		}
+		sub.addresses = make(map[common.Uint168]string, len(addresses))
+		sub.watched = make(map[common.Uint256]*watchedTx)
+		for _, addr := range addresses {
+			programHash, err := common.Uint168FromAddress(addr)
+			if err != nil {
+				return nil, errors.New("invalid address: " + addr)
+			}
+			sub.addresses[*programHash] = addr
+		}
+
+		sub.direction, ok = params.String("direction")
+		if !ok {
+			sub.direction = DirectionAll
+		}
+		switch sub.direction {
+		case DirectionAll, DirectionIncoming, DirectionOutgoing:
+		default:
+			return nil, errors.New("invalid direction: " + sub.direction)
+		}
+
+	case TopicTxConfirmations:
+		if err := sub.parseTxID(params); err != nil {
+			return nil, err
+		}
+		sub.confirmations, ok = params.Uint("confirmations")
+		if !ok || sub.confirmations == 0 {
+			sub.confirmations = 1
+		}
+
+	case TopicTxFinality:
+		if err := sub.parseTxID(params); err != nil {
+			return nil, err
+		}
+
+	case TopicProducer:
+		publicKeys, _ := params.ArrayString("publickeys")
+		sub.publicKeys = make(map[string]struct{}, len(publicKeys))
+		for _, pk := range publicKeys {
+			if _, err := common.HexStringToBytes(pk); err != nil {
+				return nil, errors.New("invalid public key: " + pk)
+			}
+			sub.publicKeys[pk] = struct{}{}
+		}
+
+	case TopicConfirm, TopicArbiters:
+
+	default:
+		if _, ok := legacyTopics[topic]; !ok {
+			return nil, errors.New("unknown topic: " + topic)
+		}
+	}
+	return sub, nil
+}
+
+// parseTxID parses the txid parameter of the transaction topics.
+func (sub *subscription) parseTxID(params servers.Params) error {
+	str, ok := params.String("txid")
+	if !ok {
+		return errors.New("need a string parameter named txid")
+	}
+	hashBytes, err := servers.FromReversedString(str)
+	if err != nil {
+		return errors.New("invalid txid")
+	}
+	if err := sub.txID.Deserialize(bytes.NewReader(hashBytes)); err != nil {
+		return errors.New("invalid txid")
+	}
+	return nil
+}
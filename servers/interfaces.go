@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	aux "github.com/elastos/Elastos.ELA/auxpow"
 	"github.com/elastos/Elastos.ELA/blockchain"
@@ -19,6 +21,7 @@ import (
 	"github.com/elastos/Elastos.ELA/core/types/payload"
 	"github.com/elastos/Elastos.ELA/dpos"
 	"github.com/elastos/Elastos.ELA/dpos/state"
+	"github.com/elastos/Elastos.ELA/dpos/store"
 	"github.com/elastos/Elastos.ELA/elanet"
 	"github.com/elastos/Elastos.ELA/elanet/pact"
 	. "github.com/elastos/Elastos.ELA/errors"
@@ -28,15 +31,17 @@ import (
 )
 
 var (
-	Compile   string
-	Config    *config.Configuration
-	Chain     *blockchain.BlockChain
-	Store     blockchain.IChainStore
-	TxMemPool *mempool.TxPool
-	Pow       *pow.Service
-	Server    elanet.Server
-	Arbiter   *dpos.Arbitrator
-	Arbiters  state.Arbitrators
+	Compile     string
+	Config      *config.Configuration
+	ChainParams *config.Params
+	Chain       *blockchain.BlockChain
+	Store       blockchain.IChainStore
+	TxMemPool   *mempool.TxPool
+	Pow         *pow.Service
+	Server      elanet.Server
+	Arbiter     *dpos.Arbitrator
+	Arbiters    state.Arbitrators
+	DposStore   store.IDposStore
 )
 
 func ToReversedString(hash common.Uint256) string {
@@ -154,7 +159,11 @@ func GetRawTransaction(param Params) map[string]interface{} {
 
 	verbose, _ := param.Bool("verbose")
 	if verbose {
-		return ResponsePack(Success, GetTransactionInfo(header, tx))
+		info := GetTransactionInfo(header, tx)
+		if header != nil {
+			info.Finality = GetFinalityInfo(header)
+		}
+		return ResponsePack(Success, info)
 	} else {
 		buf := new(bytes.Buffer)
 		tx.Serialize(buf)
@@ -374,6 +383,93 @@ func GetArbitersInfo(params Params) map[string]interface{} {
 	return ResponsePack(Success, result)
 }
 
+func GetArbiterMetrics(param Params) map[string]interface{} {
+	if Arbiter == nil {
+		return ResponsePack(InternalError, "arbiter disabled")
+	}
+
+	publicKey, _ := param.String("publickey")
+	rounds, ok := param.Uint("rounds")
+	if !ok {
+		rounds = 1
+	}
+
+	result, err := GetArbiterMetricsInfo(publicKey, int(rounds))
+	if err != nil {
+		return ResponsePack(InvalidParams, err.Error())
+	}
+	return ResponsePack(Success, result)
+}
+
+// GetArbiterMetricsInfo returns the metrics of the given arbiter in recent
+// rounds, metrics of all arbiters will be returned if publicKey is empty.
+func GetArbiterMetricsInfo(publicKey string,
+	rounds int) (*ArbiterMetricsInfo, error) {
+	// the public keys of the metrics are in lower case hex strings
+	if publicKey != "" {
+		pk, err := common.HexStringToBytes(publicKey)
+		if err != nil {
+			return nil, errors.New("invalid public key")
+		}
+		publicKey = common.BytesToHexString(pk)
+	}
+
+	windows := Arbiter.GetArbiterMetrics()
+	if rounds > 0 && rounds < len(windows) {
+		windows = windows[len(windows)-rounds:]
+	}
+
+	result := &ArbiterMetricsInfo{
+		MaxInactiveRounds: ChainParams.MaxInactiveRounds,
+		InactiveRounds:    make(map[string]uint32),
+		Rounds:            make([]ArbiterMetricsWindowInfo, 0, len(windows)),
+	}
+	// iterate from the latest round
+	for i := len(windows) - 1; i >= 0; i-- {
+		w := windows[i]
+		info := ArbiterMetricsWindowInfo{
+			StartHeight: w.StartHeight,
+			EndHeight:   w.EndHeight,
+			Arbiters:    make([]ArbiterCountersInfo, 0, len(w.Counters)),
+		}
+		for k, v := range w.Counters {
+			if publicKey != "" && k != publicKey {
+				continue
+			}
+			info.Arbiters = append(info.Arbiters, ArbiterCountersInfo{
+				PublicKey:         k,
+				Proposals:         v.Proposals,
+				VotesOnTime:       v.VotesOnTime,
+				VotesLate:         v.VotesLate,
+				RoundsMissed:      v.RoundsMissed,
+				ViewChanges:       v.ViewChanges,
+				InactivityStrikes: v.InactivityStrikes,
+			})
+		}
+		sort.Slice(info.Arbiters, func(i, j int) bool {
+			return info.Arbiters[i].PublicKey < info.Arbiters[j].PublicKey
+		})
+		result.Rounds = append(result.Rounds, info)
+	}
+
+	height := Store.GetHeight()
+	for _, a := range Arbiters.GetArbitrators() {
+		key := common.BytesToHexString(a)
+		if publicKey != "" && key != publicKey {
+			continue
+		}
+		producer := Chain.GetState().GetProducer(a)
+		if producer == nil || producer.InactiveCountingHeight() == 0 ||
+			producer.InactiveCountingHeight() > height {
+			result.InactiveRounds[key] = 0
+			continue
+		}
+		result.InactiveRounds[key] = height - producer.InactiveCountingHeight()
+	}
+
+	return result, nil
+}
+
 func GetInfo(param Params) map[string]interface{} {
 	RetVal := struct {
 		Version       uint32 `json:"version"`
@@ -645,6 +741,59 @@ func SendRawTransaction(param Params) map[string]interface{} {
 	return ResponsePack(Success, ToReversedString(txn.Hash()))
 }
 
+// TestMempoolAccept runs the checks of the mempool on the transaction without
+// adding it to the mempool or relaying it.
+func TestMempoolAccept(param Params) map[string]interface{} {
+	str, ok := param.String("data")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a string parameter named data")
+	}
+
+	bys, err := common.HexStringToBytes(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "hex string to bytes error")
+	}
+	var txn Transaction
+	if err := txn.Deserialize(bytes.NewReader(bys)); err != nil {
+		return ResponsePack(InvalidTransaction, err.Error())
+	}
+
+	size := txn.GetSize()
+	result := MempoolAcceptInfo{
+		TxID:    ToReversedString(txn.Hash()),
+		Allowed: true,
+		Size:    uint32(size),
+	}
+
+	// fee is unknown if the referenced outputs are not found.
+	if feeMap, err := blockchain.GetTxFeeMap(&txn); err == nil {
+		fee := feeMap[config.ELAAssetID]
+		result.Fee = fee.String()
+		result.FeeRate = (fee * 1000 / common.Fixed64(size)).String()
+	}
+
+	for _, r := range TxMemPool.TestAcceptTransaction(&txn) {
+		check := MempoolCheckInfo{
+			Check:  r.Check,
+			Passed: r.Code == Success,
+		}
+		if r.Code != Success {
+			reason := r.Code.Error()
+			if r.Err != nil {
+				reason = r.Err.Error()
+			}
+			check.RejectCode = r.Code
+			check.Reason = reason
+			result.Allowed = false
+			result.RejectCode = r.Code
+			result.RejectReason = reason
+		}
+		result.Checks = append(result.Checks, check)
+	}
+
+	return ResponsePack(Success, result)
+}
+
 func GetBlockHeight(param Params) map[string]interface{} {
 	return ResponsePack(Success, Store.GetHeight())
 }
@@ -755,6 +904,78 @@ func GetArbitratorGroupByHeight(param Params) map[string]interface{} {
 	return ResponsePack(Success, result)
 }
 
+func GetConsensusRounds(param Params) map[string]interface{} {
+	if DposStore == nil {
+		return ResponsePack(InternalError, "dpos store disabled")
+	}
+
+	start, ok := param.Uint("startheight")
+	if !ok {
+		return ResponsePack(InvalidParams, "startheight parameter should be a positive integer")
+	}
+	end, ok := param.Uint("endheight")
+	if !ok {
+		end = start
+	}
+
+	rounds, err := DposStore.GetConsensusRounds(start, end)
+	if err != nil {
+		return ResponsePack(InvalidParams, err.Error())
+	}
+
+	result := make([]ConsensusRoundInfo, 0, len(rounds))
+	for _, r := range rounds {
+		info := ConsensusRoundInfo{
+			Height:      r.Height,
+			StartTime:   unixTime(r.StartTime),
+			EndTime:     unixTime(r.EndTime),
+			Duration:    r.Duration().Seconds(),
+			ViewChanges: r.ViewChanges(),
+			Proposals:   make([]ConsensusProposalInfo, 0, len(r.Proposals)),
+			Views:       make([]ConsensusViewInfo, 0, len(r.Views)),
+		}
+		for _, p := range r.Proposals {
+			proposal := ConsensusProposalInfo{
+				Sponsor:      p.Sponsor,
+				BlockHash:    ToReversedString(p.BlockHash),
+				ProposalHash: ToReversedString(p.ProposalHash),
+				ViewOffset:   p.ViewOffset,
+				ReceivedTime: unixTime(p.ReceivedTime),
+				EndTime:      unixTime(p.EndTime),
+				Result:       p.Result,
+				AcceptVotes:  p.AcceptCount(),
+				RejectVotes:  p.RejectCount(),
+				Votes:        make([]ConsensusVoteInfo, 0, len(p.Votes)),
+			}
+			for _, v := range p.Votes {
+				proposal.Votes = append(proposal.Votes, ConsensusVoteInfo{
+					Signer:       v.Signer,
+					Accept:       v.Accept,
+					ReceivedTime: unixTime(v.ReceivedTime),
+				})
+			}
+			info.Proposals = append(info.Proposals, proposal)
+		}
+		for _, v := range r.Views {
+			info.Views = append(info.Views, ConsensusViewInfo{
+				OnDutyArbiter: v.OnDutyArbitrator,
+				Offset:        v.Offset,
+				StartTime:     unixTime(v.StartTime),
+			})
+		}
+		result = append(result, info)
+	}
+
+	return ResponsePack(Success, result)
+}
+
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
 //Asset
 func GetAssetByHash(param Params) map[string]interface{} {
 	str, ok := param.String("hash")
@@ -1133,7 +1354,9 @@ func GetTransactionByHash(param Params) map[string]interface{} {
 		return ResponsePack(UnknownBlock, "")
 	}
 
-	return ResponsePack(Success, GetTransactionInfo(header, txn))
+	info := GetTransactionInfo(header, txn)
+	info.Finality = GetFinalityInfo(header)
+	return ResponsePack(Success, info)
 }
 
 func GetExistWithdrawTransactions(param Params) map[string]interface{} {
@@ -1168,6 +1391,7 @@ type Producer struct {
 	Nickname       string `json:"nickname"`
 	Url            string `json:"url"`
 	Location       uint64 `json:"location"`
+	NetAddress     string `json:"netaddress"`
 	Active         bool   `json:"active"`
 	Votes          string `json:"votes"`
 	State          string `json:"state"`
@@ -1175,6 +1399,7 @@ type Producer struct {
 	CancelHeight   uint32 `json:"cancelheight"`
 	InactiveHeight uint32 `json:"inactiveheight"`
 	IllegalHeight  uint32 `json:"illegalheight"`
+	Penalty        string `json:"penalty"`
 	Index          uint64 `json:"index"`
 }
 
@@ -1232,6 +1457,7 @@ func ListProducers(param Params) map[string]interface{} {
 			Nickname:       p.Info().NickName,
 			Url:            p.Info().Url,
 			Location:       p.Info().Location,
+			NetAddress:     p.Info().NetAddress,
 			Active:         p.State() == state.Active,
 			Votes:          p.Votes().String(),
 			State:          p.State().String(),
@@ -1239,6 +1465,7 @@ func ListProducers(param Params) map[string]interface{} {
 			CancelHeight:   p.CancelHeight(),
 			InactiveHeight: p.InactiveSince(),
 			IllegalHeight:  p.IllegalHeight(),
+			Penalty:        p.Penalty().String(),
 			Index:          uint64(i),
 		}
 		ps = append(ps, producer)
@@ -1441,6 +1668,10 @@ func getPayloadInfo(p Payload) PayloadInfo {
 		return obj
 	case *payload.TransferAsset:
 	case *payload.Record:
+		obj := new(RecordInfo)
+		obj.Type = object.Type
+		obj.Content = common.BytesToHexString(object.Content)
+		return obj
 	case *payload.ProducerInfo:
 		obj := new(ProducerInfo)
 		obj.OwnerPublicKey = common.BytesToHexString(object.OwnerPublicKey)
@@ -1461,10 +1692,90 @@ func getPayloadInfo(p Payload) PayloadInfo {
 		obj.NodePublicKey = common.BytesToHexString(object.NodePublicKey)
 		obj.Signature = common.BytesToHexString(object.Signature)
 		return obj
+	case *payload.ReturnDepositCoin:
+	case *payload.DPOSIllegalProposals:
+		obj := new(DPOSIllegalProposalsInfo)
+		obj.Evidence = getProposalEvidenceInfo(&object.Evidence)
+		obj.CompareEvidence = getProposalEvidenceInfo(&object.CompareEvidence)
+		return obj
+	case *payload.DPOSIllegalVotes:
+		obj := new(DPOSIllegalVotesInfo)
+		obj.Evidence = getVoteEvidenceInfo(&object.Evidence)
+		obj.CompareEvidence = getVoteEvidenceInfo(&object.CompareEvidence)
+		return obj
+	case *payload.DPOSIllegalBlocks:
+		obj := new(DPOSIllegalBlocksInfo)
+		obj.CoinType = uint32(object.CoinType)
+		obj.BlockHeight = object.BlockHeight
+		obj.Evidence = getBlockEvidenceInfo(&object.Evidence)
+		obj.CompareEvidence = getBlockEvidenceInfo(&object.CompareEvidence)
+		return obj
+	case *payload.SidechainIllegalData:
+		obj := new(SidechainIllegalDataInfo)
+		obj.IllegalType = uint8(object.IllegalType)
+		obj.Height = object.Height
+		obj.IllegalSigner = common.BytesToHexString(object.IllegalSigner)
+		obj.Evidence = ToReversedString(object.Evidence.DataHash)
+		obj.CompareEvidence = ToReversedString(object.CompareEvidence.DataHash)
+		obj.GenesisBlockAddress = object.GenesisBlockAddress
+		obj.Signs = bytesToHexStrings(object.Signs)
+		return obj
+	case *payload.InactiveArbitrators:
+		obj := new(InactiveArbitratorsInfo)
+		obj.Sponsor = common.BytesToHexString(object.Sponsor)
+		obj.Arbitrators = bytesToHexStrings(object.Arbitrators)
+		obj.BlockHeight = object.BlockHeight
+		return obj
+	case *payload.UpdateVersion:
+		obj := new(UpdateVersionInfo)
+		obj.StartHeight = object.StartHeight
+		obj.EndHeight = object.EndHeight
+		return obj
 	}
 	return nil
 }
 
+func getBlockEvidenceInfo(evidence *payload.BlockEvidence) BlockEvidenceInfo {
+	return BlockEvidenceInfo{
+		Header:       common.BytesToHexString(evidence.Header),
+		BlockConfirm: common.BytesToHexString(evidence.BlockConfirm),
+		Signers:      bytesToHexStrings(evidence.Signers),
+	}
+}
+
+func getProposalEvidenceInfo(evidence *payload.ProposalEvidence) ProposalEvidenceInfo {
+	return ProposalEvidenceInfo{
+		Proposal: DPOSProposalInfo{
+			Sponsor:    common.BytesToHexString(evidence.Proposal.Sponsor),
+			BlockHash:  ToReversedString(evidence.Proposal.BlockHash),
+			ViewOffset: evidence.Proposal.ViewOffset,
+			Sign:       common.BytesToHexString(evidence.Proposal.Sign),
+		},
+		BlockHeader: common.BytesToHexString(evidence.BlockHeader),
+		BlockHeight: evidence.BlockHeight,
+	}
+}
+
+func getVoteEvidenceInfo(evidence *payload.VoteEvidence) VoteEvidenceInfo {
+	return VoteEvidenceInfo{
+		ProposalEvidenceInfo: getProposalEvidenceInfo(&evidence.ProposalEvidence),
+		Vote: DPOSProposalVoteInfo{
+			ProposalHash: ToReversedString(evidence.Vote.ProposalHash),
+			Signer:       common.BytesToHexString(evidence.Vote.Signer),
+			Accept:       evidence.Vote.Accept,
+			Sign:         common.BytesToHexString(evidence.Vote.Sign),
+		},
+	}
+}
+
+func bytesToHexStrings(data [][]byte) []string {
+	result := make([]string, 0, len(data))
+	for _, d := range data {
+		result = append(result, common.BytesToHexString(d))
+	}
+	return result
+}
+
 func getOutputPayloadInfo(op OutputPayload) OutputPayloadInfo {
 	switch object := op.(type) {
 	case *outputpayload.DefaultOutput:
@@ -1482,6 +1793,13 @@ func getOutputPayloadInfo(op OutputPayload) OutputPayloadInfo {
 			obj.Contents = append(obj.Contents, contentInfo)
 		}
 		return obj
+	case *outputpayload.Mapping:
+		obj := new(MappingOutputInfo)
+		obj.Version = object.Version
+		obj.OwnerPublicKey = common.BytesToHexString(object.OwnerPublicKey)
+		obj.SideProducerID = common.BytesToHexString(object.SideProducerID)
+		obj.Signature = common.BytesToHexString(object.Signature)
+		return obj
 	}
 
 	return nil
@@ -0,0 +1,63 @@
+package servers
+
+import (
+	"net"
+
+	"github.com/elastos/Elastos.ELA/common/config"
+	. "github.com/elastos/Elastos.ELA/errors"
+	htp "github.com/elastos/Elastos.ELA/utils/http"
+)
+
+var (
+	// IPLimiter limits the requests of each client IP to the HTTP APIs.
+	IPLimiter *htp.Limiter
+
+	// UserLimiter limits the requests of each JSON-RPC user.
+	UserLimiter *htp.Limiter
+)
+
+// InitLimiters creates the limiters of the HTTP APIs by the configured rate
+// limits, a limiter without limits configured will be nil.
+func InitLimiters(cfg *config.RateLimits) {
+	IPLimiter = htp.NewLimiter(htp.Limits{
+		Rate:          cfg.IP.Rate,
+		Burst:         cfg.IP.Burst,
+		MaxConcurrent: cfg.IP.MaxConcurrent,
+	}, cfg.MethodCosts)
+	UserLimiter = htp.NewLimiter(htp.Limits{
+		Rate:          cfg.User.Rate,
+		Burst:         cfg.User.Burst,
+		MaxConcurrent: cfg.User.MaxConcurrent,
+	}, cfg.MethodCosts)
+}
+
+// AcquireIP takes the cost of the method from the limiter of the remote
+// address, the returned function should be called after the request finished.
+// RateLimited error code will be returned if the request is refused.
+func AcquireIP(remoteAddr, method string) (func(), ErrCode) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	release, err := IPLimiter.Acquire(host, method)
+	if err != nil {
+		return nil, RateLimited
+	}
+	return release, Success
+}
+
+func GetThrottleStats(param Params) map[string]interface{} {
+	return ResponsePack(Success, ThrottleStatsInfo{
+		IP:   limiterStatsInfo(IPLimiter.Stats()),
+		User: limiterStatsInfo(UserLimiter.Stats()),
+	})
+}
+
+func limiterStatsInfo(stats htp.LimiterStats) LimiterStatsInfo {
+	return LimiterStatsInfo{
+		Clients:   stats.Clients,
+		Allowed:   stats.Allowed,
+		Throttled: stats.Throttled,
+		Rejected:  stats.Rejected,
+	}
+}
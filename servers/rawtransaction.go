@@ -0,0 +1,467 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/config"
+	"github.com/elastos/Elastos.ELA/core/contract"
+	pg "github.com/elastos/Elastos.ELA/core/contract/program"
+	. "github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/outputpayload"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/crypto"
+	. "github.com/elastos/Elastos.ELA/errors"
+)
+
+const (
+	// ScriptTypeStandard is the type of a single signature redeem script.
+	ScriptTypeStandard = "standard"
+
+	// ScriptTypeMultiSig is the type of a multiple signatures redeem script.
+	ScriptTypeMultiSig = "multisig"
+
+	// ScriptTypeCrossChain is the type of a cross chain redeem script.
+	ScriptTypeCrossChain = "crosschain"
+)
+
+func DecodeRawTransaction(param Params) map[string]interface{} {
+	str, ok := param.String("data")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a string parameter named data")
+	}
+
+	bys, err := common.HexStringToBytes(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "hex string to bytes error")
+	}
+	var txn Transaction
+	if err := txn.Deserialize(bytes.NewReader(bys)); err != nil {
+		return ResponsePack(InvalidTransaction, err.Error())
+	}
+
+	return ResponsePack(Success, GetTransactionInfo(nil, &txn))
+}
+
+// CreateRawTransaction creates an unsigned transaction of the inputs, outputs
+// and payload, the programs are created by the redeem scripts with empty
+// parameters to be signed.
+func CreateRawTransaction(param Params) map[string]interface{} {
+	inputs, ok := param["inputs"].([]interface{})
+	if !ok || len(inputs) == 0 {
+		return ResponsePack(InvalidParams, "need an array parameter named inputs")
+	}
+	outputs, ok := param["outputs"].([]interface{})
+	if !ok {
+		return ResponsePack(InvalidParams, "need an array parameter named outputs")
+	}
+	lockTime, _ := param.Uint("locktime")
+
+	txType := TransferAsset
+	if t, ok := param.Uint("type"); ok {
+		if t > math.MaxUint8 {
+			return ResponsePack(InvalidParams, "invalid transaction type")
+		}
+		txType = TxType(t)
+	}
+	payloadVersion, _ := param.Uint("payloadversion")
+	if payloadVersion > math.MaxUint8 {
+		return ResponsePack(InvalidParams, "invalid payload version")
+	}
+	txPayload, err := getPayload(txType, param["payload"])
+	if err != nil {
+		return ResponsePack(InvalidParams, err.Error())
+	}
+
+	txn := &Transaction{
+		Version:        TxVersion09,
+		TxType:         txType,
+		PayloadVersion: byte(payloadVersion),
+		Payload:        txPayload,
+		LockTime:       lockTime,
+		Programs:       []*pg.Program{},
+	}
+	attr := NewAttribute(Nonce, []byte(strconv.FormatInt(rand.Int63(), 10)))
+	txn.Attributes = []*Attribute{&attr}
+
+	for i, v := range inputs {
+		input, err := getInput(v)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid input "+
+				strconv.Itoa(i)+": "+err.Error())
+		}
+		txn.Inputs = append(txn.Inputs, input)
+	}
+	for i, v := range outputs {
+		output, err := getOutput(v)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid output "+
+				strconv.Itoa(i)+": "+err.Error())
+		}
+		txn.Outputs = append(txn.Outputs, output)
+	}
+
+	redeemScripts, _ := param.ArrayString("redeemscripts")
+	for _, str := range redeemScripts {
+		code, err := common.HexStringToBytes(str)
+		if err != nil {
+			return ResponsePack(InvalidParams, "invalid redeem script "+str)
+		}
+		if _, err := crypto.GetScriptType(code); err != nil {
+			return ResponsePack(InvalidParams, err.Error())
+		}
+		txn.Programs = append(txn.Programs, &pg.Program{Code: code})
+	}
+
+	buf := new(bytes.Buffer)
+	if err := txn.Serialize(buf); err != nil {
+		return ResponsePack(InternalError, err.Error())
+	}
+	return ResponsePack(Success, common.BytesToHexString(buf.Bytes()))
+}
+
+func DecodeScript(param Params) map[string]interface{} {
+	str, ok := param.String("script")
+	if !ok {
+		return ResponsePack(InvalidParams, "need a string parameter named script")
+	}
+	code, err := common.HexStringToBytes(str)
+	if err != nil {
+		return ResponsePack(InvalidParams, "hex string to bytes error")
+	}
+
+	info, err := getScriptInfo(code)
+	if err != nil {
+		return ResponsePack(InvalidParams, err.Error())
+	}
+	return ResponsePack(Success, info)
+}
+
+func getScriptInfo(code []byte) (*ScriptInfo, error) {
+	scriptType, err := crypto.GetScriptType(code)
+	if err != nil {
+		return nil, err
+	}
+
+	info := new(ScriptInfo)
+	var publicKeys [][]byte
+	var prefix contract.PrefixType
+	switch scriptType {
+	case common.STANDARD:
+		if !contract.IsStandard(code) {
+			return nil, errors.New("invalid standard redeem script")
+		}
+		info.Type = ScriptTypeStandard
+		info.ReqSigs = 1
+		publicKeys = [][]byte{code[:len(code)-1]}
+		prefix = contract.PrefixStandard
+
+		deposit := common.ToProgramHash(byte(contract.PrefixDeposit), code)
+		info.DepositAddress, err = deposit.ToAddress()
+		if err != nil {
+			return nil, err
+		}
+
+	case common.MULTISIG:
+		if !contract.IsMultiSig(code) {
+			return nil, errors.New("invalid multi sign redeem script")
+		}
+		info.Type = ScriptTypeMultiSig
+		m, err := crypto.GetM(code)
+		if err != nil {
+			return nil, err
+		}
+		info.ReqSigs = uint32(m)
+		if publicKeys, err = crypto.ParseMultisigScript(code); err != nil {
+			return nil, err
+		}
+		prefix = contract.PrefixMultiSig
+
+	case common.CROSSCHAIN:
+		// the cross chain script is in the format of the multi sign script
+		multiSig := make([]byte, len(code))
+		copy(multiSig, code)
+		multiSig[len(multiSig)-1] = common.MULTISIG
+		if !contract.IsMultiSig(multiSig) {
+			return nil, errors.New("invalid cross chain redeem script")
+		}
+		info.Type = ScriptTypeCrossChain
+		info.ReqSigs = uint32(code[0] - crypto.PUSH1 + 1)
+		if publicKeys, err = crypto.ParseCrossChainScript(code); err != nil {
+			return nil, err
+		}
+		prefix = contract.PrefixCrossChain
+
+	default:
+		return nil, errors.New("unknown redeem script type")
+	}
+
+	// the public keys are pushed with a leading length byte.
+	for _, pk := range publicKeys {
+		info.PublicKeys = append(info.PublicKeys, common.BytesToHexString(pk[1:]))
+	}
+	info.Address, err = common.ToProgramHash(byte(prefix), code).ToAddress()
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func getInput(value interface{}) (*Input, error) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("input should be an object")
+	}
+	param := Params(m)
+
+	str, ok := param.String("txid")
+	if !ok {
+		return nil, errors.New("need a string parameter named txid")
+	}
+	hashBytes, err := FromReversedString(str)
+	if err != nil {
+		return nil, errors.New("invalid txid")
+	}
+	var txID common.Uint256
+	if err := txID.Deserialize(bytes.NewReader(hashBytes)); err != nil {
+		return nil, errors.New("invalid txid")
+	}
+	vout, ok := param.Uint("vout")
+	if !ok || vout > math.MaxUint16 {
+		return nil, errors.New("invalid vout")
+	}
+	sequence, ok := param.Uint("sequence")
+	if !ok {
+		sequence = math.MaxUint32
+	}
+
+	return &Input{
+		Previous: *NewOutPoint(txID, uint16(vout)),
+		Sequence: sequence,
+	}, nil
+}
+
+func getOutput(value interface{}) (*Output, error) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("output should be an object")
+	}
+	param := Params(m)
+
+	address, ok := param.String("address")
+	if !ok {
+		return nil, errors.New("need a string parameter named address")
+	}
+	programHash, err := common.Uint168FromAddress(address)
+	if err != nil {
+		return nil, errors.New("invalid address " + address)
+	}
+	str, ok := param.String("amount")
+	if !ok {
+		return nil, errors.New("need a string parameter named amount")
+	}
+	amount, err := common.StringToFixed64(str)
+	if err != nil || *amount <= 0 {
+		return nil, errors.New("invalid amount " + str)
+	}
+
+	assetID := config.ELAAssetID
+	if str, ok := param.String("assetid"); ok {
+		hashBytes, err := FromReversedString(str)
+		if err != nil {
+			return nil, errors.New("invalid assetid")
+		}
+		if err := assetID.Deserialize(bytes.NewReader(hashBytes)); err != nil {
+			return nil, errors.New("invalid assetid")
+		}
+	}
+	outputLock, _ := param.Uint("outputlock")
+
+	outputType := OTNone
+	if t, ok := param.Uint("type"); ok {
+		outputType = OutputType(t)
+	}
+	outputPayload, err := getOutputPayload(outputType, param["payload"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Output{
+		AssetID:     assetID,
+		Value:       *amount,
+		OutputLock:  outputLock,
+		ProgramHash: *programHash,
+		Type:        outputType,
+		Payload:     outputPayload,
+	}, nil
+}
+
+// getOutputPayload creates the output payload from the JSON object which is
+// the same as the decoded output payload.
+func getOutputPayload(outputType OutputType,
+	value interface{}) (OutputPayload, error) {
+	switch outputType {
+	case OTNone:
+		return &outputpayload.DefaultOutput{}, nil
+
+	case OTVote:
+		var info VoteOutputInfo
+		if err := unmarshalParam(value, &info); err != nil {
+			return nil, err
+		}
+		vote := &outputpayload.VoteOutput{Version: info.Version}
+		for _, c := range info.Contents {
+			candidates, err := hexStringsToBytes(c.CandidatesInfo)
+			if err != nil {
+				return nil, err
+			}
+			vote.Contents = append(vote.Contents, outputpayload.VoteContent{
+				VoteType:   c.VoteType,
+				Candidates: candidates,
+			})
+		}
+		return vote, vote.Validate()
+
+	case OTMapping:
+		var info MappingOutputInfo
+		if err := unmarshalParam(value, &info); err != nil {
+			return nil, err
+		}
+		mapping := &outputpayload.Mapping{Version: info.Version}
+		var err error
+		if mapping.OwnerPublicKey, err = common.HexStringToBytes(
+			info.OwnerPublicKey); err != nil {
+			return nil, err
+		}
+		if mapping.SideProducerID, err = common.HexStringToBytes(
+			info.SideProducerID); err != nil {
+			return nil, err
+		}
+		if mapping.Signature, err = common.HexStringToBytes(
+			info.Signature); err != nil {
+			return nil, err
+		}
+		return mapping, nil
+	}
+	return nil, errors.New("invalid output type")
+}
+
+// getPayload creates the payload of the transaction type from the JSON object
+// which is the same as the decoded payload, the payloads created by the node
+// itself are not supported.
+func getPayload(txType TxType, value interface{}) (Payload, error) {
+	var err error
+	switch txType {
+	case TransferAsset:
+		return &payload.TransferAsset{}, nil
+
+	case ReturnDepositCoin:
+		return &payload.ReturnDepositCoin{}, nil
+
+	case Record:
+		var info RecordInfo
+		if err = unmarshalParam(value, &info); err != nil {
+			return nil, err
+		}
+		p := &payload.Record{Type: info.Type}
+		if p.Content, err = common.HexStringToBytes(info.Content); err != nil {
+			return nil, err
+		}
+		return p, nil
+
+	case TransferCrossChainAsset:
+		var info TransferCrossChainAssetInfo
+		if err = unmarshalParam(value, &info); err != nil {
+			return nil, err
+		}
+		return &payload.TransferCrossChainAsset{
+			CrossChainAddresses: info.CrossChainAddresses,
+			OutputIndexes:       info.OutputIndexes,
+			CrossChainAmounts:   info.CrossChainAmounts,
+		}, nil
+
+	case RegisterProducer, UpdateProducer:
+		var info ProducerInfo
+		if err = unmarshalParam(value, &info); err != nil {
+			return nil, err
+		}
+		p := &payload.ProducerInfo{
+			NickName:   info.NickName,
+			Url:        info.Url,
+			Location:   info.Location,
+			NetAddress: info.NetAddress,
+		}
+		if p.OwnerPublicKey, err = common.HexStringToBytes(
+			info.OwnerPublicKey); err != nil {
+			return nil, err
+		}
+		if p.NodePublicKey, err = common.HexStringToBytes(
+			info.NodePublicKey); err != nil {
+			return nil, err
+		}
+		if p.Signature, err = common.HexStringToBytes(info.Signature); err != nil {
+			return nil, err
+		}
+		return p, nil
+
+	case CancelProducer:
+		var info CancelProducerInfo
+		if err = unmarshalParam(value, &info); err != nil {
+			return nil, err
+		}
+		p := &payload.ProcessProducer{}
+		if p.OwnerPublicKey, err = common.HexStringToBytes(
+			info.OwnerPublicKey); err != nil {
+			return nil, err
+		}
+		if p.Signature, err = common.HexStringToBytes(info.Signature); err != nil {
+			return nil, err
+		}
+		return p, nil
+
+	case ActivateProducer:
+		var info ActivateProducerInfo
+		if err = unmarshalParam(value, &info); err != nil {
+			return nil, err
+		}
+		p := &payload.ActivateProducer{}
+		if p.NodePublicKey, err = common.HexStringToBytes(
+			info.NodePublicKey); err != nil {
+			return nil, err
+		}
+		if p.Signature, err = common.HexStringToBytes(info.Signature); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	return nil, errors.New("unsupported transaction type " + txType.Name())
+}
+
+// unmarshalParam converts the JSON value of a parameter into v.
+func unmarshalParam(value interface{}, v interface{}) error {
+	if value == nil {
+		return errors.New("need a parameter named payload")
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func hexStringsToBytes(strs []string) ([][]byte, error) {
+	result := make([][]byte, 0, len(strs))
+	for _, str := range strs {
+		data, err := common.HexStringToBytes(str)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
@@ -0,0 +1,489 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/common"
+	"github.com/elastos/Elastos.ELA/common/config"
+	"github.com/elastos/Elastos.ELA/core/contract"
+	"github.com/elastos/Elastos.ELA/core/contract/program"
+	"github.com/elastos/Elastos.ELA/core/types"
+	"github.com/elastos/Elastos.ELA/core/types/outputpayload"
+	"github.com/elastos/Elastos.ELA/core/types/payload"
+	"github.com/elastos/Elastos.ELA/crypto"
+	"github.com/elastos/Elastos.ELA/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPublicKeys generates n public keys.
+func newTestPublicKeys(t *testing.T, n int) []*crypto.PublicKey {
+	publicKeys := make([]*crypto.PublicKey, 0, n)
+	for i := 0; i < n; i++ {
+		_, pk, err := crypto.GenerateKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys = append(publicKeys, pk)
+	}
+	return publicKeys
+}
+
+// publicKeyHex returns the hex string of the compressed public key.
+func publicKeyHex(t *testing.T, pk *crypto.PublicKey) string {
+	data, err := pk.EncodePoint(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return common.BytesToHexString(data)
+}
+
+// scriptAddress returns the address of the redeem script with the prefix.
+func scriptAddress(t *testing.T, prefix contract.PrefixType,
+	code []byte) string {
+	address, err := common.ToProgramHash(byte(prefix), code).ToAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return address
+}
+
+// toParams converts the value into params as it's parsed from a request.
+func toParams(t *testing.T, v interface{}) Params {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var params Params
+	if err := json.Unmarshal(data, &params); err != nil {
+		t.Fatal(err)
+	}
+	return params
+}
+
+// assertJSONEqual asserts the values are encoded into the same JSON.
+func assertJSONEqual(t *testing.T, expected, actual interface{},
+	msgAndArgs ...interface{}) {
+	want, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, string(want), string(got), msgAndArgs...)
+}
+
+func TestCreateRawTransaction_RoundTrip(t *testing.T) {
+	publicKeys := newTestPublicKeys(t, 2)
+	pk0, pk1 := publicKeyHex(t, publicKeys[0]), publicKeyHex(t, publicKeys[1])
+	redeemScript, err := contract.CreateStandardRedeemScript(publicKeys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := scriptAddress(t, contract.PrefixStandard, redeemScript)
+	signature := common.BytesToHexString(make([]byte, crypto.SignatureLength))
+	txID := ToReversedString(common.Uint256{1})
+	assetID := ToReversedString(config.ELAAssetID)
+
+	normalOutput := map[string]interface{}{
+		"address": address, "amount": "1.5", "outputlock": 10,
+	}
+	tests := []struct {
+		name    string
+		txType  types.TxType
+		payload interface{}
+		outputs []map[string]interface{}
+	}{
+		{"transfer asset", types.TransferAsset, nil, nil},
+		{"return deposit coin", types.ReturnDepositCoin, nil, nil},
+		{"record", types.Record, &RecordInfo{Type: "test",
+			Content: "0102"}, nil},
+		{"transfer cross chain asset", types.TransferCrossChainAsset,
+			&TransferCrossChainAssetInfo{
+				CrossChainAddresses: []string{"EKn3UGyEoxZmxGTHp2p7Bz4KD6vhXrbAAo"},
+				OutputIndexes:       []uint64{0},
+				CrossChainAmounts:   []common.Fixed64{100000000},
+			}, nil},
+		{"register producer", types.RegisterProducer, &ProducerInfo{
+			OwnerPublicKey: pk0, NodePublicKey: pk1, NickName: "producer",
+			Url: "http://producer.org", Location: 86,
+			NetAddress: "127.0.0.1:20339", Signature: signature}, nil},
+		{"update producer", types.UpdateProducer, &ProducerInfo{
+			OwnerPublicKey: pk0, NodePublicKey: pk0, NickName: "updated",
+			Url: "http://updated.org", Location: 1,
+			NetAddress: "127.0.0.1:20339", Signature: signature}, nil},
+		{"cancel producer", types.CancelProducer, &CancelProducerInfo{
+			OwnerPublicKey: pk0, Signature: signature}, nil},
+		{"activate producer", types.ActivateProducer, &ActivateProducerInfo{
+			NodePublicKey: pk1, Signature: signature}, nil},
+		{"vote output", types.TransferAsset, nil,
+			[]map[string]interface{}{{
+				"address": address, "amount": "1", "type": types.OTVote,
+				"payload": &VoteOutputInfo{Contents: []VoteContentInfo{{
+					VoteType:       outputpayload.Delegate,
+					CandidatesInfo: []string{pk0, pk1},
+				}}},
+			}}},
+		{"mapping output", types.TransferAsset, nil,
+			[]map[string]interface{}{{
+				"address": address, "amount": "1", "type": types.OTMapping,
+				"payload": &MappingOutputInfo{OwnerPublicKey: pk0,
+					SideProducerID: "0102", Signature: signature},
+			}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputs := test.outputs
+			if outputs == nil {
+				outputs = []map[string]interface{}{normalOutput}
+			}
+			params := map[string]interface{}{
+				"inputs": []map[string]interface{}{
+					{"txid": txID, "vout": 1},
+					{"txid": txID, "vout": 2, "sequence": 100},
+				},
+				"outputs":        outputs,
+				"type":           test.txType,
+				"payloadversion": 0,
+				"locktime":       100,
+				"redeemscripts": []string{
+					common.BytesToHexString(redeemScript)},
+			}
+			if test.payload != nil {
+				params["payload"] = test.payload
+			}
+			resp := CreateRawTransaction(toParams(t, params))
+			if !assert.Equal(t, errors.Success, resp["Error"], resp["Result"]) {
+				return
+			}
+
+			resp = DecodeRawTransaction(Params{"data": resp["Result"]})
+			if !assert.Equal(t, errors.Success, resp["Error"], resp["Result"]) {
+				return
+			}
+			info := resp["Result"].(*TransactionInfo)
+			assert.Equal(t, types.TxVersion09, info.Version)
+			assert.Equal(t, test.txType, info.TxType)
+			assert.Equal(t, uint32(100), info.LockTime)
+			assertJSONEqual(t, test.payload, info.Payload)
+			assert.Equal(t, []InputInfo{
+				{TxID: txID, VOut: 1, Sequence: 0xffffffff},
+				{TxID: txID, VOut: 2, Sequence: 100},
+			}, info.Inputs)
+			if assert.Len(t, info.Outputs, len(outputs)) {
+				for i, output := range outputs {
+					decoded := info.Outputs[i]
+					amount, _ := common.StringToFixed64(
+						output["amount"].(string))
+					assert.Equal(t, address, decoded.Address)
+					assert.Equal(t, amount.String(), decoded.Value)
+					assert.Equal(t, assetID, decoded.AssetID)
+					if outputType, ok := output["type"]; ok {
+						assert.Equal(t, uint32(outputType.(types.OutputType)),
+							decoded.OutputType)
+						assertJSONEqual(t, output["payload"],
+							decoded.OutputPayload)
+					} else {
+						assert.Equal(t, uint32(types.OTNone),
+							decoded.OutputType)
+						assertJSONEqual(t, &DefaultOutputInfo{},
+							decoded.OutputPayload)
+					}
+				}
+			}
+			assert.Equal(t, []ProgramInfo{{
+				Code: common.BytesToHexString(redeemScript)}}, info.Programs)
+		})
+	}
+}
+
+func TestCreateRawTransaction_Invalid(t *testing.T) {
+	publicKeys := newTestPublicKeys(t, 1)
+	pk := publicKeyHex(t, publicKeys[0])
+	redeemScript, err := contract.CreateStandardRedeemScript(publicKeys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := scriptAddress(t, contract.PrefixStandard, redeemScript)
+	input := map[string]interface{}{
+		"txid": ToReversedString(common.Uint256{1}), "vout": 0}
+	output := map[string]interface{}{"address": address, "amount": "1"}
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+	}{
+		{"no inputs", map[string]interface{}{
+			"outputs": []interface{}{output}}},
+		{"no outputs", map[string]interface{}{
+			"inputs": []interface{}{input}}},
+		{"invalid txid", map[string]interface{}{
+			"inputs":  []interface{}{map[string]interface{}{"txid": "xyz"}},
+			"outputs": []interface{}{output}}},
+		{"invalid address", map[string]interface{}{
+			"inputs": []interface{}{input},
+			"outputs": []interface{}{map[string]interface{}{
+				"address": "xyz", "amount": "1"}}}},
+		{"invalid amount", map[string]interface{}{
+			"inputs": []interface{}{input},
+			"outputs": []interface{}{map[string]interface{}{
+				"address": address, "amount": "0"}}}},
+		{"invalid output type", map[string]interface{}{
+			"inputs": []interface{}{input},
+			"outputs": []interface{}{map[string]interface{}{
+				"address": address, "amount": "1", "type": 100}}}},
+		{"duplicate candidates", map[string]interface{}{
+			"inputs": []interface{}{input},
+			"outputs": []interface{}{map[string]interface{}{
+				"address": address, "amount": "1", "type": types.OTVote,
+				"payload": &VoteOutputInfo{Contents: []VoteContentInfo{{
+					VoteType:       outputpayload.Delegate,
+					CandidatesInfo: []string{pk, pk},
+				}}}}}}},
+		{"unsupported type", map[string]interface{}{
+			"inputs": []interface{}{input}, "outputs": []interface{}{output},
+			"type": types.CoinBase}},
+		{"no payload", map[string]interface{}{
+			"inputs": []interface{}{input}, "outputs": []interface{}{output},
+			"type": types.Record}},
+		{"invalid payload", map[string]interface{}{
+			"inputs": []interface{}{input}, "outputs": []interface{}{output},
+			"type":    types.CancelProducer,
+			"payload": &CancelProducerInfo{OwnerPublicKey: "xyz"}}},
+		{"invalid redeem script", map[string]interface{}{
+			"inputs": []interface{}{input}, "outputs": []interface{}{output},
+			"redeemscripts": []string{"0102"}}},
+	}
+	for _, test := range tests {
+		resp := CreateRawTransaction(toParams(t, test.params))
+		assert.Equal(t, errors.InvalidParams, resp["Error"], test.name)
+	}
+}
+
+func TestDecodeRawTransaction_Payloads(t *testing.T) {
+	publicKeys := newTestPublicKeys(t, 2)
+	pk0, err := publicKeys[0].EncodePoint(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk1, err := publicKeys[1].EncodePoint(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposal := payload.DPOSProposal{Sponsor: pk0,
+		BlockHash: common.Uint256{1}, Sign: []byte{1}}
+	proposalEvidence := payload.ProposalEvidence{Proposal: proposal,
+		BlockHeader: []byte{1, 2}, BlockHeight: 10}
+	vote := payload.DPOSProposalVote{ProposalHash: proposal.Hash(),
+		Signer: pk1, Accept: true, Sign: []byte{2}}
+
+	// the payloads created by the node are decoded as well
+	tests := []struct {
+		txType  types.TxType
+		payload types.Payload
+	}{
+		{types.CoinBase, &payload.CoinBase{Content: []byte("ELA")}},
+		{types.RegisterAsset, &payload.RegisterAsset{
+			Asset: payload.Asset{Name: "TEST", Precision: 8,
+				AssetType: payload.Token},
+			Amount:     100000000,
+			Controller: common.Uint168{1},
+		}},
+		{types.SideChainPow, &payload.SideChainPow{
+			SideBlockHash:   common.Uint256{1},
+			SideGenesisHash: common.Uint256{2},
+			BlockHeight:     10,
+			Signature:       []byte{1, 2},
+		}},
+		{types.WithdrawFromSideChain, &payload.WithdrawFromSideChain{
+			BlockHeight:                10,
+			GenesisBlockAddress:        "XKUh4GLhFJiqAMTF6HyWQrV9pK9HcGUdfJ",
+			SideChainTransactionHashes: []common.Uint256{{1}, {2}},
+		}},
+		{types.IllegalProposalEvidence, &payload.DPOSIllegalProposals{
+			Evidence:        proposalEvidence,
+			CompareEvidence: proposalEvidence,
+		}},
+		{types.IllegalVoteEvidence, &payload.DPOSIllegalVotes{
+			Evidence: payload.VoteEvidence{
+				ProposalEvidence: proposalEvidence, Vote: vote},
+			CompareEvidence: payload.VoteEvidence{
+				ProposalEvidence: proposalEvidence, Vote: vote},
+		}},
+		{types.IllegalBlockEvidence, &payload.DPOSIllegalBlocks{
+			CoinType:    payload.ELACoin,
+			BlockHeight: 10,
+			Evidence: payload.BlockEvidence{Header: []byte{1},
+				BlockConfirm: []byte{2}, Signers: [][]byte{pk0}},
+			CompareEvidence: payload.BlockEvidence{Header: []byte{3},
+				BlockConfirm: []byte{4}, Signers: [][]byte{pk1}},
+		}},
+		{types.IllegalSidechainEvidence, &payload.SidechainIllegalData{
+			IllegalType:         payload.SidechainIllegalProposal,
+			Height:              10,
+			IllegalSigner:       pk0,
+			Evidence:            payload.SidechainIllegalEvidence{DataHash: common.Uint256{1}},
+			CompareEvidence:     payload.SidechainIllegalEvidence{DataHash: common.Uint256{2}},
+			GenesisBlockAddress: "XKUh4GLhFJiqAMTF6HyWQrV9pK9HcGUdfJ",
+			Signs:               [][]byte{{1}, {2}},
+		}},
+		{types.InactiveArbitrators, &payload.InactiveArbitrators{
+			Sponsor:     pk0,
+			Arbitrators: [][]byte{pk1},
+			BlockHeight: 10,
+		}},
+		{types.UpdateVersion, &payload.UpdateVersion{
+			StartHeight: 10,
+			EndHeight:   20,
+		}},
+	}
+	for _, test := range tests {
+		txn := &types.Transaction{
+			Version:    types.TxVersion09,
+			TxType:     test.txType,
+			Payload:    test.payload,
+			Attributes: []*types.Attribute{},
+			Programs:   []*program.Program{},
+		}
+		buf := new(bytes.Buffer)
+		if err := txn.Serialize(buf); err != nil {
+			t.Fatal(test.txType.Name(), err)
+		}
+		resp := DecodeRawTransaction(Params{
+			"data": common.BytesToHexString(buf.Bytes())})
+		if !assert.Equal(t, errors.Success, resp["Error"], "%s %v",
+			test.txType.Name(), resp["Result"]) {
+			continue
+		}
+		info := resp["Result"].(*TransactionInfo)
+		assert.Equal(t, test.txType, info.TxType)
+		assert.NotNil(t, info.Payload, test.txType.Name())
+		assertJSONEqual(t, getPayloadInfo(test.payload), info.Payload,
+			test.txType.Name())
+	}
+
+	// malformed transactions
+	buf := new(bytes.Buffer)
+	if err := (&types.Transaction{
+		Version:    types.TxVersion09,
+		TxType:     types.UpdateVersion,
+		Payload:    &payload.UpdateVersion{StartHeight: 10, EndHeight: 20},
+		Attributes: []*types.Attribute{},
+		Programs:   []*program.Program{},
+	}).Serialize(buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	resp := DecodeRawTransaction(Params{
+		"data": common.BytesToHexString(data[:len(data)-2])})
+	assert.Equal(t, errors.InvalidTransaction, resp["Error"])
+	resp = DecodeRawTransaction(Params{"data": "xyz"})
+	assert.Equal(t, errors.InvalidParams, resp["Error"])
+	resp = DecodeRawTransaction(Params{})
+	assert.Equal(t, errors.InvalidParams, resp["Error"])
+}
+
+func TestDecodeScript(t *testing.T) {
+	publicKeys := newTestPublicKeys(t, 3)
+	keys := make([]string, 0, len(publicKeys))
+	for _, pk := range publicKeys {
+		keys = append(keys, publicKeyHex(t, pk))
+	}
+	standard, err := contract.CreateStandardRedeemScript(publicKeys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	multiSig, err := contract.CreateMultiSigRedeemScript(2, publicKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crossChain := append([]byte{}, multiSig...)
+	crossChain[len(crossChain)-1] = common.CROSSCHAIN
+
+	decode := func(code []byte) (*ScriptInfo, errors.ErrCode) {
+		resp := DecodeScript(Params{
+			"script": common.BytesToHexString(code)})
+		info, _ := resp["Result"].(*ScriptInfo)
+		return info, resp["Error"].(errors.ErrCode)
+	}
+
+	info, code := decode(standard)
+	if assert.Equal(t, errors.Success, code) {
+		assert.Equal(t, &ScriptInfo{
+			Type:       ScriptTypeStandard,
+			ReqSigs:    1,
+			PublicKeys: []string{keys[0]},
+			Address:    scriptAddress(t, contract.PrefixStandard, standard),
+			DepositAddress: scriptAddress(t, contract.PrefixDeposit,
+				standard),
+		}, info)
+	}
+
+	info, code = decode(multiSig)
+	if assert.Equal(t, errors.Success, code) {
+		assert.Equal(t, ScriptTypeMultiSig, info.Type)
+		assert.Equal(t, uint32(2), info.ReqSigs)
+		assert.ElementsMatch(t, keys, info.PublicKeys)
+		assert.Equal(t, scriptAddress(t, contract.PrefixMultiSig, multiSig),
+			info.Address)
+		assert.Empty(t, info.DepositAddress)
+	}
+
+	info, code = decode(crossChain)
+	if assert.Equal(t, errors.Success, code) {
+		assert.Equal(t, ScriptTypeCrossChain, info.Type)
+		assert.Equal(t, uint32(2), info.ReqSigs)
+		assert.ElementsMatch(t, keys, info.PublicKeys)
+		assert.Equal(t, scriptAddress(t, contract.PrefixCrossChain,
+			crossChain), info.Address)
+	}
+
+	// malformed scripts
+	modify := func(script []byte, f func(code []byte) []byte) []byte {
+		return f(append([]byte{}, script...))
+	}
+	tests := []struct {
+		name string
+		code []byte
+	}{
+		{"empty", []byte{}},
+		{"too short", standard[:10]},
+		{"unknown type", modify(standard, func(code []byte) []byte {
+			code[len(code)-1] = 0xad
+			return code
+		})},
+		{"invalid public key length", modify(standard,
+			func(code []byte) []byte {
+				code[0] = 32
+				return code
+			})},
+		{"truncated multisig", modify(multiSig, func(code []byte) []byte {
+			return append(code[:len(code)-3], common.MULTISIG)
+		})},
+		{"multisig m more than n", modify(multiSig,
+			func(code []byte) []byte {
+				code[0] = crypto.PUSH1 + 3
+				return code
+			})},
+		{"crosschain m more than n", modify(crossChain,
+			func(code []byte) []byte {
+				code[0] = crypto.PUSH1 + 3
+				return code
+			})},
+		{"crosschain wrong n", modify(crossChain, func(code []byte) []byte {
+			code[len(code)-2] = crypto.PUSH1
+			return code
+		})},
+	}
+	for _, test := range tests {
+		_, code := decode(test.code)
+		assert.Equal(t, errors.InvalidParams, code, test.name)
+	}
+	resp := DecodeScript(Params{"script": "xyz"})
+	assert.Equal(t, errors.InvalidParams, resp["Error"])
+}
@@ -0,0 +1,195 @@
+package jsonrpc
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net"
+
+	"github.com/elastos/Elastos.ELA/common/log"
+)
+
+const (
+	// RoleRead is the role of methods querying the chain and node status.
+	RoleRead = "read"
+
+	// RoleWallet is the role of methods sending transactions.
+	RoleWallet = "wallet"
+
+	// RoleMining is the role of methods controlling the mining.
+	RoleMining = "mining"
+
+	// RoleAdmin is the role of methods changing the node behavior, a user with
+	// admin role is allowed to call all methods.
+	RoleAdmin = "admin"
+)
+
+var (
+	// ErrClientNotAllowed is returned when the client IP is not in the white
+	// list.
+	ErrClientNotAllowed = errors.New("client ip is not allowed")
+
+	// ErrAuthFailed is returned when the credential of the client does not
+	// match any user.
+	ErrAuthFailed = errors.New("client authenticate failed")
+
+	// ErrMethodNotAllowed is returned when the authenticated user is not
+	// allowed to call the method.
+	ErrMethodNotAllowed = errors.New("method not allowed")
+)
+
+// User is a JSON-RPC credential bound to the roles and methods it's allowed to
+// call.
+type User struct {
+	Name    string
+	Pass    string
+	Roles   []string
+	Methods []string
+}
+
+// user is the authenticated user of a request.
+type user struct {
+	name       string
+	authSha256 [sha256.Size]byte
+	roles      map[string]struct{}
+	methods    map[string]struct{}
+}
+
+// allowed returns if the user is allowed to call the method of the role.
+func (u *user) allowed(method, role string) bool {
+	if _, ok := u.roles[RoleAdmin]; ok {
+		return true
+	}
+	if _, ok := u.roles[role]; ok {
+		return true
+	}
+	_, ok := u.methods[method]
+	return ok
+}
+
+func newUser(cfg *User) *user {
+	login := cfg.Name + ":" + cfg.Pass
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	u := &user{
+		name:       cfg.Name,
+		authSha256: sha256.Sum256([]byte(auth)),
+		roles:      make(map[string]struct{}, len(cfg.Roles)),
+		methods:    make(map[string]struct{}, len(cfg.Methods)),
+	}
+	for _, r := range cfg.Roles {
+		u.roles[r] = struct{}{}
+	}
+	for _, m := range cfg.Methods {
+		u.methods[m] = struct{}{}
+	}
+	return u
+}
+
+// newUsers returns the users of the configuration, the User and Pass is taken
+// as a user with admin role.
+func newUsers(cfg *Config) []*user {
+	var users []*user
+	if len(cfg.User) > 0 || len(cfg.Pass) > 0 {
+		users = append(users, newUser(&User{Name: cfg.User, Pass: cfg.Pass,
+			Roles: []string{RoleAdmin}}))
+	}
+	for i := range cfg.Users {
+		users = append(users, newUser(&cfg.Users[i]))
+	}
+	return users
+}
+
+// ipAllowed returns if the client of the remote address is allowed by the
+// white list, the loopback address is always allowed.
+func ipAllowed(whiteList []string, remoteAddr string) bool {
+	//this ipAbbr  may be  ::1 when request is localhost
+	ipAbbr, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		log.Errorf("RemoteAddr clientAllowed SplitHostPort failure %s \n", remoteAddr)
+		return false
+
+	}
+	//after ParseIP ::1 chg to 0:0:0:0:0:0:0:1 the true ip
+	remoteIp := net.ParseIP(ipAbbr)
+
+	if remoteIp == nil {
+		log.Errorf("clientAllowed ParseIP ipAbbr %s failure  \n", ipAbbr)
+		return false
+	}
+
+	if remoteIp.IsLoopback() {
+		//log.Debugf("remoteIp %s IsLoopback\n", remoteIp)
+		return true
+	}
+
+	for _, cfgIp := range whiteList {
+		//WhiteIPList have 0.0.0.0  allow all ip in
+		if cfgIp == "0.0.0.0" {
+			return true
+		}
+		if cfgIp == remoteIp.String() {
+			return true
+		}
+
+	}
+
+	return false
+}
+
+// authenticate returns the user matches the authorization, nil user will be
+// returned if no credential configured.
+func authenticate(users []*user, authorization string) (*user, bool) {
+	if len(users) == 0 {
+		return nil, true
+	}
+	if len(authorization) == 0 {
+		return nil, false
+	}
+
+	authSha256 := sha256.Sum256([]byte(authorization))
+
+	for _, u := range users {
+		resultCmp := subtle.ConstantTimeCompare(authSha256[:], u.authSha256[:])
+		if resultCmp == 1 {
+			return u, true
+		}
+	}
+
+	// Request's auth doesn't match any user
+	return nil, false
+}
+
+// Authenticator checks the clients by the white list and the users of the
+// JSON-RPC configuration, so that the other services of the node apply the
+// same access control as the JSON-RPC server.
+type Authenticator struct {
+	whiteList []string
+	users     []*user
+}
+
+// Authorize returns an error if the client of the remote address with the
+// authorization is not allowed to call the method of the role. The
+// authorization is the same as the HTTP basic authorization header.
+func (a *Authenticator) Authorize(remoteAddr, authorization, method,
+	role string) error {
+	if !ipAllowed(a.whiteList, remoteAddr) {
+		return ErrClientNotAllowed
+	}
+	u, ok := authenticate(a.users, authorization)
+	if !ok {
+		return ErrAuthFailed
+	}
+	if u != nil && !u.allowed(method, role) {
+		log.Warnf("[RPC audit] user %s from %s is not allowed to call %s",
+			u.name, remoteAddr, method)
+		return ErrMethodNotAllowed
+	}
+	return nil
+}
+
+// NewAuthenticator creates an authenticator by the User, Pass, WhiteList and
+// Users of the configuration.
+func NewAuthenticator(cfg *Config) *Authenticator {
+	return &Authenticator{whiteList: cfg.WhiteList, users: newUsers(cfg)}
+}
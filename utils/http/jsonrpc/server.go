@@ -1,17 +1,16 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
 	"sync"
-
-	"crypto/sha256"
-	"crypto/subtle"
-	"encoding/base64"
+	"time"
 
 	"github.com/elastos/Elastos.ELA/common/log"
 	htp "github.com/elastos/Elastos.ELA/utils/http"
@@ -27,6 +26,25 @@ const (
 	InvalidParams  = -32602
 	InternalError  = -32603
 	//-32000 to -32099	Server error, waiting for defining
+
+	// MethodNotAllowed is the error code returned when the authenticated
+	// user is not allowed to call the method.
+	MethodNotAllowed = -32000
+
+	// RateLimited is the error code returned when the request exceeds the
+	// rate limit or concurrency cap of the client.
+	RateLimited = -32001
+
+	// IOTimeout is the maximum duration for JSON-RPC reading or writing
+	// timeout.
+	IOTimeout = 60 * time.Second
+
+	// MaxBatchSize is the maximum count of request objects in a batch call.
+	MaxBatchSize = 100
+
+	// authFailedMethod is the method name a failed authentication is charged
+	// to IPLimiter as, it's cost weight can be configured like other methods.
+	authFailedMethod = "authenticate"
 )
 
 //if  we want to run server_test.go, set this to be true (add one test action)
@@ -42,30 +60,67 @@ type Request struct {
 	Params  interface{} `json:"params"`
 }
 
+// request is the JSON-RPC request decoded by server, the id is kept raw so
+// that a notification without id can be told apart from a null id.
+type request struct {
+	Id      json.RawMessage `json:"id"`
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  interface{}     `json:"params"`
+}
+
 // Response represent the standard JSON-RPC Response data structure.
 type Response struct {
 	Id      interface{} `json:"id"`
 	Version string      `json:"jsonrpc"`
 	Result  interface{} `json:"result"`
 	Error   *htp.Error  `json:"error"`
+
+	// legacy indicates the response of a request not in JSON-RPC 2.0, both
+	// result and error are returned as before.
+	legacy bool
+}
+
+// MarshalJSON implements the json.Marshaler interface, result and error are
+// mutually exclusive in a JSON-RPC 2.0 response.
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.legacy {
+		return json.Marshal(&struct {
+			Id      interface{} `json:"id"`
+			Version string      `json:"jsonrpc"`
+			Result  interface{} `json:"result"`
+			Error   *htp.Error  `json:"error"`
+		}{Id: r.Id, Version: r.Version, Result: r.Result, Error: r.Error})
+	}
+	if r.Error != nil {
+		return json.Marshal(&struct {
+			Id      interface{} `json:"id"`
+			Version string      `json:"jsonrpc"`
+			Error   *htp.Error  `json:"error"`
+		}{Id: r.Id, Version: r.Version, Error: r.Error})
+	}
+	return json.Marshal(&struct {
+		Id      interface{} `json:"id"`
+		Version string      `json:"jsonrpc"`
+		Result  interface{} `json:"result"`
+	}{Id: r.Id, Version: r.Version, Result: r.Result})
 }
 
-// error returns an error response to the http client.
-func (r *Response) error(w http.ResponseWriter, httpStatus, code int, message string) {
-	r.Error = &htp.Error{
-		Code:    code,
-		Message: message,
+// newErrorResponse creates an error response of the given request id.
+func newErrorResponse(id interface{}, code int, message string) *Response {
+	return &Response{
+		Id:      id,
+		Version: Version,
+		Error:   &htp.Error{Code: code, Message: message},
 	}
-	r.write(w, httpStatus)
 }
 
-// write returns a normal response to the http client.
-func (r *Response) write(w http.ResponseWriter, httpStatus int) {
-	r.Version = Version
+// writeResponse returns the response data to the http client.
+func writeResponse(w http.ResponseWriter, httpStatus int, data interface{}) {
+	buf, _ := json.Marshal(data)
 	w.Header().Set("Content-Type", "application/json;charset=utf-8")
 	w.WriteHeader(httpStatus)
-	data, _ := json.Marshal(r)
-	w.Write(data)
+	w.Write(buf)
 }
 
 // Config is the configuration of the JSON-RPC server.
@@ -76,6 +131,15 @@ type Config struct {
 	Pass      string
 	WhiteList []string
 	NetListen func(port uint16) (net.Listener, error)
+
+	// Users are the credentials bound to the allowed roles and methods, the
+	// User and Pass above is taken as a user with admin role.
+	Users []User
+
+	// IPLimiter and UserLimiter limit the requests of each client IP and
+	// each user, nil means no limit.
+	IPLimiter   *htp.Limiter
+	UserLimiter *htp.Limiter
 }
 
 // Server is the JSON-RPC server instance class.
@@ -83,19 +147,64 @@ type Server struct {
 	cfg    Config
 	server *http.Server
 
+	users []*user
+
 	mutex     sync.Mutex
 	paramsMap map[string][]string
 	handlers  map[string]Handler
+	roles     map[string]string
+	sensitive map[string][]string
 }
 
 // RegisterAction register a service handler method by it's name and parameters. When a
 // JSON-RPC client's request method matches the registered handler name, it will be invoked.
+// The method is registered with RoleRead.
 // This method is safe for concurrency access.
 func (s *Server) RegisterAction(name string, handler Handler, params ...string) {
+	s.RegisterRoleAction(RoleRead, name, handler, params...)
+}
+
+// RegisterRoleAction register a service handler method like RegisterAction,
+// and only the users with the given role are allowed to call it.
+// This method is safe for concurrency access.
+func (s *Server) RegisterRoleAction(role, name string, handler Handler,
+	params ...string) {
 	s.mutex.Lock()
 	s.paramsMap[name] = params
 	s.handlers[name] = handler
+	s.roles[name] = role
+	s.mutex.Unlock()
+}
+
+// RegisterSensitiveParams marks the parameters of the method as sensitive,
+// such as passwords, their values are redacted from the logs.
+// This method is safe for concurrency access.
+func (s *Server) RegisterSensitiveParams(name string, params ...string) {
+	s.mutex.Lock()
+	s.sensitive[name] = params
+	s.mutex.Unlock()
+}
+
+// redactParams returns a copy of the parameters to be logged, with the values
+// of the sensitive parameters of the method replaced.
+func (s *Server) redactParams(method string, params htp.Params) htp.Params {
+	s.mutex.Lock()
+	sensitive := s.sensitive[method]
 	s.mutex.Unlock()
+	if len(sensitive) == 0 {
+		return params
+	}
+
+	redacted := make(htp.Params, len(params))
+	for k, v := range params {
+		redacted[k] = v
+	}
+	for _, k := range sensitive {
+		if _, ok := redacted[k]; ok {
+			redacted[k] = "[redacted]"
+		}
+	}
+	return redacted
 }
 
 func (s *Server) Start() error {
@@ -121,6 +230,8 @@ func (s *Server) Start() error {
 		http.Handle(s.cfg.Path, s)
 		s.server = &http.Server{}
 	}
+	s.server.ReadTimeout = IOTimeout
+	s.server.WriteTimeout = IOTimeout
 	return s.server.Serve(listener)
 }
 
@@ -131,6 +242,9 @@ func (s *Server) Stop() error {
 	return fmt.Errorf("server not started")
 }
 
+// parseParams converts the positional parameters into named parameters by the
+// parameter names registered with the method, the extra parameters are
+// ignored.
 func (s *Server) parseParams(method string, array []interface{}) htp.Params {
 	s.mutex.Lock()
 	fields := s.paramsMap[method]
@@ -145,68 +259,54 @@ func (s *Server) parseParams(method string, array []interface{}) htp.Params {
 }
 
 func (s *Server) clientAllowed(r *http.Request) bool {
-	//this ipAbbr  may be  ::1 when request is localhost
-	ipAbbr, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		log.Errorf("RemoteAddr clientAllowed SplitHostPort failure %s \n", r.RemoteAddr)
-		return false
+	return ipAllowed(s.cfg.WhiteList, r.RemoteAddr)
+}
 
-	}
-	//after ParseIP ::1 chg to 0:0:0:0:0:0:0:1 the true ip
-	remoteIp := net.ParseIP(ipAbbr)
+// checkAuth returns the user matches the authorization of the request, nil
+// user will be returned if no credential configured.
+func (s *Server) checkAuth(r *http.Request) (*user, bool) {
+	return authenticate(s.users, r.Header.Get("Authorization"))
+}
 
-	if remoteIp == nil {
-		log.Errorf("clientAllowed ParseIP ipAbbr %s failure  \n", ipAbbr)
-		return false
+// acquire takes the cost of the method from the limiters of the client IP and
+// the user, the returned function should be called after the request finished.
+func (s *Server) acquire(u *user, addr, method string) (func(), error) {
+	releaseIP, err := s.cfg.IPLimiter.Acquire(remoteHost(addr), method)
+	if err != nil {
+		return nil, err
 	}
-
-	if remoteIp.IsLoopback() {
-		//log.Debugf("remoteIp %s IsLoopback\n", remoteIp)
-		return true
+	if u == nil {
+		return releaseIP, nil
 	}
-
-	for _, cfgIp := range s.cfg.WhiteList {
-		//WhiteIPList have 0.0.0.0  allow all ip in
-		if cfgIp == "0.0.0.0" {
-			return true
-		}
-		if cfgIp == remoteIp.String() {
-			return true
-		}
-
+	releaseUser, err := s.cfg.UserLimiter.Acquire(u.name, method)
+	if err != nil {
+		releaseIP()
+		return nil, err
 	}
-
-	return false
+	return func() {
+		releaseUser()
+		releaseIP()
+	}, nil
 }
 
-func (s *Server) checkAuth(r *http.Request) bool {
-	User := s.cfg.User
-	Pass := s.cfg.Pass
-
-	//log.Infof("ServeHTTP checkAuth RpcConfiguration %+v" , s.cfg.RpcConfiguration)
-	if (User == Pass) && (len(User) == 0) {
-		return true
-	}
-	authHeader := r.Header["Authorization"]
-
-	if len(authHeader) <= 0 {
-		return false
+// remoteHost returns the host of the remote address as the key of IPLimiter.
+func remoteHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
 	}
+	return host
+}
 
-	authSha256 := sha256.Sum256([]byte(authHeader[0]))
-
-	login := User + ":" + Pass
-	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
-	cfgAuthSha256 := sha256.Sum256([]byte(auth))
-
-	resultCmp := subtle.ConstantTimeCompare(authSha256[:], cfgAuthSha256[:])
-	if resultCmp == 1 {
-
-		return true
+// chargeAuthFailure takes the cost of a failed authentication from the client
+// IP, so that guessing credentials is throttled as other requests.
+func (s *Server) chargeAuthFailure(addr string) error {
+	release, err := s.cfg.IPLimiter.Acquire(remoteHost(addr), authFailedMethod)
+	if err != nil {
+		return err
 	}
-
-	// Request's auth doesn't match  user
-	return false
+	release()
+	return nil
 }
 
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -224,43 +324,143 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.Header["Content-Type"][0] != "application/json" {
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != "application/json" {
 		//log.Warn("need content type to be application/json")
 
 		http.Error(w, "need content type to be application/json",
 			http.StatusUnsupportedMediaType)
 		return
 	}
-	isCheckAuthOk := s.checkAuth(r)
+	u, isCheckAuthOk := s.checkAuth(r)
 
 	if !isCheckAuthOk {
 		//log.Warn("checkAuth client authenticate failed %v",r.RemoteAddr)
+		if err := s.chargeAuthFailure(r.RemoteAddr); err != nil {
+			log.Debugf("RPC client %s refused: %s", r.RemoteAddr, err)
+			writeResponse(w, http.StatusTooManyRequests, newErrorResponse(nil,
+				RateLimited, err.Error()))
+			return
+		}
 		http.Error(w, "client authenticate failed", http.StatusUnauthorized)
 		return
 	}
 	//read the body of the request
-	body, _ := ioutil.ReadAll(r.Body)
-	var req Request
-	var resp Response
-	err := json.Unmarshal(body, &req)
+	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		resp.error(w, http.StatusBadRequest, ParseError,
-			fmt.Sprintf("json parse failed: %s", err))
+		writeResponse(w, http.StatusBadRequest, newErrorResponse(nil,
+			ParseError, fmt.Sprintf("read request failed: %s", err)))
 		return
 	}
+	body = bytes.TrimSpace(body)
 
-	resp.Id = req.Id
+	// A batch call is an array of request objects, the responses will be
+	// returned in an array except for the notifications.
+	if len(body) > 0 && body[0] == '[' {
+		s.serveBatch(w, body, u, r.RemoteAddr)
+		return
+	}
 
-	if len(req.Method) == 0 {
-		resp.error(w, http.StatusBadRequest, InvalidRequest,
-			"method parameter not found")
+	resp, httpStatus := s.handle(body, u, r.RemoteAddr)
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
+	writeResponse(w, httpStatus, resp)
+}
+
+// serveBatch handles a batch call, requests are handled in order and the
+// response of a notification will be omitted.
+func (s *Server) serveBatch(w http.ResponseWriter, body []byte, u *user,
+	addr string) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		writeResponse(w, http.StatusBadRequest, newErrorResponse(nil,
+			ParseError, fmt.Sprintf("json parse failed: %s", err)))
+		return
+	}
+	if len(batch) == 0 {
+		writeResponse(w, http.StatusBadRequest, newErrorResponse(nil,
+			InvalidRequest, "empty batch request"))
+		return
+	}
+	if len(batch) > MaxBatchSize {
+		writeResponse(w, http.StatusBadRequest, newErrorResponse(nil,
+			InvalidRequest, fmt.Sprintf("batch request exceeds the maximum"+
+				" size %d", MaxBatchSize)))
+		return
+	}
+
+	responses := make([]*Response, 0, len(batch))
+	for _, data := range batch {
+		if resp, _ := s.handle(data, u, addr); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeResponse(w, http.StatusOK, responses)
+}
+
+// handle handles a single request object of the user from the remote address
+// and returns the response with it's http status, nil will be returned if the
+// request is a notification.
+func (s *Server) handle(data []byte, u *user, addr string) (*Response, int) {
+	if !json.Valid(data) {
+		return newErrorResponse(nil, ParseError, "json parse failed"),
+			http.StatusBadRequest
+	}
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return newErrorResponse(nil, InvalidRequest,
+			fmt.Sprintf("invalid request: %s", err)), http.StatusBadRequest
+	}
+
+	// A JSON-RPC 2.0 request without id is a notification, the server must
+	// not reply to it even if an error occurred.
+	notification := req.Id == nil && req.Version == Version
+	var id interface{}
+	if req.Id != nil {
+		id = req.Id
+	}
+	resp, httpStatus := s.call(id, &req, u, addr)
+	if notification {
+		return nil, httpStatus
+	}
+	// the node RPC returned the errors of the handler with OK status before
+	// JSON-RPC 2.0, so only the response shape differs for them.
+	if req.Version != Version {
+		resp.legacy = true
+		if httpStatus == http.StatusInternalServerError {
+			httpStatus = http.StatusOK
+		}
+	}
+	return resp, httpStatus
+}
+
+// call invokes the handler of the request method and returns the response.
+func (s *Server) call(id interface{}, req *request, u *user,
+	addr string) (*Response, int) {
+	if len(req.Method) == 0 {
+		return newErrorResponse(id, InvalidRequest,
+			"method parameter not found"), http.StatusBadRequest
+	}
+	s.mutex.Lock()
 	handler, ok := s.handlers[req.Method]
+	role := s.roles[req.Method]
+	s.mutex.Unlock()
 	if !ok {
-		resp.error(w, http.StatusNotFound, MethodNotFound,
-			fmt.Sprintf("method %s not found", req.Method))
-		return
+		return newErrorResponse(id, MethodNotFound,
+			fmt.Sprintf("method %s not found", req.Method)), http.StatusNotFound
+	}
+	if u != nil && !u.allowed(req.Method, role) {
+		log.Warnf("[RPC audit] user %s from %s is not allowed to call %s",
+			u.name, addr, req.Method)
+		resp := newErrorResponse(id, MethodNotAllowed,
+			fmt.Sprintf("method %s not allowed", req.Method))
+		return resp, http.StatusForbidden
 	}
 
 	// Json rpc 1.0 support positional parameters while json rpc 2.0 support
@@ -271,42 +471,64 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var params htp.Params
 	switch requestParams := req.Params.(type) {
 	case nil:
+		params = make(htp.Params)
 	case []interface{}:
 		params = s.parseParams(req.Method, requestParams)
 	case map[string]interface{}:
 		params = htp.Params(requestParams)
 	default:
-		resp.error(w, http.StatusBadRequest, InvalidRequest,
+		resp := newErrorResponse(id, InvalidRequest,
 			"params format err, must be an array or a map")
-		return
+		return resp, http.StatusBadRequest
 	}
+	log.Debug("RPC method:", req.Method)
+	logParams := s.redactParams(req.Method, params)
+	log.Debug("RPC params:", logParams)
 
-	result, err := handler(params)
+	release, err := s.acquire(u, addr, req.Method)
 	if err != nil {
-		code := InternalError
-		message := fmt.Sprintf("internal error: %s", err)
+		log.Debugf("RPC request %s from %s refused: %s", req.Method, addr, err)
+		resp := newErrorResponse(id, RateLimited, err.Error())
+		return resp, http.StatusTooManyRequests
+	}
+	defer release()
 
+	result, err := handler(params)
+	if role != RoleRead {
+		name := "anonymous"
+		if u != nil {
+			name = u.name
+		}
+		log.Infof("[RPC audit] user %s from %s called %s with params %v,"+
+			" error: %v", name, addr, req.Method, logParams, err)
+	}
+	if err != nil {
+		// Errors defined by the handler, such as ELA error codes, are
+		// returned as they are, other errors are taken as internal error.
 		switch e := err.(type) {
 		case *htp.Error:
-			code = e.Code
-			message = e.Message
+			return newErrorResponse(id, e.Code, e.Message), http.StatusOK
+		default:
+			resp := newErrorResponse(id, InternalError,
+				fmt.Sprintf("internal error: %s", err))
+			return resp, http.StatusInternalServerError
 		}
-
-		resp.error(w, http.StatusInternalServerError, code, message)
-		return
 	}
 
-	resp.Result = result
-	resp.write(w, http.StatusOK)
+	return &Response{Id: id, Version: Version, Result: result}, http.StatusOK
 }
 
 // NewServer creates and return a JSON-RPC server instance.
 func NewServer(cfg *Config) *Server {
-	return &Server{
+	s := &Server{
 		cfg:       *cfg,
 		paramsMap: make(map[string][]string),
 		handlers:  make(map[string]Handler),
+		roles:     make(map[string]string),
+		sensitive: make(map[string][]string),
 	}
+	s.users = newUsers(cfg)
+	return s
 }
 
 func min(a int, b int) int {
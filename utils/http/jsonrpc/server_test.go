@@ -3,12 +3,16 @@ package jsonrpc
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/elastos/Elastos.ELA/common/log"
 	htp "github.com/elastos/Elastos.ELA/utils/http"
 	"github.com/elastos/Elastos.ELA/utils/test"
 )
@@ -574,4 +578,370 @@ func TestServer_WithIpNotLoopBackIp(t *testing.T) {
 	urlLoopbackWithAuthWrongUserTest(urlLoopBack, true, http.StatusUnauthorized, t)
 
 	Wait(s)
-}
\ No newline at end of file
+}
+
+func serveTestRequest(s *Server, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.RemoteAddr = "127.0.0.1:20336"
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	return w
+}
+
+func TestServer_JSONRPC2(t *testing.T) {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+	s := NewServer(&Config{})
+	s.RegisterAction("add", func(params htp.Params) (interface{}, error) {
+		a, _ := params.Int("a")
+		b, _ := params.Int("b")
+		return a + b, nil
+	}, "a", "b")
+	s.RegisterAction("fail", func(params htp.Params) (interface{}, error) {
+		return nil, htp.NewError(45002, "Internal error")
+	})
+	s.RegisterAction("unexpected", func(params htp.Params) (interface{}, error) {
+		return nil, errors.New("unexpected")
+	})
+
+	// positional and named params
+	w := serveTestRequest(s, `{"jsonrpc":"2.0","id":1,"method":"add","params":[1,2]}`)
+	if w.Code != http.StatusOK ||
+		w.Body.String() != `{"id":1,"jsonrpc":"2.0","result":3}` {
+		t.Fatalf("unexpected response %d %s", w.Code, w.Body)
+	}
+	w = serveTestRequest(s, `{"jsonrpc":"2.0","id":"a","method":"add","params":{"a":3,"b":4}}`)
+	if w.Body.String() != `{"id":"a","jsonrpc":"2.0","result":7}` {
+		t.Fatalf("unexpected response %s", w.Body)
+	}
+
+	// the extra positional params are ignored
+	w = serveTestRequest(s, `{"jsonrpc":"2.0","id":1,"method":"add","params":[1,2,3]}`)
+	if w.Body.String() != `{"id":1,"jsonrpc":"2.0","result":3}` {
+		t.Fatalf("unexpected response %s", w.Body)
+	}
+
+	// error codes
+	cases := []struct {
+		body   string
+		status int
+		code   int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"add"`, http.StatusBadRequest, ParseError},
+		{`"add"`, http.StatusBadRequest, InvalidRequest},
+		{`{"jsonrpc":"2.0","id":1}`, http.StatusBadRequest, InvalidRequest},
+		{`{"jsonrpc":"2.0","id":1,"method":"sub"}`, http.StatusNotFound, MethodNotFound},
+		{`{"jsonrpc":"2.0","id":1,"method":"add","params":1}`, http.StatusBadRequest, InvalidRequest},
+		{`{"jsonrpc":"2.0","id":1,"method":"fail"}`, http.StatusOK, 45002},
+		{`{"jsonrpc":"2.0","id":1,"method":"unexpected"}`, http.StatusInternalServerError, InternalError},
+		{`[]`, http.StatusBadRequest, InvalidRequest},
+	}
+	for _, c := range cases {
+		w = serveTestRequest(s, c.body)
+		var resp map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := resp["result"]; ok {
+			t.Errorf("%s: result should not exist in error response", c.body)
+		}
+		e, _ := resp["error"].(map[string]interface{})
+		if w.Code != c.status || e == nil || e["code"] != float64(c.code) {
+			t.Errorf("%s: unexpected response %d %s", c.body, w.Code, w.Body)
+		}
+	}
+
+	// notifications
+	w = serveTestRequest(s, `{"jsonrpc":"2.0","method":"add","params":[1,2]}`)
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Fatalf("unexpected notification response %d %s", w.Code, w.Body)
+	}
+	w = serveTestRequest(s, `{"jsonrpc":"2.0","method":"fail"}`)
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Fatalf("unexpected notification response %d %s", w.Code, w.Body)
+	}
+
+	// batch
+	w = serveTestRequest(s, `[
+		{"jsonrpc":"2.0","id":1,"method":"add","params":[1,2]},
+		{"jsonrpc":"2.0","method":"add","params":[1,2]},
+		{"jsonrpc":"2.0","id":2,"method":"sub"},
+		1,
+		{"jsonrpc":"2.0","id":3,"method":"add","params":{"a":5}}
+	]`)
+	var batch []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &batch); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || len(batch) != 4 {
+		t.Fatalf("unexpected batch response %d %s", w.Code, w.Body)
+	}
+	if batch[0]["id"] != float64(1) || batch[0]["result"] != float64(3) {
+		t.Errorf("unexpected batch response %v", batch[0])
+	}
+	if batch[1]["id"] != float64(2) || batch[1]["error"].(map[string]interface{})["code"] != float64(MethodNotFound) {
+		t.Errorf("unexpected batch response %v", batch[1])
+	}
+	if batch[2]["id"] != nil || batch[2]["error"].(map[string]interface{})["code"] != float64(InvalidRequest) {
+		t.Errorf("unexpected batch response %v", batch[2])
+	}
+	if batch[3]["id"] != float64(3) || batch[3]["result"] != float64(5) {
+		t.Errorf("unexpected batch response %v", batch[3])
+	}
+
+	// batch of notifications
+	w = serveTestRequest(s, `[{"jsonrpc":"2.0","method":"add"},{"jsonrpc":"2.0","method":"fail"}]`)
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Fatalf("unexpected batch response %d %s", w.Code, w.Body)
+	}
+
+	// batch exceeds the maximum size
+	requests := make([]string, MaxBatchSize+1)
+	for i := range requests {
+		requests[i] = `{"jsonrpc":"2.0","id":1,"method":"add"}`
+	}
+	w = serveTestRequest(s, "["+strings.Join(requests, ",")+"]")
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := resp["error"].(map[string]interface{})
+	if w.Code != http.StatusBadRequest || e == nil ||
+		e["code"] != float64(InvalidRequest) {
+		t.Fatalf("unexpected batch response %d %s", w.Code, w.Body)
+	}
+	w = serveTestRequest(s, "["+strings.Join(requests[1:], ",")+"]")
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected batch response %d %s", w.Code, w.Body)
+	}
+}
+
+func TestServer_LegacyResponse(t *testing.T) {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+	s := NewServer(&Config{})
+	s.RegisterAction("add", func(params htp.Params) (interface{}, error) {
+		a, _ := params.Int("a")
+		b, _ := params.Int("b")
+		return a + b, nil
+	}, "a", "b")
+	s.RegisterAction("fail", func(params htp.Params) (interface{}, error) {
+		return nil, htp.NewError(45002, "Internal error")
+	})
+	s.RegisterAction("unexpected", func(params htp.Params) (interface{}, error) {
+		return nil, errors.New("unexpected")
+	})
+
+	// the requests not in JSON-RPC 2.0 get both result and error
+	cases := []struct {
+		body     string
+		status   int
+		response string
+	}{
+		{`{"id":1,"method":"add","params":[1,2]}`, http.StatusOK,
+			`{"id":1,"jsonrpc":"2.0","result":3,"error":null}`},
+		{`{"jsonrpc":"1.0","id":1,"method":"add","params":[1,2]}`, http.StatusOK,
+			`{"id":1,"jsonrpc":"2.0","result":3,"error":null}`},
+		{`{"method":"fail"}`, http.StatusOK,
+			`{"id":null,"jsonrpc":"2.0","result":null,"error":{"code":45002,"message":"Internal error"}}`},
+		{`{"id":1,"method":"unexpected"}`, http.StatusOK,
+			`{"id":1,"jsonrpc":"2.0","result":null,"error":{"code":-32603,"message":"internal error: unexpected"}}`},
+		{`{"id":1,"method":"sub"}`, http.StatusNotFound,
+			`{"id":1,"jsonrpc":"2.0","result":null,"error":{"code":-32601,"message":"method sub not found"}}`},
+	}
+	for _, c := range cases {
+		w := serveTestRequest(s, c.body)
+		if w.Code != c.status || w.Body.String() != c.response {
+			t.Errorf("%s: unexpected response %d %s", c.body, w.Code, w.Body)
+		}
+	}
+}
+
+func TestServer_Roles(t *testing.T) {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+	s := NewServer(&Config{
+		User: "admin",
+		Pass: "admin",
+		Users: []User{
+			{Name: "explorer", Pass: "explorer", Roles: []string{RoleRead}},
+			{Name: "miner", Pass: "miner", Methods: []string{"togglemining"}},
+		},
+	})
+	handler := func(params htp.Params) (interface{}, error) {
+		return true, nil
+	}
+	s.RegisterAction("getinfo", handler)
+	s.RegisterRoleAction(RoleMining, "togglemining", handler, "mining")
+	s.RegisterRoleAction(RoleAdmin, "setloglevel", handler, "level")
+
+	call := func(user, pass, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.RemoteAddr = "127.0.0.1:20336"
+		req.Header.Set("Content-Type", "application/json")
+		if len(user) > 0 {
+			req.SetBasicAuth(user, pass)
+		}
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w
+	}
+
+	cases := []struct {
+		user   string
+		pass   string
+		method string
+		status int
+	}{
+		{"", "", "getinfo", http.StatusUnauthorized},
+		{"explorer", "wrong", "getinfo", http.StatusUnauthorized},
+		{"explorer", "explorer", "getinfo", http.StatusOK},
+		{"explorer", "explorer", "togglemining", http.StatusForbidden},
+		{"explorer", "explorer", "setloglevel", http.StatusForbidden},
+		{"miner", "miner", "getinfo", http.StatusForbidden},
+		{"miner", "miner", "togglemining", http.StatusOK},
+		{"miner", "miner", "setloglevel", http.StatusForbidden},
+		{"admin", "admin", "getinfo", http.StatusOK},
+		{"admin", "admin", "togglemining", http.StatusOK},
+		{"admin", "admin", "setloglevel", http.StatusOK},
+	}
+	for _, c := range cases {
+		w := call(c.user, c.pass, `{"jsonrpc":"2.0","id":1,"method":"`+
+			c.method+`"}`)
+		if w.Code != c.status {
+			t.Errorf("user %s call %s expect status %d, got %d %s",
+				c.user, c.method, c.status, w.Code, w.Body)
+		}
+	}
+
+	// methods in a batch are checked one by one
+	w := call("explorer", "explorer", `[
+		{"jsonrpc":"2.0","id":1,"method":"getinfo"},
+		{"jsonrpc":"2.0","id":2,"method":"setloglevel","params":[0]}
+	]`)
+	var batch []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &batch); err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 2 || batch[0]["result"] != true ||
+		batch[1]["error"].(map[string]interface{})["code"] !=
+			float64(MethodNotAllowed) {
+		t.Errorf("unexpected batch response %s", w.Body)
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+	s := NewServer(&Config{
+		IPLimiter: htp.NewLimiter(htp.Limits{Rate: 1, Burst: 2}, nil),
+	})
+	s.RegisterAction("getinfo", func(params htp.Params) (interface{}, error) {
+		return true, nil
+	})
+
+	w := serveTestRequest(s, `[
+		{"jsonrpc":"2.0","id":1,"method":"getinfo"},
+		{"jsonrpc":"2.0","id":2,"method":"getinfo"},
+		{"jsonrpc":"2.0","id":3,"method":"getinfo"}
+	]`)
+	var batch []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &batch); err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 3 || batch[0]["result"] != true ||
+		batch[1]["result"] != true ||
+		batch[2]["error"].(map[string]interface{})["code"] !=
+			float64(RateLimited) {
+		t.Errorf("unexpected batch response %s", w.Body)
+	}
+
+	w = serveTestRequest(s, `{"jsonrpc":"2.0","id":1,"method":"getinfo"}`)
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+}
+
+func TestServer_RateLimitAuthFailure(t *testing.T) {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+	s := NewServer(&Config{
+		User:      "admin",
+		Pass:      "admin",
+		IPLimiter: htp.NewLimiter(htp.Limits{Rate: 1, Burst: 3}, nil),
+	})
+	s.RegisterAction("getinfo", func(params htp.Params) (interface{}, error) {
+		return true, nil
+	})
+
+	call := func(pass string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(
+			`{"jsonrpc":"2.0","id":1,"method":"getinfo"}`))
+		req.RemoteAddr = "127.0.0.1:20336"
+		req.Header.Set("Content-Type", "application/json")
+		req.SetBasicAuth("admin", pass)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 3; i++ {
+		if w := call("wrong"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d unexpected response %d %s", i, w.Code,
+				w.Body)
+		}
+	}
+
+	// the failed attempts have spent the burst of the client IP
+	w := call("wrong")
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusTooManyRequests ||
+		resp["error"].(map[string]interface{})["code"] !=
+			float64(RateLimited) {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+	if w := call("admin"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+	if stats := s.cfg.IPLimiter.Stats(); stats.Throttled != 2 {
+		t.Errorf("unexpected limiter stats %+v", stats)
+	}
+}
+
+func TestServer_RedactParams(t *testing.T) {
+	log.NewDefault(test.NodeLogPath, 0, 0, 0)
+	s := NewServer(&Config{User: "admin", Pass: "admin"})
+	var received htp.Params
+	handler := func(params htp.Params) (interface{}, error) {
+		received = params
+		return nil, nil
+	}
+	s.RegisterRoleAction(RoleWallet, "unlock", handler, "passphrase",
+		"timeout")
+	s.RegisterSensitiveParams("unlock", "passphrase")
+
+	params := htp.Params{"passphrase": "secret", "timeout": float64(60)}
+	redacted := s.redactParams("unlock", params)
+	if redacted["passphrase"] == "secret" ||
+		redacted["timeout"] != float64(60) {
+		t.Errorf("unexpected redacted params %v", redacted)
+	}
+	if params["passphrase"] != "secret" {
+		t.Errorf("params changed by redaction %v", params)
+	}
+	if p := s.redactParams("getinfo", params); p["passphrase"] != "secret" {
+		t.Errorf("params of other methods should not be redacted %v", p)
+	}
+
+	// the handler receives the original parameters
+	req := httptest.NewRequest("POST", "/", strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"unlock","params":["secret",60]}`))
+	req.RemoteAddr = "127.0.0.1:20336"
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth("admin", "admin")
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || received["passphrase"] != "secret" {
+		t.Errorf("unexpected response %d %s, params %v", w.Code, w.Body,
+			received)
+	}
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// idleClientTimeout is the duration after which an idle client will be
+	// removed from Limiter.
+	idleClientTimeout = 10 * time.Minute
+)
+
+var (
+	// ErrRateLimited is returned when the cost of a client exceeds the rate
+	// limit.
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrTooManyRequests is returned when the concurrent requests of a client
+	// exceeds the concurrency cap.
+	ErrTooManyRequests = errors.New("too many concurrent requests")
+)
+
+// Limits defines the rate limit and concurrency cap of each client.
+type Limits struct {
+	// Rate is the cost allowed per second, zero means no rate limit.
+	Rate float64
+
+	// Burst is the maximum cost can be spent at once, Rate will be used if
+	// it's lower than Rate.
+	Burst float64
+
+	// MaxConcurrent is the maximum count of concurrent requests, zero means
+	// no concurrency cap.
+	MaxConcurrent int
+}
+
+// LimiterStats is the throttle counters of a Limiter.
+type LimiterStats struct {
+	Clients   int
+	Allowed   uint64
+	Throttled uint64
+	Rejected  uint64
+}
+
+// client is the token bucket and running requests of a client.
+type client struct {
+	tokens     float64
+	lastActive time.Time
+	running    int
+}
+
+// Limiter limits the request rate and concurrent requests of each client, the
+// cost of each request is weighted by it's method.
+type Limiter struct {
+	limits Limits
+	costs  map[string]int
+	now    func() time.Time
+
+	mtx       sync.Mutex
+	clients   map[string]*client
+	lastPrune time.Time
+	allowed   uint64
+	throttled uint64
+	rejected  uint64
+}
+
+// cost returns the cost weight of the method, the weight of a method not
+// configured is 1.
+func (l *Limiter) cost(method string) float64 {
+	if c, ok := l.costs[method]; ok && c > 0 {
+		return float64(c)
+	}
+	return 1
+}
+
+// Acquire takes the cost of the method from the client, and returns a release
+// function to be called after the request finished. ErrRateLimited or
+// ErrTooManyRequests will be returned if the request is refused.
+// A nil Limiter allows all requests.
+func (l *Limiter) Acquire(key, method string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{tokens: l.limits.Burst, lastActive: now}
+		l.clients[key] = c
+	}
+
+	if l.limits.Rate > 0 {
+		c.tokens += now.Sub(c.lastActive).Seconds() * l.limits.Rate
+		if c.tokens > l.limits.Burst {
+			c.tokens = l.limits.Burst
+		}
+	}
+	c.lastActive = now
+
+	if l.limits.MaxConcurrent > 0 && c.running >= l.limits.MaxConcurrent {
+		l.rejected++
+		return nil, ErrTooManyRequests
+	}
+	if l.limits.Rate > 0 {
+		// a request costs more than burst is allowed when the bucket is full
+		cost := l.cost(method)
+		if cost > l.limits.Burst {
+			cost = l.limits.Burst
+		}
+		if c.tokens < cost {
+			l.throttled++
+			return nil, ErrRateLimited
+		}
+		c.tokens -= cost
+	}
+	c.running++
+	l.allowed++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mtx.Lock()
+			c.running--
+			l.mtx.Unlock()
+		})
+	}, nil
+}
+
+// prune removes the idle clients, the token bucket of an idle client has been
+// refilled so it's same as a new client.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < idleClientTimeout {
+		return
+	}
+	l.lastPrune = now
+	for k, c := range l.clients {
+		if c.running == 0 && now.Sub(c.lastActive) >= idleClientTimeout {
+			delete(l.clients, k)
+		}
+	}
+}
+
+// Stats returns the throttle counters of the Limiter.
+func (l *Limiter) Stats() LimiterStats {
+	if l == nil {
+		return LimiterStats{}
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return LimiterStats{
+		Clients:   len(l.clients),
+		Allowed:   l.allowed,
+		Throttled: l.throttled,
+		Rejected:  l.rejected,
+	}
+}
+
+// NewLimiter creates a Limiter with the limits of each client and the cost
+// weights of methods, nil will be returned if no limit configured.
+func NewLimiter(limits Limits, costs map[string]int) *Limiter {
+	if limits.Rate <= 0 && limits.MaxConcurrent <= 0 {
+		return nil
+	}
+	if limits.Burst < limits.Rate {
+		limits.Burst = limits.Rate
+	}
+	return &Limiter{
+		limits:  limits,
+		costs:   costs,
+		now:     time.Now,
+		clients: make(map[string]*client),
+	}
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter_Rate(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(Limits{Rate: 2, Burst: 4},
+		map[string]int{"listunspent": 3})
+	l.now = func() time.Time { return now }
+
+	acquire := func(key, method string) error {
+		release, err := l.Acquire(key, method)
+		if err == nil {
+			release()
+		}
+		return err
+	}
+
+	// burst is available at first
+	for i := 0; i < 4; i++ {
+		if err := acquire("a", "getinfo"); err != nil {
+			t.Fatalf("request %d refused: %s", i, err)
+		}
+	}
+	if err := acquire("a", "getinfo"); err != ErrRateLimited {
+		t.Fatalf("expect ErrRateLimited, got %v", err)
+	}
+
+	// clients are limited separately
+	if err := acquire("b", "listunspent"); err != nil {
+		t.Fatal(err)
+	}
+	if err := acquire("b", "listunspent"); err != ErrRateLimited {
+		t.Fatalf("expect ErrRateLimited, got %v", err)
+	}
+
+	// tokens refilled by rate
+	now = now.Add(time.Second)
+	if err := acquire("a", "listunspent"); err == nil {
+		t.Fatal("expect weighted request refused")
+	}
+	if err := acquire("a", "getinfo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := acquire("a", "getinfo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// idle clients are pruned
+	now = now.Add(idleClientTimeout)
+	if err := acquire("c", "getinfo"); err != nil {
+		t.Fatal(err)
+	}
+	stats := l.Stats()
+	if stats.Clients != 1 || stats.Allowed != 8 || stats.Throttled != 3 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLimiter_Concurrent(t *testing.T) {
+	l := NewLimiter(Limits{MaxConcurrent: 2}, nil)
+
+	r1, err := l.Acquire("a", "getinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := l.Acquire("a", "getinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Acquire("a", "getinfo"); err != ErrTooManyRequests {
+		t.Fatalf("expect ErrTooManyRequests, got %v", err)
+	}
+	r1()
+	r1() // release more than once takes no effect
+	r3, err := l.Acquire("a", "getinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Acquire("a", "getinfo"); err != ErrTooManyRequests {
+		t.Fatalf("expect ErrTooManyRequests, got %v", err)
+	}
+	r2()
+	r3()
+	if stats := l.Stats(); stats.Allowed != 3 || stats.Rejected != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	// nil limiter allows all requests
+	var nl *Limiter
+	if NewLimiter(Limits{}, nil) != nil {
+		t.Fatal("expect nil limiter without limits")
+	}
+	if _, err := nl.Acquire("a", "getinfo"); err != nil {
+		t.Fatal(err)
+	}
+}
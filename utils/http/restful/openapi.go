@@ -0,0 +1,156 @@
+package restful
+
+import (
+	"net/http"
+	"strings"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification followed by the
+// generated document.
+const OpenAPIVersion = "3.0.0"
+
+// Doc describes a RESTful action in the OpenAPI document.
+type Doc struct {
+	// Name is the unique name of the action, it's used as the operation id
+	// in the document and the method name to weight the request cost.
+	Name string
+
+	// Summary is a short description of the action.
+	Summary string
+
+	// Tag groups the actions in the document.
+	Tag string
+
+	// Query is the names of the optional query parameters.
+	Query []string
+}
+
+// DocInfo is the metadata of the OpenAPI document.
+type DocInfo struct {
+	Title       string
+	Description string
+	Version     string
+}
+
+// action is a registered RESTful action.
+type action struct {
+	method string
+	url    string
+	params []string
+	doc    *Doc
+}
+
+// ServeOpenAPI serves the OpenAPI document of the registered actions at the
+// given url.
+func (s *Server) ServeOpenAPI(url string, info DocInfo) {
+	s.docUrl = url
+	s.docInfo = info
+}
+
+// OpenAPI returns the OpenAPI document of the registered actions, it can be
+// marshaled into JSON directly.
+func (s *Server) OpenAPI() map[string]interface{} {
+	paths := make(map[string]interface{})
+	for _, a := range s.actions {
+		path := a.url
+		for _, p := range a.params {
+			path = strings.Replace(path, ":"+p, "{"+p+"}", 1)
+		}
+
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+		item[strings.ToLower(a.method)] = a.operation()
+	}
+
+	info := map[string]interface{}{
+		"title":   s.docInfo.Title,
+		"version": s.docInfo.Version,
+	}
+	if len(s.docInfo.Description) > 0 {
+		info["description"] = s.docInfo.Description
+	}
+
+	return map[string]interface{}{
+		"openapi": OpenAPIVersion,
+		"info":    info,
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"Result": map[string]interface{}{},
+						"Error":  map[string]interface{}{"type": "integer"},
+						"Desc":   map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// operation returns the OpenAPI operation object of the action.
+func (a *action) operation() map[string]interface{} {
+	parameters := make([]interface{}, 0, len(a.params))
+	for _, p := range a.params {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     p,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+
+	op := map[string]interface{}{
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "the result and error code of the action",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"$ref": "#/components/schemas/Response",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if a.doc != nil {
+		if len(a.doc.Name) > 0 {
+			op["operationId"] = a.doc.Name
+		}
+		if len(a.doc.Summary) > 0 {
+			op["summary"] = a.doc.Summary
+		}
+		if len(a.doc.Tag) > 0 {
+			op["tags"] = []string{a.doc.Tag}
+		}
+		for _, q := range a.doc.Query {
+			parameters = append(parameters, map[string]interface{}{
+				"name":     q,
+				"in":       "query",
+				"required": false,
+				"schema":   map[string]interface{}{"type": "string"},
+			})
+		}
+	}
+	if len(parameters) > 0 {
+		op["parameters"] = parameters
+	}
+
+	if a.method == http.MethodPost {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{"type": "object"},
+				},
+			},
+		}
+	}
+	return op
+}
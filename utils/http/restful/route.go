@@ -21,6 +21,7 @@ type Route struct {
 	// The route path in regex
 	path   *regexp.Regexp
 	params [][]string
+	names  map[string]string
 
 	GetHandler    func(params htp.Params) (interface{}, error)
 	PutHandler    func(params htp.Params) (interface{}, error)
@@ -38,22 +39,22 @@ func (r *Route) handle(req *http.Request) (interface{}, error) {
 	switch req.Method {
 	case http.MethodGet:
 		if r.GetHandler != nil {
-			return r.GetHandler(r.parseParams(req.URL.Path, getIndex))
+			return r.GetHandler(r.parseParams(req, getIndex))
 		}
 
 	case http.MethodPut:
 		if r.PutHandler != nil {
-			return r.PutHandler(r.parseParams(req.URL.Path, putIndex))
+			return r.PutHandler(r.parseParams(req, putIndex))
 		}
 
 	case http.MethodPatch:
 		if r.PatchHandler != nil {
-			return r.PatchHandler(r.parseParams(req.URL.Path, patchIndex))
+			return r.PatchHandler(r.parseParams(req, patchIndex))
 		}
 
 	case http.MethodDelete:
 		if r.DeleteHandler != nil {
-			return r.DeleteHandler(r.parseParams(req.URL.Path, deleteIndex))
+			return r.DeleteHandler(r.parseParams(req, deleteIndex))
 		}
 
 	case http.MethodPost:
@@ -68,16 +69,51 @@ func (r *Route) handle(req *http.Request) (interface{}, error) {
 	return nil, nil
 }
 
-func (r *Route) parseParams(path string, index int) htp.Params {
+// parseParams returns the path parameters and query parameters of the
+// request, a query parameter will not overwrite the path parameter.
+func (r *Route) parseParams(req *http.Request, index int) htp.Params {
 	params := r.params[index]
-	matches := r.path.FindStringSubmatch(path)
+	matches := r.path.FindStringSubmatch(req.URL.Path)
 	ret := htp.Params{}
 	for k, v := range matches[1:] {
 		ret[params[k]] = v
 	}
+	for k, v := range req.URL.Query() {
+		if _, ok := ret[k]; !ok && len(v) > 0 {
+			ret[k] = v[0]
+		}
+	}
 	return ret
 }
 
+// hasHandler returns if a handler has been set for the method.
+func (r *Route) hasHandler(method string) bool {
+	switch method {
+	case http.MethodGet:
+		return r.GetHandler != nil
+	case http.MethodPut:
+		return r.PutHandler != nil
+	case http.MethodPatch:
+		return r.PatchHandler != nil
+	case http.MethodDelete:
+		return r.DeleteHandler != nil
+	case http.MethodPost:
+		return r.PostHandler != nil
+	}
+	return false
+}
+
+// name returns the action name of the method.
+func (r *Route) name(method string) string {
+	return r.names[method]
+}
+
+// SetName sets the action name of the method.
+func (r *Route) SetName(method, name string) *Route {
+	r.names[method] = name
+	return r
+}
+
 func (r *Route) SetParams(method string, params ...string) *Route {
 	switch method {
 	case http.MethodGet:
@@ -146,6 +182,7 @@ func NewRoute(regex *regexp.Regexp) *Route {
 	return &Route{
 		path:   regex,
 		params: make([][]string, indexLength),
+		names:  make(map[string]string),
 	}
 }
 
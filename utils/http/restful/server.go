@@ -24,12 +24,22 @@ type Config struct {
 	ServePort uint16
 	NetListen func(port uint16) (net.Listener, error)
 	Response  func(result interface{}, err error) []byte
+
+	// IPLimiter limits the requests of each client IP, nil means no limit.
+	IPLimiter *htp.Limiter
+
+	// RateLimitedCode is the error code returned when a request is refused by
+	// IPLimiter, http.StatusTooManyRequests will be used if not set.
+	RateLimitedCode int
 }
 
 type Server struct {
-	cfg    Config
-	server *http.Server
-	routes map[string]*Route
+	cfg     Config
+	server  *http.Server
+	routes  map[string]*Route
+	actions []*action
+	docUrl  string
+	docInfo DocInfo
 }
 
 func (s *Server) write(w http.ResponseWriter, data []byte) {
@@ -93,6 +103,13 @@ func (s *Server) RegisterDeleteAction(url string, handler func(htp.Params) (inte
 // registered handler type must match the method, for example, GET method must use
 // func(params http.Params) (interface{}, error) as handler.
 func (s *Server) RegisterAction(method, url string, handler interface{}) error {
+	return s.RegisterDocAction(method, url, handler, nil)
+}
+
+// RegisterDocAction register a RESTful handler like RegisterAction, and the
+// action will be described by the given doc in the OpenAPI document.
+func (s *Server) RegisterDocAction(method, url string, handler interface{},
+	doc *Doc) error {
 	// check url format.
 	if !strings.HasPrefix(url, "/") {
 		return fmt.Errorf(`resource url must begin with "/"`)
@@ -133,24 +150,80 @@ func (s *Server) RegisterAction(method, url string, handler interface{}) error {
 	if len(params) > 0 {
 		route.SetParams(method, params...)
 	}
+	if doc != nil {
+		route.SetName(method, doc.Name)
+	}
 
 	s.routes[path] = route
+	s.actions = append(s.actions, &action{
+		method: method,
+		url:    url,
+		params: params,
+		doc:    doc,
+	})
 
 	return nil
 }
 
+// respond returns the result or error of a request to the http client.
+func (s *Server) respond(w http.ResponseWriter, result interface{}, err error) {
+	if s.cfg.Response != nil {
+		data := s.cfg.Response(result, err)
+		s.write(w, data)
+
+	} else {
+		s.response(w, result, err)
+
+	}
+}
+
+// acquire takes the cost of the action from the limiter of the client IP, the
+// returned function should be called after the request finished.
+func (s *Server) acquire(req *http.Request, name string) (func(), error) {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	release, err := s.cfg.IPLimiter.Acquire(host, name)
+	if err != nil {
+		code := s.cfg.RateLimitedCode
+		if code == 0 {
+			code = http.StatusTooManyRequests
+		}
+		return nil, htp.NewError(code, err.Error())
+	}
+	return release, nil
+}
+
 func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if len(s.docUrl) > 0 && req.URL.Path == s.docUrl &&
+		req.Method == http.MethodGet {
+		data, _ := json.Marshal(s.OpenAPI())
+		s.write(w, data)
+		return
+	}
+
 	for _, r := range s.routes {
 		if r.matches(req) {
-			result, err := r.handle(req)
-			if s.cfg.Response != nil {
-				data := s.cfg.Response(result, err)
-				s.write(w, data)
-
-			} else {
-				s.response(w, result, err)
+			// answer the CORS preflight request.
+			if req.Method == http.MethodOptions {
+				s.write(w, []byte{})
+				return
+			}
+			if !r.hasHandler(req.Method) {
+				http.Error(w, fmt.Sprintf("method %s not allowed",
+					req.Method), http.StatusMethodNotAllowed)
+				return
+			}
 
+			release, err := s.acquire(req, r.name(req.Method))
+			if err != nil {
+				s.respond(w, nil, err)
+				return
 			}
+			defer release()
+			result, err := r.handle(req)
+			s.respond(w, result, err)
 
 			// return when handler finished.
 			return
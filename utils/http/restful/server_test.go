@@ -2,8 +2,10 @@ package restful
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -442,3 +444,86 @@ func TestServer_ServeHTTP_Path(t *testing.T) {
 		s.Stop()
 	}
 }
+
+func TestServer_OpenAPI(t *testing.T) {
+	s := NewServer(&Config{
+		IPLimiter: htp.NewLimiter(htp.Limits{Rate: 1, Burst: 2}, nil),
+	})
+	err := s.RegisterDocAction(http.MethodGet, "/api/v2/items/:id",
+		func(params htp.Params) (interface{}, error) {
+			return params, nil
+		}, &Doc{Name: "getitem", Tag: "item", Summary: "Returns an item",
+			Query: []string{"verbose"}})
+	assert.NoError(t, err)
+	err = s.RegisterPostAction("/api/v2/items",
+		func(data []byte) (interface{}, error) {
+			return string(data), nil
+		})
+	assert.NoError(t, err)
+	s.ServeOpenAPI("/api/v2/openapi.json", DocInfo{Title: "test", Version: "v1"})
+
+	serve := func(method, url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader("data"))
+		req.RemoteAddr = "127.0.0.1:20334"
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w
+	}
+
+	// query parameters do not overwrite path parameters
+	w := serve(http.MethodGet, "/api/v2/items/abc?verbose=1&id=def")
+	var resp Response
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 0, resp.Error)
+	assert.Equal(t, map[string]interface{}{"id": "abc", "verbose": "1"},
+		resp.Result)
+
+	// method not allowed and CORS preflight are not limited
+	w = serve(http.MethodDelete, "/api/v2/items/abc")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	w = serve(http.MethodOptions, "/api/v2/items")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	// the third request exceeds the burst
+	w = serve(http.MethodPost, "/api/v2/items")
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "data", resp.Result)
+	w = serve(http.MethodPost, "/api/v2/items")
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusTooManyRequests, resp.Error)
+
+	// OpenAPI document
+	w = serve(http.MethodGet, "/api/v2/openapi.json")
+	var doc struct {
+		OpenAPI string
+		Info    struct{ Title, Version string }
+		Paths   map[string]map[string]struct {
+			OperationId string
+			Tags        []string
+			Parameters  []struct {
+				Name, In string
+				Required bool
+			}
+			RequestBody *struct{ Required bool }
+		}
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, OpenAPIVersion, doc.OpenAPI)
+	assert.Equal(t, "test", doc.Info.Title)
+	assert.Len(t, doc.Paths, 2)
+
+	get := doc.Paths["/api/v2/items/{id}"]["get"]
+	assert.Equal(t, "getitem", get.OperationId)
+	assert.Equal(t, []string{"item"}, get.Tags)
+	if assert.Len(t, get.Parameters, 2) {
+		assert.Equal(t, "id", get.Parameters[0].Name)
+		assert.Equal(t, "path", get.Parameters[0].In)
+		assert.True(t, get.Parameters[0].Required)
+		assert.Equal(t, "verbose", get.Parameters[1].Name)
+		assert.Equal(t, "query", get.Parameters[1].In)
+	}
+	post := doc.Paths["/api/v2/items"]["post"]
+	assert.Empty(t, post.OperationId)
+	assert.NotNil(t, post.RequestBody)
+}